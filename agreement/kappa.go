@@ -0,0 +1,256 @@
+// Package agreement measures how much raters agree when they classify the
+// same items into categories.
+package agreement
+
+import (
+	"errors"
+	"math"
+	"sort"
+)
+
+// z95 is the normal quantile of a two-sided 95% confidence interval.
+const z95 = 1.959963984540054
+
+var (
+	ErrorTooFewItems = errors.New("at least two rated items are needed")
+	ErrorUndefined   = errors.New("kappa is undefined when every rating falls in one category")
+	ErrorRaters      = errors.New("every item must be rated by the same number of raters")
+)
+
+// Estimate is a kappa value with its standard error and 95% confidence
+// interval.
+type Estimate struct {
+	Value  float64 `json:"value"`
+	StdErr float64 `json:"stdErr"`
+	Lower  float64 `json:"lower"`
+	Upper  float64 `json:"upper"`
+	Items  int     `json:"items"`
+}
+
+func newEstimate(value float64, stdErr float64, items int) *Estimate {
+	return &Estimate{
+		Value:  value,
+		StdErr: stdErr,
+		Lower:  math.Max(-1, value-z95*stdErr),
+		Upper:  math.Min(1, value+z95*stdErr),
+		Items:  items,
+	}
+}
+
+// Percent returns the share of items, among those rated at least twice, on
+// which every rating is the same.
+func Percent(items [][]string) float64 {
+	rated, agreed := 0, 0
+	for _, ratings := range items {
+		if len(ratings) < 2 {
+			continue
+		}
+		rated++
+		same := true
+		for _, rating := range ratings[1:] {
+			if rating != ratings[0] {
+				same = false
+				break
+			}
+		}
+		if same {
+			agreed++
+		}
+	}
+	if rated == 0 {
+		return 0
+	}
+	return float64(agreed) / float64(rated)
+}
+
+func categories(items [][]string) []string {
+	seen := make(map[string]bool)
+	for _, ratings := range items {
+		for _, rating := range ratings {
+			seen[rating] = true
+		}
+	}
+	list := make([]string, 0, len(seen))
+	for category := range seen {
+		list = append(list, category)
+	}
+	sort.Strings(list)
+	return list
+}
+
+// Cohen computes Cohen's kappa of two raters. first[i] and second[i] are the
+// ratings of the same item. The standard error is the large sample one of
+// Fleiss, Cohen and Everitt (1969), which does not assume kappa is zero.
+func Cohen(first []string, second []string) (*Estimate, error) {
+	n := len(first)
+	if n != len(second) {
+		return nil, ErrorRaters
+	}
+	if n < 2 {
+		return nil, ErrorTooFewItems
+	}
+
+	pairs := make([][]string, n)
+	for i := range first {
+		pairs[i] = []string{first[i], second[i]}
+	}
+	cats := categories(pairs)
+	index := make(map[string]int, len(cats))
+	for i, category := range cats {
+		index[category] = i
+	}
+
+	k := len(cats)
+	p := make([][]float64, k)
+	for i := range p {
+		p[i] = make([]float64, k)
+	}
+	for i := range first {
+		p[index[first[i]]][index[second[i]]] += 1 / float64(n)
+	}
+
+	rows := make([]float64, k)
+	cols := make([]float64, k)
+	for i := 0; i < k; i++ {
+		for j := 0; j < k; j++ {
+			rows[i] += p[i][j]
+			cols[j] += p[i][j]
+		}
+	}
+
+	po, pe := 0.0, 0.0
+	for i := 0; i < k; i++ {
+		po += p[i][i]
+		pe += rows[i] * cols[i]
+	}
+	if pe >= 1 {
+		return nil, ErrorUndefined
+	}
+	kappa := (po - pe) / (1 - pe)
+
+	a, b := 0.0, 0.0
+	for i := 0; i < k; i++ {
+		a += p[i][i] * math.Pow(1-(rows[i]+cols[i])*(1-kappa), 2)
+		for j := 0; j < k; j++ {
+			if i != j {
+				b += p[i][j] * math.Pow(cols[i]+rows[j], 2)
+			}
+		}
+	}
+	b *= math.Pow(1-kappa, 2)
+	c := math.Pow(kappa-pe*(1-kappa), 2)
+	variance := (a + b - c) / (float64(n) * math.Pow(1-pe, 2))
+
+	return newEstimate(kappa, math.Sqrt(math.Max(variance, 0)), n), nil
+}
+
+// fleissCounts turns the ratings into per item category counts.
+func fleissCounts(items [][]string, cats []string) [][]float64 {
+	index := make(map[string]int, len(cats))
+	for i, category := range cats {
+		index[category] = i
+	}
+	counts := make([][]float64, len(items))
+	for i, ratings := range items {
+		counts[i] = make([]float64, len(cats))
+		for _, rating := range ratings {
+			counts[i][index[rating]]++
+		}
+	}
+	return counts
+}
+
+func fleissKappa(counts [][]float64, raters float64) (float64, bool) {
+	if len(counts) == 0 {
+		return 0, false
+	}
+	n := float64(len(counts))
+	totals := make([]float64, len(counts[0]))
+	observed := 0.0
+	for _, row := range counts {
+		squares := 0.0
+		for j, count := range row {
+			totals[j] += count
+			squares += count * count
+		}
+		observed += (squares - raters) / (raters * (raters - 1))
+	}
+	observed /= n
+
+	expected := 0.0
+	for _, total := range totals {
+		share := total / (n * raters)
+		expected += share * share
+	}
+	if expected >= 1 {
+		return 0, false
+	}
+	return (observed - expected) / (1 - expected), true
+}
+
+// LargestGroup groups the items rated at least twice by their number of
+// ratings and returns the largest group, the one with more ratings on a tie.
+// Fleiss' kappa lets the raters differ between items but not their number, so
+// records screened by two of several reviewers still make a valid group.
+func LargestGroup(items [][]string) [][]string {
+	groups := make(map[int][][]string)
+	for _, ratings := range items {
+		if len(ratings) >= 2 {
+			groups[len(ratings)] = append(groups[len(ratings)], ratings)
+		}
+	}
+	largest, raters := [][]string{}, 0
+	for count, group := range groups {
+		if len(group) > len(largest) || (len(group) == len(largest) && count > raters) {
+			largest, raters = group, count
+		}
+	}
+	return largest
+}
+
+// Fleiss computes Fleiss' kappa of items that were all rated by the same
+// number of raters, at least two. The standard error is a jackknife over the
+// items.
+func Fleiss(items [][]string) (*Estimate, error) {
+	n := len(items)
+	if n < 2 {
+		return nil, ErrorTooFewItems
+	}
+	raters := len(items[0])
+	for _, ratings := range items {
+		if len(ratings) != raters || raters < 2 {
+			return nil, ErrorRaters
+		}
+	}
+
+	counts := fleissCounts(items, categories(items))
+	kappa, ok := fleissKappa(counts, float64(raters))
+	if !ok {
+		return nil, ErrorUndefined
+	}
+
+	leaveOut := make([]float64, 0, n)
+	rest := make([][]float64, 0, n-1)
+	for i := range counts {
+		rest = append(rest[:0], counts[:i]...)
+		rest = append(rest, counts[i+1:]...)
+		if value, ok := fleissKappa(rest, float64(raters)); ok {
+			leaveOut = append(leaveOut, value)
+		}
+	}
+
+	stdErr := 0.0
+	if m := float64(len(leaveOut)); m > 1 {
+		mean := 0.0
+		for _, value := range leaveOut {
+			mean += value
+		}
+		mean /= m
+		for _, value := range leaveOut {
+			stdErr += (value - mean) * (value - mean)
+		}
+		stdErr = math.Sqrt((m - 1) / m * stdErr)
+	}
+
+	return newEstimate(kappa, stdErr, n), nil
+}
@@ -2,6 +2,7 @@ package cache
 
 import (
 	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
 	"github.com/patrickmn/go-cache"
 	"golang.org/x/exp/slog"
 	"sci-review/model"
@@ -78,10 +79,109 @@ func (rc *InvestigationRepoCache) FindOne(investigationId uuid.UUID) (*model.Inv
 	return investigation, nil
 }
 
-func (rc *InvestigationRepoCache) SaveKeyword(investigationKeyword *model.InvestigationKeyword) error {
-	return rc.InvestigationRepo.SaveKeyword(investigationKeyword)
+func (rc *InvestigationRepoCache) UpdateQuestion(investigation *model.Investigation) error {
+	err := rc.InvestigationRepo.UpdateQuestion(investigation)
+	if err != nil {
+		return err
+	}
+
+	rc.AppCache.Delete(findAllInvestigationKey(investigation.ReviewId))
+	rc.AppCache.Delete(findOneInvestigationKey(investigation.Id))
+	slog.Debug("InvestigationRepoCache.UpdateQuestion: cache cleared", "investigationId", investigation.Id)
+
+	return nil
+}
+
+func (rc *InvestigationRepoCache) UpdateStatus(investigation *model.Investigation, tx *sqlx.Tx) error {
+	return rc.InvestigationRepo.UpdateStatus(investigation, tx)
+}
+
+func (rc *InvestigationRepoCache) CreateStatusChange(change *model.InvestigationStatusChange, tx *sqlx.Tx) error {
+	return rc.InvestigationRepo.CreateStatusChange(change, tx)
+}
+
+func (rc *InvestigationRepoCache) FindStatusChanges(investigationId uuid.UUID) ([]model.InvestigationStatusChange, error) {
+	return rc.InvestigationRepo.FindStatusChanges(investigationId)
+}
+
+func (rc *InvestigationRepoCache) SaveKeyword(investigationKeyword *model.InvestigationKeyword, tx *sqlx.Tx) error {
+	return rc.InvestigationRepo.SaveKeyword(investigationKeyword, tx)
 }
 
 func (rc *InvestigationRepoCache) GetKeywordsByInvestigationId(investigationId uuid.UUID) ([]model.InvestigationKeyword, error) {
 	return rc.InvestigationRepo.GetKeywordsByInvestigationId(investigationId)
 }
+
+func (rc *InvestigationRepoCache) FindKeyword(investigationId uuid.UUID, keywordId uuid.UUID) (*model.InvestigationKeyword, error) {
+	return rc.InvestigationRepo.FindKeyword(investigationId, keywordId)
+}
+
+func (rc *InvestigationRepoCache) UpdateKeyword(investigationKeyword *model.InvestigationKeyword, tx *sqlx.Tx) error {
+	return rc.InvestigationRepo.UpdateKeyword(investigationKeyword, tx)
+}
+
+func (rc *InvestigationRepoCache) DeleteKeyword(investigationKeyword *model.InvestigationKeyword, tx *sqlx.Tx) error {
+	return rc.InvestigationRepo.DeleteKeyword(investigationKeyword, tx)
+}
+
+func (rc *InvestigationRepoCache) UpdateKeywordPositions(keywords []model.InvestigationKeyword, tx *sqlx.Tx) error {
+	return rc.InvestigationRepo.UpdateKeywordPositions(keywords, tx)
+}
+
+func (rc *InvestigationRepoCache) CreateKeywordChange(change *model.InvestigationKeywordChange, tx *sqlx.Tx) error {
+	return rc.InvestigationRepo.CreateKeywordChange(change, tx)
+}
+
+func (rc *InvestigationRepoCache) FindKeywordChanges(investigationId uuid.UUID) ([]model.InvestigationKeywordChange, error) {
+	return rc.InvestigationRepo.FindKeywordChanges(investigationId)
+}
+
+func (rc *InvestigationRepoCache) CreatePilotSearch(pilotSearch *model.PilotSearch) error {
+	return rc.InvestigationRepo.CreatePilotSearch(pilotSearch)
+}
+
+func (rc *InvestigationRepoCache) FindPilotSearches(investigationId uuid.UUID) ([]model.PilotSearch, error) {
+	return rc.InvestigationRepo.FindPilotSearches(investigationId)
+}
+
+func (rc *InvestigationRepoCache) DeletePilotSearch(investigationId uuid.UUID, pilotSearchId uuid.UUID) error {
+	return rc.InvestigationRepo.DeletePilotSearch(investigationId, pilotSearchId)
+}
+
+func (rc *InvestigationRepoCache) CreateProtocol(protocol *model.RegisteredProtocol) error {
+	return rc.InvestigationRepo.CreateProtocol(protocol)
+}
+
+func (rc *InvestigationRepoCache) FindProtocols(investigationId uuid.UUID) ([]model.RegisteredProtocol, error) {
+	return rc.InvestigationRepo.FindProtocols(investigationId)
+}
+
+func (rc *InvestigationRepoCache) DeleteProtocol(investigationId uuid.UUID, protocolId uuid.UUID) error {
+	return rc.InvestigationRepo.DeleteProtocol(investigationId, protocolId)
+}
+
+func (rc *InvestigationRepoCache) CreatePublishedReview(review *model.PublishedReview) error {
+	return rc.InvestigationRepo.CreatePublishedReview(review)
+}
+
+func (rc *InvestigationRepoCache) FindPublishedReviews(investigationId uuid.UUID) ([]model.PublishedReview, error) {
+	return rc.InvestigationRepo.FindPublishedReviews(investigationId)
+}
+
+func (rc *InvestigationRepoCache) DeletePublishedReview(investigationId uuid.UUID, publishedReviewId uuid.UUID) error {
+	return rc.InvestigationRepo.DeletePublishedReview(investigationId, publishedReviewId)
+}
+
+// Invalidate clears the cached investigation once the transaction that
+// changed it has committed, so concurrent reads cannot cache the old row
+// again.
+func (rc *InvestigationRepoCache) Invalidate(investigation *model.Investigation) {
+	rc.InvestigationRepo.Invalidate(investigation)
+	rc.AppCache.Delete(findAllInvestigationKey(investigation.ReviewId))
+	rc.AppCache.Delete(findOneInvestigationKey(investigation.Id))
+	slog.Debug("InvestigationRepoCache.Invalidate: cache cleared", "investigationId", investigation.Id)
+}
+
+func (rc *InvestigationRepoCache) GetDB() *sqlx.DB {
+	return rc.InvestigationRepo.GetDB()
+}
@@ -26,6 +26,10 @@ func findOneReviewKey(id uuid.UUID) string {
 	return "review:findOne:" + id.String()
 }
 
+func findReviewersKey(reviewId uuid.UUID) string {
+	return "review:findReviewers:" + reviewId.String()
+}
+
 func (r ReviewRepoCache) Create(review *model.Review, tx *sqlx.Tx) error {
 	err := r.ReviewRepo.Create(review, tx)
 	if err != nil {
@@ -46,6 +50,7 @@ func (r ReviewRepoCache) AddReviewer(reviewer *model.Reviewer, tx *sqlx.Tx) erro
 
 	r.AppCache.Delete(findAllReviewKey(reviewer.UserId))
 	r.AppCache.Delete(findOneReviewKey(reviewer.ReviewId))
+	r.AppCache.Delete(findReviewersKey(reviewer.ReviewId))
 	slog.Debug("ReviewRepoCache.AddReviewer: cache cleared", "userId", reviewer.UserId)
 
 	return nil
@@ -89,6 +94,38 @@ func (r ReviewRepoCache) FindById(id uuid.UUID) (*model.Review, error) {
 	return review, nil
 }
 
+func (r ReviewRepoCache) FindReviewers(reviewId uuid.UUID) ([]model.Reviewer, error) {
+	value, found := r.AppCache.Get(findReviewersKey(reviewId))
+	if found {
+		slog.Debug("ReviewRepoCache.FindReviewers: cache hit", "reviewId", reviewId)
+		return value.([]model.Reviewer), nil
+	}
+	slog.Debug("ReviewRepoCache.FindReviewers: cache miss", "reviewId", reviewId)
+
+	reviewers, err := r.ReviewRepo.FindReviewers(reviewId)
+	if err != nil {
+		return nil, err
+	}
+
+	r.AppCache.Set(findReviewersKey(reviewId), reviewers, cache.DefaultExpiration)
+	slog.Debug("ReviewRepoCache.FindReviewers: cache set", "reviewId", reviewId)
+
+	return reviewers, nil
+}
+
+func (r ReviewRepoCache) UpdateReviewerRole(reviewer *model.Reviewer, tx *sqlx.Tx) error {
+	return r.ReviewRepo.UpdateReviewerRole(reviewer, tx)
+}
+
+// InvalidateReviewers clears the cached reviewers once the transaction that
+// changed them has committed, so concurrent reads cannot cache the old roles
+// again.
+func (r ReviewRepoCache) InvalidateReviewers(reviewId uuid.UUID) {
+	r.ReviewRepo.InvalidateReviewers(reviewId)
+	r.AppCache.Delete(findReviewersKey(reviewId))
+	slog.Debug("ReviewRepoCache.InvalidateReviewers: cache cleared", "reviewId", reviewId)
+}
+
 func (r ReviewRepoCache) GetDB() *sqlx.DB {
 	return r.ReviewRepo.GetDB()
 }
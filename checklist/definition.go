@@ -0,0 +1,103 @@
+// Package checklist reads critical appraisal checklists written as JSON or
+// YAML and scores the answers given to them.
+package checklist
+
+import (
+	"bytes"
+	"fmt"
+	"gopkg.in/yaml.v3"
+	"sci-review/common"
+	"sci-review/model"
+	"slices"
+	"strings"
+)
+
+// Parse reads a checklist definition written as YAML or, YAML being a
+// superset of it, JSON, and checks it.
+func Parse(text string) (model.ChecklistDefinition, []common.ErrorResponse) {
+	definition := model.ChecklistDefinition{}
+	decoder := yaml.NewDecoder(bytes.NewBufferString(text))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(&definition); err != nil {
+		invalid := common.Message("definition", "yaml", "")
+		invalid.Error += ": " + err.Error()
+		return definition, []common.ErrorResponse{invalid}
+	}
+	if definition.Scoring.Basis == "" {
+		definition.Scoring.Basis = model.ScoringPoints
+	}
+	return definition, Check(definition)
+}
+
+// Check tells what is wrong with a definition: a missing name, questions
+// without ids or text, ids used twice, questions with fewer than two
+// options, options without values or with negative scores, and ratings
+// without labels.
+func Check(definition model.ChecklistDefinition) []common.ErrorResponse {
+	errs := []common.ErrorResponse{}
+	if strings.TrimSpace(definition.Name) == "" {
+		errs = append(errs, common.Message("name", "required", ""))
+	}
+	errs = append(errs, checkOptions(definition.Options, "options")...)
+
+	if len(definition.Questions) == 0 {
+		errs = append(errs, common.Message("questions", "required", ""))
+	}
+	ids := make(map[string]bool)
+	for i, question := range definition.Questions {
+		name := fmt.Sprintf("questions[%d]", i)
+		switch {
+		case strings.TrimSpace(question.Id) == "":
+			errs = append(errs, common.Message(name+".id", "required", ""))
+		case ids[question.Id]:
+			errs = append(errs, common.Message(name+".id", "unique", ""))
+		}
+		ids[question.Id] = true
+
+		if strings.TrimSpace(question.Text) == "" {
+			errs = append(errs, common.Message(name+".text", "required", ""))
+		}
+		errs = append(errs, checkOptions(question.Options, name+".options")...)
+		if len(definition.QuestionOptions(question)) < 2 {
+			errs = append(errs, common.Message(name+".options", "min_items", "2"))
+		}
+	}
+
+	bases := []string{}
+	for _, basis := range model.ScoringBases {
+		bases = append(bases, string(basis))
+	}
+	if !slices.Contains(bases, string(definition.Scoring.Basis)) {
+		errs = append(errs, common.Message("scoring.basis", "oneof", strings.Join(bases, " ")))
+	}
+	for i, rating := range definition.Scoring.Ratings {
+		name := fmt.Sprintf("scoring.ratings[%d]", i)
+		if strings.TrimSpace(rating.Label) == "" {
+			errs = append(errs, common.Message(name+".label", "required", ""))
+		}
+		if rating.Min < 0 {
+			errs = append(errs, common.Message(name+".min", "gte", "0"))
+		}
+	}
+	return errs
+}
+
+func checkOptions(options []model.ChecklistOption, prefix string) []common.ErrorResponse {
+	errs := []common.ErrorResponse{}
+	values := make(map[string]bool)
+	for i, option := range options {
+		name := fmt.Sprintf("%s[%d]", prefix, i)
+		switch {
+		case strings.TrimSpace(option.Value) == "":
+			errs = append(errs, common.Message(name+".value", "required", ""))
+		case values[option.Value]:
+			errs = append(errs, common.Message(name+".value", "unique", ""))
+		}
+		values[option.Value] = true
+
+		if option.Score < 0 {
+			errs = append(errs, common.Message(name+".score", "gte", "0"))
+		}
+	}
+	return errs
+}
@@ -0,0 +1,74 @@
+package checklist
+
+import (
+	"sci-review/model"
+)
+
+// Result is the score of a set of answers. Max leaves out the questions
+// answered as not applicable; Rating is empty when the checklist does not
+// rate studies or no rating is reached.
+type Result struct {
+	Score   float64
+	Max     float64
+	Percent float64
+	Rating  string
+}
+
+func findOption(options []model.ChecklistOption, value string) (model.ChecklistOption, bool) {
+	for _, option := range options {
+		if option.Value == value {
+			return option, true
+		}
+	}
+	return model.ChecklistOption{}, false
+}
+
+// Score adds up the points of the answers, by question id, and rates the
+// study with the highest rating whose minimum its score reaches.
+func Score(definition model.ChecklistDefinition, answers map[string]string) Result {
+	result := Result{}
+	for _, question := range definition.Questions {
+		options := definition.QuestionOptions(question)
+		option, found := findOption(options, answers[question.Id])
+		if found && option.NotApplicable {
+			continue
+		}
+		best := 0.0
+		for _, known := range options {
+			if !known.NotApplicable && known.Score > best {
+				best = known.Score
+			}
+		}
+		result.Max += best
+		if found {
+			result.Score += option.Score
+		}
+	}
+	if result.Max > 0 {
+		result.Percent = result.Score / result.Max * 100
+	}
+
+	value := result.Score
+	if definition.Scoring.Basis == model.ScoringPercent {
+		value = result.Percent
+	}
+	reached := -1.0
+	for _, rating := range definition.Scoring.Ratings {
+		if value >= rating.Min && rating.Min > reached {
+			reached = rating.Min
+			result.Rating = rating.Label
+		}
+	}
+	return result
+}
+
+// Unanswered lists the questions without one of their options as answer.
+func Unanswered(definition model.ChecklistDefinition, answers map[string]string) []model.ChecklistQuestion {
+	unanswered := []model.ChecklistQuestion{}
+	for _, question := range definition.Questions {
+		if _, found := findOption(definition.QuestionOptions(question), answers[question.Id]); !found {
+			unanswered = append(unanswered, question)
+		}
+	}
+	return unanswered
+}
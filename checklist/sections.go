@@ -0,0 +1,30 @@
+package checklist
+
+import "sci-review/model"
+
+// Item is a question with the options it can be answered with.
+type Item struct {
+	Question model.ChecklistQuestion
+	Options  []model.ChecklistOption
+}
+
+// Section groups consecutive questions of the same section, as the
+// checklist lays them out.
+type Section struct {
+	Name  string
+	Items []Item
+}
+
+func Sections(definition model.ChecklistDefinition) []Section {
+	sections := []Section{}
+	for _, question := range definition.Questions {
+		item := Item{Question: question, Options: definition.QuestionOptions(question)}
+		last := len(sections) - 1
+		if last >= 0 && sections[last].Name == question.Section {
+			sections[last].Items = append(sections[last].Items, item)
+			continue
+		}
+		sections = append(sections, Section{Name: question.Section, Items: []Item{item}})
+	}
+	return sections
+}
@@ -0,0 +1,615 @@
+package citation
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// bibtexTypeKey keeps the original entry type in the raw metadata when the
+// CSL type cannot be mapped back to it (e.g. mastersthesis).
+const bibtexTypeKey = "bibtex_type"
+
+// bibtexOriginalPrefix keeps in the raw metadata the BibTeX value of the
+// fields stored as plain text, e.g. "bibtex_title", so an export writes back
+// the braces and commands CleanLatex removed.
+const bibtexOriginalPrefix = "bibtex_"
+
+var bibtexMonths = map[string]string{
+	"jan": "January", "feb": "February", "mar": "March", "apr": "April", "may": "May", "jun": "June",
+	"jul": "July", "aug": "August", "sep": "September", "oct": "October", "nov": "November", "dec": "December",
+}
+
+// BibtexReader reads BibTeX databases (Zotero, JabRef) entry by entry.
+// @string macros are expanded, @comment and @preamble are skipped.
+type BibtexReader struct {
+	reader  *bufio.Reader
+	line    int
+	macros  map[string]string
+	lastNew bool
+}
+
+func NewBibtexReader(r io.Reader) *BibtexReader {
+	macros := map[string]string{}
+	for key, value := range bibtexMonths {
+		macros[key] = value
+	}
+	return &BibtexReader{reader: bufio.NewReader(r), line: 1, macros: macros}
+}
+
+var errBibtexEOF = errors.New("unexpected end of file")
+
+func (br *BibtexReader) read() (rune, error) {
+	r, _, err := br.reader.ReadRune()
+	if err != nil {
+		return 0, err
+	}
+	br.lastNew = r == '\n'
+	if br.lastNew {
+		br.line++
+	}
+	return r, nil
+}
+
+func (br *BibtexReader) unread() {
+	if br.reader.UnreadRune() == nil && br.lastNew {
+		br.line--
+	}
+	br.lastNew = false
+}
+
+func (br *BibtexReader) skipSpace() (rune, error) {
+	for {
+		r, err := br.read()
+		if err != nil {
+			return 0, err
+		}
+		if !unicode.IsSpace(r) {
+			return r, nil
+		}
+	}
+}
+
+func (br *BibtexReader) readIdentifier() (string, error) {
+	var sb strings.Builder
+	for {
+		r, err := br.read()
+		if err != nil {
+			return "", err
+		}
+		if unicode.IsSpace(r) || strings.ContainsRune("{}(),=#\"", r) {
+			br.unread()
+			return sb.String(), nil
+		}
+		sb.WriteRune(r)
+	}
+}
+
+// readBalanced reads until the brace that closes an already consumed '{'.
+func (br *BibtexReader) readBalanced(closing rune) (string, error) {
+	var sb strings.Builder
+	depth := 0
+	for {
+		r, err := br.read()
+		if err != nil {
+			return "", errBibtexEOF
+		}
+		if r == '\\' {
+			// an escaped brace is literal and does not open or close a group
+			sb.WriteRune(r)
+			if r, err = br.read(); err != nil {
+				return "", errBibtexEOF
+			}
+			sb.WriteRune(r)
+			continue
+		}
+		switch {
+		case r == '{':
+			depth++
+		case r == '}' && depth > 0:
+			depth--
+		case r == closing && depth == 0:
+			return sb.String(), nil
+		}
+		sb.WriteRune(r)
+	}
+}
+
+func (br *BibtexReader) readQuoted() (string, error) {
+	var sb strings.Builder
+	depth := 0
+	for {
+		r, err := br.read()
+		if err != nil {
+			return "", errBibtexEOF
+		}
+		if r == '\\' {
+			sb.WriteRune(r)
+			if r, err = br.read(); err != nil {
+				return "", errBibtexEOF
+			}
+			sb.WriteRune(r)
+			continue
+		}
+		switch {
+		case r == '{':
+			depth++
+		case r == '}' && depth > 0:
+			depth--
+		case r == '"' && depth == 0:
+			return sb.String(), nil
+		}
+		sb.WriteRune(r)
+	}
+}
+
+func (br *BibtexReader) readValue() (string, error) {
+	var sb strings.Builder
+	for {
+		r, err := br.skipSpace()
+		if err != nil {
+			return "", errBibtexEOF
+		}
+		switch {
+		case r == '{':
+			part, err := br.readBalanced('}')
+			if err != nil {
+				return "", err
+			}
+			sb.WriteString(part)
+		case r == '"':
+			part, err := br.readQuoted()
+			if err != nil {
+				return "", err
+			}
+			sb.WriteString(part)
+		default:
+			br.unread()
+			name, err := br.readIdentifier()
+			if err != nil {
+				return "", errBibtexEOF
+			}
+			if name == "" {
+				return "", fmt.Errorf("unexpected character %q in value", r)
+			}
+			if _, isNumber := parseNumber(name); isNumber {
+				sb.WriteString(name)
+			} else if macro, ok := br.macros[strings.ToLower(name)]; ok {
+				sb.WriteString(macro)
+			} else {
+				return "", fmt.Errorf("undefined string %q", name)
+			}
+		}
+
+		next, err := br.skipSpace()
+		if err != nil {
+			return "", errBibtexEOF
+		}
+		if next != '#' {
+			br.unread()
+			return sb.String(), nil
+		}
+	}
+}
+
+func parseNumber(value string) (string, bool) {
+	for _, r := range value {
+		if !unicode.IsDigit(r) {
+			return "", false
+		}
+	}
+	return value, value != ""
+}
+
+// readFields reads "name = value" pairs up to the closing delimiter.
+func (br *BibtexReader) readFields(closing rune) ([]string, map[string]string, error) {
+	names := []string{}
+	fields := map[string]string{}
+	for {
+		r, err := br.skipSpace()
+		if err != nil {
+			return nil, nil, errBibtexEOF
+		}
+		if r == closing {
+			return names, fields, nil
+		}
+		if r == ',' {
+			continue
+		}
+		br.unread()
+
+		name, err := br.readIdentifier()
+		if err != nil {
+			return nil, nil, errBibtexEOF
+		}
+		if name == "" {
+			return nil, nil, fmt.Errorf("unexpected character %q, expected a field name", r)
+		}
+		name = strings.ToLower(name)
+
+		r, err = br.skipSpace()
+		if err != nil {
+			return nil, nil, errBibtexEOF
+		}
+		if r != '=' {
+			return nil, nil, fmt.Errorf("expected '=' after field %s", name)
+		}
+
+		value, err := br.readValue()
+		if err != nil {
+			return nil, nil, fmt.Errorf("field %s: %s", name, err.Error())
+		}
+		if _, exists := fields[name]; !exists {
+			names = append(names, name)
+		}
+		fields[name] = value
+	}
+}
+
+func (br *BibtexReader) Next() (*Record, error) {
+	for {
+		r, err := br.read()
+		if err == io.EOF {
+			return nil, io.EOF
+		}
+		if err != nil {
+			return nil, err
+		}
+		if r != '@' {
+			continue
+		}
+
+		startLine := br.line
+		entryType, err := br.readIdentifier()
+		if err != nil {
+			return nil, &ParseError{Line: startLine, Message: errBibtexEOF.Error()}
+		}
+		entryType = strings.ToLower(entryType)
+
+		open, err := br.skipSpace()
+		if err != nil {
+			return nil, &ParseError{Line: startLine, Message: errBibtexEOF.Error()}
+		}
+		if open != '{' && open != '(' {
+			return nil, &ParseError{Line: startLine, Message: fmt.Sprintf("expected '{' after @%s", entryType)}
+		}
+		closing := '}'
+		if open == '(' {
+			closing = ')'
+		}
+
+		switch entryType {
+		case "comment", "preamble":
+			if _, err := br.readBalanced(closing); err != nil {
+				return nil, &ParseError{Line: startLine, Message: err.Error()}
+			}
+			continue
+		case "string":
+			_, fields, err := br.readFields(closing)
+			if err != nil {
+				return nil, &ParseError{Line: startLine, Message: err.Error()}
+			}
+			for name, value := range fields {
+				br.macros[name] = value
+			}
+			continue
+		}
+
+		key, err := br.readKey(closing)
+		if err != nil {
+			return nil, &ParseError{Line: startLine, Message: err.Error()}
+		}
+		names, fields, err := br.readFields(closing)
+		if err != nil {
+			return nil, &ParseError{Line: startLine, Key: key, Message: err.Error()}
+		}
+		return buildBibtexRecord(startLine, entryType, key, names, fields)
+	}
+}
+
+func (br *BibtexReader) readKey(closing rune) (string, error) {
+	var sb strings.Builder
+	for {
+		r, err := br.read()
+		if err != nil {
+			return "", errBibtexEOF
+		}
+		if r == ',' {
+			return strings.TrimSpace(sb.String()), nil
+		}
+		if r == closing {
+			br.unread()
+			return strings.TrimSpace(sb.String()), nil
+		}
+		if r == '\n' {
+			return "", errors.New("entry key is not followed by ','")
+		}
+		sb.WriteRune(r)
+	}
+}
+
+func buildBibtexRecord(line int, entryType string, key string, names []string, fields map[string]string) (*Record, error) {
+	record := &Record{
+		Line:     line,
+		Key:      key,
+		Type:     typeFromBibtex(entryType),
+		Authors:  []string{},
+		Keywords: []string{},
+		Raw:      map[string]any{},
+	}
+	if typeToBibtex(record.Type) != entryType {
+		record.Raw[bibtexTypeKey] = entryType
+	}
+
+	for _, name := range names {
+		value := fields[name]
+		switch name {
+		case "title":
+			record.Title = CleanLatex(value)
+			record.Raw[bibtexOriginalPrefix+"title"] = value
+		case "abstract":
+			record.Abstract = CleanLatex(value)
+			record.Raw[bibtexOriginalPrefix+"abstract"] = value
+		case "author":
+			record.Authors = cleanBibtexAuthors(value)
+			record.Raw[bibtexOriginalPrefix+"author"] = value
+		case "year":
+			record.Year = ParseYear(value)
+		case "journal", "journaltitle":
+			record.Journal = CleanLatex(value)
+			record.Raw[bibtexOriginalPrefix+"journal"] = value
+		case "doi":
+			record.Doi = NormalizeDoi(value)
+		case "keywords":
+			record.Keywords = SplitKeywords(CleanLatex(value))
+			record.Raw[bibtexOriginalPrefix+"keywords"] = value
+		case "pmid":
+			record.Pmid = strings.TrimSpace(value)
+		default:
+			record.Raw[name] = value
+		}
+	}
+
+	// booktitle is the container for chapters and proceedings papers
+	if booktitle, ok := fields["booktitle"]; ok && record.Journal == "" {
+		record.Journal = CleanLatex(booktitle)
+		record.Raw[bibtexOriginalPrefix+"journal"] = booktitle
+		delete(record.Raw, "booktitle")
+	}
+	if record.Year == 0 {
+		record.Year = ParseYear(fields["date"])
+	}
+
+	if record.Title == "" {
+		return nil, &ParseError{Line: line, Key: key, Message: "entry has no title"}
+	}
+	return record, nil
+}
+
+func cleanBibtexAuthors(value string) []string {
+	authors := []string{}
+	for _, author := range splitBibtexAuthors(value) {
+		authors = append(authors, CleanLatex(author))
+	}
+	return authors
+}
+
+// splitBibtexAuthors splits on " and " outside of braces.
+func splitBibtexAuthors(value string) []string {
+	authors := []string{}
+	depth := 0
+	start := 0
+	lower := strings.ToLower(value)
+	for i := 0; i < len(value); i++ {
+		switch value[i] {
+		case '\\':
+			i++
+			continue
+		case '{':
+			depth++
+		case '}':
+			depth--
+		}
+		if depth == 0 && strings.HasPrefix(lower[i:], " and ") {
+			authors = append(authors, strings.TrimSpace(value[start:i]))
+			start = i + len(" and ")
+			i = start - 1
+		}
+	}
+	if last := strings.TrimSpace(value[start:]); last != "" {
+		authors = append(authors, last)
+	}
+	return authors
+}
+
+// SplitKeywords splits a keyword list separated by commas or semicolons.
+func SplitKeywords(value string) []string {
+	keywords := []string{}
+	for _, keyword := range strings.FieldsFunc(value, func(r rune) bool { return r == ',' || r == ';' }) {
+		if keyword = strings.TrimSpace(keyword); keyword != "" {
+			keywords = append(keywords, keyword)
+		}
+	}
+	return keywords
+}
+
+var latexAccents = map[string]map[rune]string{
+	"'":  {'a': "á", 'e': "é", 'i': "í", 'o': "ó", 'u': "ú", 'A': "Á", 'E': "É", 'I': "Í", 'O': "Ó", 'U': "Ú", 'c': "ć", 'n': "ń", 's': "ś", 'y': "ý"},
+	"`":  {'a': "à", 'e': "è", 'i': "ì", 'o': "ò", 'u': "ù", 'A': "À", 'E': "È", 'I': "Ì", 'O': "Ò", 'U': "Ù"},
+	"^":  {'a': "â", 'e': "ê", 'i': "î", 'o': "ô", 'u': "û", 'A': "Â", 'E': "Ê", 'I': "Î", 'O': "Ô", 'U': "Û"},
+	"\"": {'a': "ä", 'e': "ë", 'i': "ï", 'o': "ö", 'u': "ü", 'A': "Ä", 'E': "Ë", 'I': "Ï", 'O': "Ö", 'U': "Ü", 'y': "ÿ"},
+	"~":  {'a': "ã", 'o': "õ", 'n': "ñ", 'A': "Ã", 'O': "Õ", 'N': "Ñ"},
+	"c":  {'c': "ç", 'C': "Ç", 's': "ş", 'S': "Ş"},
+}
+
+// latexSpecials escapes the characters of plain text that LaTeX reads as
+// commands or groups, the reverse of latexEscapes and CleanLatex.
+var latexSpecials = strings.NewReplacer(`\`, `\textbackslash{}`, "~", `\textasciitilde{}`, "^", `\textasciicircum{}`, "&", `\&`, "%", `\%`, "_", `\_`, "$", `\$`, "#", `\#`, "{", `\{`, "}", `\}`)
+
+var latexEscapes = strings.NewReplacer(`\&`, "&", `\%`, "%", `\_`, "_", `\$`, "$", `\#`, "#", `--`, "–", `\textendash`, "–",
+	`\textbackslash`, `\`, `\textasciitilde`, "~", `\textasciicircum`, "^")
+
+// CleanLatex turns a BibTeX value into plain text: accent commands are
+// resolved, escapes and grouping braces are removed and whitespace collapsed.
+func CleanLatex(value string) string {
+	var sb strings.Builder
+	runes := []rune(value)
+	for i := 0; i < len(runes); i++ {
+		r := runes[i]
+		if r == '\\' && i+1 < len(runes) {
+			if runes[i+1] == '{' || runes[i+1] == '}' {
+				sb.WriteRune(runes[i+1])
+				i++
+				continue
+			}
+			command := string(runes[i+1])
+			if accents, ok := latexAccents[command]; ok {
+				j := i + 2
+				if j < len(runes) && runes[j] == '{' {
+					j++
+				}
+				if j < len(runes) {
+					if accented, ok := accents[runes[j]]; ok {
+						sb.WriteString(accented)
+						i = j
+						if i+1 < len(runes) && runes[i+1] == '}' {
+							i++
+						}
+						continue
+					}
+				}
+			}
+		}
+		if r == '{' || r == '}' {
+			continue
+		}
+		sb.WriteRune(r)
+	}
+	return strings.Join(strings.Fields(latexEscapes.Replace(sb.String())), " ")
+}
+
+// WriteBibtex writes records as a BibTeX database. Text fields imported from
+// BibTeX are written back with their original value while it still reads as
+// the text; other text is written with its LaTeX specials escaped. Raw string
+// fields are written back as they were imported.
+func WriteBibtex(w io.Writer, records []Record) error {
+	keys := newKeyGenerator()
+	for _, record := range records {
+		entryType := typeToBibtex(record.Type)
+		if original, ok := record.Raw[bibtexTypeKey].(string); ok {
+			entryType = original
+		}
+
+		original := func(field string, text string, clean func(string) string) string {
+			if value, ok := record.Raw[bibtexOriginalPrefix+field].(string); ok && clean(value) == text {
+				return value
+			}
+			return latexSpecials.Replace(text)
+		}
+		cleanAuthors := func(value string) string {
+			return strings.Join(cleanBibtexAuthors(value), " and ")
+		}
+		cleanKeywords := func(value string) string {
+			return strings.Join(SplitKeywords(CleanLatex(value)), ", ")
+		}
+
+		fields := [][2]string{}
+		if len(record.Authors) > 0 {
+			fields = append(fields, [2]string{"author", original("author", strings.Join(record.Authors, " and "), cleanAuthors)})
+		}
+		fields = append(fields, [2]string{"title", original("title", record.Title, CleanLatex)})
+		if record.Journal != "" {
+			container := "journal"
+			if entryType == "inproceedings" || entryType == "incollection" || entryType == "inbook" {
+				container = "booktitle"
+			}
+			fields = append(fields, [2]string{container, original("journal", record.Journal, CleanLatex)})
+		}
+		if record.Year != 0 {
+			fields = append(fields, [2]string{"year", fmt.Sprint(record.Year)})
+		}
+		if record.Doi != "" {
+			fields = append(fields, [2]string{"doi", record.Doi})
+		}
+		if record.Abstract != "" {
+			fields = append(fields, [2]string{"abstract", original("abstract", record.Abstract, CleanLatex)})
+		}
+		if len(record.Keywords) > 0 {
+			fields = append(fields, [2]string{"keywords", original("keywords", strings.Join(record.Keywords, ", "), cleanKeywords)})
+		}
+		if record.Pmid != "" {
+			fields = append(fields, [2]string{"pmid", record.Pmid})
+		}
+
+		rawNames := make([]string, 0, len(record.Raw))
+		for name := range record.Raw {
+			rawNames = append(rawNames, name)
+		}
+		sort.Strings(rawNames)
+		for _, name := range rawNames {
+			value, ok := record.Raw[name].(string)
+			if !ok || strings.HasPrefix(name, bibtexOriginalPrefix) {
+				continue
+			}
+			fields = append(fields, [2]string{name, value})
+		}
+
+		if _, err := fmt.Fprintf(w, "@%s{%s,\n", entryType, keys.next(record)); err != nil {
+			return err
+		}
+		for i, field := range fields {
+			separator := ","
+			if i == len(fields)-1 {
+				separator = ""
+			}
+			if _, err := fmt.Fprintf(w, "  %s = {%s}%s\n", field[0], bibtexEscape(field[1]), separator); err != nil {
+				return err
+			}
+		}
+		if _, err := fmt.Fprint(w, "}\n\n"); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// bibtexEscape keeps balanced values untouched and escapes the braces of
+// unbalanced ones so the entry stays parseable. Braces already escaped are
+// left alone.
+func bibtexEscape(value string) string {
+	depth := 0
+	escaped := false
+	for _, r := range value {
+		switch {
+		case escaped:
+			escaped = false
+		case r == '\\':
+			escaped = true
+		case r == '{':
+			depth++
+		case r == '}':
+			depth--
+		}
+		if depth < 0 {
+			break
+		}
+	}
+	if depth == 0 {
+		return value
+	}
+
+	var sb strings.Builder
+	escaped = false
+	for _, r := range value {
+		if (r == '{' || r == '}') && !escaped {
+			sb.WriteRune('\\')
+		}
+		escaped = r == '\\' && !escaped
+		sb.WriteRune(r)
+	}
+	return sb.String()
+}
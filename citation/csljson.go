@@ -0,0 +1,242 @@
+package citation
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// cslKnownFields are mapped to reference columns; everything else is kept in
+// the raw metadata. author and issued are kept as well because the columns
+// only hold part of them (name particles, month and day).
+var cslKnownFields = map[string]bool{
+	"id": true, "type": true, "title": true, "abstract": true,
+	"container-title": true, "DOI": true, "keyword": true, "PMID": true,
+}
+
+type cslName struct {
+	Family  string `json:"family,omitempty"`
+	Given   string `json:"given,omitempty"`
+	Literal string `json:"literal,omitempty"`
+}
+
+type cslDate struct {
+	DateParts [][]any `json:"date-parts,omitempty"`
+	Raw       string  `json:"raw,omitempty"`
+	Literal   string  `json:"literal,omitempty"`
+}
+
+// CslJsonReader reads a CSL-JSON array (Zotero "CSL JSON" export) one item at
+// a time. Entries have no line numbers, so errors carry the item id instead.
+type CslJsonReader struct {
+	decoder *json.Decoder
+	started bool
+	done    bool
+	index   int
+}
+
+func NewCslJsonReader(r io.Reader) *CslJsonReader {
+	return &CslJsonReader{decoder: json.NewDecoder(r)}
+}
+
+func (cr *CslJsonReader) Next() (*Record, error) {
+	if cr.done {
+		return nil, io.EOF
+	}
+	if !cr.started {
+		cr.started = true
+		token, err := cr.decoder.Token()
+		if err == io.EOF {
+			cr.done = true
+			return nil, io.EOF
+		}
+		if delim, ok := token.(json.Delim); err != nil || !ok || delim != '[' {
+			cr.done = true
+			return nil, &ParseError{Message: "expected a JSON array of CSL items"}
+		}
+	}
+
+	if !cr.decoder.More() {
+		cr.done = true
+		return nil, io.EOF
+	}
+
+	cr.index++
+	fields := map[string]json.RawMessage{}
+	if err := cr.decoder.Decode(&fields); err != nil {
+		// the decoder cannot recover from malformed JSON
+		cr.done = true
+		return nil, &ParseError{Key: fmt.Sprintf("#%d", cr.index), Message: err.Error()}
+	}
+
+	key := fmt.Sprintf("#%d", cr.index)
+	var id any
+	if err := json.Unmarshal(fields["id"], &id); err == nil && id != nil {
+		key = fmt.Sprint(id)
+	}
+
+	record, err := buildCslRecord(fields)
+	if err != nil {
+		return nil, &ParseError{Key: key, Message: err.Error()}
+	}
+	record.Key = key
+	if record.Title == "" {
+		return nil, &ParseError{Key: key, Message: "entry has no title"}
+	}
+	return record, nil
+}
+
+func buildCslRecord(fields map[string]json.RawMessage) (*Record, error) {
+	record := &Record{
+		Type:     defaultType,
+		Authors:  []string{},
+		Keywords: []string{},
+		Raw:      map[string]any{},
+	}
+
+	for name, value := range fields {
+		if cslKnownFields[name] {
+			continue
+		}
+		var raw any
+		if err := json.Unmarshal(value, &raw); err != nil {
+			return nil, err
+		}
+		record.Raw[name] = raw
+	}
+
+	strs := map[string]*string{
+		"type":            &record.Type,
+		"title":           &record.Title,
+		"abstract":        &record.Abstract,
+		"container-title": &record.Journal,
+		"DOI":             &record.Doi,
+		"PMID":            &record.Pmid,
+	}
+	for name, target := range strs {
+		if value, ok := fields[name]; ok {
+			if err := json.Unmarshal(value, target); err != nil {
+				return nil, fmt.Errorf("%s must be a string", name)
+			}
+		}
+	}
+	record.Doi = NormalizeDoi(record.Doi)
+
+	if value, ok := fields["author"]; ok {
+		var names []cslName
+		if err := json.Unmarshal(value, &names); err != nil {
+			return nil, fmt.Errorf("author must be a list of names")
+		}
+		for _, name := range names {
+			if author := name.String(); author != "" {
+				record.Authors = append(record.Authors, author)
+			}
+		}
+	}
+
+	if value, ok := fields["issued"]; ok {
+		var issued cslDate
+		if err := json.Unmarshal(value, &issued); err != nil {
+			return nil, fmt.Errorf("issued must be a date")
+		}
+		record.Year = issued.year()
+	}
+
+	if value, ok := fields["keyword"]; ok {
+		var keyword string
+		if err := json.Unmarshal(value, &keyword); err != nil {
+			return nil, fmt.Errorf("keyword must be a string")
+		}
+		record.Keywords = SplitKeywords(keyword)
+	}
+
+	return record, nil
+}
+
+func (n cslName) String() string {
+	if n.Literal != "" {
+		return n.Literal
+	}
+	if n.Given == "" {
+		return n.Family
+	}
+	return n.Family + ", " + n.Given
+}
+
+func nameFromAuthor(author string) cslName {
+	family, given, found := strings.Cut(author, ",")
+	if !found {
+		return cslName{Literal: author}
+	}
+	return cslName{Family: strings.TrimSpace(family), Given: strings.TrimSpace(given)}
+}
+
+func (d cslDate) year() int {
+	if len(d.DateParts) > 0 && len(d.DateParts[0]) > 0 {
+		return ParseYear(fmt.Sprint(d.DateParts[0][0]))
+	}
+	if d.Raw != "" {
+		return ParseYear(d.Raw)
+	}
+	return ParseYear(d.Literal)
+}
+
+// WriteCslJson writes records as a CSL-JSON array, including the raw fields
+// kept from the original import.
+func WriteCslJson(w io.Writer, records []Record) error {
+	keys := newKeyGenerator()
+	if _, err := io.WriteString(w, "[\n"); err != nil {
+		return err
+	}
+	for i, record := range records {
+		item := map[string]any{}
+		for name, value := range record.Raw {
+			if !strings.HasPrefix(name, bibtexOriginalPrefix) {
+				item[name] = value
+			}
+		}
+		item["id"] = keys.next(record)
+		item["type"] = record.Type
+		item["title"] = record.Title
+		if record.Abstract != "" {
+			item["abstract"] = record.Abstract
+		}
+		if _, ok := item["author"]; !ok && len(record.Authors) > 0 {
+			names := make([]cslName, 0, len(record.Authors))
+			for _, author := range record.Authors {
+				names = append(names, nameFromAuthor(author))
+			}
+			item["author"] = names
+		}
+		if _, ok := item["issued"]; !ok && record.Year != 0 {
+			item["issued"] = cslDate{DateParts: [][]any{{record.Year}}}
+		}
+		if record.Journal != "" {
+			item["container-title"] = record.Journal
+		}
+		if record.Doi != "" {
+			item["DOI"] = record.Doi
+		}
+		if len(record.Keywords) > 0 {
+			item["keyword"] = strings.Join(record.Keywords, ", ")
+		}
+		if record.Pmid != "" {
+			item["PMID"] = record.Pmid
+		}
+
+		data, err := json.MarshalIndent(item, "  ", "  ")
+		if err != nil {
+			return err
+		}
+		separator := ",\n"
+		if i == len(records)-1 {
+			separator = "\n"
+		}
+		if _, err := fmt.Fprintf(w, "  %s%s", data, separator); err != nil {
+			return err
+		}
+	}
+	_, err := io.WriteString(w, "]\n")
+	return err
+}
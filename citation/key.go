@@ -0,0 +1,66 @@
+package citation
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// keyGenerator hands out citation keys for an export, reusing imported keys
+// and generating "smith2019effect" style keys for records without one.
+type keyGenerator struct {
+	used map[string]bool
+}
+
+func newKeyGenerator() *keyGenerator {
+	return &keyGenerator{used: map[string]bool{}}
+}
+
+func (kg *keyGenerator) next(record Record) string {
+	base := record.Key
+	if base == "" {
+		base = generateKey(record)
+	}
+	key := base
+	for suffix := 'a'; kg.used[key]; suffix++ {
+		key = fmt.Sprintf("%s%c", base, suffix)
+		if suffix == 'z' {
+			base += "z"
+			suffix = 'a' - 1
+		}
+	}
+	kg.used[key] = true
+	return key
+}
+
+func generateKey(record Record) string {
+	author := "anonymous"
+	if len(record.Authors) > 0 {
+		author = strings.Split(record.Authors[0], ",")[0]
+		if fields := strings.Fields(author); len(fields) > 0 && !strings.Contains(record.Authors[0], ",") {
+			author = fields[len(fields)-1]
+		}
+	}
+	word := ""
+	for _, candidate := range strings.Fields(record.Title) {
+		if len(candidate) > 3 {
+			word = candidate
+			break
+		}
+	}
+	year := ""
+	if record.Year != 0 {
+		year = fmt.Sprint(record.Year)
+	}
+	return keyPart(author) + year + keyPart(word)
+}
+
+func keyPart(value string) string {
+	var sb strings.Builder
+	for _, r := range strings.ToLower(value) {
+		if r < unicode.MaxASCII && (unicode.IsLetter(r) || unicode.IsDigit(r)) {
+			sb.WriteRune(r)
+		}
+	}
+	return sb.String()
+}
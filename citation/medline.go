@@ -0,0 +1,143 @@
+package citation
+
+import (
+	"bufio"
+	"io"
+	"strings"
+)
+
+// MedlineReader reads PubMed's MEDLINE text export (.nbib / .txt). Each
+// record starts with a PMID tag; values wrapped over several lines are
+// indented by six spaces.
+type MedlineReader struct {
+	scanner *bufio.Scanner
+	line    int
+	pending *string
+}
+
+func NewMedlineReader(r io.Reader) *MedlineReader {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxLineSize)
+	return &MedlineReader{scanner: scanner}
+}
+
+func (mr *MedlineReader) readLine() (string, bool) {
+	if mr.pending != nil {
+		line := *mr.pending
+		mr.pending = nil
+		return line, true
+	}
+	if !mr.scanner.Scan() {
+		return "", false
+	}
+	mr.line++
+	line := strings.TrimRight(mr.scanner.Text(), "\r")
+	if mr.line == 1 {
+		line = strings.TrimPrefix(line, "\ufeff")
+	}
+	return line, true
+}
+
+// splitMedlineLine splits "PMID- 12345" into its tag and value.
+func splitMedlineLine(line string) (string, string, bool) {
+	if len(line) < 5 || line[4] != '-' {
+		return "", "", false
+	}
+	tag := strings.TrimSpace(line[:4])
+	if tag == "" || strings.ContainsAny(tag, " -") {
+		return "", "", false
+	}
+	return tag, strings.TrimSpace(line[5:]), true
+}
+
+func (mr *MedlineReader) Next() (*Record, error) {
+	var fields map[string][]string
+	var lastTag string
+	startLine := 0
+	garbageLine := 0
+
+	for {
+		line, ok := mr.readLine()
+		if !ok {
+			break
+		}
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+
+		tag, value, isTag := splitMedlineLine(line)
+		if fields == nil {
+			if !isTag || tag != "PMID" {
+				if garbageLine == 0 {
+					garbageLine = mr.line
+				}
+				continue
+			}
+			if garbageLine != 0 {
+				mr.pending = &line
+				return nil, &ParseError{Line: garbageLine, Message: "content outside of a record, expected PMID tag"}
+			}
+			startLine = mr.line
+			fields = map[string][]string{tag: {value}}
+			lastTag = tag
+			continue
+		}
+
+		if !isTag {
+			if values := fields[lastTag]; len(values) > 0 {
+				values[len(values)-1] += " " + strings.TrimSpace(line)
+			}
+			continue
+		}
+		if tag == "PMID" {
+			mr.pending = &line
+			return buildMedlineRecord(startLine, fields)
+		}
+		fields[tag] = append(fields[tag], value)
+		lastTag = tag
+	}
+
+	if err := mr.scanner.Err(); err != nil {
+		return nil, err
+	}
+	if fields != nil {
+		return buildMedlineRecord(startLine, fields)
+	}
+	if garbageLine != 0 {
+		return nil, &ParseError{Line: garbageLine, Message: "content outside of a record, expected PMID tag"}
+	}
+	return nil, io.EOF
+}
+
+func buildMedlineRecord(line int, fields map[string][]string) (*Record, error) {
+	record := &Record{
+		Line:             line,
+		Type:             defaultType,
+		Pmid:             first(fields, "PMID"),
+		Title:            first(fields, "TI", "BTI"),
+		Abstract:         first(fields, "AB"),
+		Journal:          first(fields, "JT", "TA"),
+		Year:             ParseYear(first(fields, "DP", "DEP")),
+		Authors:          all(fields, "FAU"),
+		Keywords:         all(fields, "OT"),
+		MeshTerms:        all(fields, "MH"),
+		PublicationTypes: all(fields, "PT"),
+	}
+	if len(record.Authors) == 0 {
+		record.Authors = all(fields, "AU")
+	}
+	for _, id := range append(fields["LID"], fields["AID"]...) {
+		if strings.HasSuffix(id, "[doi]") {
+			record.Doi = NormalizeDoi(strings.TrimSuffix(id, "[doi]"))
+			break
+		}
+	}
+	if _, isBook := fields["BTI"]; isBook {
+		record.Type = "book"
+	}
+
+	if record.Title == "" {
+		return nil, &ParseError{Line: line, Key: record.Pmid, Message: "record has no title"}
+	}
+	return record, nil
+}
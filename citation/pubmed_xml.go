@@ -0,0 +1,198 @@
+package citation
+
+import (
+	"encoding/xml"
+	"io"
+	"regexp"
+	"strings"
+)
+
+type pubmedText struct {
+	Inner string `xml:",innerxml"`
+	Label string `xml:"Label,attr"`
+}
+
+var xmlTag = regexp.MustCompile(`<[^>]+>`)
+
+// String drops inline markup such as <i> or <sup> from titles and abstracts.
+func (t pubmedText) String() string {
+	text := xmlTag.ReplaceAllString(t.Inner, "")
+	replacer := strings.NewReplacer("&lt;", "<", "&gt;", ">", "&quot;", `"`, "&apos;", "'", "&amp;", "&")
+	return strings.Join(strings.Fields(replacer.Replace(text)), " ")
+}
+
+type pubmedName struct {
+	Major string `xml:"MajorTopicYN,attr"`
+	Value string `xml:",chardata"`
+}
+
+type pubmedArticle struct {
+	MedlineCitation struct {
+		Pmid    string `xml:"PMID"`
+		Article struct {
+			Journal struct {
+				Title        string `xml:"Title"`
+				JournalIssue struct {
+					PubDate struct {
+						Year        string `xml:"Year"`
+						MedlineDate string `xml:"MedlineDate"`
+					} `xml:"PubDate"`
+				} `xml:"JournalIssue"`
+			} `xml:"Journal"`
+			ArticleTitle pubmedText   `xml:"ArticleTitle"`
+			Abstract     []pubmedText `xml:"Abstract>AbstractText"`
+			Authors      []struct {
+				LastName       string `xml:"LastName"`
+				ForeName       string `xml:"ForeName"`
+				CollectiveName string `xml:"CollectiveName"`
+			} `xml:"AuthorList>Author"`
+			ELocationIds []struct {
+				Type  string `xml:"EIdType,attr"`
+				Value string `xml:",chardata"`
+			} `xml:"ELocationID"`
+			PublicationTypes []string `xml:"PublicationTypeList>PublicationType"`
+		} `xml:"Article"`
+		MeshHeadings []struct {
+			Descriptor pubmedName   `xml:"DescriptorName"`
+			Qualifiers []pubmedName `xml:"QualifierName"`
+		} `xml:"MeshHeadingList>MeshHeading"`
+		Keywords []string `xml:"KeywordList>Keyword"`
+	} `xml:"MedlineCitation"`
+	PubmedData struct {
+		ArticleIds []struct {
+			Type  string `xml:"IdType,attr"`
+			Value string `xml:",chardata"`
+		} `xml:"ArticleIdList>ArticleId"`
+	} `xml:"PubmedData"`
+}
+
+// PubmedXmlReader streams PubmedArticle elements out of a PubmedArticleSet
+// without loading the whole document.
+type PubmedXmlReader struct {
+	decoder *xml.Decoder
+	done    bool
+}
+
+func NewPubmedXmlReader(r io.Reader) *PubmedXmlReader {
+	decoder := xml.NewDecoder(r)
+	decoder.Strict = false
+	return &PubmedXmlReader{decoder: decoder}
+}
+
+func (pr *PubmedXmlReader) Next() (*Record, error) {
+	if pr.done {
+		return nil, io.EOF
+	}
+	for {
+		token, err := pr.decoder.Token()
+		if err == io.EOF {
+			pr.done = true
+			return nil, io.EOF
+		}
+		if err != nil {
+			line, _ := pr.decoder.InputPos()
+			pr.done = true
+			return nil, &ParseError{Line: line, Message: err.Error()}
+		}
+
+		start, ok := token.(xml.StartElement)
+		if !ok || start.Name.Local != "PubmedArticle" {
+			continue
+		}
+
+		line, _ := pr.decoder.InputPos()
+		article := pubmedArticle{}
+		if err := pr.decoder.DecodeElement(&article, &start); err != nil {
+			pr.done = true
+			return nil, &ParseError{Line: line, Message: err.Error()}
+		}
+		return buildPubmedXmlRecord(line, article)
+	}
+}
+
+func buildPubmedXmlRecord(line int, article pubmedArticle) (*Record, error) {
+	citation := article.MedlineCitation
+	record := &Record{
+		Line:             line,
+		Type:             defaultType,
+		Pmid:             strings.TrimSpace(citation.Pmid),
+		Title:            citation.Article.ArticleTitle.String(),
+		Journal:          strings.TrimSpace(citation.Article.Journal.Title),
+		Authors:          []string{},
+		Keywords:         []string{},
+		MeshTerms:        []string{},
+		PublicationTypes: []string{},
+	}
+
+	pubDate := citation.Article.Journal.JournalIssue.PubDate
+	record.Year = ParseYear(pubDate.Year)
+	if record.Year == 0 {
+		record.Year = ParseYear(pubDate.MedlineDate)
+	}
+
+	sections := []string{}
+	for _, section := range citation.Article.Abstract {
+		text := section.String()
+		if section.Label != "" {
+			text = section.Label + ": " + text
+		}
+		sections = append(sections, text)
+	}
+	record.Abstract = strings.Join(sections, " ")
+
+	for _, author := range citation.Article.Authors {
+		switch {
+		case author.CollectiveName != "":
+			record.Authors = append(record.Authors, author.CollectiveName)
+		case author.ForeName != "":
+			record.Authors = append(record.Authors, author.LastName+", "+author.ForeName)
+		case author.LastName != "":
+			record.Authors = append(record.Authors, author.LastName)
+		}
+	}
+
+	for _, id := range citation.Article.ELocationIds {
+		if id.Type == "doi" {
+			record.Doi = NormalizeDoi(id.Value)
+		}
+	}
+	if record.Doi == "" {
+		for _, id := range article.PubmedData.ArticleIds {
+			if id.Type == "doi" {
+				record.Doi = NormalizeDoi(id.Value)
+			}
+		}
+	}
+
+	for _, heading := range citation.MeshHeadings {
+		record.MeshTerms = append(record.MeshTerms, meshTerm(heading.Descriptor, heading.Qualifiers))
+	}
+	for _, publicationType := range citation.Article.PublicationTypes {
+		record.PublicationTypes = append(record.PublicationTypes, strings.TrimSpace(publicationType))
+	}
+	for _, keyword := range citation.Keywords {
+		record.Keywords = append(record.Keywords, strings.TrimSpace(keyword))
+	}
+
+	if record.Title == "" {
+		return nil, &ParseError{Line: line, Key: record.Pmid, Message: "article has no title"}
+	}
+	return record, nil
+}
+
+// meshTerm formats a heading the way the MEDLINE export does, so both PubMed
+// formats store identical terms.
+func meshTerm(descriptor pubmedName, qualifiers []pubmedName) string {
+	term := strings.TrimSpace(descriptor.Value)
+	if len(qualifiers) == 0 && descriptor.Major == "Y" {
+		return "*" + term
+	}
+	for _, qualifier := range qualifiers {
+		term += "/"
+		if qualifier.Major == "Y" {
+			term += "*"
+		}
+		term += strings.TrimSpace(qualifier.Value)
+	}
+	return term
+}
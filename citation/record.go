@@ -0,0 +1,86 @@
+package citation
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Record is a bibliographic entry as read from an export file, before it is
+// attached to a review.
+type Record struct {
+	Line     int
+	Key      string
+	Type     string
+	Title    string
+	Abstract string
+	Authors  []string
+	Year     int
+	Journal  string
+	Doi      string
+	Keywords []string
+	Pmid     string
+	// MeshTerms are MeSH headings in MEDLINE notation, e.g.
+	// "Lung Neoplasms/*drug therapy" (* marks a major topic).
+	MeshTerms        []string
+	PublicationTypes []string
+	// Raw holds the fields the importer does not map to a column, keyed by
+	// their name in the source format.
+	Raw map[string]any
+}
+
+// Reader streams records out of an export file. Next returns io.EOF once the
+// input is exhausted. A *ParseError means only the current record is broken
+// and the caller may keep reading.
+type Reader interface {
+	Next() (*Record, error)
+}
+
+type ParseError struct {
+	Line    int
+	Key     string
+	Message string
+}
+
+func (e *ParseError) Error() string {
+	if e.Key != "" {
+		return fmt.Sprintf("line %d: entry %s: %s", e.Line, e.Key, e.Message)
+	}
+	return fmt.Sprintf("line %d: %s", e.Line, e.Message)
+}
+
+type Format string
+
+const (
+	FormatRis       Format = "ris"
+	FormatBibtex           = "bibtex"
+	FormatCslJson          = "csljson"
+	FormatMedline          = "medline"
+	FormatPubmedXml        = "pubmedxml"
+)
+
+// NormalizeDoi strips resolver prefixes so DOIs from different databases
+// compare equal.
+func NormalizeDoi(doi string) string {
+	doi = strings.TrimSpace(doi)
+	lower := strings.ToLower(doi)
+	for _, prefix := range []string{"https://doi.org/", "http://doi.org/", "https://dx.doi.org/", "http://dx.doi.org/", "doi:"} {
+		if strings.HasPrefix(lower, prefix) {
+			doi = strings.TrimSpace(doi[len(prefix):])
+			break
+		}
+	}
+	return doi
+}
+
+// ParseYear returns the first four-digit year found in value, or 0.
+func ParseYear(value string) int {
+	for i := 0; i+4 <= len(value); i++ {
+		chunk := value[i : i+4]
+		year, err := strconv.Atoi(chunk)
+		if err == nil && year >= 1000 && year <= 2999 {
+			return year
+		}
+	}
+	return 0
+}
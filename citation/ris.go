@@ -0,0 +1,160 @@
+package citation
+
+import (
+	"bufio"
+	"io"
+	"strings"
+)
+
+const maxLineSize = 1024 * 1024
+
+// RisReader reads RIS exports (PubMed, Scopus, Web of Science) one record at
+// a time, so large files never have to be held in memory.
+type RisReader struct {
+	scanner *bufio.Scanner
+	line    int
+	pending *string
+}
+
+func NewRisReader(r io.Reader) *RisReader {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxLineSize)
+	return &RisReader{scanner: scanner}
+}
+
+func (rr *RisReader) readLine() (string, bool) {
+	if rr.pending != nil {
+		line := *rr.pending
+		rr.pending = nil
+		return line, true
+	}
+	if !rr.scanner.Scan() {
+		return "", false
+	}
+	rr.line++
+	line := strings.TrimRight(rr.scanner.Text(), "\r")
+	if rr.line == 1 {
+		line = strings.TrimPrefix(line, "\ufeff")
+	}
+	return line, true
+}
+
+func (rr *RisReader) unreadLine(line string) {
+	rr.pending = &line
+}
+
+// splitRisLine splits "TI  - Some title" into its tag and value.
+func splitRisLine(line string) (string, string, bool) {
+	if len(line) < 5 || line[2:5] != "  -" {
+		return "", "", false
+	}
+	tag := line[:2]
+	for _, c := range tag {
+		if !(c >= 'A' && c <= 'Z' || c >= '0' && c <= '9') {
+			return "", "", false
+		}
+	}
+	return tag, strings.TrimSpace(line[5:]), true
+}
+
+func (rr *RisReader) Next() (*Record, error) {
+	var record *Record
+	var lastTag string
+	var fields map[string][]string
+	garbageLine := 0
+
+	for {
+		line, ok := rr.readLine()
+		if !ok {
+			break
+		}
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+
+		tag, value, isTag := splitRisLine(line)
+		if record == nil {
+			if !isTag || tag != "TY" {
+				// remember the first stray line and skip to the next record
+				if garbageLine == 0 {
+					garbageLine = rr.line
+				}
+				continue
+			}
+			if garbageLine != 0 {
+				rr.unreadLine(line)
+				return nil, &ParseError{Line: garbageLine, Message: "content outside of a record, expected TY tag"}
+			}
+			record = &Record{Line: rr.line, Type: typeFromRis(value)}
+			fields = map[string][]string{}
+			lastTag = tag
+			continue
+		}
+
+		if !isTag {
+			// continuation of a wrapped value
+			if values := fields[lastTag]; len(values) > 0 {
+				values[len(values)-1] += " " + strings.TrimSpace(line)
+			}
+			continue
+		}
+
+		switch tag {
+		case "ER":
+			return rr.build(record, fields)
+		case "TY":
+			rr.unreadLine(line)
+			return nil, &ParseError{Line: record.Line, Message: "record is missing ER tag"}
+		}
+
+		fields[tag] = append(fields[tag], value)
+		lastTag = tag
+	}
+
+	if err := rr.scanner.Err(); err != nil {
+		return nil, err
+	}
+	if record != nil {
+		return nil, &ParseError{Line: record.Line, Message: "record is missing ER tag"}
+	}
+	if garbageLine != 0 {
+		return nil, &ParseError{Line: garbageLine, Message: "content outside of a record, expected TY tag"}
+	}
+	return nil, io.EOF
+}
+
+func (rr *RisReader) build(record *Record, fields map[string][]string) (*Record, error) {
+	record.Title = first(fields, "TI", "T1", "CT", "BT")
+	record.Abstract = first(fields, "AB", "N2")
+	record.Journal = first(fields, "JO", "JF", "T2", "JA", "J2")
+	record.Doi = NormalizeDoi(first(fields, "DO"))
+	record.Year = ParseYear(first(fields, "PY", "Y1", "DA"))
+	record.Authors = all(fields, "AU", "A1")
+	record.Keywords = all(fields, "KW")
+
+	if record.Title == "" {
+		return nil, &ParseError{Line: record.Line, Message: "record has no title"}
+	}
+	return record, nil
+}
+
+func first(fields map[string][]string, tags ...string) string {
+	for _, tag := range tags {
+		if values := fields[tag]; len(values) > 0 && values[0] != "" {
+			return values[0]
+		}
+	}
+	return ""
+}
+
+func all(fields map[string][]string, tags ...string) []string {
+	values := []string{}
+	for _, tag := range tags {
+		for _, value := range fields[tag] {
+			if value != "" {
+				values = append(values, value)
+			}
+		}
+	}
+	return values
+}
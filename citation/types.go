@@ -0,0 +1,67 @@
+package citation
+
+// Entry types are stored using the CSL vocabulary, which is the richest of
+// the supported formats; the maps below translate to and from it.
+
+const defaultType = "article-journal"
+
+var risTypes = map[string]string{
+	"JOUR":   "article-journal",
+	"JFULL":  "article-journal",
+	"EJOUR":  "article-journal",
+	"ABST":   "article-journal",
+	"BOOK":   "book",
+	"EBOOK":  "book",
+	"CHAP":   "chapter",
+	"ECHAP":  "chapter",
+	"CONF":   "paper-conference",
+	"CPAPER": "paper-conference",
+	"THES":   "thesis",
+	"RPRT":   "report",
+	"GEN":    "document",
+}
+
+var bibtexToCsl = map[string]string{
+	"article":       "article-journal",
+	"book":          "book",
+	"inbook":        "chapter",
+	"incollection":  "chapter",
+	"inproceedings": "paper-conference",
+	"conference":    "paper-conference",
+	"phdthesis":     "thesis",
+	"mastersthesis": "thesis",
+	"techreport":    "report",
+	"misc":          "document",
+}
+
+var cslToBibtex = map[string]string{
+	"article-journal":  "article",
+	"article":          "article",
+	"book":             "book",
+	"chapter":          "incollection",
+	"paper-conference": "inproceedings",
+	"thesis":           "phdthesis",
+	"report":           "techreport",
+	"document":         "misc",
+}
+
+func typeFromRis(ty string) string {
+	if cslType, ok := risTypes[ty]; ok {
+		return cslType
+	}
+	return defaultType
+}
+
+func typeFromBibtex(entryType string) string {
+	if cslType, ok := bibtexToCsl[entryType]; ok {
+		return cslType
+	}
+	return "document"
+}
+
+func typeToBibtex(cslType string) string {
+	if entryType, ok := cslToBibtex[cslType]; ok {
+		return entryType
+	}
+	return "misc"
+}
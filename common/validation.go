@@ -14,6 +14,7 @@ type ErrorResponse struct {
 var errorMessages = map[string]string{
 	"alpha":       "%s must contain only alphabetic characters",
 	"alphanum":    "%s must contain only alphanumeric characters",
+	"datetime":    "%s must be a date in format %s",
 	"email":       "%s must be a valid email address",
 	"eq":          "%s must be equal to %s",
 	"gt":          "%s must be greater than %s",
@@ -25,11 +26,14 @@ var errorMessages = map[string]string{
 	"ipv6":        "%s must be a valid IPv6 address",
 	"isbn10":      "%s must be a valid ISBN-10",
 	"isbn13":      "%s must be a valid ISBN-13",
+	"json":        "%s must be valid JSON",
+	"key":         "%s must start with a letter and contain only lowercase letters, digits and underscores",
 	"lt":          "%s must be less than %s",
 	"lte":         "%s must be less than or equal to %s",
 	"mac":         "%s must be a valid MAC address",
 	"max":         "%s must be at most %s characters long",
 	"min":         "%s must be at least %s characters long",
+	"min_items":   "%s must have at least %s items",
 	"ne":          "%s must not be equal to %s",
 	"numeric":     "%s must be a numeric value",
 	"oneof":       "%s must be one of the following: %s",
@@ -37,6 +41,14 @@ var errorMessages = map[string]string{
 	"unique":      "%s must be unique",
 	"url":         "%s must be a valid URL",
 	"uuid":        "%s must be a valid UUID",
+	"yaml":        "%s must be valid JSON or YAML",
+}
+
+// Message formats the error message of a validation tag for a field, so that
+// checks done outside the validator read like the ones done by it.
+func Message(field string, tag string, param string) ErrorResponse {
+	message := fmt.Sprintf(errorMessages[tag], field, param)
+	return ErrorResponse{Field: field, Error: strings.TrimSuffix(message, "%!(EXTRA string=)")}
 }
 
 func Validate(data interface{}) []ErrorResponse {
@@ -47,14 +59,9 @@ func Validate(data interface{}) []ErrorResponse {
 
 	if errs != nil {
 		for _, err := range errs.(validator.ValidationErrors) {
-			var elem ErrorResponse
-
 			fieldName := err.Field()
-			elem.Field = strings.ToLower(fieldName[:1]) + fieldName[1:]
-			elem.Error = fmt.Sprintf(errorMessages[err.Tag()], elem.Field, err.Param())
-			elem.Error = strings.TrimSuffix(elem.Error, "%!(EXTRA string=)")
-
-			validationErrors = append(validationErrors, elem)
+			field := strings.ToLower(fieldName[:1]) + fieldName[1:]
+			validationErrors = append(validationErrors, Message(field, err.Tag(), err.Param()))
 		}
 	}
 
@@ -0,0 +1 @@
+DROP TABLE review_references;
\ No newline at end of file
@@ -0,0 +1,20 @@
+CREATE TABLE review_references(
+    id UUID,
+    review_id UUID NOT NULL,
+    user_id UUID NOT NULL,
+    title TEXT NOT NULL,
+    abstract TEXT NOT NULL,
+    authors VARCHAR[] NULL,
+    year INTEGER NOT NULL,
+    journal VARCHAR NOT NULL,
+    doi VARCHAR NOT NULL,
+    keywords VARCHAR[] NULL,
+    source_database VARCHAR NOT NULL,
+    created_at TIMESTAMP NOT NULL,
+    updated_at TIMESTAMP NOT NULL,
+    CONSTRAINT review_references_pk PRIMARY KEY (id),
+    CONSTRAINT review_references_fk1 FOREIGN KEY (review_id) REFERENCES reviews(id),
+    CONSTRAINT review_references_fk2 FOREIGN KEY (user_id) REFERENCES users(id)
+);
+
+CREATE INDEX review_references_review_id_idx ON review_references(review_id);
@@ -0,0 +1,4 @@
+ALTER TABLE review_references
+    DROP COLUMN entry_type,
+    DROP COLUMN citation_key,
+    DROP COLUMN raw_metadata;
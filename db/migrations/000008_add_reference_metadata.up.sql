@@ -0,0 +1,4 @@
+ALTER TABLE review_references
+    ADD COLUMN entry_type VARCHAR NOT NULL DEFAULT '',
+    ADD COLUMN citation_key VARCHAR NOT NULL DEFAULT '',
+    ADD COLUMN raw_metadata JSONB NOT NULL DEFAULT '{}';
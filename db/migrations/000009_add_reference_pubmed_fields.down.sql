@@ -0,0 +1,6 @@
+DROP INDEX review_references_pmid_idx;
+
+ALTER TABLE review_references
+    DROP COLUMN pmid,
+    DROP COLUMN mesh_terms,
+    DROP COLUMN publication_types;
@@ -0,0 +1,6 @@
+ALTER TABLE review_references
+    ADD COLUMN pmid VARCHAR NOT NULL DEFAULT '',
+    ADD COLUMN mesh_terms VARCHAR[] NULL,
+    ADD COLUMN publication_types VARCHAR[] NULL;
+
+CREATE INDEX review_references_pmid_idx ON review_references(pmid);
@@ -0,0 +1,7 @@
+DROP TABLE duplicate_cluster_members;
+DROP TABLE duplicate_clusters;
+
+ALTER TABLE review_references
+    DROP CONSTRAINT review_references_fk3,
+    DROP COLUMN duplicate_of,
+    DROP COLUMN source_databases;
@@ -0,0 +1,31 @@
+ALTER TABLE review_references
+    ADD COLUMN duplicate_of UUID NULL,
+    ADD COLUMN source_databases VARCHAR[] NULL,
+    ADD CONSTRAINT review_references_fk3 FOREIGN KEY (duplicate_of) REFERENCES review_references(id);
+
+UPDATE review_references SET source_databases = ARRAY[source_database];
+
+CREATE TABLE duplicate_clusters(
+    id UUID,
+    review_id UUID NOT NULL,
+    primary_id UUID NOT NULL,
+    match_type VARCHAR NOT NULL,
+    score DOUBLE PRECISION NOT NULL,
+    status VARCHAR NOT NULL,
+    decided_by UUID NULL,
+    decided_at TIMESTAMP NULL,
+    created_at TIMESTAMP NOT NULL,
+    updated_at TIMESTAMP NOT NULL,
+    CONSTRAINT duplicate_clusters_pk PRIMARY KEY (id),
+    CONSTRAINT duplicate_clusters_fk1 FOREIGN KEY (review_id) REFERENCES reviews(id),
+    CONSTRAINT duplicate_clusters_fk2 FOREIGN KEY (primary_id) REFERENCES review_references(id),
+    CONSTRAINT duplicate_clusters_fk3 FOREIGN KEY (decided_by) REFERENCES users(id)
+);
+
+CREATE TABLE duplicate_cluster_members(
+    cluster_id UUID NOT NULL,
+    reference_id UUID NOT NULL,
+    CONSTRAINT duplicate_cluster_members_pk PRIMARY KEY (cluster_id, reference_id),
+    CONSTRAINT duplicate_cluster_members_fk1 FOREIGN KEY (cluster_id) REFERENCES duplicate_clusters(id) ON DELETE CASCADE,
+    CONSTRAINT duplicate_cluster_members_fk2 FOREIGN KEY (reference_id) REFERENCES review_references(id)
+);
@@ -0,0 +1 @@
+DROP TABLE screening_decisions;
\ No newline at end of file
@@ -0,0 +1,18 @@
+CREATE TABLE screening_decisions(
+    id UUID,
+    review_id UUID NOT NULL,
+    reference_id UUID NOT NULL,
+    reviewer_id UUID NOT NULL,
+    stage VARCHAR NOT NULL,
+    decision VARCHAR NOT NULL,
+    note TEXT NOT NULL,
+    created_at TIMESTAMP NOT NULL,
+    updated_at TIMESTAMP NOT NULL,
+    CONSTRAINT screening_decisions_pk PRIMARY KEY (id),
+    CONSTRAINT screening_decisions_fk1 FOREIGN KEY (review_id) REFERENCES reviews(id),
+    CONSTRAINT screening_decisions_fk2 FOREIGN KEY (reference_id) REFERENCES review_references(id),
+    CONSTRAINT screening_decisions_fk3 FOREIGN KEY (reviewer_id) REFERENCES reviewers(id),
+    CONSTRAINT screening_decisions_uq UNIQUE (reference_id, reviewer_id, stage)
+);
+
+CREATE INDEX screening_decisions_review_id_idx ON screening_decisions(review_id, stage);
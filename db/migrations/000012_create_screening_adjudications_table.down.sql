@@ -0,0 +1,2 @@
+DROP TABLE screening_history;
+DROP TABLE screening_adjudications;
@@ -0,0 +1,36 @@
+CREATE TABLE screening_adjudications(
+    id UUID,
+    review_id UUID NOT NULL,
+    reference_id UUID NOT NULL,
+    adjudicator_id UUID NOT NULL,
+    stage VARCHAR NOT NULL,
+    decision VARCHAR NOT NULL,
+    note TEXT NOT NULL,
+    created_at TIMESTAMP NOT NULL,
+    updated_at TIMESTAMP NOT NULL,
+    CONSTRAINT screening_adjudications_pk PRIMARY KEY (id),
+    CONSTRAINT screening_adjudications_fk1 FOREIGN KEY (review_id) REFERENCES reviews(id),
+    CONSTRAINT screening_adjudications_fk2 FOREIGN KEY (reference_id) REFERENCES review_references(id),
+    CONSTRAINT screening_adjudications_fk3 FOREIGN KEY (adjudicator_id) REFERENCES reviewers(id),
+    CONSTRAINT screening_adjudications_uq UNIQUE (reference_id, stage)
+);
+
+CREATE INDEX screening_adjudications_review_id_idx ON screening_adjudications(review_id, stage);
+
+CREATE TABLE screening_history(
+    id UUID,
+    review_id UUID NOT NULL,
+    reference_id UUID NOT NULL,
+    reviewer_id UUID NOT NULL,
+    stage VARCHAR NOT NULL,
+    action VARCHAR NOT NULL,
+    decision VARCHAR NOT NULL,
+    note TEXT NOT NULL,
+    created_at TIMESTAMP NOT NULL,
+    CONSTRAINT screening_history_pk PRIMARY KEY (id),
+    CONSTRAINT screening_history_fk1 FOREIGN KEY (review_id) REFERENCES reviews(id),
+    CONSTRAINT screening_history_fk2 FOREIGN KEY (reference_id) REFERENCES review_references(id),
+    CONSTRAINT screening_history_fk3 FOREIGN KEY (reviewer_id) REFERENCES reviewers(id)
+);
+
+CREATE INDEX screening_history_reference_id_idx ON screening_history(reference_id, created_at);
@@ -0,0 +1,4 @@
+ALTER TABLE screening_history DROP COLUMN exclusion_reason_id;
+ALTER TABLE screening_adjudications DROP COLUMN exclusion_reason_id;
+ALTER TABLE screening_decisions DROP COLUMN exclusion_reason_id;
+DROP TABLE exclusion_reasons;
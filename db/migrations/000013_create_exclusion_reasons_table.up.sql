@@ -0,0 +1,15 @@
+CREATE TABLE exclusion_reasons(
+    id UUID,
+    review_id UUID NOT NULL,
+    label VARCHAR NOT NULL,
+    position INTEGER NOT NULL,
+    created_at TIMESTAMP NOT NULL,
+    updated_at TIMESTAMP NOT NULL,
+    CONSTRAINT exclusion_reasons_pk PRIMARY KEY (id),
+    CONSTRAINT exclusion_reasons_fk1 FOREIGN KEY (review_id) REFERENCES reviews(id),
+    CONSTRAINT exclusion_reasons_uq UNIQUE (review_id, label)
+);
+
+ALTER TABLE screening_decisions ADD COLUMN exclusion_reason_id UUID REFERENCES exclusion_reasons(id);
+ALTER TABLE screening_adjudications ADD COLUMN exclusion_reason_id UUID REFERENCES exclusion_reasons(id);
+ALTER TABLE screening_history ADD COLUMN exclusion_reason_id UUID REFERENCES exclusion_reasons(id);
@@ -0,0 +1 @@
+ALTER TABLE review_references DROP COLUMN not_retrieved;
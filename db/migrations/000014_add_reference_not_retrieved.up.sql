@@ -0,0 +1 @@
+ALTER TABLE review_references ADD COLUMN not_retrieved BOOLEAN NOT NULL DEFAULT false;
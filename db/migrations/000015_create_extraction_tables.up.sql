@@ -0,0 +1,30 @@
+CREATE TABLE extraction_forms(
+    id UUID,
+    review_id UUID NOT NULL,
+    version INTEGER NOT NULL,
+    schema JSONB NOT NULL,
+    created_by UUID NOT NULL,
+    created_at TIMESTAMP NOT NULL,
+    CONSTRAINT extraction_forms_pk PRIMARY KEY (id),
+    CONSTRAINT extraction_forms_fk1 FOREIGN KEY (review_id) REFERENCES reviews(id),
+    CONSTRAINT extraction_forms_fk2 FOREIGN KEY (created_by) REFERENCES users(id),
+    CONSTRAINT extraction_forms_uq UNIQUE (review_id, version)
+);
+
+CREATE TABLE extractions(
+    id UUID,
+    review_id UUID NOT NULL,
+    reference_id UUID NOT NULL,
+    form_id UUID NOT NULL,
+    form_version INTEGER NOT NULL,
+    reviewer_id UUID NOT NULL,
+    data JSONB NOT NULL,
+    created_at TIMESTAMP NOT NULL,
+    updated_at TIMESTAMP NOT NULL,
+    CONSTRAINT extractions_pk PRIMARY KEY (id),
+    CONSTRAINT extractions_fk1 FOREIGN KEY (review_id) REFERENCES reviews(id),
+    CONSTRAINT extractions_fk2 FOREIGN KEY (reference_id) REFERENCES review_references(id),
+    CONSTRAINT extractions_fk3 FOREIGN KEY (form_id) REFERENCES extraction_forms(id),
+    CONSTRAINT extractions_fk4 FOREIGN KEY (reviewer_id) REFERENCES reviewers(id),
+    CONSTRAINT extractions_uq UNIQUE (reference_id, form_id, reviewer_id)
+);
@@ -0,0 +1,22 @@
+CREATE TABLE extraction_consensus(
+    id UUID,
+    review_id UUID NOT NULL,
+    reference_id UUID NOT NULL,
+    form_id UUID NOT NULL,
+    form_version INTEGER NOT NULL,
+    reviewer_id UUID NOT NULL,
+    left_id UUID NOT NULL,
+    right_id UUID NOT NULL,
+    discrepancies INTEGER NOT NULL,
+    data JSONB NOT NULL,
+    created_at TIMESTAMP NOT NULL,
+    updated_at TIMESTAMP NOT NULL,
+    CONSTRAINT extraction_consensus_pk PRIMARY KEY (id),
+    CONSTRAINT extraction_consensus_fk1 FOREIGN KEY (review_id) REFERENCES reviews(id),
+    CONSTRAINT extraction_consensus_fk2 FOREIGN KEY (reference_id) REFERENCES review_references(id),
+    CONSTRAINT extraction_consensus_fk3 FOREIGN KEY (form_id) REFERENCES extraction_forms(id),
+    CONSTRAINT extraction_consensus_fk4 FOREIGN KEY (reviewer_id) REFERENCES reviewers(id),
+    CONSTRAINT extraction_consensus_fk5 FOREIGN KEY (left_id) REFERENCES extractions(id),
+    CONSTRAINT extraction_consensus_fk6 FOREIGN KEY (right_id) REFERENCES extractions(id),
+    CONSTRAINT extraction_consensus_uq UNIQUE (reference_id, form_id)
+);
@@ -0,0 +1 @@
+DROP TABLE rob_assessments;
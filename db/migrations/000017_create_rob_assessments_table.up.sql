@@ -0,0 +1,19 @@
+CREATE TABLE rob_assessments(
+    id UUID,
+    review_id UUID NOT NULL,
+    reference_id UUID NOT NULL,
+    reviewer_id UUID NOT NULL,
+    tool VARCHAR NOT NULL,
+    answers JSONB NOT NULL,
+    judgements JSONB NOT NULL,
+    overall VARCHAR NOT NULL,
+    weight DOUBLE PRECISION NOT NULL DEFAULT 1,
+    note TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP NOT NULL,
+    updated_at TIMESTAMP NOT NULL,
+    CONSTRAINT rob_assessments_pk PRIMARY KEY (id),
+    CONSTRAINT rob_assessments_fk1 FOREIGN KEY (review_id) REFERENCES reviews(id),
+    CONSTRAINT rob_assessments_fk2 FOREIGN KEY (reference_id) REFERENCES review_references(id),
+    CONSTRAINT rob_assessments_fk3 FOREIGN KEY (reviewer_id) REFERENCES reviewers(id),
+    CONSTRAINT rob_assessments_uq UNIQUE (reference_id, reviewer_id, tool)
+);
@@ -0,0 +1,2 @@
+DROP TABLE checklist_appraisals;
+DROP TABLE checklist_templates;
@@ -0,0 +1,155 @@
+CREATE TABLE checklist_templates(
+    id UUID,
+    organization_id UUID,
+    name VARCHAR NOT NULL,
+    definition JSONB NOT NULL,
+    archived BOOLEAN NOT NULL DEFAULT false,
+    created_by UUID,
+    created_at TIMESTAMP NOT NULL,
+    updated_at TIMESTAMP NOT NULL,
+    CONSTRAINT checklist_templates_pk PRIMARY KEY (id),
+    CONSTRAINT checklist_templates_fk1 FOREIGN KEY (organization_id) REFERENCES organizations(id),
+    CONSTRAINT checklist_templates_fk2 FOREIGN KEY (created_by) REFERENCES users(id)
+);
+
+CREATE TABLE checklist_appraisals(
+    id UUID,
+    review_id UUID NOT NULL,
+    reference_id UUID NOT NULL,
+    template_id UUID NOT NULL,
+    reviewer_id UUID NOT NULL,
+    answers JSONB NOT NULL,
+    score DOUBLE PRECISION NOT NULL,
+    max_score DOUBLE PRECISION NOT NULL,
+    rating VARCHAR NOT NULL DEFAULT '',
+    note TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP NOT NULL,
+    updated_at TIMESTAMP NOT NULL,
+    CONSTRAINT checklist_appraisals_pk PRIMARY KEY (id),
+    CONSTRAINT checklist_appraisals_fk1 FOREIGN KEY (review_id) REFERENCES reviews(id),
+    CONSTRAINT checklist_appraisals_fk2 FOREIGN KEY (reference_id) REFERENCES review_references(id),
+    CONSTRAINT checklist_appraisals_fk3 FOREIGN KEY (template_id) REFERENCES checklist_templates(id),
+    CONSTRAINT checklist_appraisals_fk4 FOREIGN KEY (reviewer_id) REFERENCES reviewers(id),
+    CONSTRAINT checklist_appraisals_uq UNIQUE (reference_id, template_id, reviewer_id)
+);
+
+-- Built-in templates belong to no organization.
+
+INSERT INTO checklist_templates (id, organization_id, name, definition, created_at, updated_at)
+VALUES ('6f0c1a3e-2b9d-4c1e-9f57-0a1b2c3d4e01', NULL, 'JBI Checklist for Qualitative Research', '{
+    "name": "JBI Checklist for Qualitative Research",
+    "description": "JBI critical appraisal checklist for qualitative research. Studies are rated on the share of applicable questions answered yes.",
+    "design": "Qualitative studies",
+    "options": [
+        {"value": "Yes", "score": 1},
+        {"value": "No", "score": 0},
+        {"value": "Unclear", "score": 0},
+        {"value": "Not applicable", "score": 0, "notApplicable": true}
+    ],
+    "questions": [
+        {"id": "1", "text": "Is there congruity between the stated philosophical perspective and the research methodology?"},
+        {"id": "2", "text": "Is there congruity between the research methodology and the research question or objectives?"},
+        {"id": "3", "text": "Is there congruity between the research methodology and the methods used to collect data?"},
+        {"id": "4", "text": "Is there congruity between the research methodology and the representation and analysis of data?"},
+        {"id": "5", "text": "Is there congruity between the research methodology and the interpretation of results?"},
+        {"id": "6", "text": "Is there a statement locating the researcher culturally or theoretically?"},
+        {"id": "7", "text": "Is the influence of the researcher on the research, and vice-versa, addressed?"},
+        {"id": "8", "text": "Are participants, and their voices, adequately represented?"},
+        {"id": "9", "text": "Is the research ethical according to current criteria or, for recent studies, is there evidence of ethical approval by an appropriate body?"},
+        {"id": "10", "text": "Do the conclusions drawn in the research report flow from the analysis, or interpretation, of the data?"}
+    ],
+    "scoring": {
+        "basis": "percent",
+        "ratings": [
+            {"min": 0, "label": "Low quality"},
+            {"min": 50, "label": "Moderate quality"},
+            {"min": 70, "label": "High quality"}
+        ]
+    }
+}'::jsonb, NOW(), NOW());
+
+INSERT INTO checklist_templates (id, organization_id, name, definition, created_at, updated_at)
+VALUES ('6f0c1a3e-2b9d-4c1e-9f57-0a1b2c3d4e02', NULL, 'CASP Qualitative Studies Checklist', '{
+    "name": "CASP Qualitative Studies Checklist",
+    "description": "Critical Appraisal Skills Programme checklist for qualitative studies. CASP does not rate studies; the score counts the questions answered yes.",
+    "design": "Qualitative studies",
+    "options": [
+        {"value": "Yes", "score": 1},
+        {"value": "Can''t tell", "score": 0},
+        {"value": "No", "score": 0}
+    ],
+    "questions": [
+        {"id": "1", "section": "Are the results valid?", "text": "Was there a clear statement of the aims of the research?"},
+        {"id": "2", "section": "Are the results valid?", "text": "Is a qualitative methodology appropriate?"},
+        {"id": "3", "section": "Are the results valid?", "text": "Was the research design appropriate to address the aims of the research?"},
+        {"id": "4", "section": "Are the results valid?", "text": "Was the recruitment strategy appropriate to the aims of the research?"},
+        {"id": "5", "section": "Are the results valid?", "text": "Was the data collected in a way that addressed the research issue?"},
+        {"id": "6", "section": "Are the results valid?", "text": "Has the relationship between researcher and participants been adequately considered?"},
+        {"id": "7", "section": "What are the results?", "text": "Have ethical issues been taken into consideration?"},
+        {"id": "8", "section": "What are the results?", "text": "Was the data analysis sufficiently rigorous?"},
+        {"id": "9", "section": "What are the results?", "text": "Is there a clear statement of findings?"},
+        {"id": "10", "section": "Will the results help locally?", "text": "Is the research valuable?"}
+    ],
+    "scoring": {
+        "basis": "points"
+    }
+}'::jsonb, NOW(), NOW());
+
+INSERT INTO checklist_templates (id, organization_id, name, definition, created_at, updated_at)
+VALUES ('6f0c1a3e-2b9d-4c1e-9f57-0a1b2c3d4e03', NULL, 'Newcastle-Ottawa Scale (cohort studies)', '{
+    "name": "Newcastle-Ottawa Scale (cohort studies)",
+    "description": "Newcastle-Ottawa quality assessment scale for cohort studies. A study can be awarded up to four stars for selection, two for comparability and three for outcome.",
+    "design": "Cohort studies",
+    "questions": [
+        {"id": "S1", "section": "Selection", "text": "Representativeness of the exposed cohort", "options": [
+            {"value": "Truly representative of the average in the community", "score": 1},
+            {"value": "Somewhat representative of the average in the community", "score": 1},
+            {"value": "Selected group of users", "score": 0},
+            {"value": "No description of the derivation of the cohort", "score": 0}
+        ]},
+        {"id": "S2", "section": "Selection", "text": "Selection of the non exposed cohort", "options": [
+            {"value": "Drawn from the same community as the exposed cohort", "score": 1},
+            {"value": "Drawn from a different source", "score": 0},
+            {"value": "No description of the derivation of the non exposed cohort", "score": 0}
+        ]},
+        {"id": "S3", "section": "Selection", "text": "Ascertainment of exposure", "options": [
+            {"value": "Secure record", "score": 1},
+            {"value": "Structured interview", "score": 1},
+            {"value": "Written self report", "score": 0},
+            {"value": "No description", "score": 0}
+        ]},
+        {"id": "S4", "section": "Selection", "text": "Demonstration that outcome of interest was not present at start of study", "options": [
+            {"value": "Yes", "score": 1},
+            {"value": "No", "score": 0}
+        ]},
+        {"id": "C1", "section": "Comparability", "text": "Comparability of cohorts on the basis of the design or analysis", "options": [
+            {"value": "Controls for the most important factor and any additional factor", "score": 2},
+            {"value": "Controls for the most important factor only", "score": 1},
+            {"value": "Not comparable", "score": 0}
+        ]},
+        {"id": "O1", "section": "Outcome", "text": "Assessment of outcome", "options": [
+            {"value": "Independent blind assessment", "score": 1},
+            {"value": "Record linkage", "score": 1},
+            {"value": "Self report", "score": 0},
+            {"value": "No description", "score": 0}
+        ]},
+        {"id": "O2", "section": "Outcome", "text": "Was follow-up long enough for outcomes to occur?", "options": [
+            {"value": "Yes", "score": 1},
+            {"value": "No", "score": 0}
+        ]},
+        {"id": "O3", "section": "Outcome", "text": "Adequacy of follow up of cohorts", "options": [
+            {"value": "Complete follow up, all subjects accounted for", "score": 1},
+            {"value": "Subjects lost to follow up unlikely to introduce bias", "score": 1},
+            {"value": "Follow up rate low and no description of those lost", "score": 0},
+            {"value": "No statement", "score": 0}
+        ]}
+    ],
+    "scoring": {
+        "basis": "points",
+        "ratings": [
+            {"min": 0, "label": "Poor"},
+            {"min": 4, "label": "Fair"},
+            {"min": 7, "label": "Good"}
+        ]
+    }
+}'::jsonb, NOW(), NOW());
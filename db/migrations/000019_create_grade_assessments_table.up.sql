@@ -0,0 +1,30 @@
+CREATE TABLE grade_assessments(
+    id UUID,
+    review_id UUID NOT NULL,
+    outcome VARCHAR NOT NULL,
+    importance VARCHAR NOT NULL,
+    design VARCHAR NOT NULL,
+    studies INTEGER NOT NULL DEFAULT 0,
+    participants INTEGER NOT NULL DEFAULT 0,
+    comparator_risk VARCHAR NOT NULL DEFAULT '',
+    intervention_risk VARCHAR NOT NULL DEFAULT '',
+    relative_effect VARCHAR NOT NULL DEFAULT '',
+    risk_of_bias INTEGER NOT NULL DEFAULT 0,
+    inconsistency INTEGER NOT NULL DEFAULT 0,
+    indirectness INTEGER NOT NULL DEFAULT 0,
+    imprecision INTEGER NOT NULL DEFAULT 0,
+    publication_bias INTEGER NOT NULL DEFAULT 0,
+    large_effect INTEGER NOT NULL DEFAULT 0,
+    dose_response INTEGER NOT NULL DEFAULT 0,
+    plausible_confounding INTEGER NOT NULL DEFAULT 0,
+    footnotes JSONB NOT NULL,
+    certainty VARCHAR NOT NULL,
+    comments TEXT NOT NULL DEFAULT '',
+    position INTEGER NOT NULL DEFAULT 0,
+    created_by UUID NOT NULL,
+    created_at TIMESTAMP NOT NULL,
+    updated_at TIMESTAMP NOT NULL,
+    CONSTRAINT grade_assessments_pk PRIMARY KEY (id),
+    CONSTRAINT grade_assessments_fk1 FOREIGN KEY (review_id) REFERENCES reviews(id),
+    CONSTRAINT grade_assessments_fk2 FOREIGN KEY (created_by) REFERENCES users(id)
+);
@@ -0,0 +1 @@
+DROP TABLE outcome_data;
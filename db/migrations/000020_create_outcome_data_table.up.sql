@@ -0,0 +1,28 @@
+CREATE TABLE outcome_data(
+    id UUID,
+    review_id UUID NOT NULL,
+    reference_id UUID NOT NULL,
+    outcome VARCHAR NOT NULL,
+    data_type VARCHAR NOT NULL,
+    events_t DOUBLE PRECISION NOT NULL DEFAULT 0,
+    total_t DOUBLE PRECISION NOT NULL DEFAULT 0,
+    events_c DOUBLE PRECISION NOT NULL DEFAULT 0,
+    total_c DOUBLE PRECISION NOT NULL DEFAULT 0,
+    mean_t DOUBLE PRECISION NOT NULL DEFAULT 0,
+    sd_t DOUBLE PRECISION NOT NULL DEFAULT 0,
+    n_t DOUBLE PRECISION NOT NULL DEFAULT 0,
+    mean_c DOUBLE PRECISION NOT NULL DEFAULT 0,
+    sd_c DOUBLE PRECISION NOT NULL DEFAULT 0,
+    n_c DOUBLE PRECISION NOT NULL DEFAULT 0,
+    measure VARCHAR NOT NULL DEFAULT '',
+    effect DOUBLE PRECISION NOT NULL DEFAULT 0,
+    se DOUBLE PRECISION NOT NULL DEFAULT 0,
+    created_by UUID NOT NULL,
+    created_at TIMESTAMP NOT NULL,
+    updated_at TIMESTAMP NOT NULL,
+    CONSTRAINT outcome_data_pk PRIMARY KEY (id),
+    CONSTRAINT outcome_data_fk1 FOREIGN KEY (review_id) REFERENCES reviews(id),
+    CONSTRAINT outcome_data_fk2 FOREIGN KEY (reference_id) REFERENCES review_references(id),
+    CONSTRAINT outcome_data_fk3 FOREIGN KEY (created_by) REFERENCES users(id),
+    CONSTRAINT outcome_data_uq UNIQUE (reference_id, outcome)
+);
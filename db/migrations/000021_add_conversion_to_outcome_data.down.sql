@@ -0,0 +1,3 @@
+ALTER TABLE outcome_data DROP COLUMN conversion;
+ALTER TABLE outcome_data DROP COLUMN inputs;
+ALTER TABLE outcome_data DROP COLUMN median_method;
@@ -0,0 +1,3 @@
+ALTER TABLE outcome_data ADD COLUMN median_method VARCHAR NOT NULL DEFAULT '';
+ALTER TABLE outcome_data ADD COLUMN inputs JSONB NOT NULL DEFAULT '{}';
+ALTER TABLE outcome_data ADD COLUMN conversion VARCHAR NOT NULL DEFAULT '';
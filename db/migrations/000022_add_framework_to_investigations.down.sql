@@ -0,0 +1,3 @@
+ALTER TABLE investigation_keywords DROP COLUMN concept;
+ALTER TABLE investigations DROP COLUMN elements;
+ALTER TABLE investigations DROP COLUMN framework;
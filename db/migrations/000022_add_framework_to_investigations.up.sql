@@ -0,0 +1,3 @@
+ALTER TABLE investigations ADD COLUMN framework VARCHAR NOT NULL DEFAULT '';
+ALTER TABLE investigations ADD COLUMN elements JSONB NOT NULL DEFAULT '{}';
+ALTER TABLE investigation_keywords ADD COLUMN concept VARCHAR NOT NULL DEFAULT '';
@@ -0,0 +1,2 @@
+DROP TABLE investigation_status_changes;
+ALTER TABLE investigations DROP COLUMN conclusion;
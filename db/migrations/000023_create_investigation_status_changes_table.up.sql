@@ -0,0 +1,16 @@
+ALTER TABLE investigations ADD COLUMN conclusion TEXT NOT NULL DEFAULT '';
+
+CREATE TABLE investigation_status_changes(
+    id UUID,
+    investigation_id UUID NOT NULL,
+    user_id UUID NOT NULL,
+    from_status VARCHAR NOT NULL,
+    to_status VARCHAR NOT NULL,
+    conclusion TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP NOT NULL,
+    CONSTRAINT investigation_status_changes_pk PRIMARY KEY (id),
+    CONSTRAINT investigation_status_changes_fk1 FOREIGN KEY (investigation_id) REFERENCES investigations(id) ON DELETE CASCADE,
+    CONSTRAINT investigation_status_changes_fk2 FOREIGN KEY (user_id) REFERENCES users(id)
+);
+
+CREATE INDEX investigation_status_changes_investigation_id_idx ON investigation_status_changes(investigation_id);
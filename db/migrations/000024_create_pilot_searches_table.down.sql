@@ -0,0 +1 @@
+DROP TABLE pilot_searches;
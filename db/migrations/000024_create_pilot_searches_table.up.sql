@@ -0,0 +1,17 @@
+CREATE TABLE pilot_searches(
+    id UUID,
+    investigation_id UUID NOT NULL,
+    user_id UUID NOT NULL,
+    source_database VARCHAR NOT NULL,
+    query TEXT NOT NULL,
+    run_on DATE NOT NULL,
+    hits INTEGER NOT NULL,
+    notes TEXT NOT NULL DEFAULT '',
+    keywords JSONB NOT NULL DEFAULT '[]',
+    created_at TIMESTAMP NOT NULL,
+    CONSTRAINT pilot_searches_pk PRIMARY KEY (id),
+    CONSTRAINT pilot_searches_fk1 FOREIGN KEY (investigation_id) REFERENCES investigations(id) ON DELETE CASCADE,
+    CONSTRAINT pilot_searches_fk2 FOREIGN KEY (user_id) REFERENCES users(id)
+);
+
+CREATE INDEX pilot_searches_investigation_id_idx ON pilot_searches(investigation_id);
@@ -0,0 +1,2 @@
+DROP TABLE published_reviews;
+DROP TABLE registered_protocols;
@@ -0,0 +1,34 @@
+CREATE TABLE registered_protocols(
+    id UUID,
+    investigation_id UUID NOT NULL,
+    user_id UUID NOT NULL,
+    registry VARCHAR NOT NULL,
+    registration_id VARCHAR NOT NULL DEFAULT '',
+    title TEXT NOT NULL,
+    status VARCHAR NOT NULL,
+    url TEXT NOT NULL DEFAULT '',
+    notes TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP NOT NULL,
+    CONSTRAINT registered_protocols_pk PRIMARY KEY (id),
+    CONSTRAINT registered_protocols_fk1 FOREIGN KEY (investigation_id) REFERENCES investigations(id) ON DELETE CASCADE,
+    CONSTRAINT registered_protocols_fk2 FOREIGN KEY (user_id) REFERENCES users(id)
+);
+
+CREATE INDEX registered_protocols_investigation_id_idx ON registered_protocols(investigation_id);
+
+CREATE TABLE published_reviews(
+    id UUID,
+    investigation_id UUID NOT NULL,
+    user_id UUID NOT NULL,
+    citation TEXT NOT NULL,
+    doi VARCHAR NOT NULL DEFAULT '',
+    year INTEGER NOT NULL DEFAULT 0,
+    overlap VARCHAR NOT NULL,
+    notes TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP NOT NULL,
+    CONSTRAINT published_reviews_pk PRIMARY KEY (id),
+    CONSTRAINT published_reviews_fk1 FOREIGN KEY (investigation_id) REFERENCES investigations(id) ON DELETE CASCADE,
+    CONSTRAINT published_reviews_fk2 FOREIGN KEY (user_id) REFERENCES users(id)
+);
+
+CREATE INDEX published_reviews_investigation_id_idx ON published_reviews(investigation_id);
@@ -0,0 +1,2 @@
+DROP TABLE investigation_keyword_changes;
+ALTER TABLE investigation_keywords DROP COLUMN position;
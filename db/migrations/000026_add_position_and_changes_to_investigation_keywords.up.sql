@@ -0,0 +1,25 @@
+ALTER TABLE investigation_keywords ADD COLUMN position INTEGER NOT NULL DEFAULT 0;
+
+UPDATE investigation_keywords k
+SET position = o.position
+FROM (
+    SELECT id, ROW_NUMBER() OVER (PARTITION BY investigation_id ORDER BY created_at) AS position
+    FROM investigation_keywords
+) o
+WHERE k.id = o.id;
+
+CREATE TABLE investigation_keyword_changes(
+    id UUID,
+    investigation_id UUID NOT NULL,
+    keyword_id UUID NOT NULL,
+    user_id UUID NOT NULL,
+    action VARCHAR NOT NULL,
+    word VARCHAR NOT NULL,
+    detail TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP NOT NULL,
+    CONSTRAINT investigation_keyword_changes_pk PRIMARY KEY (id),
+    CONSTRAINT investigation_keyword_changes_fk1 FOREIGN KEY (investigation_id) REFERENCES investigations(id) ON DELETE CASCADE,
+    CONSTRAINT investigation_keyword_changes_fk2 FOREIGN KEY (user_id) REFERENCES users(id)
+);
+
+CREATE INDEX investigation_keyword_changes_investigation_id_idx ON investigation_keyword_changes(investigation_id);
@@ -0,0 +1,182 @@
+package dedup
+
+import (
+	"github.com/google/uuid"
+	"sort"
+)
+
+type Match string
+
+const (
+	MatchDoi   Match = "DOI"
+	MatchPmid  Match = "PMID"
+	MatchFuzzy Match = "Fuzzy"
+)
+
+// strength orders matches so a cluster reports its strongest evidence.
+var strength = map[Match]int{MatchFuzzy: 1, MatchPmid: 2, MatchDoi: 3}
+
+type Candidate struct {
+	Id          uuid.UUID
+	Title       string
+	Year        int
+	FirstAuthor string
+	Doi         string
+	Pmid        string
+}
+
+type Cluster struct {
+	Ids   []uuid.UUID
+	Match Match
+	// Score is the lowest title similarity that joined the cluster, or 1 when
+	// it was built from identifiers only.
+	Score float64
+}
+
+type unionFind struct {
+	parent []int
+	match  []Match
+	score  []float64
+	doi    []string
+}
+
+func newUnionFind(size int) *unionFind {
+	uf := &unionFind{parent: make([]int, size), match: make([]Match, size), score: make([]float64, size), doi: make([]string, size)}
+	for i := range uf.parent {
+		uf.parent[i] = i
+		uf.score[i] = 1
+	}
+	return uf
+}
+
+func (uf *unionFind) find(i int) int {
+	for uf.parent[i] != i {
+		uf.parent[i] = uf.parent[uf.parent[i]]
+		i = uf.parent[i]
+	}
+	return i
+}
+
+func (uf *unionFind) union(a int, b int, match Match, score float64) {
+	ra, rb := uf.find(a), uf.find(b)
+	if ra != rb {
+		uf.parent[rb] = ra
+		if strength[uf.match[rb]] > strength[uf.match[ra]] {
+			uf.match[ra] = uf.match[rb]
+		}
+		if uf.score[rb] < uf.score[ra] {
+			uf.score[ra] = uf.score[rb]
+		}
+		if uf.doi[ra] == "" {
+			uf.doi[ra] = uf.doi[rb]
+		}
+	}
+	if strength[match] > strength[uf.match[ra]] {
+		uf.match[ra] = match
+	}
+	if score < uf.score[ra] {
+		uf.score[ra] = score
+	}
+}
+
+// conflicting reports whether joining a and b would put two different DOIs
+// in the same cluster.
+func (uf *unionFind) conflicting(a int, b int) bool {
+	da, db := uf.doi[uf.find(a)], uf.doi[uf.find(b)]
+	return da != "" && db != "" && da != db
+}
+
+type edge struct {
+	a, b  int
+	score float64
+}
+
+// FindClusters groups candidates that describe the same study. Records
+// sharing a DOI or PMID are always grouped. Otherwise records are compared
+// when their first authors share a family name and their years are at most
+// one apart, and grouped when their normalized titles are at least threshold
+// similar, most similar pairs first. A fuzzy match never joins records, or
+// clusters, carrying different DOIs.
+func FindClusters(candidates []Candidate, threshold float64) []Cluster {
+	uf := newUnionFind(len(candidates))
+
+	byDoi := map[string]int{}
+	byPmid := map[string]int{}
+	blocks := map[string][]int{}
+	titles := make([]string, len(candidates))
+	dois := make([]string, len(candidates))
+
+	for i, candidate := range candidates {
+		titles[i] = NormalizeTitle(candidate.Title)
+		dois[i] = NormalizeDoi(candidate.Doi)
+		uf.doi[i] = dois[i]
+
+		if dois[i] != "" {
+			if j, ok := byDoi[dois[i]]; ok {
+				uf.union(j, i, MatchDoi, 1)
+			} else {
+				byDoi[dois[i]] = i
+			}
+		}
+		if candidate.Pmid != "" {
+			if j, ok := byPmid[candidate.Pmid]; ok {
+				uf.union(j, i, MatchPmid, 1)
+			} else {
+				byPmid[candidate.Pmid] = i
+			}
+		}
+		author := NormalizeAuthor(candidate.FirstAuthor)
+		blocks[author] = append(blocks[author], i)
+	}
+
+	edges := []edge{}
+	for _, block := range blocks {
+		for x := 0; x < len(block); x++ {
+			for y := x + 1; y < len(block); y++ {
+				i, j := block[x], block[y]
+				if uf.find(i) == uf.find(j) || !yearsCompatible(candidates[i].Year, candidates[j].Year) {
+					continue
+				}
+				if score := Similarity(titles[i], titles[j]); score >= threshold {
+					edges = append(edges, edge{a: i, b: j, score: score})
+				}
+			}
+		}
+	}
+	sort.SliceStable(edges, func(x, y int) bool { return edges[x].score > edges[y].score })
+	for _, e := range edges {
+		if uf.find(e.a) != uf.find(e.b) && !uf.conflicting(e.a, e.b) {
+			uf.union(e.a, e.b, MatchFuzzy, e.score)
+		}
+	}
+
+	groups := map[int][]int{}
+	for i := range candidates {
+		root := uf.find(i)
+		groups[root] = append(groups[root], i)
+	}
+
+	clusters := []Cluster{}
+	for root, members := range groups {
+		if len(members) < 2 {
+			continue
+		}
+		cluster := Cluster{Match: uf.match[root], Score: uf.score[root]}
+		for _, member := range members {
+			cluster.Ids = append(cluster.Ids, candidates[member].Id)
+		}
+		clusters = append(clusters, cluster)
+	}
+	sort.Slice(clusters, func(a, b int) bool {
+		return clusters[a].Ids[0].String() < clusters[b].Ids[0].String()
+	})
+	return clusters
+}
+
+func yearsCompatible(a int, b int) bool {
+	if a == 0 || b == 0 {
+		return true
+	}
+	diff := a - b
+	return diff >= -1 && diff <= 1
+}
@@ -0,0 +1,116 @@
+package dedup
+
+import (
+	"strings"
+	"unicode"
+)
+
+var foldedLetters = map[rune]rune{
+	'á': 'a', 'à': 'a', 'â': 'a', 'ä': 'a', 'ã': 'a', 'å': 'a',
+	'é': 'e', 'è': 'e', 'ê': 'e', 'ë': 'e',
+	'í': 'i', 'ì': 'i', 'î': 'i', 'ï': 'i',
+	'ó': 'o', 'ò': 'o', 'ô': 'o', 'ö': 'o', 'õ': 'o', 'ø': 'o',
+	'ú': 'u', 'ù': 'u', 'û': 'u', 'ü': 'u',
+	'ç': 'c', 'ñ': 'n', 'ý': 'y', 'ÿ': 'y',
+}
+
+// NormalizeTitle lowercases a title, folds common accents and drops
+// punctuation so that "Exercise & Depression." and "exercise depression"
+// compare equal.
+func NormalizeTitle(title string) string {
+	var sb strings.Builder
+	for _, r := range strings.ToLower(title) {
+		if folded, ok := foldedLetters[r]; ok {
+			r = folded
+		}
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			sb.WriteRune(r)
+		} else {
+			sb.WriteRune(' ')
+		}
+	}
+	return strings.Join(strings.Fields(sb.String()), " ")
+}
+
+// NormalizeAuthor returns the lowercased family name of an author written as
+// "Smith, John", "Smith J" or "John Smith".
+func NormalizeAuthor(author string) string {
+	family, _, found := strings.Cut(author, ",")
+	if !found {
+		fields := strings.Fields(author)
+		switch {
+		case len(fields) == 0:
+			return ""
+		case len(fields) > 1 && isInitials(fields[len(fields)-1]):
+			// "Smith J" / "Smith JA": initials come last
+			family = fields[0]
+		default:
+			family = fields[len(fields)-1]
+		}
+	}
+	return NormalizeTitle(family)
+}
+
+// isInitials tells initials such as "J", "JA" or "J.A." from short family
+// names such as "Li" or "Wu": they are at most three upper-case letters.
+func isInitials(token string) bool {
+	letters := []rune(strings.ReplaceAll(token, ".", ""))
+	if len(letters) == 0 || len(letters) > 3 {
+		return false
+	}
+	for _, r := range letters {
+		if !unicode.IsUpper(r) {
+			return false
+		}
+	}
+	return true
+}
+
+// NormalizeDoi lowercases a DOI, DOIs being case-insensitive.
+func NormalizeDoi(doi string) string {
+	return strings.ToLower(strings.TrimSpace(doi))
+}
+
+// Similarity is the Levenshtein ratio of two strings: 1 for identical
+// strings, 0 for completely different ones.
+func Similarity(a string, b string) float64 {
+	ra, rb := []rune(a), []rune(b)
+	if len(ra) == 0 && len(rb) == 0 {
+		return 1
+	}
+	longest := len(ra)
+	if len(rb) > longest {
+		longest = len(rb)
+	}
+	return 1 - float64(levenshtein(ra, rb))/float64(longest)
+}
+
+func levenshtein(a []rune, b []rune) int {
+	previous := make([]int, len(b)+1)
+	current := make([]int, len(b)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		current[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			current[j] = minInt(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+		}
+		previous, current = current, previous
+	}
+	return previous[len(b)]
+}
+
+func minInt(values ...int) int {
+	result := values[0]
+	for _, value := range values[1:] {
+		if value < result {
+			result = value
+		}
+	}
+	return result
+}
@@ -0,0 +1,127 @@
+package extraction
+
+import (
+	"fmt"
+	"math"
+	"sci-review/model"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// epsilon absorbs the rounding of numbers compared with no tolerance.
+const epsilon = 1e-9
+
+// Compare lines up two sets of answers to a schema field by field. Tables
+// are compared cell by cell, row by row, over the rows of the longer one.
+// Text is compared ignoring case and spacing, multiple choices ignoring
+// order, and numbers within the tolerance of their field.
+func Compare(schema model.ExtractionSchema, left model.ExtractionValues, right model.ExtractionValues) []model.FieldComparison {
+	comparisons := []model.FieldComparison{}
+	for _, field := range schema.Fields {
+		if field.Type != model.FieldTable {
+			comparisons = append(comparisons, compareField(field, field.Key, field.Label, left[field.Key], right[field.Key]))
+			continue
+		}
+
+		leftRows, rightRows := list(left[field.Key]), list(right[field.Key])
+		for i := 0; i < max(len(leftRows), len(rightRows)); i++ {
+			leftRow, rightRow := row(leftRows, i), row(rightRows, i)
+			for _, column := range field.Columns {
+				path := fmt.Sprintf("%s[%d].%s", field.Key, i, column.Key)
+				label := fmt.Sprintf("%s row %d %s", field.Label, i+1, column.Label)
+				comparisons = append(comparisons, compareField(column, path, label, leftRow[column.Key], rightRow[column.Key]))
+			}
+		}
+	}
+	return comparisons
+}
+
+// Discrepancies counts the comparisons that do not agree.
+func Discrepancies(comparisons []model.FieldComparison) int {
+	count := 0
+	for _, comparison := range comparisons {
+		if !comparison.Agree {
+			count++
+		}
+	}
+	return count
+}
+
+func row(rows []any, i int) map[string]any {
+	if i >= len(rows) {
+		return map[string]any{}
+	}
+	cells, _ := rows[i].(map[string]any)
+	return cells
+}
+
+func compareField(field model.ExtractionField, path string, label string, left any, right any) model.FieldComparison {
+	comparison := model.FieldComparison{Path: path, Label: label, Type: field.Type}
+	switch field.Type {
+	case model.FieldMultiChoice:
+		leftChoices, rightChoices := choices(left), choices(right)
+		comparison.Left = strings.Join(leftChoices, ", ")
+		comparison.Right = strings.Join(rightChoices, ", ")
+		comparison.Agree = slices.Equal(leftChoices, rightChoices)
+	case model.FieldNumber:
+		comparison.Left, comparison.Right = Text(left), Text(right)
+		comparison.Agree = sameNumber(field, comparison.Left, comparison.Right)
+	default:
+		comparison.Left, comparison.Right = Text(left), Text(right)
+		comparison.Agree = normalize(comparison.Left) == normalize(comparison.Right)
+	}
+	return comparison
+}
+
+func choices(value any) []string {
+	sorted := []string{}
+	for _, choice := range list(value) {
+		sorted = append(sorted, Text(choice))
+	}
+	slices.Sort(sorted)
+	return sorted
+}
+
+func normalize(text string) string {
+	return strings.ToLower(strings.Join(strings.Fields(text), " "))
+}
+
+func sameNumber(field model.ExtractionField, left string, right string) bool {
+	if left == "" || right == "" {
+		return left == right
+	}
+	leftNumber, leftErr := strconv.ParseFloat(left, 64)
+	rightNumber, rightErr := strconv.ParseFloat(right, 64)
+	if leftErr != nil || rightErr != nil {
+		return left == right
+	}
+	tolerance := 0.0
+	if field.Tolerance != nil {
+		tolerance = *field.Tolerance
+	}
+	return math.Abs(leftNumber-rightNumber) <= tolerance+epsilon
+}
+
+// Agreed keeps the answers both extractions agree on, to start a consensus
+// from. Tables are kept when every cell agrees.
+func Agreed(schema model.ExtractionSchema, left model.ExtractionValues, right model.ExtractionValues) model.ExtractionValues {
+	comparisons := Compare(schema, left, right)
+	agreed := model.ExtractionValues{}
+	for _, field := range schema.Fields {
+		value, found := left[field.Key]
+		if !found {
+			continue
+		}
+		agree := true
+		for _, comparison := range comparisons {
+			if comparison.Path == field.Key || strings.HasPrefix(comparison.Path, field.Key+"[") {
+				agree = agree && comparison.Agree
+			}
+		}
+		if agree {
+			agreed[field.Key] = value
+		}
+	}
+	return agreed
+}
@@ -0,0 +1,89 @@
+package extraction
+
+import (
+	"fmt"
+	"sci-review/model"
+	"strconv"
+)
+
+// BlankRows is the number of empty rows offered below the rows of a table.
+const BlankRows = 3
+
+// Input is a field of the extraction form ready to be rendered, holding the
+// current answer as text.
+type Input struct {
+	Field   model.ExtractionField
+	Name    string
+	Value   string
+	Checked map[string]bool
+	Rows    [][]Input
+}
+
+// Inputs lays out the fields of a schema with the answers of an extraction.
+// Answers to fields the schema does not have are ignored, so the values of
+// an older form version can prefill a newer one.
+func Inputs(schema model.ExtractionSchema, values model.ExtractionValues) []Input {
+	inputs := make([]Input, len(schema.Fields))
+	for i, field := range schema.Fields {
+		inputs[i] = input(field, InputName(field.Key), values[field.Key])
+	}
+	return inputs
+}
+
+func input(field model.ExtractionField, name string, value any) Input {
+	in := Input{Field: field, Name: name, Checked: map[string]bool{}}
+	switch field.Type {
+	case model.FieldMultiChoice:
+		for _, choice := range list(value) {
+			in.Checked[Text(choice)] = true
+		}
+	case model.FieldTable:
+		rows := list(value)
+		for i := 0; i < len(rows)+BlankRows; i++ {
+			var cells map[string]any
+			if i < len(rows) {
+				cells, _ = rows[i].(map[string]any)
+			}
+			row := make([]Input, len(field.Columns))
+			for j, column := range field.Columns {
+				row[j] = input(column, cellName(name, i, column.Key), cells[column.Key])
+			}
+			in.Rows = append(in.Rows, row)
+		}
+	default:
+		in.Value = Text(value)
+		in.Checked[in.Value] = true
+	}
+	return in
+}
+
+// list reads a multiple choice or a table, which come back from JSON as
+// []any but are built as []string and []any.
+func list(value any) []any {
+	switch items := value.(type) {
+	case []any:
+		return items
+	case []string:
+		result := make([]any, len(items))
+		for i, item := range items {
+			result[i] = item
+		}
+		return result
+	}
+	return nil
+}
+
+// Text writes a single answer as it is typed in the form.
+func Text(value any) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case float64:
+		return formatNumber(v)
+	case int:
+		return strconv.Itoa(v)
+	}
+	return fmt.Sprint(value)
+}
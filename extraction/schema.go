@@ -0,0 +1,115 @@
+// Package extraction checks extraction form schemas and the answers given
+// to them.
+package extraction
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sci-review/common"
+	"sci-review/model"
+	"strconv"
+	"strings"
+)
+
+var keyPattern = regexp.MustCompile(`^[a-z][a-z0-9_]*$`)
+
+// ParseSchema reads a schema written as JSON and checks it.
+func ParseSchema(text string) (model.ExtractionSchema, []common.ErrorResponse) {
+	schema := model.ExtractionSchema{}
+	decoder := json.NewDecoder(bytes.NewBufferString(text))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&schema); err != nil {
+		invalid := common.Message("schema", "json", "")
+		invalid.Error += ": " + err.Error()
+		return schema, []common.ErrorResponse{invalid}
+	}
+	return schema, CheckSchema(schema)
+}
+
+// CheckSchema tells what is wrong with a schema: missing keys or labels,
+// keys used twice, choice fields without options, tables without columns or
+// nested in other tables, and bounds the wrong way round.
+func CheckSchema(schema model.ExtractionSchema) []common.ErrorResponse {
+	if len(schema.Fields) == 0 {
+		return []common.ErrorResponse{common.Message("fields", "required", "")}
+	}
+	return checkFields(schema.Fields, "fields", true)
+}
+
+func fieldTypes(table bool) []string {
+	types := []string{}
+	for _, fieldType := range model.FieldTypes {
+		if table || fieldType != model.FieldTable {
+			types = append(types, string(fieldType))
+		}
+	}
+	return types
+}
+
+func checkFields(fields []model.ExtractionField, prefix string, table bool) []common.ErrorResponse {
+	errs := []common.ErrorResponse{}
+	keys := make(map[string]bool)
+	for i, field := range fields {
+		name := fmt.Sprintf("%s[%d]", prefix, i)
+
+		switch {
+		case field.Key == "":
+			errs = append(errs, common.Message(name+".key", "required", ""))
+		case !keyPattern.MatchString(field.Key):
+			errs = append(errs, common.Message(name+".key", "key", ""))
+		case keys[field.Key]:
+			errs = append(errs, common.Message(name+".key", "unique", ""))
+		}
+		keys[field.Key] = true
+
+		if strings.TrimSpace(field.Label) == "" {
+			errs = append(errs, common.Message(name+".label", "required", ""))
+		}
+
+		types := fieldTypes(table)
+		known := false
+		for _, fieldType := range types {
+			known = known || string(field.Type) == fieldType
+		}
+		if !known {
+			errs = append(errs, common.Message(name+".type", "oneof", strings.Join(types, " ")))
+			continue
+		}
+
+		switch field.Type {
+		case model.FieldSingleChoice, model.FieldMultiChoice:
+			if len(field.Options) == 0 {
+				errs = append(errs, common.Message(name+".options", "required", ""))
+			}
+			options := make(map[string]bool)
+			for j, option := range field.Options {
+				optionName := fmt.Sprintf("%s.options[%d]", name, j)
+				if strings.TrimSpace(option) == "" {
+					errs = append(errs, common.Message(optionName, "required", ""))
+				} else if options[option] {
+					errs = append(errs, common.Message(optionName, "unique", ""))
+				}
+				options[option] = true
+			}
+		case model.FieldNumber:
+			if field.Min != nil && field.Max != nil && *field.Min > *field.Max {
+				errs = append(errs, common.Message(name+".min", "lte", formatNumber(*field.Max)))
+			}
+			if field.Tolerance != nil && *field.Tolerance < 0 {
+				errs = append(errs, common.Message(name+".tolerance", "gte", "0"))
+			}
+		case model.FieldTable:
+			if len(field.Columns) == 0 {
+				errs = append(errs, common.Message(name+".columns", "required", ""))
+			}
+			errs = append(errs, checkFields(field.Columns, name+".columns", false)...)
+		}
+	}
+	return errs
+}
+
+func formatNumber(value float64) string {
+	return strconv.FormatFloat(value, 'f', -1, 64)
+}
@@ -0,0 +1,220 @@
+package extraction
+
+import (
+	"fmt"
+	"math"
+	"net/url"
+	"sci-review/common"
+	"sci-review/model"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DateLayout is the format of date answers, as sent by date inputs.
+const DateLayout = "2006-01-02"
+
+// InputName is the name of the form input of a field. Table cells are named
+// after their table, row and column.
+func InputName(key string) string {
+	return "f." + key
+}
+
+func cellName(table string, row int, column string) string {
+	return table + "." + strconv.Itoa(row) + "." + column
+}
+
+// fieldError reports the error of a tag on a field, worded with its label
+// but pointing at its input.
+func fieldError(name string, label string, tag string, param string) common.ErrorResponse {
+	err := common.Message(label, tag, param)
+	err.Field = name
+	return err
+}
+
+// Parse reads the answers of a submitted extraction form and checks them
+// against the schema. Empty answers are left out of the values.
+func Parse(schema model.ExtractionSchema, form url.Values) (model.ExtractionValues, []common.ErrorResponse) {
+	values := model.ExtractionValues{}
+	errs := []common.ErrorResponse{}
+	for _, field := range schema.Fields {
+		value, fieldErrs := parseField(field, InputName(field.Key), field.Label, form)
+		errs = append(errs, fieldErrs...)
+		if value != nil {
+			values[field.Key] = value
+		}
+	}
+	return values, errs
+}
+
+func parseField(field model.ExtractionField, name string, label string, form url.Values) (any, []common.ErrorResponse) {
+	switch field.Type {
+	case model.FieldMultiChoice:
+		return parseChoices(field, name, label, form[name])
+	case model.FieldTable:
+		return parseTable(field, name, label, form)
+	}
+
+	text := strings.TrimSpace(form.Get(name))
+	if text == "" {
+		if field.Required {
+			return nil, []common.ErrorResponse{fieldError(name, label, "required", "")}
+		}
+		return nil, nil
+	}
+
+	switch field.Type {
+	case model.FieldNumber:
+		number, err := strconv.ParseFloat(text, 64)
+		// NaN and infinities parse but cannot be stored as JSON
+		if err != nil || math.IsNaN(number) || math.IsInf(number, 0) {
+			return nil, []common.ErrorResponse{fieldError(name, label, "numeric", "")}
+		}
+		if field.Min != nil && number < *field.Min {
+			return nil, []common.ErrorResponse{fieldError(name, label, "gte", formatNumber(*field.Min))}
+		}
+		if field.Max != nil && number > *field.Max {
+			return nil, []common.ErrorResponse{fieldError(name, label, "lte", formatNumber(*field.Max))}
+		}
+		return number, nil
+	case model.FieldSingleChoice:
+		if !slices.Contains(field.Options, text) {
+			return nil, []common.ErrorResponse{fieldError(name, label, "oneof", strings.Join(field.Options, ", "))}
+		}
+		return text, nil
+	case model.FieldDate:
+		if _, err := time.Parse(DateLayout, text); err != nil {
+			return nil, []common.ErrorResponse{fieldError(name, label, "datetime", "YYYY-MM-DD")}
+		}
+		return text, nil
+	}
+	return text, nil
+}
+
+func parseChoices(field model.ExtractionField, name string, label string, given []string) (any, []common.ErrorResponse) {
+	choices := []string{}
+	for _, choice := range given {
+		choice = strings.TrimSpace(choice)
+		if choice == "" || slices.Contains(choices, choice) {
+			continue
+		}
+		if !slices.Contains(field.Options, choice) {
+			return nil, []common.ErrorResponse{fieldError(name, label, "oneof", strings.Join(field.Options, ", "))}
+		}
+		choices = append(choices, choice)
+	}
+	if len(choices) == 0 {
+		if field.Required {
+			return nil, []common.ErrorResponse{fieldError(name, label, "required", "")}
+		}
+		return nil, nil
+	}
+	return choices, nil
+}
+
+// rowNumbers finds the rows submitted for a table, in order.
+func rowNumbers(name string, form url.Values) []int {
+	seen := make(map[int]bool)
+	for input := range form {
+		rest, found := strings.CutPrefix(input, name+".")
+		if !found {
+			continue
+		}
+		row, _, _ := strings.Cut(rest, ".")
+		if number, err := strconv.Atoi(row); err == nil {
+			seen[number] = true
+		}
+	}
+	numbers := []int{}
+	for number := range seen {
+		numbers = append(numbers, number)
+	}
+	sort.Ints(numbers)
+	return numbers
+}
+
+// parseTable keeps the rows with at least one answer; the required columns
+// are only checked in those.
+func parseTable(field model.ExtractionField, name string, label string, form url.Values) (any, []common.ErrorResponse) {
+	rows := []any{}
+	errs := []common.ErrorResponse{}
+	for _, number := range rowNumbers(name, form) {
+		row := map[string]any{}
+		rowErrs := []common.ErrorResponse{}
+		for _, column := range field.Columns {
+			cell := cellName(name, number, column.Key)
+			cellLabel := fmt.Sprintf("%s row %d %s", label, len(rows)+1, column.Label)
+			value, cellErrs := parseField(column, cell, cellLabel, form)
+			rowErrs = append(rowErrs, cellErrs...)
+			if value != nil {
+				row[column.Key] = value
+			}
+		}
+		if len(row) == 0 && !hasAnswer(name, number, form) {
+			continue
+		}
+		errs = append(errs, rowErrs...)
+		rows = append(rows, row)
+	}
+
+	if len(errs) > 0 {
+		return nil, errs
+	}
+	if len(rows) == 0 {
+		if field.Required {
+			return nil, []common.ErrorResponse{fieldError(name, label, "required", "")}
+		}
+		return nil, nil
+	}
+	return rows, nil
+}
+
+// hasAnswer tells whether anything was typed in a row, even an answer that
+// was not valid.
+func hasAnswer(name string, number int, form url.Values) bool {
+	prefix := name + "." + strconv.Itoa(number) + "."
+	for input, given := range form {
+		if !strings.HasPrefix(input, prefix) {
+			continue
+		}
+		for _, value := range given {
+			if strings.TrimSpace(value) != "" {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Submitted keeps the answers of a submitted form as they were typed, valid
+// or not, so the form can be shown again with them.
+func Submitted(schema model.ExtractionSchema, form url.Values) model.ExtractionValues {
+	values := model.ExtractionValues{}
+	for _, field := range schema.Fields {
+		values[field.Key] = submitted(field, InputName(field.Key), form)
+	}
+	return values
+}
+
+func submitted(field model.ExtractionField, name string, form url.Values) any {
+	switch field.Type {
+	case model.FieldMultiChoice:
+		return form[name]
+	case model.FieldTable:
+		rows := []any{}
+		for _, number := range rowNumbers(name, form) {
+			if !hasAnswer(name, number, form) {
+				continue
+			}
+			row := map[string]any{}
+			for _, column := range field.Columns {
+				row[column.Key] = submitted(column, cellName(name, number, column.Key), form)
+			}
+			rows = append(rows, row)
+		}
+		return rows
+	}
+	return form.Get(name)
+}
@@ -0,0 +1,27 @@
+package form
+
+import "golang.org/x/exp/slog"
+
+// ChecklistImportForm carries a checklist definition, pasted or read from
+// the uploaded file.
+type ChecklistImportForm struct {
+	Definition string `json:"definition" form:"definition" validate:"required,max=100000"`
+}
+
+func (c ChecklistImportForm) LogValue() slog.Value {
+	return slog.GroupValue(
+		slog.Int("definition_length", len(c.Definition)),
+	)
+}
+
+// ChecklistAppraisalForm carries what goes with the answers to a checklist,
+// which are read against its questions.
+type ChecklistAppraisalForm struct {
+	Note string `json:"note" form:"note" validate:"max=2000"`
+}
+
+func (c ChecklistAppraisalForm) LogValue() slog.Value {
+	return slog.GroupValue(
+		slog.Int("note_length", len(c.Note)),
+	)
+}
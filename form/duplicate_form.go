@@ -0,0 +1,23 @@
+package form
+
+import "golang.org/x/exp/slog"
+
+type DuplicateDetectForm struct {
+	Threshold float64 `json:"threshold" form:"threshold" validate:"required,gt=0,lte=1"`
+}
+
+func (d DuplicateDetectForm) LogValue() slog.Value {
+	return slog.GroupValue(
+		slog.Float64("threshold", d.Threshold),
+	)
+}
+
+type DuplicateConfirmForm struct {
+	PrimaryId string `json:"primary_id" form:"primary_id" validate:"required,uuid"`
+}
+
+func (d DuplicateConfirmForm) LogValue() slog.Value {
+	return slog.GroupValue(
+		slog.String("primary_id", d.PrimaryId),
+	)
+}
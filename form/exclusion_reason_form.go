@@ -0,0 +1,15 @@
+package form
+
+import "golang.org/x/exp/slog"
+
+type ExclusionReasonForm struct {
+	Label    string `json:"label" form:"label" validate:"required,max=200"`
+	Position int    `json:"position" form:"position" validate:"min=0"`
+}
+
+func (e ExclusionReasonForm) LogValue() slog.Value {
+	return slog.GroupValue(
+		slog.String("label", e.Label),
+		slog.Int("position", e.Position),
+	)
+}
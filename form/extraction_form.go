@@ -0,0 +1,25 @@
+package form
+
+import "golang.org/x/exp/slog"
+
+type ExtractionSchemaForm struct {
+	Schema string `json:"schema" form:"schema" validate:"required,max=100000"`
+}
+
+func (e ExtractionSchemaForm) LogValue() slog.Value {
+	return slog.GroupValue(
+		slog.Int("schema_length", len(e.Schema)),
+	)
+}
+
+// ExtractionSaveForm carries the form version the answers were given to;
+// the answers themselves are read against its schema.
+type ExtractionSaveForm struct {
+	FormId string `json:"form_id" form:"form_id" validate:"required,uuid"`
+}
+
+func (e ExtractionSaveForm) LogValue() slog.Value {
+	return slog.GroupValue(
+		slog.String("form_id", e.FormId),
+	)
+}
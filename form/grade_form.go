@@ -0,0 +1,34 @@
+package form
+
+import "golang.org/x/exp/slog"
+
+// GradeAssessmentForm carries the outcome, the effects shown in the Summary
+// of Findings table and the levels of each GRADE factor; the footnotes are
+// read by factor.
+type GradeAssessmentForm struct {
+	Outcome          string `json:"outcome" form:"outcome" validate:"required,max=255"`
+	Importance       string `json:"importance" form:"importance" validate:"required,oneof=Critical Important Limited"`
+	Design           string `json:"design" form:"design" validate:"required,oneof=Randomized Observational"`
+	Studies          int    `json:"studies" form:"studies" validate:"gte=0"`
+	Participants     int    `json:"participants" form:"participants" validate:"gte=0"`
+	ComparatorRisk   string `json:"comparator_risk" form:"comparator_risk" validate:"max=255"`
+	InterventionRisk string `json:"intervention_risk" form:"intervention_risk" validate:"max=255"`
+	RelativeEffect   string `json:"relative_effect" form:"relative_effect" validate:"max=255"`
+	RiskOfBias       int    `json:"risk_of_bias" form:"risk_of_bias" validate:"gte=0,lte=2"`
+	Inconsistency    int    `json:"inconsistency" form:"inconsistency" validate:"gte=0,lte=2"`
+	Indirectness     int    `json:"indirectness" form:"indirectness" validate:"gte=0,lte=2"`
+	Imprecision      int    `json:"imprecision" form:"imprecision" validate:"gte=0,lte=2"`
+	PublicationBias  int    `json:"publication_bias" form:"publication_bias" validate:"gte=0,lte=2"`
+	LargeEffect      int    `json:"large_effect" form:"large_effect" validate:"gte=0,lte=2"`
+	DoseResponse     int    `json:"dose_response" form:"dose_response" validate:"gte=0,lte=1"`
+	Confounding      int    `json:"plausible_confounding" form:"plausible_confounding" validate:"gte=0,lte=1"`
+	Comments         string `json:"comments" form:"comments" validate:"max=2000"`
+	Position         int    `json:"position" form:"position" validate:"min=0"`
+}
+
+func (g GradeAssessmentForm) LogValue() slog.Value {
+	return slog.GroupValue(
+		slog.String("outcome", g.Outcome),
+		slog.String("design", g.Design),
+	)
+}
@@ -2,14 +2,102 @@ package form
 
 import (
 	"golang.org/x/exp/slog"
+	"sci-review/model"
+	"strings"
 )
 
+// InvestigationForm carries the question of an investigation and, when a
+// framework is chosen, the text of its elements. Each element has its own
+// field; only those of the framework are kept.
 type InvestigationForm struct {
-	Question string `json:"question" form:"question" validate:"required,min=3"`
+	Question     string                  `json:"question" form:"question" validate:"required,min=3"`
+	Framework    model.QuestionFramework `json:"framework" form:"framework" validate:"omitempty,oneof=PICO PICOS PCC SPIDER"`
+	Population   string                  `json:"population" form:"population" validate:"max=1000"`
+	Intervention string                  `json:"intervention" form:"intervention" validate:"max=1000"`
+	Comparison   string                  `json:"comparison" form:"comparison" validate:"max=1000"`
+	Outcome      string                  `json:"outcome" form:"outcome" validate:"max=1000"`
+	StudyDesign  string                  `json:"study_design" form:"study_design" validate:"max=1000"`
+	Concept      string                  `json:"concept" form:"concept" validate:"max=1000"`
+	Context      string                  `json:"context" form:"context" validate:"max=1000"`
+	Sample       string                  `json:"sample" form:"sample" validate:"max=1000"`
+	Phenomenon   string                  `json:"phenomenon" form:"phenomenon" validate:"max=1000"`
+	Design       string                  `json:"design" form:"design" validate:"max=1000"`
+	Evaluation   string                  `json:"evaluation" form:"evaluation" validate:"max=1000"`
+	ResearchType string                  `json:"research_type" form:"research_type" validate:"max=1000"`
+}
+
+func (i InvestigationForm) values() map[string]string {
+	return map[string]string{
+		"population":    i.Population,
+		"intervention":  i.Intervention,
+		"comparison":    i.Comparison,
+		"outcome":       i.Outcome,
+		"study_design":  i.StudyDesign,
+		"concept":       i.Concept,
+		"context":       i.Context,
+		"sample":        i.Sample,
+		"phenomenon":    i.Phenomenon,
+		"design":        i.Design,
+		"evaluation":    i.Evaluation,
+		"research_type": i.ResearchType,
+	}
+}
+
+// Elements returns the text of the elements of the framework by key.
+func (i InvestigationForm) Elements() model.QuestionElements {
+	values := i.values()
+	elements := model.QuestionElements{}
+	for _, element := range i.Framework.Elements() {
+		if text := strings.TrimSpace(values[element.Key]); text != "" {
+			elements[element.Key] = text
+		}
+	}
+	return elements
+}
+
+// Value returns the text of the element with the key, to fill the form.
+func (i InvestigationForm) Value(key string) string {
+	return i.values()[key]
+}
+
+// NewInvestigationForm fills the form with the question of an
+// investigation, to edit it.
+func NewInvestigationForm(investigation *model.Investigation) *InvestigationForm {
+	elements := investigation.Elements
+	return &InvestigationForm{
+		Question:     investigation.Question,
+		Framework:    investigation.Framework,
+		Population:   elements["population"],
+		Intervention: elements["intervention"],
+		Comparison:   elements["comparison"],
+		Outcome:      elements["outcome"],
+		StudyDesign:  elements["study_design"],
+		Concept:      elements["concept"],
+		Context:      elements["context"],
+		Sample:       elements["sample"],
+		Phenomenon:   elements["phenomenon"],
+		Design:       elements["design"],
+		Evaluation:   elements["evaluation"],
+		ResearchType: elements["research_type"],
+	}
 }
 
 func (i InvestigationForm) LogValue() slog.Value {
 	return slog.GroupValue(
 		slog.String("question", i.Question),
+		slog.String("framework", string(i.Framework)),
+	)
+}
+
+// InvestigationStatusForm moves an investigation to a status with the
+// conclusion or rationale for it.
+type InvestigationStatusForm struct {
+	Status     model.InvestigationStatus `json:"status" form:"status" validate:"required,oneof=InProgress Proceed DoNotProceed Cancelled"`
+	Conclusion string                    `json:"conclusion" form:"conclusion" validate:"max=10000"`
+}
+
+func (i InvestigationStatusForm) LogValue() slog.Value {
+	return slog.GroupValue(
+		slog.String("status", string(i.Status)),
 	)
 }
@@ -2,14 +2,50 @@ package form
 
 import "golang.org/x/exp/slog"
 
+// KeywordForm carries a keyword with a synonym per line and the key of the
+// element of the question it searches for, if any.
 type KeywordForm struct {
 	Word     string `json:"word" form:"word" validate:"required,min=3,max=255"`
 	Synonyms string `json:"synonyms" form:"synonyms"`
+	Concept  string `json:"concept" form:"concept" validate:"max=50"`
 }
 
 func (k KeywordForm) LogValue() slog.Value {
 	return slog.GroupValue(
 		slog.String("word", k.Word),
 		slog.String("synonyms", k.Synonyms),
+		slog.String("concept", k.Concept),
 	)
 }
+
+// KeywordUpdateForm edits a keyword with a field per synonym; synonyms left
+// empty are removed.
+type KeywordUpdateForm struct {
+	Word     string   `json:"word" form:"word" validate:"required,min=3,max=255"`
+	Synonyms []string `json:"synonyms" form:"synonyms" validate:"dive,max=255"`
+	Concept  string   `json:"concept" form:"concept" validate:"max=50"`
+}
+
+func (k KeywordUpdateForm) LogValue() slog.Value {
+	return slog.GroupValue(
+		slog.String("word", k.Word),
+		slog.Int("synonyms", len(k.Synonyms)),
+		slog.String("concept", k.Concept),
+	)
+}
+
+// KeywordMergeForm names the keyword another one is merged into.
+type KeywordMergeForm struct {
+	Into string `json:"into" form:"into" validate:"required,uuid"`
+}
+
+// KeywordMoveForm moves a keyword one place up or down.
+type KeywordMoveForm struct {
+	Direction string `json:"direction" form:"direction" validate:"required,oneof=up down"`
+}
+
+// KeywordSynonymForm adds a synonym to the keyword chosen in the form.
+type KeywordSynonymForm struct {
+	Keyword string `json:"keyword" form:"keyword" validate:"required,uuid"`
+	Synonym string `json:"synonym" form:"synonym" validate:"required,max=255"`
+}
@@ -0,0 +1,67 @@
+package form
+
+import "golang.org/x/exp/slog"
+
+// OutcomeDataForm carries the data of a study for an outcome. Which of the
+// numbers are read depends on the data type; the checks of the numbers
+// themselves are left to the meta-analysis package. Group sizes are shared
+// by means, medians, t statistics and p-values.
+type OutcomeDataForm struct {
+	ReferenceId  string  `json:"reference_id" form:"reference_id" validate:"required,uuid"`
+	Outcome      string  `json:"outcome" form:"outcome" validate:"required,max=255"`
+	DataType     string  `json:"data_type" form:"data_type" validate:"required,oneof=Binary Continuous Median TStatistic PValue Generic"`
+	EventsT      float64 `json:"events_t" form:"events_t" validate:"gte=0"`
+	TotalT       float64 `json:"total_t" form:"total_t" validate:"gte=0"`
+	EventsC      float64 `json:"events_c" form:"events_c" validate:"gte=0"`
+	TotalC       float64 `json:"total_c" form:"total_c" validate:"gte=0"`
+	MeanT        float64 `json:"mean_t" form:"mean_t"`
+	SdT          float64 `json:"sd_t" form:"sd_t" validate:"gte=0"`
+	NT           float64 `json:"n_t" form:"n_t" validate:"gte=0"`
+	MeanC        float64 `json:"mean_c" form:"mean_c"`
+	SdC          float64 `json:"sd_c" form:"sd_c" validate:"gte=0"`
+	NC           float64 `json:"n_c" form:"n_c" validate:"gte=0"`
+	MedianMethod string  `json:"median_method" form:"median_method" validate:"omitempty,oneof=WanIQR WanRange WanRangeIQR Hozo"`
+	MedianT      float64 `json:"median_t" form:"median_t"`
+	Q1T          float64 `json:"q1_t" form:"q1_t"`
+	Q3T          float64 `json:"q3_t" form:"q3_t"`
+	MinT         float64 `json:"min_t" form:"min_t"`
+	MaxT         float64 `json:"max_t" form:"max_t"`
+	MedianC      float64 `json:"median_c" form:"median_c"`
+	Q1C          float64 `json:"q1_c" form:"q1_c"`
+	Q3C          float64 `json:"q3_c" form:"q3_c"`
+	MinC         float64 `json:"min_c" form:"min_c"`
+	MaxC         float64 `json:"max_c" form:"max_c"`
+	T            float64 `json:"t" form:"t"`
+	P            float64 `json:"p" form:"p" validate:"gte=0,lte=1"`
+	Negative     bool    `json:"negative" form:"negative"`
+	Measure      string  `json:"measure" form:"measure" validate:"omitempty,oneof=OR RR RD MD SMD"`
+	Effect       float64 `json:"effect" form:"effect"`
+	SE           float64 `json:"se" form:"se" validate:"gte=0"`
+}
+
+func (o OutcomeDataForm) LogValue() slog.Value {
+	return slog.GroupValue(
+		slog.String("reference_id", o.ReferenceId),
+		slog.String("outcome", o.Outcome),
+		slog.String("data_type", o.DataType),
+	)
+}
+
+// MetaAnalysisForm selects the outcome to pool and how, and optionally the
+// key of the extraction field to explore heterogeneity with.
+type MetaAnalysisForm struct {
+	Outcome   string  `json:"outcome" form:"outcome" validate:"required,max=255"`
+	Measure   string  `json:"measure" form:"measure" validate:"required,oneof=OR RR RD MD SMD"`
+	Method    string  `json:"method" form:"method" validate:"required,oneof=IV MH DL REML"`
+	Level     float64 `json:"level" form:"level" validate:"gt=0,lt=1"`
+	Moderator string  `json:"moderator" form:"moderator" validate:"max=255"`
+}
+
+func (m MetaAnalysisForm) LogValue() slog.Value {
+	return slog.GroupValue(
+		slog.String("outcome", m.Outcome),
+		slog.String("measure", m.Measure),
+		slog.String("method", m.Method),
+		slog.String("moderator", m.Moderator),
+	)
+}
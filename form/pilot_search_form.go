@@ -0,0 +1,24 @@
+package form
+
+import (
+	"golang.org/x/exp/slog"
+	"sci-review/model"
+)
+
+// PilotSearchForm logs a pilot search. A query left empty is generated
+// from the keywords for the database.
+type PilotSearchForm struct {
+	SourceDatabase model.SourceDatabase `json:"source_database" form:"source_database" validate:"required,oneof=PubMed Scopus WebOfScience Embase CINAHL Other"`
+	Query          string               `json:"query" form:"query" validate:"max=20000"`
+	RunOn          string               `json:"run_on" form:"run_on" validate:"required"`
+	Hits           int                  `json:"hits" form:"hits" validate:"gte=0"`
+	Notes          string               `json:"notes" form:"notes" validate:"max=5000"`
+}
+
+func (p PilotSearchForm) LogValue() slog.Value {
+	return slog.GroupValue(
+		slog.String("source_database", string(p.SourceDatabase)),
+		slog.String("run_on", p.RunOn),
+		slog.Int("hits", p.Hits),
+	)
+}
@@ -0,0 +1,43 @@
+package form
+
+import (
+	"golang.org/x/exp/slog"
+	"sci-review/model"
+)
+
+// ProtocolForm logs the protocol of a review on a similar question found
+// in a registry.
+type ProtocolForm struct {
+	Registry       model.Registry       `json:"registry" form:"registry" validate:"required,oneof=PROSPERO OSF INPLASY ResearchRegistry Other"`
+	RegistrationId string               `json:"registration_id" form:"registration_id" validate:"max=255"`
+	Title          string               `json:"title" form:"title" validate:"required,max=1000"`
+	Status         model.ProtocolStatus `json:"status" form:"status" validate:"required,oneof=Ongoing Completed Published Abandoned Unknown"`
+	Url            string               `json:"url" form:"url" validate:"omitempty,url,max=2000"`
+	Notes          string               `json:"notes" form:"notes" validate:"max=5000"`
+}
+
+func (p ProtocolForm) LogValue() slog.Value {
+	return slog.GroupValue(
+		slog.String("registry", string(p.Registry)),
+		slog.String("registration_id", p.RegistrationId),
+		slog.String("status", string(p.Status)),
+	)
+}
+
+// PublishedReviewForm logs a review already published on a similar
+// question with how much it overlaps the one investigated.
+type PublishedReviewForm struct {
+	Citation string              `json:"citation" form:"citation" validate:"required,max=2000"`
+	Doi      string              `json:"doi" form:"doi" validate:"max=255"`
+	Year     int                 `json:"year" form:"year" validate:"omitempty,gte=1900,lte=2100"`
+	Overlap  model.ReviewOverlap `json:"overlap" form:"overlap" validate:"required,oneof=None Partial Full"`
+	Notes    string              `json:"notes" form:"notes" validate:"max=5000"`
+}
+
+func (p PublishedReviewForm) LogValue() slog.Value {
+	return slog.GroupValue(
+		slog.String("doi", p.Doi),
+		slog.Int("year", p.Year),
+		slog.String("overlap", string(p.Overlap)),
+	)
+}
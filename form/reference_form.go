@@ -0,0 +1,24 @@
+package form
+
+import (
+	"golang.org/x/exp/slog"
+	"sci-review/citation"
+	"sci-review/model"
+)
+
+type ReferenceImportForm struct {
+	SourceDatabase model.SourceDatabase `json:"source_database" form:"source_database" validate:"required,oneof=PubMed Scopus WebOfScience Embase CINAHL Other"`
+	Format         citation.Format      `json:"format" form:"format" validate:"required,oneof=ris bibtex csljson medline pubmedxml"`
+}
+
+func (r ReferenceImportForm) LogValue() slog.Value {
+	return slog.GroupValue(
+		slog.String("source_database", string(r.SourceDatabase)),
+		slog.String("format", string(r.Format)),
+	)
+}
+
+type ReferenceExportForm struct {
+	model.ReferenceFilter
+	Format citation.Format `json:"format" form:"format" validate:"required,oneof=bibtex csljson"`
+}
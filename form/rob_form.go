@@ -0,0 +1,16 @@
+package form
+
+import "golang.org/x/exp/slog"
+
+// RobAssessmentForm carries what goes with the answers to the signalling
+// questions, which are read against the questions of the tool.
+type RobAssessmentForm struct {
+	Weight float64 `json:"weight" form:"weight" validate:"gte=0"`
+	Note   string  `json:"note" form:"note" validate:"max=2000"`
+}
+
+func (r RobAssessmentForm) LogValue() slog.Value {
+	return slog.GroupValue(
+		slog.Float64("weight", r.Weight),
+	)
+}
@@ -0,0 +1,54 @@
+package form
+
+import (
+	"golang.org/x/exp/slog"
+	"sci-review/model"
+)
+
+type ScreeningDecisionForm struct {
+	Stage             model.ScreeningStage `json:"stage" form:"stage" validate:"required,oneof=TitleAbstract FullText"`
+	Decision          model.Decision       `json:"decision" form:"decision" validate:"required,oneof=Include Exclude Maybe"`
+	ExclusionReasonId string               `json:"exclusion_reason_id" form:"exclusion_reason_id" validate:"omitempty,uuid"`
+	Note              string               `json:"note" form:"note" validate:"max=2000"`
+}
+
+func (s ScreeningDecisionForm) LogValue() slog.Value {
+	return slog.GroupValue(
+		slog.String("stage", string(s.Stage)),
+		slog.String("decision", string(s.Decision)),
+	)
+}
+
+type ScreeningAdjudicationForm struct {
+	Stage             model.ScreeningStage `json:"stage" form:"stage" validate:"required,oneof=TitleAbstract FullText"`
+	Decision          model.Decision       `json:"decision" form:"decision" validate:"required,oneof=Include Exclude"`
+	ExclusionReasonId string               `json:"exclusion_reason_id" form:"exclusion_reason_id" validate:"omitempty,uuid"`
+	Note              string               `json:"note" form:"note" validate:"max=2000"`
+}
+
+func (s ScreeningAdjudicationForm) LogValue() slog.Value {
+	return slog.GroupValue(
+		slog.String("stage", string(s.Stage)),
+		slog.String("decision", string(s.Decision)),
+	)
+}
+
+type AdjudicatorForm struct {
+	ReviewerId string `json:"reviewer_id" form:"reviewer_id" validate:"required,uuid"`
+}
+
+func (a AdjudicatorForm) LogValue() slog.Value {
+	return slog.GroupValue(
+		slog.String("reviewer_id", a.ReviewerId),
+	)
+}
+
+type RetrievalForm struct {
+	NotRetrieved bool `json:"not_retrieved" form:"not_retrieved"`
+}
+
+func (r RetrievalForm) LogValue() slog.Value {
+	return slog.GroupValue(
+		slog.Bool("not_retrieved", r.NotRetrieved),
+	)
+}
@@ -0,0 +1,113 @@
+// Package grade rates the certainty of evidence with GRADE and lays out the
+// Summary of Findings table.
+package grade
+
+import (
+	"sci-review/common"
+	"sci-review/model"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Factor is a reason to rate the certainty of the evidence down, or, for
+// observational studies, up, by at most Max levels.
+type Factor struct {
+	Id      model.GradeFactor
+	Name    string
+	Upgrade bool
+	Max     int
+}
+
+var Factors = []Factor{
+	{Id: model.FactorRiskOfBias, Name: "Risk of bias", Max: 2},
+	{Id: model.FactorInconsistency, Name: "Inconsistency", Max: 2},
+	{Id: model.FactorIndirectness, Name: "Indirectness", Max: 2},
+	{Id: model.FactorImprecision, Name: "Imprecision", Max: 2},
+	{Id: model.FactorPublicationBias, Name: "Publication bias", Max: 2},
+	{Id: model.FactorLargeEffect, Name: "Large effect", Upgrade: true, Max: 2},
+	{Id: model.FactorDoseResponse, Name: "Dose-response gradient", Upgrade: true, Max: 1},
+	{Id: model.FactorConfounding, Name: "Plausible confounding", Upgrade: true, Max: 1},
+}
+
+// Levels lists the levels a factor can be rated, from none.
+func (f Factor) Levels() []int {
+	levels := make([]int, f.Max+1)
+	for i := range levels {
+		levels[i] = i
+	}
+	return levels
+}
+
+// Certainties lists the certainty levels from the lowest.
+var Certainties = []model.Certainty{model.CertaintyVeryLow, model.CertaintyLow, model.CertaintyModerate, model.CertaintyHigh}
+
+// Start is the certainty the evidence starts from: high for randomized
+// trials, low for observational studies.
+func Start(design model.StudyDesign) model.Certainty {
+	if design == model.DesignRandomized {
+		return model.CertaintyHigh
+	}
+	return model.CertaintyLow
+}
+
+// Rate moves the certainty down by the levels of the downgrading factors and
+// up by those of the upgrading ones, within very low and high.
+func Rate(assessment model.GradeAssessment) model.Certainty {
+	level := slices.Index(Certainties, Start(assessment.Design))
+	for _, factor := range Factors {
+		if factor.Upgrade {
+			level += assessment.Level(factor.Id)
+		} else {
+			level -= assessment.Level(factor.Id)
+		}
+	}
+	level = max(0, min(level, len(Certainties)-1))
+	return Certainties[level]
+}
+
+// Check tells which factors are rated beyond their levels, and which are
+// upgrades of evidence from randomized trials, that GRADE does not rate up.
+func Check(assessment model.GradeAssessment) []common.ErrorResponse {
+	errs := []common.ErrorResponse{}
+	for _, factor := range Factors {
+		level := assessment.Level(factor.Id)
+		switch {
+		case level < 0:
+			errs = append(errs, common.Message(factor.Name, "gte", "0"))
+		case level > factor.Max:
+			errs = append(errs, common.Message(factor.Name, "lte", strconv.Itoa(factor.Max)))
+		case level > 0 && factor.Upgrade && assessment.Design != model.DesignObservational:
+			errs = append(errs, common.Message(factor.Name, "eq", "0 for randomized trials"))
+		}
+	}
+	return errs
+}
+
+var levelWords = []string{"", "one level", "two levels"}
+
+// Explain returns the footnote of a factor: the one written by the reviewers
+// or, when they wrote none for a factor that changed the rating, a default
+// one. It is empty when there is nothing to explain.
+func Explain(assessment model.GradeAssessment, factor Factor) string {
+	note := strings.TrimSpace(assessment.Footnotes[string(factor.Id)])
+	level := assessment.Level(factor.Id)
+	if note != "" || level <= 0 || level >= len(levelWords) {
+		return note
+	}
+	if factor.Upgrade {
+		return "Upgraded " + levelWords[level] + " for " + strings.ToLower(factor.Name) + "."
+	}
+	seriousness := "serious"
+	if level == 2 {
+		seriousness = "very serious"
+	}
+	return "Downgraded " + levelWords[level] + " for " + seriousness + " " + strings.ToLower(factor.Name) + "."
+}
+
+// Symbols returns the GRADE symbols of a certainty, such as ⊕⊕⊕◯ for
+// moderate.
+func Symbols(certainty model.Certainty) string {
+	level := slices.Index(Certainties, certainty) + 1
+	return strings.Repeat("⊕", level) + strings.Repeat("◯", len(Certainties)-level)
+}
@@ -0,0 +1,162 @@
+package grade
+
+import (
+	"bufio"
+	"encoding/csv"
+	"fmt"
+	"html"
+	"io"
+	"sci-review/model"
+	"strconv"
+	"strings"
+)
+
+// Row is an outcome of the Summary of Findings table with the numbers of the
+// footnotes explaining its certainty.
+type Row struct {
+	Assessment model.GradeAssessment
+	Footnotes  []int
+}
+
+// Table is a Summary of Findings table. Footnotes are numbered from one in
+// the order they first appear; rows sharing an explanation share its number.
+type Table struct {
+	Title     string
+	Rows      []Row
+	Footnotes []string
+}
+
+func NewTable(title string, assessments []model.GradeAssessment) Table {
+	table := Table{Title: title, Rows: []Row{}, Footnotes: []string{}}
+	numbers := make(map[string]int)
+	for _, assessment := range assessments {
+		row := Row{Assessment: assessment, Footnotes: []int{}}
+		for _, factor := range Factors {
+			note := Explain(assessment, factor)
+			if note == "" {
+				continue
+			}
+			if _, found := numbers[note]; !found {
+				table.Footnotes = append(table.Footnotes, note)
+				numbers[note] = len(table.Footnotes)
+			}
+			row.Footnotes = append(row.Footnotes, numbers[note])
+		}
+		table.Rows = append(table.Rows, row)
+	}
+	return table
+}
+
+func participants(assessment model.GradeAssessment) string {
+	return fmt.Sprintf("%d (%d %s)", assessment.Participants, assessment.Studies, studies(assessment))
+}
+
+func studies(assessment model.GradeAssessment) string {
+	switch {
+	case assessment.Design == model.DesignRandomized && assessment.Studies == 1:
+		return "RCT"
+	case assessment.Design == model.DesignRandomized:
+		return "RCTs"
+	case assessment.Studies == 1:
+		return "observational study"
+	}
+	return "observational studies"
+}
+
+func joinNumbers(numbers []int) string {
+	texts := make([]string, len(numbers))
+	for i, number := range numbers {
+		texts[i] = strconv.Itoa(number)
+	}
+	return strings.Join(texts, ",")
+}
+
+var csvHeader = []string{
+	"Outcome", "Importance", "Risk with comparator", "Risk with intervention", "Relative effect (95% CI)",
+	"Participants", "Studies", "Design", "Certainty", "Explanations", "Comments",
+}
+
+// WriteCsv writes a line per outcome with the explanations of its certainty
+// in full, as a spreadsheet has no room for footnotes.
+func WriteCsv(w io.Writer, table Table) error {
+	out := csv.NewWriter(w)
+	if err := out.Write(csvHeader); err != nil {
+		return err
+	}
+	for _, row := range table.Rows {
+		assessment := row.Assessment
+		explanations := make([]string, len(row.Footnotes))
+		for i, number := range row.Footnotes {
+			explanations[i] = table.Footnotes[number-1]
+		}
+		record := []string{
+			assessment.Outcome,
+			string(assessment.Importance),
+			assessment.ComparatorRisk,
+			assessment.InterventionRisk,
+			assessment.RelativeEffect,
+			strconv.Itoa(assessment.Participants),
+			strconv.Itoa(assessment.Studies),
+			string(assessment.Design),
+			string(assessment.Certainty),
+			strings.Join(explanations, " "),
+			assessment.Comments,
+		}
+		if err := out.Write(record); err != nil {
+			return err
+		}
+	}
+	out.Flush()
+	return out.Error()
+}
+
+// WriteTable writes the table and its footnotes as an HTML fragment.
+func WriteTable(w io.Writer, table Table) error {
+	out := bufio.NewWriter(w)
+	escape := html.EscapeString
+
+	fmt.Fprint(out, `<table class="sof">`+"\n")
+	fmt.Fprint(out, `<thead><tr><th rowspan="2">Outcomes</th><th colspan="2">Anticipated absolute effects (95% CI)</th>`)
+	fmt.Fprint(out, `<th rowspan="2">Relative effect (95% CI)</th><th rowspan="2">№ of participants (studies)</th>`)
+	fmt.Fprint(out, `<th rowspan="2">Certainty of the evidence (GRADE)</th><th rowspan="2">Comments</th></tr>`+"\n")
+	fmt.Fprint(out, `<tr><th>Risk with comparator</th><th>Risk with intervention</th></tr></thead>`+"\n<tbody>\n")
+	for _, row := range table.Rows {
+		assessment := row.Assessment
+		certainty := Symbols(assessment.Certainty) + " " + escape(string(assessment.Certainty))
+		if len(row.Footnotes) > 0 {
+			certainty += "<sup>" + joinNumbers(row.Footnotes) + "</sup>"
+		}
+		fmt.Fprintf(out, "<tr><td>%s<br><small>%s</small></td><td>%s</td><td>%s</td><td>%s</td><td>%s</td><td>%s</td><td>%s</td></tr>\n",
+			escape(assessment.Outcome), escape(string(assessment.Importance)),
+			escape(assessment.ComparatorRisk), escape(assessment.InterventionRisk), escape(assessment.RelativeEffect),
+			escape(participants(assessment)), certainty, escape(assessment.Comments))
+	}
+	fmt.Fprint(out, "</tbody>\n</table>\n")
+
+	if len(table.Footnotes) > 0 {
+		fmt.Fprint(out, `<ol class="sof-footnotes">`+"\n")
+		for _, note := range table.Footnotes {
+			fmt.Fprintf(out, "<li>%s</li>\n", escape(note))
+		}
+		fmt.Fprint(out, "</ol>\n")
+	}
+	return out.Flush()
+}
+
+const documentStyle = `body { font-family: sans-serif; font-size: 14px; }
+table.sof { border-collapse: collapse; }
+table.sof th, table.sof td { border: 1px solid #999; padding: 4px 8px; vertical-align: top; }
+table.sof th { background: #eee; }`
+
+// WriteHtml writes the table as a standalone HTML document.
+func WriteHtml(w io.Writer, table Table) error {
+	title := html.EscapeString("Summary of findings: " + table.Title)
+	if _, err := fmt.Fprintf(w, "<!DOCTYPE html>\n<html>\n<head>\n<meta charset=\"utf-8\">\n<title>%s</title>\n<style>\n%s\n</style>\n</head>\n<body>\n<h1>%s</h1>\n", title, documentStyle, title); err != nil {
+		return err
+	}
+	if err := WriteTable(w, table); err != nil {
+		return err
+	}
+	_, err := fmt.Fprint(w, "</body>\n</html>\n")
+	return err
+}
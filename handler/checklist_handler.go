@@ -0,0 +1,358 @@
+package handler
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"golang.org/x/exp/slog"
+	"io"
+	"net/url"
+	"sci-review/checklist"
+	"sci-review/common"
+	"sci-review/form"
+	"sci-review/model"
+	"sci-review/service"
+	"strings"
+)
+
+type ChecklistHandler struct {
+	ChecklistService *service.ChecklistService
+}
+
+func NewChecklistHandler(checklistService *service.ChecklistService) *ChecklistHandler {
+	return &ChecklistHandler{ChecklistService: checklistService}
+}
+
+func organizationChecklistsUrl(organizationId uuid.UUID) string {
+	return "/organizations/" + organizationId.String() + "/checklists"
+}
+
+func appraisalUrl(review *model.Review, path string, template *model.ChecklistTemplate) string {
+	return "/reviews/" + review.Id.String() + "/appraisal" + path + "?template=" + template.Id.String()
+}
+
+func (ch *ChecklistHandler) renderOrganization(c *gin.Context, status int, pageData common.PageData, organizationId uuid.UUID, importForm *form.ChecklistImportForm) {
+	principal := c.MustGet("principal").(*model.Principal)
+
+	organization, templates, err := ch.ChecklistService.OrganizationTemplates(organizationId, principal.Id)
+	if err != nil {
+		c.Redirect(302, "/organizations")
+		return
+	}
+
+	c.HTML(status, "checklists/organization.html", gin.H{
+		"pageData":     pageData,
+		"organization": organization,
+		"owner":        organization.IsOwner(principal.Id),
+		"templates":    templates,
+		"importForm":   importForm,
+	})
+}
+
+func (ch *ChecklistHandler) Organization(c *gin.Context) {
+	pageData := common.PageData{
+		Title:   "Appraisal checklists",
+		Active:  "organizations",
+		User:    c.MustGet("principal").(*model.Principal),
+		Message: c.Query("message"),
+	}
+
+	organizationId, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.Redirect(302, "/organizations")
+		return
+	}
+
+	ch.renderOrganization(c, 200, pageData, organizationId, &form.ChecklistImportForm{})
+}
+
+func (ch *ChecklistHandler) Import(c *gin.Context) {
+	principal := c.MustGet("principal").(*model.Principal)
+	pageData := common.PageData{
+		Title:  "Appraisal checklists",
+		Active: "organizations",
+		User:   principal,
+	}
+
+	organizationId, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.Redirect(302, "/organizations")
+		return
+	}
+
+	importForm := new(form.ChecklistImportForm)
+	if err := c.ShouldBind(&importForm); err != nil {
+		slog.Warn("checklist import", "error", err.Error())
+		pageData.Message = "Invalid form data"
+		ch.renderOrganization(c, 400, pageData, organizationId, importForm)
+		return
+	}
+
+	// An uploaded file takes the place of the pasted definition.
+	if fileHeader, err := c.FormFile("file"); err == nil {
+		file, err := fileHeader.Open()
+		if err != nil {
+			slog.Error("checklist import", "error", err.Error())
+			pageData.Message = "Could not read the uploaded file"
+			ch.renderOrganization(c, 400, pageData, organizationId, importForm)
+			return
+		}
+		defer file.Close()
+		content, err := io.ReadAll(io.LimitReader(file, 100001))
+		if err != nil {
+			slog.Error("checklist import", "error", err.Error())
+			pageData.Message = "Could not read the uploaded file"
+			ch.renderOrganization(c, 400, pageData, organizationId, importForm)
+			return
+		}
+		importForm.Definition = string(content)
+	}
+	slog.Info("checklist import", "data", importForm)
+
+	if err := common.Validate(importForm); len(err) > 0 {
+		slog.Warn("checklist import", "error", "validation error")
+		pageData.Errors = err
+		ch.renderOrganization(c, 400, pageData, organizationId, importForm)
+		return
+	}
+
+	definition, errs := checklist.Parse(importForm.Definition)
+	if len(errs) > 0 {
+		slog.Warn("checklist import", "error", "definition error")
+		pageData.Errors = errs
+		ch.renderOrganization(c, 400, pageData, organizationId, importForm)
+		return
+	}
+
+	template, err := ch.ChecklistService.Import(organizationId, principal.Id, definition)
+	if err != nil {
+		pageData.Message = err.Error()
+		ch.renderOrganization(c, 409, pageData, organizationId, importForm)
+		return
+	}
+
+	c.Redirect(302, organizationChecklistsUrl(organizationId)+"?message="+url.QueryEscape(template.Name+" imported"))
+}
+
+func (ch *ChecklistHandler) Archive(c *gin.Context) {
+	principal := c.MustGet("principal").(*model.Principal)
+
+	organizationId, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.Redirect(302, "/organizations")
+		return
+	}
+
+	templateId, err := uuid.Parse(c.Param("templateId"))
+	if err != nil {
+		c.Redirect(302, organizationChecklistsUrl(organizationId))
+		return
+	}
+
+	message := "Checklist archived"
+	if err := ch.ChecklistService.Archive(organizationId, templateId, principal.Id); err != nil {
+		message = err.Error()
+	}
+
+	c.Redirect(302, organizationChecklistsUrl(organizationId)+"?message="+url.QueryEscape(message))
+}
+
+// findTemplate reads the template from the query string, falling back to
+// the first template available to the user.
+func (ch *ChecklistHandler) findTemplate(c *gin.Context) (*model.ChecklistTemplate, error) {
+	principal := c.MustGet("principal").(*model.Principal)
+
+	var templateId *uuid.UUID
+	if id, err := uuid.Parse(c.Query("template")); err == nil {
+		templateId = &id
+	}
+	return ch.ChecklistService.FindTemplate(templateId, principal.Id)
+}
+
+func (ch *ChecklistHandler) Index(c *gin.Context) {
+	principal := c.MustGet("principal").(*model.Principal)
+	review := c.MustGet("review").(*model.Review)
+	pageData := common.PageData{
+		Title:   "Critical appraisal",
+		Active:  "reviews",
+		User:    principal,
+		Message: c.Query("message"),
+	}
+
+	templates, err := ch.ChecklistService.Available(principal.Id)
+	if err != nil {
+		pageData.Message = err.Error()
+	}
+
+	studies := []model.ChecklistStudy{}
+	template, err := ch.findTemplate(c)
+	if err != nil {
+		pageData.Message = err.Error()
+	} else if studies, err = ch.ChecklistService.Studies(review, template); err != nil {
+		pageData.Message = err.Error()
+	}
+
+	c.HTML(200, "checklists/index.html", gin.H{
+		"pageData":  pageData,
+		"review":    review,
+		"template":  template,
+		"templates": templates,
+		"studies":   studies,
+	})
+}
+
+func (ch *ChecklistHandler) renderRecord(c *gin.Context, status int, pageData common.PageData, reference *model.Reference, template *model.ChecklistTemplate, answers map[string]string, appraisal *model.ChecklistAppraisal, appraisalForm *form.ChecklistAppraisalForm) {
+	review := c.MustGet("review").(*model.Review)
+
+	c.HTML(status, "checklists/record.html", gin.H{
+		"pageData":      pageData,
+		"review":        review,
+		"reference":     reference,
+		"template":      template,
+		"sections":      checklist.Sections(template.Definition),
+		"answers":       answers,
+		"appraisal":     appraisal,
+		"appraisalForm": appraisalForm,
+	})
+}
+
+func (ch *ChecklistHandler) Show(c *gin.Context) {
+	principal := c.MustGet("principal").(*model.Principal)
+	review := c.MustGet("review").(*model.Review)
+	reviewer := c.MustGet("reviewer").(*model.Reviewer)
+	pageData := common.PageData{
+		Title:   "Appraise study",
+		Active:  "reviews",
+		User:    principal,
+		Message: c.Query("message"),
+	}
+
+	template, err := ch.findTemplate(c)
+	if err != nil {
+		c.Redirect(302, "/reviews/"+review.Id.String()+"/appraisal?message="+url.QueryEscape(err.Error()))
+		return
+	}
+
+	referenceId, err := uuid.Parse(c.Param("referenceId"))
+	if err != nil {
+		c.Redirect(302, appraisalUrl(review, "", template))
+		return
+	}
+
+	reference, err := ch.ChecklistService.FindStudy(review, referenceId)
+	if err != nil {
+		c.Redirect(302, appraisalUrl(review, "", template)+"&message="+url.QueryEscape(err.Error()))
+		return
+	}
+
+	appraisal, err := ch.ChecklistService.FindAppraisal(referenceId, template, reviewer)
+	if err != nil {
+		pageData.Message = err.Error()
+	}
+
+	answers := map[string]string{}
+	appraisalForm := &form.ChecklistAppraisalForm{}
+	if appraisal != nil {
+		answers = appraisal.Answers
+		appraisalForm.Note = appraisal.Note
+	}
+
+	ch.renderRecord(c, 200, pageData, reference, template, answers, appraisal, appraisalForm)
+}
+
+func (ch *ChecklistHandler) Save(c *gin.Context) {
+	principal := c.MustGet("principal").(*model.Principal)
+	review := c.MustGet("review").(*model.Review)
+	reviewer := c.MustGet("reviewer").(*model.Reviewer)
+	pageData := common.PageData{
+		Title:  "Appraise study",
+		Active: "reviews",
+		User:   principal,
+	}
+
+	template, err := ch.findTemplate(c)
+	if err != nil {
+		c.Redirect(302, "/reviews/"+review.Id.String()+"/appraisal?message="+url.QueryEscape(err.Error()))
+		return
+	}
+
+	referenceId, err := uuid.Parse(c.Param("referenceId"))
+	if err != nil {
+		c.Redirect(302, appraisalUrl(review, "", template))
+		return
+	}
+
+	reference, err := ch.ChecklistService.FindStudy(review, referenceId)
+	if err != nil {
+		c.Redirect(302, appraisalUrl(review, "", template)+"&message="+url.QueryEscape(err.Error()))
+		return
+	}
+
+	appraisalForm := new(form.ChecklistAppraisalForm)
+	if err := c.ShouldBind(&appraisalForm); err != nil {
+		slog.Warn("checklist save", "error", err.Error())
+		pageData.Message = "Invalid form data"
+		ch.renderRecord(c, 400, pageData, reference, template, map[string]string{}, nil, appraisalForm)
+		return
+	}
+	slog.Info("checklist save", "data", appraisalForm)
+
+	answers := map[string]string{}
+	for input, values := range c.Request.PostForm {
+		if id, found := strings.CutPrefix(input, "q."); found && len(values) > 0 {
+			answers[id] = values[0]
+		}
+	}
+
+	errs := common.Validate(appraisalForm)
+	for _, question := range checklist.Unanswered(template.Definition, answers) {
+		errs = append(errs, common.Message("Question "+question.Id, "required", ""))
+	}
+	if len(errs) > 0 {
+		slog.Warn("checklist save", "error", "validation error")
+		pageData.Errors = errs
+		ch.renderRecord(c, 400, pageData, reference, template, answers, nil, appraisalForm)
+		return
+	}
+
+	if _, err := ch.ChecklistService.Save(review, reviewer, referenceId, template, answers, *appraisalForm); err != nil {
+		pageData.Message = err.Error()
+		ch.renderRecord(c, 409, pageData, reference, template, answers, nil, appraisalForm)
+		return
+	}
+
+	c.Redirect(302, appraisalUrl(review, "/"+referenceId.String(), template)+"&message="+url.QueryEscape("Appraisal saved"))
+}
+
+func RegisterChecklistHandler(
+	r *gin.Engine,
+	checklistService *service.ChecklistService,
+	authMiddleware gin.HandlerFunc,
+	reviewMiddleware gin.HandlerFunc,
+	reviewerMiddleware gin.HandlerFunc,
+) {
+	checklistHandler := NewChecklistHandler(checklistService)
+	r.GET("/organizations/:id/checklists", authMiddleware, checklistHandler.Organization)
+	r.POST("/organizations/:id/checklists", authMiddleware, checklistHandler.Import)
+	r.POST("/organizations/:id/checklists/:templateId/archive", authMiddleware, checklistHandler.Archive)
+	r.GET(
+		"/reviews/:reviewId/appraisal",
+		authMiddleware,
+		reviewMiddleware,
+		reviewerMiddleware,
+		checklistHandler.Index,
+	)
+	r.GET(
+		"/reviews/:reviewId/appraisal/:referenceId",
+		authMiddleware,
+		reviewMiddleware,
+		reviewerMiddleware,
+		checklistHandler.Show,
+	)
+	r.POST(
+		"/reviews/:reviewId/appraisal/:referenceId",
+		authMiddleware,
+		reviewMiddleware,
+		reviewerMiddleware,
+		checklistHandler.Save,
+	)
+}
@@ -0,0 +1,188 @@
+package handler
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"golang.org/x/exp/slog"
+	"sci-review/common"
+	"sci-review/form"
+	"sci-review/model"
+	"sci-review/service"
+)
+
+type DuplicateHandler struct {
+	DeduplicationService *service.DeduplicationService
+}
+
+func NewDuplicateHandler(deduplicationService *service.DeduplicationService) *DuplicateHandler {
+	return &DuplicateHandler{DeduplicationService: deduplicationService}
+}
+
+func (dh *DuplicateHandler) render(c *gin.Context, status int, pageData common.PageData, detectForm *form.DuplicateDetectForm) {
+	review := c.MustGet("review").(*model.Review)
+
+	clusters, err := dh.DeduplicationService.FindProposed(review.Id)
+	if err != nil {
+		pageData.Message = err.Error()
+		status = 500
+	}
+
+	removed, err := dh.DeduplicationService.DuplicatesRemoved(review.Id)
+	if err != nil {
+		pageData.Message = err.Error()
+		status = 500
+	}
+
+	c.HTML(status, "duplicates/index.html", gin.H{
+		"pageData":   pageData,
+		"review":     review,
+		"clusters":   clusters,
+		"removed":    removed,
+		"detectForm": detectForm,
+	})
+}
+
+func (dh *DuplicateHandler) Index(c *gin.Context) {
+	principal := c.MustGet("principal").(*model.Principal)
+	pageData := common.PageData{
+		Title:  "Duplicates",
+		Active: "reviews",
+		User:   principal,
+	}
+	dh.render(c, 200, pageData, &form.DuplicateDetectForm{Threshold: service.DefaultDuplicateThreshold})
+}
+
+func (dh *DuplicateHandler) Detect(c *gin.Context) {
+	principal := c.MustGet("principal").(*model.Principal)
+	review := c.MustGet("review").(*model.Review)
+	pageData := common.PageData{
+		Title:  "Duplicates",
+		Active: "reviews",
+		User:   principal,
+	}
+
+	detectForm := new(form.DuplicateDetectForm)
+	if err := c.ShouldBind(&detectForm); err != nil {
+		slog.Warn("duplicate detect", "error", err.Error())
+		pageData.Message = "Invalid form data"
+		dh.render(c, 400, pageData, detectForm)
+		return
+	}
+	slog.Info("duplicate detect", "data", detectForm)
+
+	if err := common.Validate(detectForm); len(err) > 0 {
+		slog.Warn("duplicate detect", "error", "validation error")
+		pageData.Errors = err
+		dh.render(c, 400, pageData, detectForm)
+		return
+	}
+
+	if _, err := dh.DeduplicationService.Detect(review.Id, *detectForm); err != nil {
+		pageData.Message = err.Error()
+		dh.render(c, 500, pageData, detectForm)
+		return
+	}
+
+	c.Redirect(302, "/reviews/"+review.Id.String()+"/duplicates")
+}
+
+func (dh *DuplicateHandler) Confirm(c *gin.Context) {
+	principal := c.MustGet("principal").(*model.Principal)
+	review := c.MustGet("review").(*model.Review)
+	pageData := common.PageData{
+		Title:  "Duplicates",
+		Active: "reviews",
+		User:   principal,
+	}
+	defaultForm := &form.DuplicateDetectForm{Threshold: service.DefaultDuplicateThreshold}
+
+	clusterId, err := uuid.Parse(c.Param("clusterId"))
+	if err != nil {
+		c.Redirect(302, "/reviews/"+review.Id.String()+"/duplicates")
+		return
+	}
+
+	confirmForm := new(form.DuplicateConfirmForm)
+	if err := c.ShouldBind(&confirmForm); err != nil {
+		slog.Warn("duplicate confirm", "error", err.Error())
+		pageData.Message = "Invalid form data"
+		dh.render(c, 400, pageData, defaultForm)
+		return
+	}
+
+	if err := common.Validate(confirmForm); len(err) > 0 {
+		slog.Warn("duplicate confirm", "error", "validation error")
+		pageData.Errors = err
+		dh.render(c, 400, pageData, defaultForm)
+		return
+	}
+
+	if err := dh.DeduplicationService.Confirm(clusterId, review.Id, principal.Id, *confirmForm); err != nil {
+		pageData.Message = err.Error()
+		dh.render(c, 409, pageData, defaultForm)
+		return
+	}
+
+	c.Redirect(302, "/reviews/"+review.Id.String()+"/duplicates")
+}
+
+func (dh *DuplicateHandler) Reject(c *gin.Context) {
+	principal := c.MustGet("principal").(*model.Principal)
+	review := c.MustGet("review").(*model.Review)
+	pageData := common.PageData{
+		Title:  "Duplicates",
+		Active: "reviews",
+		User:   principal,
+	}
+
+	clusterId, err := uuid.Parse(c.Param("clusterId"))
+	if err != nil {
+		c.Redirect(302, "/reviews/"+review.Id.String()+"/duplicates")
+		return
+	}
+
+	if err := dh.DeduplicationService.Reject(clusterId, review.Id, principal.Id); err != nil {
+		pageData.Message = err.Error()
+		dh.render(c, 409, pageData, &form.DuplicateDetectForm{Threshold: service.DefaultDuplicateThreshold})
+		return
+	}
+
+	c.Redirect(302, "/reviews/"+review.Id.String()+"/duplicates")
+}
+
+func RegisterDuplicateHandler(
+	r *gin.Engine,
+	deduplicationService *service.DeduplicationService,
+	authMiddleware gin.HandlerFunc,
+	reviewMiddleware gin.HandlerFunc,
+	reviewerMiddleware gin.HandlerFunc,
+) {
+	duplicateHandler := NewDuplicateHandler(deduplicationService)
+	r.GET(
+		"/reviews/:reviewId/duplicates",
+		authMiddleware,
+		reviewMiddleware,
+		duplicateHandler.Index,
+	)
+	r.POST(
+		"/reviews/:reviewId/duplicates/detect",
+		authMiddleware,
+		reviewMiddleware,
+		reviewerMiddleware,
+		duplicateHandler.Detect,
+	)
+	r.POST(
+		"/reviews/:reviewId/duplicates/:clusterId/confirm",
+		authMiddleware,
+		reviewMiddleware,
+		reviewerMiddleware,
+		duplicateHandler.Confirm,
+	)
+	r.POST(
+		"/reviews/:reviewId/duplicates/:clusterId/reject",
+		authMiddleware,
+		reviewMiddleware,
+		reviewerMiddleware,
+		duplicateHandler.Reject,
+	)
+}
@@ -0,0 +1,188 @@
+package handler
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"golang.org/x/exp/slog"
+	"net/url"
+	"sci-review/common"
+	"sci-review/form"
+	"sci-review/model"
+	"sci-review/service"
+	"strconv"
+)
+
+type ExclusionReasonHandler struct {
+	ExclusionReasonService *service.ExclusionReasonService
+}
+
+func NewExclusionReasonHandler(exclusionReasonService *service.ExclusionReasonService) *ExclusionReasonHandler {
+	return &ExclusionReasonHandler{ExclusionReasonService: exclusionReasonService}
+}
+
+func reasonsUrl(review *model.Review, message string) string {
+	location := "/reviews/" + review.Id.String() + "/screening/reasons"
+	if message != "" {
+		location += "?message=" + url.QueryEscape(message)
+	}
+	return location
+}
+
+func (eh *ExclusionReasonHandler) Index(c *gin.Context) {
+	principal := c.MustGet("principal").(*model.Principal)
+	review := c.MustGet("review").(*model.Review)
+	reviewer := c.MustGet("reviewer").(*model.Reviewer)
+
+	pageData := common.PageData{
+		Title:   "Exclusion reasons",
+		Active:  "reviews",
+		User:    principal,
+		Message: c.Query("message"),
+	}
+
+	reasons, err := eh.ExclusionReasonService.FindAll(review.Id)
+	if err != nil {
+		pageData.Message = err.Error()
+	}
+
+	c.HTML(200, "screening/reasons.html", gin.H{
+		"pageData": pageData,
+		"review":   review,
+		"reviewer": reviewer,
+		"reasons":  reasons,
+		"next":     strconv.Itoa(len(reasons) + 1),
+	})
+}
+
+func (eh *ExclusionReasonHandler) bind(c *gin.Context) (*form.ExclusionReasonForm, string) {
+	reasonForm := new(form.ExclusionReasonForm)
+	if err := c.ShouldBind(&reasonForm); err != nil {
+		slog.Warn("exclusion reason", "error", err.Error())
+		return nil, "Invalid form data"
+	}
+	slog.Info("exclusion reason", "data", reasonForm)
+
+	if errs := common.Validate(reasonForm); len(errs) > 0 {
+		slog.Warn("exclusion reason", "error", "validation error")
+		return nil, errs[0].Error
+	}
+	return reasonForm, ""
+}
+
+func (eh *ExclusionReasonHandler) Create(c *gin.Context) {
+	review := c.MustGet("review").(*model.Review)
+	reviewer := c.MustGet("reviewer").(*model.Reviewer)
+
+	reasonForm, message := eh.bind(c)
+	if reasonForm == nil {
+		c.Redirect(302, reasonsUrl(review, message))
+		return
+	}
+
+	if _, err := eh.ExclusionReasonService.Create(review.Id, reviewer, *reasonForm); err != nil {
+		c.Redirect(302, reasonsUrl(review, err.Error()))
+		return
+	}
+
+	c.Redirect(302, reasonsUrl(review, ""))
+}
+
+func (eh *ExclusionReasonHandler) CreateDefaults(c *gin.Context) {
+	review := c.MustGet("review").(*model.Review)
+	reviewer := c.MustGet("reviewer").(*model.Reviewer)
+
+	created, err := eh.ExclusionReasonService.CreateDefaults(review.Id, reviewer)
+	if err != nil {
+		c.Redirect(302, reasonsUrl(review, err.Error()))
+		return
+	}
+
+	c.Redirect(302, reasonsUrl(review, strconv.Itoa(created)+" default reasons added"))
+}
+
+func (eh *ExclusionReasonHandler) Update(c *gin.Context) {
+	review := c.MustGet("review").(*model.Review)
+	reviewer := c.MustGet("reviewer").(*model.Reviewer)
+
+	reasonId, err := uuid.Parse(c.Param("reasonId"))
+	if err != nil {
+		c.Redirect(302, reasonsUrl(review, service.ErrorReasonNotFound.Error()))
+		return
+	}
+
+	reasonForm, message := eh.bind(c)
+	if reasonForm == nil {
+		c.Redirect(302, reasonsUrl(review, message))
+		return
+	}
+
+	if _, err := eh.ExclusionReasonService.Update(reasonId, review.Id, reviewer, *reasonForm); err != nil {
+		c.Redirect(302, reasonsUrl(review, err.Error()))
+		return
+	}
+
+	c.Redirect(302, reasonsUrl(review, ""))
+}
+
+func (eh *ExclusionReasonHandler) Delete(c *gin.Context) {
+	review := c.MustGet("review").(*model.Review)
+	reviewer := c.MustGet("reviewer").(*model.Reviewer)
+
+	reasonId, err := uuid.Parse(c.Param("reasonId"))
+	if err != nil {
+		c.Redirect(302, reasonsUrl(review, service.ErrorReasonNotFound.Error()))
+		return
+	}
+
+	if err := eh.ExclusionReasonService.Delete(reasonId, review.Id, reviewer); err != nil {
+		c.Redirect(302, reasonsUrl(review, err.Error()))
+		return
+	}
+
+	c.Redirect(302, reasonsUrl(review, ""))
+}
+
+func RegisterExclusionReasonHandler(
+	r *gin.Engine,
+	exclusionReasonService *service.ExclusionReasonService,
+	authMiddleware gin.HandlerFunc,
+	reviewMiddleware gin.HandlerFunc,
+	reviewerMiddleware gin.HandlerFunc,
+) {
+	exclusionReasonHandler := NewExclusionReasonHandler(exclusionReasonService)
+	r.GET(
+		"/reviews/:reviewId/screening/reasons",
+		authMiddleware,
+		reviewMiddleware,
+		reviewerMiddleware,
+		exclusionReasonHandler.Index,
+	)
+	r.POST(
+		"/reviews/:reviewId/screening/reasons",
+		authMiddleware,
+		reviewMiddleware,
+		reviewerMiddleware,
+		exclusionReasonHandler.Create,
+	)
+	r.POST(
+		"/reviews/:reviewId/screening/reasons/defaults",
+		authMiddleware,
+		reviewMiddleware,
+		reviewerMiddleware,
+		exclusionReasonHandler.CreateDefaults,
+	)
+	r.POST(
+		"/reviews/:reviewId/screening/reasons/:reasonId",
+		authMiddleware,
+		reviewMiddleware,
+		reviewerMiddleware,
+		exclusionReasonHandler.Update,
+	)
+	r.POST(
+		"/reviews/:reviewId/screening/reasons/:reasonId/delete",
+		authMiddleware,
+		reviewMiddleware,
+		reviewerMiddleware,
+		exclusionReasonHandler.Delete,
+	)
+}
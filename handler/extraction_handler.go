@@ -0,0 +1,448 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"golang.org/x/exp/slog"
+	"net/url"
+	"sci-review/common"
+	"sci-review/extraction"
+	"sci-review/form"
+	"sci-review/model"
+	"sci-review/service"
+	"strconv"
+)
+
+type ExtractionHandler struct {
+	ExtractionService *service.ExtractionService
+}
+
+func NewExtractionHandler(extractionService *service.ExtractionService) *ExtractionHandler {
+	return &ExtractionHandler{ExtractionService: extractionService}
+}
+
+func extractionUrl(review *model.Review, path string) string {
+	return "/reviews/" + review.Id.String() + "/extraction" + path
+}
+
+func (eh *ExtractionHandler) Index(c *gin.Context) {
+	principal := c.MustGet("principal").(*model.Principal)
+	review := c.MustGet("review").(*model.Review)
+	reviewer := c.MustGet("reviewer").(*model.Reviewer)
+	pageData := common.PageData{
+		Title:   "Data extraction",
+		Active:  "reviews",
+		User:    principal,
+		Message: c.Query("message"),
+	}
+
+	extractionForm, err := eh.ExtractionService.FindLatestForm(review.Id)
+	if err != nil && !errors.Is(err, service.ErrorNoExtractionForm) {
+		pageData.Message = err.Error()
+	}
+
+	studies, err := eh.ExtractionService.Studies(review)
+	if err != nil {
+		pageData.Message = err.Error()
+	}
+
+	c.HTML(200, "extraction/index.html", gin.H{
+		"pageData":       pageData,
+		"review":         review,
+		"reviewer":       reviewer,
+		"extractionForm": extractionForm,
+		"studies":        studies,
+	})
+}
+
+func (eh *ExtractionHandler) renderBuilder(c *gin.Context, status int, pageData common.PageData, schemaForm *form.ExtractionSchemaForm) {
+	review := c.MustGet("review").(*model.Review)
+	reviewer := c.MustGet("reviewer").(*model.Reviewer)
+
+	forms, err := eh.ExtractionService.FindForms(review.Id)
+	if err != nil {
+		pageData.Message = err.Error()
+		status = 500
+	}
+
+	var preview []extraction.Input
+	if len(forms) > 0 {
+		preview = extraction.Inputs(forms[0].Schema, model.ExtractionValues{})
+	}
+
+	c.HTML(status, "extraction/builder.html", gin.H{
+		"pageData":   pageData,
+		"review":     review,
+		"reviewer":   reviewer,
+		"forms":      forms,
+		"preview":    preview,
+		"schemaForm": schemaForm,
+		"fieldTypes": model.FieldTypes,
+	})
+}
+
+func (eh *ExtractionHandler) Builder(c *gin.Context) {
+	principal := c.MustGet("principal").(*model.Principal)
+	review := c.MustGet("review").(*model.Review)
+	pageData := common.PageData{
+		Title:   "Extraction form",
+		Active:  "reviews",
+		User:    principal,
+		Message: c.Query("message"),
+	}
+
+	schemaForm := &form.ExtractionSchemaForm{Schema: "{\n  \"fields\": []\n}"}
+	extractionForm, err := eh.ExtractionService.FindLatestForm(review.Id)
+	if err == nil {
+		schema, _ := json.MarshalIndent(extractionForm.Schema, "", "  ")
+		schemaForm.Schema = string(schema)
+	}
+
+	eh.renderBuilder(c, 200, pageData, schemaForm)
+}
+
+func (eh *ExtractionHandler) Publish(c *gin.Context) {
+	principal := c.MustGet("principal").(*model.Principal)
+	review := c.MustGet("review").(*model.Review)
+	reviewer := c.MustGet("reviewer").(*model.Reviewer)
+	pageData := common.PageData{
+		Title:  "Extraction form",
+		Active: "reviews",
+		User:   principal,
+	}
+
+	schemaForm := new(form.ExtractionSchemaForm)
+	if err := c.ShouldBind(&schemaForm); err != nil {
+		slog.Warn("extraction publish", "error", err.Error())
+		pageData.Message = "Invalid form data"
+		eh.renderBuilder(c, 400, pageData, schemaForm)
+		return
+	}
+	slog.Info("extraction publish", "data", schemaForm)
+
+	if err := common.Validate(schemaForm); len(err) > 0 {
+		slog.Warn("extraction publish", "error", "validation error")
+		pageData.Errors = err
+		eh.renderBuilder(c, 400, pageData, schemaForm)
+		return
+	}
+
+	schema, errs := extraction.ParseSchema(schemaForm.Schema)
+	if len(errs) > 0 {
+		slog.Warn("extraction publish", "error", "schema error")
+		pageData.Errors = errs
+		eh.renderBuilder(c, 400, pageData, schemaForm)
+		return
+	}
+
+	extractionForm, err := eh.ExtractionService.Publish(review, reviewer, schema)
+	if err != nil {
+		pageData.Message = err.Error()
+		eh.renderBuilder(c, 409, pageData, schemaForm)
+		return
+	}
+
+	c.Redirect(302, extractionUrl(review, "/form")+"?message="+url.QueryEscape("Version "+strconv.Itoa(extractionForm.Version)+" published"))
+}
+
+func (eh *ExtractionHandler) renderRecord(c *gin.Context, status int, pageData common.PageData, reference *model.Reference, extractionForm *model.ExtractionForm, data model.ExtractionValues) {
+	review := c.MustGet("review").(*model.Review)
+
+	c.HTML(status, "extraction/record.html", gin.H{
+		"pageData":       pageData,
+		"review":         review,
+		"reference":      reference,
+		"extractionForm": extractionForm,
+		"inputs":         extraction.Inputs(extractionForm.Schema, data),
+	})
+}
+
+func (eh *ExtractionHandler) Show(c *gin.Context) {
+	principal := c.MustGet("principal").(*model.Principal)
+	review := c.MustGet("review").(*model.Review)
+	reviewer := c.MustGet("reviewer").(*model.Reviewer)
+	pageData := common.PageData{
+		Title:   "Extract data",
+		Active:  "reviews",
+		User:    principal,
+		Message: c.Query("message"),
+	}
+
+	referenceId, err := uuid.Parse(c.Param("referenceId"))
+	if err != nil {
+		c.Redirect(302, extractionUrl(review, ""))
+		return
+	}
+
+	reference, err := eh.ExtractionService.FindStudy(review, referenceId)
+	if err != nil {
+		c.Redirect(302, extractionUrl(review, "")+"?message="+url.QueryEscape(err.Error()))
+		return
+	}
+
+	extractionForm, err := eh.ExtractionService.FindLatestForm(review.Id)
+	if err != nil {
+		c.Redirect(302, extractionUrl(review, "")+"?message="+url.QueryEscape(err.Error()))
+		return
+	}
+
+	data := model.ExtractionValues{}
+	previous, err := eh.ExtractionService.FindExtraction(referenceId, reviewer)
+	if err != nil {
+		pageData.Message = err.Error()
+	}
+	if previous != nil {
+		data = previous.Data
+		if previous.FormId != extractionForm.Id && pageData.Message == "" {
+			pageData.Message = "Answers were carried over from version " + strconv.Itoa(previous.FormVersion) + " of the form; check them and save."
+		}
+	}
+
+	eh.renderRecord(c, 200, pageData, reference, extractionForm, data)
+}
+
+func (eh *ExtractionHandler) Save(c *gin.Context) {
+	principal := c.MustGet("principal").(*model.Principal)
+	review := c.MustGet("review").(*model.Review)
+	reviewer := c.MustGet("reviewer").(*model.Reviewer)
+	pageData := common.PageData{
+		Title:  "Extract data",
+		Active: "reviews",
+		User:   principal,
+	}
+
+	referenceId, err := uuid.Parse(c.Param("referenceId"))
+	if err != nil {
+		c.Redirect(302, extractionUrl(review, ""))
+		return
+	}
+
+	reference, err := eh.ExtractionService.FindStudy(review, referenceId)
+	if err != nil {
+		c.Redirect(302, extractionUrl(review, "")+"?message="+url.QueryEscape(err.Error()))
+		return
+	}
+
+	saveForm := new(form.ExtractionSaveForm)
+	if err := c.ShouldBind(&saveForm); err != nil {
+		slog.Warn("extraction save", "error", err.Error())
+		c.Redirect(302, extractionUrl(review, "/"+referenceId.String())+"?message="+url.QueryEscape("Invalid form data"))
+		return
+	}
+	slog.Info("extraction save", "data", saveForm)
+
+	if err := common.Validate(saveForm); len(err) > 0 {
+		slog.Warn("extraction save", "error", "validation error")
+		c.Redirect(302, extractionUrl(review, "/"+referenceId.String())+"?message="+url.QueryEscape("Invalid form version"))
+		return
+	}
+
+	extractionForm, err := eh.ExtractionService.FindForm(review.Id, uuid.MustParse(saveForm.FormId))
+	if err != nil {
+		c.Redirect(302, extractionUrl(review, "/"+referenceId.String())+"?message="+url.QueryEscape(err.Error()))
+		return
+	}
+
+	data, errs := extraction.Parse(extractionForm.Schema, c.Request.PostForm)
+	if len(errs) > 0 {
+		slog.Warn("extraction save", "error", "validation error")
+		pageData.Errors = errs
+		eh.renderRecord(c, 400, pageData, reference, extractionForm, extraction.Submitted(extractionForm.Schema, c.Request.PostForm))
+		return
+	}
+
+	if _, err := eh.ExtractionService.Save(review, reviewer, referenceId, extractionForm, data); err != nil {
+		pageData.Message = err.Error()
+		eh.renderRecord(c, 409, pageData, reference, extractionForm, data)
+		return
+	}
+
+	c.Redirect(302, extractionUrl(review, "")+"?message="+url.QueryEscape("Extraction saved"))
+}
+
+func (eh *ExtractionHandler) renderComparison(c *gin.Context, status int, pageData common.PageData, comparison *model.ExtractionComparison, data model.ExtractionValues) {
+	review := c.MustGet("review").(*model.Review)
+	reviewer := c.MustGet("reviewer").(*model.Reviewer)
+
+	c.HTML(status, "extraction/compare.html", gin.H{
+		"pageData":   pageData,
+		"review":     review,
+		"comparison": comparison,
+		"reconciler": reviewer.Id != comparison.Left.ReviewerId && reviewer.Id != comparison.Right.ReviewerId,
+		"inputs":     extraction.Inputs(comparison.Form.Schema, data),
+	})
+}
+
+func (eh *ExtractionHandler) Compare(c *gin.Context) {
+	principal := c.MustGet("principal").(*model.Principal)
+	review := c.MustGet("review").(*model.Review)
+	pageData := common.PageData{
+		Title:   "Compare extractions",
+		Active:  "reviews",
+		User:    principal,
+		Message: c.Query("message"),
+	}
+
+	referenceId, err := uuid.Parse(c.Param("referenceId"))
+	if err != nil {
+		c.Redirect(302, extractionUrl(review, ""))
+		return
+	}
+
+	comparison, err := eh.ExtractionService.Compare(review, referenceId)
+	if err != nil {
+		c.Redirect(302, extractionUrl(review, "")+"?message="+url.QueryEscape(err.Error()))
+		return
+	}
+
+	data := extraction.Agreed(comparison.Form.Schema, comparison.Left.Data, comparison.Right.Data)
+	if comparison.Consensus != nil {
+		data = comparison.Consensus.Data
+	}
+
+	eh.renderComparison(c, 200, pageData, comparison, data)
+}
+
+func (eh *ExtractionHandler) Comparison(c *gin.Context) {
+	review := c.MustGet("review").(*model.Review)
+
+	referenceId, err := uuid.Parse(c.Param("referenceId"))
+	if err != nil {
+		c.JSON(404, gin.H{"error": service.ErrorNotIncluded.Error()})
+		return
+	}
+
+	comparison, err := eh.ExtractionService.Compare(review, referenceId)
+	if err != nil {
+		status := 500
+		if errors.Is(err, service.ErrorNotIncluded) || errors.Is(err, service.ErrorNotDual) {
+			status = 404
+		}
+		c.JSON(status, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(200, comparison)
+}
+
+func (eh *ExtractionHandler) Reconcile(c *gin.Context) {
+	principal := c.MustGet("principal").(*model.Principal)
+	review := c.MustGet("review").(*model.Review)
+	reviewer := c.MustGet("reviewer").(*model.Reviewer)
+	pageData := common.PageData{
+		Title:  "Compare extractions",
+		Active: "reviews",
+		User:   principal,
+	}
+
+	referenceId, err := uuid.Parse(c.Param("referenceId"))
+	if err != nil {
+		c.Redirect(302, extractionUrl(review, ""))
+		return
+	}
+	compareUrl := extractionUrl(review, "/"+referenceId.String()+"/compare")
+
+	comparison, err := eh.ExtractionService.Compare(review, referenceId)
+	if err != nil {
+		c.Redirect(302, extractionUrl(review, "")+"?message="+url.QueryEscape(err.Error()))
+		return
+	}
+
+	saveForm := new(form.ExtractionSaveForm)
+	if err := c.ShouldBind(&saveForm); err != nil {
+		slog.Warn("extraction reconcile", "error", err.Error())
+		c.Redirect(302, compareUrl+"?message="+url.QueryEscape("Invalid form data"))
+		return
+	}
+	slog.Info("extraction reconcile", "data", saveForm)
+
+	if err := common.Validate(saveForm); len(err) > 0 {
+		slog.Warn("extraction reconcile", "error", "validation error")
+		c.Redirect(302, compareUrl+"?message="+url.QueryEscape("Invalid form version"))
+		return
+	}
+
+	data, errs := extraction.Parse(comparison.Form.Schema, c.Request.PostForm)
+	if len(errs) > 0 {
+		slog.Warn("extraction reconcile", "error", "validation error")
+		pageData.Errors = errs
+		eh.renderComparison(c, 400, pageData, comparison, extraction.Submitted(comparison.Form.Schema, c.Request.PostForm))
+		return
+	}
+
+	if _, err := eh.ExtractionService.Reconcile(reviewer, comparison, uuid.MustParse(saveForm.FormId), data); err != nil {
+		pageData.Message = err.Error()
+		eh.renderComparison(c, 409, pageData, comparison, data)
+		return
+	}
+
+	c.Redirect(302, compareUrl+"?message="+url.QueryEscape("Consensus saved"))
+}
+
+func RegisterExtractionHandler(
+	r *gin.Engine,
+	extractionService *service.ExtractionService,
+	authMiddleware gin.HandlerFunc,
+	reviewMiddleware gin.HandlerFunc,
+	reviewerMiddleware gin.HandlerFunc,
+) {
+	extractionHandler := NewExtractionHandler(extractionService)
+	r.GET(
+		"/reviews/:reviewId/extraction",
+		authMiddleware,
+		reviewMiddleware,
+		reviewerMiddleware,
+		extractionHandler.Index,
+	)
+	r.GET(
+		"/reviews/:reviewId/extraction/form",
+		authMiddleware,
+		reviewMiddleware,
+		reviewerMiddleware,
+		extractionHandler.Builder,
+	)
+	r.POST(
+		"/reviews/:reviewId/extraction/form",
+		authMiddleware,
+		reviewMiddleware,
+		reviewerMiddleware,
+		extractionHandler.Publish,
+	)
+	r.GET(
+		"/reviews/:reviewId/extraction/:referenceId",
+		authMiddleware,
+		reviewMiddleware,
+		reviewerMiddleware,
+		extractionHandler.Show,
+	)
+	r.POST(
+		"/reviews/:reviewId/extraction/:referenceId",
+		authMiddleware,
+		reviewMiddleware,
+		reviewerMiddleware,
+		extractionHandler.Save,
+	)
+	r.GET(
+		"/reviews/:reviewId/extraction/:referenceId/compare",
+		authMiddleware,
+		reviewMiddleware,
+		reviewerMiddleware,
+		extractionHandler.Compare,
+	)
+	r.GET(
+		"/reviews/:reviewId/extraction/:referenceId/comparison",
+		authMiddleware,
+		reviewMiddleware,
+		reviewerMiddleware,
+		extractionHandler.Comparison,
+	)
+	r.POST(
+		"/reviews/:reviewId/extraction/:referenceId/consensus",
+		authMiddleware,
+		reviewMiddleware,
+		reviewerMiddleware,
+		extractionHandler.Reconcile,
+	)
+}
@@ -0,0 +1,325 @@
+package handler
+
+import (
+	"bytes"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"golang.org/x/exp/slog"
+	"html/template"
+	"net/url"
+	"sci-review/common"
+	"sci-review/form"
+	"sci-review/grade"
+	"sci-review/model"
+	"sci-review/service"
+	"strings"
+)
+
+type GradeHandler struct {
+	GradeService *service.GradeService
+}
+
+func NewGradeHandler(gradeService *service.GradeService) *GradeHandler {
+	return &GradeHandler{GradeService: gradeService}
+}
+
+func gradeUrl(review *model.Review, path string) string {
+	return "/reviews/" + review.Id.String() + "/grade" + path
+}
+
+func (gh *GradeHandler) Index(c *gin.Context) {
+	principal := c.MustGet("principal").(*model.Principal)
+	review := c.MustGet("review").(*model.Review)
+	reviewer := c.MustGet("reviewer").(*model.Reviewer)
+	pageData := common.PageData{
+		Title:   "Certainty of evidence",
+		Active:  "reviews",
+		User:    principal,
+		Message: c.Query("message"),
+	}
+
+	table, err := gh.GradeService.SummaryOfFindings(review)
+	if err != nil {
+		pageData.Message = err.Error()
+	}
+
+	var summary bytes.Buffer
+	if len(table.Rows) > 0 {
+		if err := grade.WriteTable(&summary, table); err != nil {
+			slog.Error("grade summary of findings", "error", err.Error())
+		}
+	}
+
+	c.HTML(200, "grade/index.html", gin.H{
+		"pageData": pageData,
+		"review":   review,
+		"reviewer": reviewer,
+		"table":    table,
+		"summary":  template.HTML(summary.String()),
+	})
+}
+
+func (gh *GradeHandler) Export(c *gin.Context) {
+	review := c.MustGet("review").(*model.Review)
+
+	table, err := gh.GradeService.SummaryOfFindings(review)
+	if err != nil {
+		c.JSON(500, gin.H{"error": err.Error()})
+		return
+	}
+
+	if strings.HasSuffix(c.Request.URL.Path, ".csv") {
+		c.Header("Content-Disposition", "attachment; filename=summary-of-findings.csv")
+		c.Header("Content-Type", "text/csv; charset=utf-8")
+		err = grade.WriteCsv(c.Writer, table)
+	} else {
+		c.Header("Content-Disposition", "attachment; filename=summary-of-findings.html")
+		c.Header("Content-Type", "text/html; charset=utf-8")
+		err = grade.WriteHtml(c.Writer, table)
+	}
+	if err != nil {
+		slog.Error("grade export", "error", err.Error())
+		c.Status(500)
+	}
+}
+
+// gradeLevels lays the levels of the form out by factor for the template.
+func gradeLevels(data *form.GradeAssessmentForm) map[model.GradeFactor]int {
+	return map[model.GradeFactor]int{
+		model.FactorRiskOfBias:      data.RiskOfBias,
+		model.FactorInconsistency:   data.Inconsistency,
+		model.FactorIndirectness:    data.Indirectness,
+		model.FactorImprecision:     data.Imprecision,
+		model.FactorPublicationBias: data.PublicationBias,
+		model.FactorLargeEffect:     data.LargeEffect,
+		model.FactorDoseResponse:    data.DoseResponse,
+		model.FactorConfounding:     data.Confounding,
+	}
+}
+
+func (gh *GradeHandler) renderEdit(c *gin.Context, status int, pageData common.PageData, assessment *model.GradeAssessment, gradeForm *form.GradeAssessmentForm, footnotes map[string]string) {
+	review := c.MustGet("review").(*model.Review)
+	reviewer := c.MustGet("reviewer").(*model.Reviewer)
+
+	c.HTML(status, "grade/edit.html", gin.H{
+		"pageData":   pageData,
+		"review":     review,
+		"reviewer":   reviewer,
+		"assessment": assessment,
+		"gradeForm":  gradeForm,
+		"levels":     gradeLevels(gradeForm),
+		"footnotes":  footnotes,
+		"factors":    grade.Factors,
+	})
+}
+
+func (gh *GradeHandler) New(c *gin.Context) {
+	pageData := common.PageData{
+		Title:  "Rate an outcome",
+		Active: "reviews",
+		User:   c.MustGet("principal").(*model.Principal),
+	}
+
+	gradeForm := &form.GradeAssessmentForm{Importance: string(model.ImportanceCritical), Design: string(model.DesignRandomized)}
+	gh.renderEdit(c, 200, pageData, nil, gradeForm, map[string]string{})
+}
+
+func (gh *GradeHandler) Edit(c *gin.Context) {
+	review := c.MustGet("review").(*model.Review)
+	pageData := common.PageData{
+		Title:   "Rate an outcome",
+		Active:  "reviews",
+		User:    c.MustGet("principal").(*model.Principal),
+		Message: c.Query("message"),
+	}
+
+	assessmentId, err := uuid.Parse(c.Param("assessmentId"))
+	if err != nil {
+		c.Redirect(302, gradeUrl(review, ""))
+		return
+	}
+
+	assessment, err := gh.GradeService.FindById(assessmentId, review.Id)
+	if err != nil {
+		c.Redirect(302, gradeUrl(review, "")+"?message="+url.QueryEscape(err.Error()))
+		return
+	}
+
+	gradeForm := &form.GradeAssessmentForm{
+		Outcome:          assessment.Outcome,
+		Importance:       string(assessment.Importance),
+		Design:           string(assessment.Design),
+		Studies:          assessment.Studies,
+		Participants:     assessment.Participants,
+		ComparatorRisk:   assessment.ComparatorRisk,
+		InterventionRisk: assessment.InterventionRisk,
+		RelativeEffect:   assessment.RelativeEffect,
+		RiskOfBias:       assessment.RiskOfBias,
+		Inconsistency:    assessment.Inconsistency,
+		Indirectness:     assessment.Indirectness,
+		Imprecision:      assessment.Imprecision,
+		PublicationBias:  assessment.PublicationBias,
+		LargeEffect:      assessment.LargeEffect,
+		DoseResponse:     assessment.DoseResponse,
+		Confounding:      assessment.Confounding,
+		Comments:         assessment.Comments,
+		Position:         assessment.Position,
+	}
+	gh.renderEdit(c, 200, pageData, assessment, gradeForm, assessment.Footnotes)
+}
+
+// save binds the form and the footnotes, named after their factor, and
+// creates the assessment, or updates it when one is given.
+func (gh *GradeHandler) save(c *gin.Context, assessment *model.GradeAssessment) {
+	review := c.MustGet("review").(*model.Review)
+	reviewer := c.MustGet("reviewer").(*model.Reviewer)
+	pageData := common.PageData{
+		Title:  "Rate an outcome",
+		Active: "reviews",
+		User:   c.MustGet("principal").(*model.Principal),
+	}
+
+	gradeForm := new(form.GradeAssessmentForm)
+	if err := c.ShouldBind(&gradeForm); err != nil {
+		slog.Warn("grade save", "error", err.Error())
+		pageData.Message = "Invalid form data"
+		gh.renderEdit(c, 400, pageData, assessment, gradeForm, map[string]string{})
+		return
+	}
+	slog.Info("grade save", "data", gradeForm)
+
+	footnotes := map[string]string{}
+	for input, values := range c.Request.PostForm {
+		if factor, found := strings.CutPrefix(input, "footnote."); found && len(values) > 0 {
+			footnotes[factor] = values[0]
+		}
+	}
+
+	if err := common.Validate(gradeForm); len(err) > 0 {
+		slog.Warn("grade save", "error", "validation error")
+		pageData.Errors = err
+		gh.renderEdit(c, 400, pageData, assessment, gradeForm, footnotes)
+		return
+	}
+
+	var saved *model.GradeAssessment
+	var err error
+	if assessment == nil {
+		saved, err = gh.GradeService.Create(review, reviewer, *gradeForm, footnotes)
+	} else {
+		saved, err = gh.GradeService.Update(assessment.Id, review, *gradeForm, footnotes)
+	}
+	if err != nil {
+		pageData.Message = err.Error()
+		gh.renderEdit(c, 409, pageData, assessment, gradeForm, footnotes)
+		return
+	}
+
+	c.Redirect(302, gradeUrl(review, "/"+saved.Id.String())+"?message="+url.QueryEscape("Certainty rated "+string(saved.Certainty)))
+}
+
+func (gh *GradeHandler) Create(c *gin.Context) {
+	gh.save(c, nil)
+}
+
+func (gh *GradeHandler) Update(c *gin.Context) {
+	review := c.MustGet("review").(*model.Review)
+
+	assessmentId, err := uuid.Parse(c.Param("assessmentId"))
+	if err != nil {
+		c.Redirect(302, gradeUrl(review, ""))
+		return
+	}
+
+	assessment, err := gh.GradeService.FindById(assessmentId, review.Id)
+	if err != nil {
+		c.Redirect(302, gradeUrl(review, "")+"?message="+url.QueryEscape(err.Error()))
+		return
+	}
+
+	gh.save(c, assessment)
+}
+
+func (gh *GradeHandler) Delete(c *gin.Context) {
+	review := c.MustGet("review").(*model.Review)
+	reviewer := c.MustGet("reviewer").(*model.Reviewer)
+
+	assessmentId, err := uuid.Parse(c.Param("assessmentId"))
+	if err != nil {
+		c.Redirect(302, gradeUrl(review, ""))
+		return
+	}
+
+	message := "Outcome removed"
+	if err := gh.GradeService.Delete(assessmentId, review, reviewer); err != nil {
+		message = err.Error()
+	}
+
+	c.Redirect(302, gradeUrl(review, "")+"?message="+url.QueryEscape(message))
+}
+
+func RegisterGradeHandler(
+	r *gin.Engine,
+	gradeService *service.GradeService,
+	authMiddleware gin.HandlerFunc,
+	reviewMiddleware gin.HandlerFunc,
+	reviewerMiddleware gin.HandlerFunc,
+) {
+	gradeHandler := NewGradeHandler(gradeService)
+	r.GET(
+		"/reviews/:reviewId/grade",
+		authMiddleware,
+		reviewMiddleware,
+		reviewerMiddleware,
+		gradeHandler.Index,
+	)
+	r.POST(
+		"/reviews/:reviewId/grade",
+		authMiddleware,
+		reviewMiddleware,
+		reviewerMiddleware,
+		gradeHandler.Create,
+	)
+	r.GET(
+		"/reviews/:reviewId/grade/new",
+		authMiddleware,
+		reviewMiddleware,
+		reviewerMiddleware,
+		gradeHandler.New,
+	)
+	r.GET(
+		"/reviews/:reviewId/grade/summary-of-findings.html",
+		authMiddleware,
+		reviewMiddleware,
+		reviewerMiddleware,
+		gradeHandler.Export,
+	)
+	r.GET(
+		"/reviews/:reviewId/grade/summary-of-findings.csv",
+		authMiddleware,
+		reviewMiddleware,
+		reviewerMiddleware,
+		gradeHandler.Export,
+	)
+	r.GET(
+		"/reviews/:reviewId/grade/:assessmentId",
+		authMiddleware,
+		reviewMiddleware,
+		reviewerMiddleware,
+		gradeHandler.Edit,
+	)
+	r.POST(
+		"/reviews/:reviewId/grade/:assessmentId",
+		authMiddleware,
+		reviewMiddleware,
+		reviewerMiddleware,
+		gradeHandler.Update,
+	)
+	r.POST(
+		"/reviews/:reviewId/grade/:assessmentId/delete",
+		authMiddleware,
+		reviewMiddleware,
+		reviewerMiddleware,
+		gradeHandler.Delete,
+	)
+}
@@ -2,11 +2,15 @@ package handler
 
 import (
 	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
 	"golang.org/x/exp/slog"
+	"net/url"
 	"sci-review/common"
 	"sci-review/form"
 	"sci-review/model"
 	"sci-review/service"
+	"strings"
+	"time"
 )
 
 type InvestigationHandler struct {
@@ -28,8 +32,11 @@ func (pi *InvestigationHandler) CreateForm(c *gin.Context) {
 		User:   principal,
 	}
 	c.HTML(200, "investigations/create.html", gin.H{
-		"pageData": pageData,
-		"review":   review,
+		"pageData":          pageData,
+		"investigationForm": &form.InvestigationForm{Framework: review.ReviewType.DefaultFramework()},
+		"review":            review,
+		"frameworks":        model.QuestionFrameworks,
+		"elements":          model.AllElements(),
 	})
 }
 
@@ -51,6 +58,8 @@ func (pi *InvestigationHandler) Create(c *gin.Context) {
 			"pageData":          pageData,
 			"investigationForm": investigationForm,
 			"review":            review,
+			"frameworks":        model.QuestionFrameworks,
+			"elements":          model.AllElements(),
 		})
 		return
 	}
@@ -63,6 +72,8 @@ func (pi *InvestigationHandler) Create(c *gin.Context) {
 			"pageData":          pageData,
 			"investigationForm": investigationForm,
 			"review":            review,
+			"frameworks":        model.QuestionFrameworks,
+			"elements":          model.AllElements(),
 		})
 		return
 	}
@@ -74,6 +85,8 @@ func (pi *InvestigationHandler) Create(c *gin.Context) {
 			"pageData":          pageData,
 			"investigationForm": investigationForm,
 			"review":            review,
+			"frameworks":        model.QuestionFrameworks,
+			"elements":          model.AllElements(),
 		})
 		return
 	}
@@ -81,15 +94,143 @@ func (pi *InvestigationHandler) Create(c *gin.Context) {
 	c.Redirect(302, "/reviews/"+review.Id.String())
 }
 
-func (pi *InvestigationHandler) Show(c *gin.Context) {
-	principal := c.MustGet("principal").(*model.Principal)
+// showData gathers what the investigation page shows, on the tab, keywords
+// by default. Failed names the form whose errors are shown, which opens its
+// modal or tab: keyword, keywordEdit, question, pilot, protocol,
+// publishedReview or status. Values given override the forms filled by
+// default. It returns nil when the keywords cannot be read.
+func (pi *InvestigationHandler) showData(c *gin.Context, pageData common.PageData, values gin.H) gin.H {
 	review := c.MustGet("review").(*model.Review)
 	investigation := c.MustGet("investigation").(*model.Investigation)
 
 	keywords, err := pi.InvestigationService.GetKeywordsByInvestigationId(investigation.Id)
 	if err != nil {
+		return nil
+	}
+
+	queries, err := pi.InvestigationService.SearchStrings(investigation)
+	if err != nil {
+		return nil
+	}
+
+	keywordHistory, err := pi.InvestigationService.KeywordHistory(investigation.Id)
+	if err != nil {
+		pageData.Message = err.Error()
+	}
+
+	history, err := pi.InvestigationService.StatusHistory(investigation.Id)
+	if err != nil {
+		pageData.Message = err.Error()
+	}
+
+	pilots, err := pi.InvestigationService.PilotComparisons(investigation.Id)
+	if err != nil {
+		pageData.Message = err.Error()
+	}
+
+	protocols, err := pi.InvestigationService.Protocols(investigation.Id)
+	if err != nil {
+		pageData.Message = err.Error()
+	}
+
+	publishedReviews, err := pi.InvestigationService.PublishedReviews(investigation.Id)
+	if err != nil {
+		pageData.Message = err.Error()
+	}
+
+	headings, err := pi.InvestigationService.MeshSuggestions(investigation, keywords)
+	if err != nil {
+		pageData.Message = err.Error()
+	}
+
+	data := gin.H{
+		"pageData":          pageData,
+		"review":            review,
+		"investigation":     investigation,
+		"keywords":          keywords,
+		"keywordForm":       new(form.KeywordForm),
+		"keywordHistory":    keywordHistory,
+		"headings":          headings,
+		"keywordId":         "",
+		"queries":           queries,
+		"criteria":          investigation.Criteria(keywords),
+		"investigationForm": form.NewInvestigationForm(investigation),
+		"statusForm":        &form.InvestigationStatusForm{Conclusion: investigation.Conclusion},
+		"history":           history,
+		"pilotForm":         &form.PilotSearchForm{SourceDatabase: model.SourcePubMed, RunOn: time.Now().Format("2006-01-02")},
+		"pilots":            pilots,
+		"protocolForm":      &form.ProtocolForm{Registry: model.RegistryPROSPERO, Status: model.ProtocolOngoing},
+		"protocols":         protocols,
+		"reviewForm":        &form.PublishedReviewForm{Overlap: model.OverlapPartial},
+		"publishedReviews":  publishedReviews,
+		"priorWork":         model.NewPriorWork(protocols, publishedReviews),
+		"registries":        model.Registries,
+		"protocolStatuses":  model.ProtocolStatuses,
+		"overlaps":          model.ReviewOverlaps,
+		"frameworks":        model.QuestionFrameworks,
+		"elements":          model.AllElements(),
+		"tab":               "keywords",
+		"failed":            "",
+	}
+	for key, value := range values {
+		data[key] = value
+	}
+	return data
+}
+
+func (pi *InvestigationHandler) renderShow(c *gin.Context, status int, pageData common.PageData, values gin.H) {
+	if data := pi.showData(c, pageData, values); data != nil {
+		c.HTML(status, "investigations/show.html", data)
+	}
+}
+
+// renderKeywords renders only the keywords pane for requests that replace
+// it in place, and the whole page otherwise.
+func (pi *InvestigationHandler) renderKeywords(c *gin.Context, status int, pageData common.PageData, values gin.H) {
+	if c.GetHeader("X-Partial") != "keywords" {
+		pi.renderShow(c, status, pageData, values)
 		return
 	}
+	if data := pi.showData(c, pageData, values); data != nil {
+		c.HTML(status, "keywords/pane.html", data)
+	}
+}
+
+// keywordsChanged re-renders the keywords pane in place with the message,
+// or redirects to the page for requests made without scripts.
+func (pi *InvestigationHandler) keywordsChanged(c *gin.Context, pageData common.PageData, message string) {
+	if c.GetHeader("X-Partial") == "keywords" {
+		pi.renderKeywords(c, 200, pageData, gin.H{"keywordMessage": message})
+		return
+	}
+	review := c.MustGet("review").(*model.Review)
+	investigation := c.MustGet("investigation").(*model.Investigation)
+	c.Redirect(302, investigationUrl(review, investigation)+"?tab=keywords&message="+url.QueryEscape(message))
+}
+
+func (pi *InvestigationHandler) Show(c *gin.Context) {
+	principal := c.MustGet("principal").(*model.Principal)
+
+	pageData := common.PageData{
+		Title:   "Investigation",
+		Active:  "reviews",
+		User:    principal,
+		Message: c.Query("message"),
+	}
+
+	tab := c.Query("tab")
+	if tab == "" {
+		tab = "keywords"
+	}
+	pi.renderShow(c, 200, pageData, gin.H{"tab": tab})
+}
+
+// UpdateQuestion rewrites the question of the investigation and its
+// breakdown with a framework.
+func (pi *InvestigationHandler) UpdateQuestion(c *gin.Context) {
+	principal := c.MustGet("principal").(*model.Principal)
+	review := c.MustGet("review").(*model.Review)
+	investigation := c.MustGet("investigation").(*model.Investigation)
 
 	pageData := common.PageData{
 		Title:  "Investigation",
@@ -97,41 +238,66 @@ func (pi *InvestigationHandler) Show(c *gin.Context) {
 		User:   principal,
 	}
 
-	c.HTML(200, "investigations/show.html", gin.H{
-		"pageData":      pageData,
-		"review":        review,
-		"investigation": investigation,
-		"keywords":      keywords,
-	})
+	questionForm := new(form.InvestigationForm)
+	if err := c.ShouldBind(&questionForm); err != nil {
+		slog.Warn("investigation question update", "error", err.Error())
+		pageData.Message = "Invalid form data"
+		pi.renderShow(c, 400, pageData, gin.H{"investigationForm": questionForm, "failed": "question"})
+		return
+	}
+	slog.Info("investigation question update", "data", questionForm)
+
+	if err := common.Validate(questionForm); len(err) > 0 {
+		slog.Warn("investigation question update", "error", "validation error")
+		pageData.Errors = err
+		pi.renderShow(c, 400, pageData, gin.H{"investigationForm": questionForm, "failed": "question"})
+		return
+	}
+
+	if err := pi.InvestigationService.UpdateQuestion(investigation, *questionForm); err != nil {
+		pageData.Message = err.Error()
+		pi.renderShow(c, 409, pageData, gin.H{"investigationForm": questionForm, "failed": "question"})
+		return
+	}
+
+	c.Redirect(302, "/reviews/"+review.Id.String()+"/investigations/"+investigation.Id.String())
 }
 
-func (pi *InvestigationHandler) CreateKeyword(c *gin.Context) {
-	principal := c.MustGet("principal").(*model.Principal)
-	review := c.MustGet("review").(*model.Review)
+// SearchStrings exports the search strings of every database as text.
+func (pi *InvestigationHandler) SearchStrings(c *gin.Context) {
 	investigation := c.MustGet("investigation").(*model.Investigation)
 
-	keywords, err := pi.InvestigationService.GetKeywordsByInvestigationId(investigation.Id)
+	queries, err := pi.InvestigationService.SearchStrings(investigation)
 	if err != nil {
+		slog.Error("investigation search strings", "error", err.Error())
+		c.Status(500)
 		return
 	}
 
+	var out strings.Builder
+	for _, query := range queries {
+		out.WriteString(query.Name + "\n" + query.Text + "\n\n")
+	}
+
+	c.Header("Content-Disposition", "attachment; filename=search-strings.txt")
+	c.Data(200, "text/plain; charset=utf-8", []byte(out.String()))
+}
+
+func (pi *InvestigationHandler) CreateKeyword(c *gin.Context) {
+	principal := c.MustGet("principal").(*model.Principal)
+	review := c.MustGet("review").(*model.Review)
+	investigation := c.MustGet("investigation").(*model.Investigation)
+
 	pageData := common.PageData{
 		Title:  "Preliminary Investigation",
 		Active: "reviews",
 		User:   principal,
 	}
-
 	keywordForm := new(form.KeywordForm)
 	if err := c.ShouldBind(&keywordForm); err != nil {
 		slog.Warn("investigation keyword create", "error", err.Error())
 		pageData.Message = "Invalid form data"
-		c.HTML(200, "investigations/show.html", gin.H{
-			"pageData":      pageData,
-			"keywordForm":   keywordForm,
-			"review":        review,
-			"investigation": investigation,
-			"keywords":      keywords,
-		})
+		pi.renderShow(c, 200, pageData, gin.H{"keywordForm": keywordForm, "failed": "keyword"})
 		return
 	}
 	slog.Info("investigation keyword create", "data", keywordForm)
@@ -139,26 +305,14 @@ func (pi *InvestigationHandler) CreateKeyword(c *gin.Context) {
 	if err := common.Validate(keywordForm); len(err) > 0 {
 		slog.Warn("investigation keyword create", "error", "validation error")
 		pageData.Errors = err
-		c.HTML(400, "investigations/show.html", gin.H{
-			"pageData":      pageData,
-			"keywordForm":   keywordForm,
-			"review":        review,
-			"investigation": investigation,
-			"keywords":      keywords,
-		})
+		pi.renderShow(c, 400, pageData, gin.H{"keywordForm": keywordForm, "failed": "keyword"})
 		return
 	}
 
-	err = pi.InvestigationService.SaveKeyword(investigation.Id, principal.Id, *keywordForm)
+	err := pi.InvestigationService.SaveKeyword(investigation, principal.Id, *keywordForm)
 	if err != nil {
 		pageData.Message = err.Error()
-		c.HTML(409, "investigations/show.html", gin.H{
-			"pageData":      pageData,
-			"keywordForm":   keywordForm,
-			"review":        review,
-			"investigation": investigation,
-			"keywords":      keywords,
-		})
+		pi.renderShow(c, 409, pageData, gin.H{"keywordForm": keywordForm, "failed": "keyword"})
 		return
 	}
 
@@ -166,12 +320,409 @@ func (pi *InvestigationHandler) CreateKeyword(c *gin.Context) {
 
 }
 
+// UpdateKeyword edits the word, synonyms and concept of a keyword.
+func (pi *InvestigationHandler) UpdateKeyword(c *gin.Context) {
+	principal := c.MustGet("principal").(*model.Principal)
+	investigation := c.MustGet("investigation").(*model.Investigation)
+
+	pageData := common.PageData{
+		Title:  "Investigation",
+		Active: "reviews",
+		User:   principal,
+	}
+
+	updateForm := new(form.KeywordUpdateForm)
+	failed := gin.H{"keywordUpdateForm": updateForm, "failed": "keywordEdit", "keywordId": c.Param("keywordId")}
+	if err := c.ShouldBind(&updateForm); err != nil {
+		slog.Warn("keyword update", "error", err.Error())
+		pageData.Message = "Invalid form data"
+		pi.renderKeywords(c, 400, pageData, failed)
+		return
+	}
+	slog.Info("keyword update", "data", updateForm)
+
+	if err := common.Validate(updateForm); len(err) > 0 {
+		slog.Warn("keyword update", "error", "validation error")
+		pageData.Errors = err
+		pi.renderKeywords(c, 400, pageData, failed)
+		return
+	}
+
+	keywordId, err := uuid.Parse(c.Param("keywordId"))
+	if err == nil {
+		err = pi.InvestigationService.UpdateKeyword(investigation, keywordId, principal.Id, *updateForm)
+	} else {
+		err = service.ErrorKeywordNotFound
+	}
+	if err != nil {
+		pageData.Message = err.Error()
+		pi.renderKeywords(c, 409, pageData, failed)
+		return
+	}
+
+	pi.keywordsChanged(c, pageData, "Keyword updated")
+}
+
+func (pi *InvestigationHandler) DeleteKeyword(c *gin.Context) {
+	principal := c.MustGet("principal").(*model.Principal)
+	investigation := c.MustGet("investigation").(*model.Investigation)
+
+	pageData := common.PageData{
+		Title:  "Investigation",
+		Active: "reviews",
+		User:   principal,
+	}
+
+	keywordId, err := uuid.Parse(c.Param("keywordId"))
+	if err == nil {
+		err = pi.InvestigationService.DeleteKeyword(investigation, keywordId, principal.Id)
+	} else {
+		err = service.ErrorKeywordNotFound
+	}
+	if err != nil {
+		pageData.Message = err.Error()
+		pi.renderKeywords(c, 409, pageData, gin.H{"failed": "keywordEdit"})
+		return
+	}
+
+	pi.keywordsChanged(c, pageData, "Keyword deleted")
+}
+
+// MergeKeyword merges a keyword into another, whose synonyms it joins.
+func (pi *InvestigationHandler) MergeKeyword(c *gin.Context) {
+	principal := c.MustGet("principal").(*model.Principal)
+	investigation := c.MustGet("investigation").(*model.Investigation)
+
+	pageData := common.PageData{
+		Title:  "Investigation",
+		Active: "reviews",
+		User:   principal,
+	}
+
+	mergeForm := new(form.KeywordMergeForm)
+	if err := c.ShouldBind(&mergeForm); err != nil {
+		slog.Warn("keyword merge", "error", err.Error())
+		pageData.Message = "Invalid form data"
+		pi.renderKeywords(c, 400, pageData, gin.H{"failed": "keywordEdit"})
+		return
+	}
+	if err := common.Validate(mergeForm); len(err) > 0 {
+		slog.Warn("keyword merge", "error", "validation error")
+		pageData.Errors = err
+		pi.renderKeywords(c, 400, pageData, gin.H{"failed": "keywordEdit"})
+		return
+	}
+
+	keywordId, err := uuid.Parse(c.Param("keywordId"))
+	if err == nil {
+		err = pi.InvestigationService.MergeKeywords(investigation, keywordId, uuid.MustParse(mergeForm.Into), principal.Id)
+	} else {
+		err = service.ErrorKeywordNotFound
+	}
+	if err != nil {
+		pageData.Message = err.Error()
+		pi.renderKeywords(c, 409, pageData, gin.H{"failed": "keywordEdit"})
+		return
+	}
+
+	pi.keywordsChanged(c, pageData, "Keywords merged")
+}
+
+// MoveKeyword moves a keyword one place up or down.
+func (pi *InvestigationHandler) MoveKeyword(c *gin.Context) {
+	principal := c.MustGet("principal").(*model.Principal)
+	investigation := c.MustGet("investigation").(*model.Investigation)
+
+	pageData := common.PageData{
+		Title:  "Investigation",
+		Active: "reviews",
+		User:   principal,
+	}
+
+	moveForm := new(form.KeywordMoveForm)
+	if err := c.ShouldBind(&moveForm); err != nil {
+		slog.Warn("keyword move", "error", err.Error())
+		pageData.Message = "Invalid form data"
+		pi.renderKeywords(c, 400, pageData, gin.H{"failed": "keywordEdit"})
+		return
+	}
+	if err := common.Validate(moveForm); len(err) > 0 {
+		slog.Warn("keyword move", "error", "validation error")
+		pageData.Errors = err
+		pi.renderKeywords(c, 400, pageData, gin.H{"failed": "keywordEdit"})
+		return
+	}
+
+	keywordId, err := uuid.Parse(c.Param("keywordId"))
+	if err == nil {
+		err = pi.InvestigationService.MoveKeyword(investigation, keywordId, principal.Id, moveForm.Direction)
+	} else {
+		err = service.ErrorKeywordNotFound
+	}
+	if err != nil {
+		pageData.Message = err.Error()
+		pi.renderKeywords(c, 409, pageData, gin.H{"failed": "keywordEdit"})
+		return
+	}
+
+	pi.keywordsChanged(c, pageData, "Keyword moved")
+}
+
+// AddSynonym adds a synonym, such as a suggested MeSH heading, to a keyword.
+func (pi *InvestigationHandler) AddSynonym(c *gin.Context) {
+	principal := c.MustGet("principal").(*model.Principal)
+	investigation := c.MustGet("investigation").(*model.Investigation)
+
+	pageData := common.PageData{
+		Title:  "Investigation",
+		Active: "reviews",
+		User:   principal,
+	}
+
+	synonymForm := new(form.KeywordSynonymForm)
+	if err := c.ShouldBind(&synonymForm); err != nil {
+		slog.Warn("keyword synonym", "error", err.Error())
+		pageData.Message = "Invalid form data"
+		pi.renderKeywords(c, 400, pageData, gin.H{"failed": "keywordEdit"})
+		return
+	}
+	if err := common.Validate(synonymForm); len(err) > 0 {
+		slog.Warn("keyword synonym", "error", "validation error")
+		pageData.Errors = err
+		pi.renderKeywords(c, 400, pageData, gin.H{"failed": "keywordEdit"})
+		return
+	}
+
+	keywordId, err := uuid.Parse(synonymForm.Keyword)
+	if err == nil {
+		err = pi.InvestigationService.AddSynonym(investigation, keywordId, principal.Id, synonymForm.Synonym)
+	} else {
+		err = service.ErrorKeywordNotFound
+	}
+	if err != nil {
+		pageData.Message = err.Error()
+		pi.renderKeywords(c, 409, pageData, gin.H{"failed": "keywordEdit"})
+		return
+	}
+
+	pi.keywordsChanged(c, pageData, "Synonym added")
+}
+
+// ChangeStatus moves the investigation to another status with the
+// conclusion given for it.
+func (pi *InvestigationHandler) ChangeStatus(c *gin.Context) {
+	principal := c.MustGet("principal").(*model.Principal)
+	review := c.MustGet("review").(*model.Review)
+	investigation := c.MustGet("investigation").(*model.Investigation)
+
+	pageData := common.PageData{
+		Title:  "Investigation",
+		Active: "reviews",
+		User:   principal,
+	}
+
+	statusForm := new(form.InvestigationStatusForm)
+	if err := c.ShouldBind(&statusForm); err != nil {
+		slog.Warn("investigation status", "error", err.Error())
+		pageData.Message = "Invalid form data"
+		pi.renderShow(c, 400, pageData, gin.H{"statusForm": statusForm, "tab": "conclusion", "failed": "status"})
+		return
+	}
+	slog.Info("investigation status", "data", statusForm)
+
+	if err := common.Validate(statusForm); len(err) > 0 {
+		slog.Warn("investigation status", "error", "validation error")
+		pageData.Errors = err
+		pi.renderShow(c, 400, pageData, gin.H{"statusForm": statusForm, "tab": "conclusion", "failed": "status"})
+		return
+	}
+
+	if err := pi.InvestigationService.ChangeStatus(investigation, principal.Id, *statusForm); err != nil {
+		pageData.Message = err.Error()
+		pi.renderShow(c, 409, pageData, gin.H{"statusForm": statusForm, "tab": "conclusion", "failed": "status"})
+		return
+	}
+
+	c.Redirect(302, "/reviews/"+review.Id.String()+"/investigations/"+investigation.Id.String()+"?tab=conclusion&message="+url.QueryEscape("Status changed to "+investigation.Status.Name()))
+}
+
+func investigationUrl(review *model.Review, investigation *model.Investigation) string {
+	return "/reviews/" + review.Id.String() + "/investigations/" + investigation.Id.String()
+}
+
+// LogPilotSearch records a search run in a database with its hits.
+func (pi *InvestigationHandler) LogPilotSearch(c *gin.Context) {
+	principal := c.MustGet("principal").(*model.Principal)
+	review := c.MustGet("review").(*model.Review)
+	investigation := c.MustGet("investigation").(*model.Investigation)
+
+	pageData := common.PageData{
+		Title:  "Investigation",
+		Active: "reviews",
+		User:   principal,
+	}
+
+	pilotForm := new(form.PilotSearchForm)
+	if err := c.ShouldBind(&pilotForm); err != nil {
+		slog.Warn("pilot search log", "error", err.Error())
+		pageData.Message = "Invalid form data"
+		pi.renderShow(c, 400, pageData, gin.H{"pilotForm": pilotForm, "tab": "pilot", "failed": "pilot"})
+		return
+	}
+	slog.Info("pilot search log", "data", pilotForm)
+
+	if err := common.Validate(pilotForm); len(err) > 0 {
+		slog.Warn("pilot search log", "error", "validation error")
+		pageData.Errors = err
+		pi.renderShow(c, 400, pageData, gin.H{"pilotForm": pilotForm, "tab": "pilot", "failed": "pilot"})
+		return
+	}
+
+	if _, err := pi.InvestigationService.LogPilotSearch(investigation, principal.Id, *pilotForm); err != nil {
+		pageData.Message = err.Error()
+		pi.renderShow(c, 409, pageData, gin.H{"pilotForm": pilotForm, "tab": "pilot", "failed": "pilot"})
+		return
+	}
+
+	c.Redirect(302, investigationUrl(review, investigation)+"?tab=pilot&message="+url.QueryEscape("Pilot search logged"))
+}
+
+func (pi *InvestigationHandler) DeletePilotSearch(c *gin.Context) {
+	review := c.MustGet("review").(*model.Review)
+	investigation := c.MustGet("investigation").(*model.Investigation)
+
+	pilotSearchId, err := uuid.Parse(c.Param("pilotSearchId"))
+	if err != nil {
+		c.Redirect(302, investigationUrl(review, investigation)+"?tab=pilot")
+		return
+	}
+
+	message := "Pilot search removed"
+	if err := pi.InvestigationService.DeletePilotSearch(investigation.Id, pilotSearchId); err != nil {
+		message = err.Error()
+	}
+
+	c.Redirect(302, investigationUrl(review, investigation)+"?tab=pilot&message="+url.QueryEscape(message))
+}
+
+// AddProtocol logs the protocol of a review on a similar question.
+func (pi *InvestigationHandler) AddProtocol(c *gin.Context) {
+	principal := c.MustGet("principal").(*model.Principal)
+	review := c.MustGet("review").(*model.Review)
+	investigation := c.MustGet("investigation").(*model.Investigation)
+
+	pageData := common.PageData{
+		Title:  "Investigation",
+		Active: "reviews",
+		User:   principal,
+	}
+
+	protocolForm := new(form.ProtocolForm)
+	if err := c.ShouldBind(&protocolForm); err != nil {
+		slog.Warn("protocol create", "error", err.Error())
+		pageData.Message = "Invalid form data"
+		pi.renderShow(c, 400, pageData, gin.H{"protocolForm": protocolForm, "tab": "protocols", "failed": "protocol"})
+		return
+	}
+	slog.Info("protocol create", "data", protocolForm)
+
+	if err := common.Validate(protocolForm); len(err) > 0 {
+		slog.Warn("protocol create", "error", "validation error")
+		pageData.Errors = err
+		pi.renderShow(c, 400, pageData, gin.H{"protocolForm": protocolForm, "tab": "protocols", "failed": "protocol"})
+		return
+	}
+
+	if _, err := pi.InvestigationService.AddProtocol(investigation, principal.Id, *protocolForm); err != nil {
+		pageData.Message = err.Error()
+		pi.renderShow(c, 409, pageData, gin.H{"protocolForm": protocolForm, "tab": "protocols", "failed": "protocol"})
+		return
+	}
+
+	c.Redirect(302, investigationUrl(review, investigation)+"?tab=protocols&message="+url.QueryEscape("Protocol logged"))
+}
+
+func (pi *InvestigationHandler) DeleteProtocol(c *gin.Context) {
+	review := c.MustGet("review").(*model.Review)
+	investigation := c.MustGet("investigation").(*model.Investigation)
+
+	protocolId, err := uuid.Parse(c.Param("protocolId"))
+	if err != nil {
+		c.Redirect(302, investigationUrl(review, investigation)+"?tab=protocols")
+		return
+	}
+
+	message := "Protocol removed"
+	if err := pi.InvestigationService.DeleteProtocol(investigation.Id, protocolId); err != nil {
+		message = err.Error()
+	}
+
+	c.Redirect(302, investigationUrl(review, investigation)+"?tab=protocols&message="+url.QueryEscape(message))
+}
+
+// AddPublishedReview logs a review already published on a similar
+// question.
+func (pi *InvestigationHandler) AddPublishedReview(c *gin.Context) {
+	principal := c.MustGet("principal").(*model.Principal)
+	review := c.MustGet("review").(*model.Review)
+	investigation := c.MustGet("investigation").(*model.Investigation)
+
+	pageData := common.PageData{
+		Title:  "Investigation",
+		Active: "reviews",
+		User:   principal,
+	}
+
+	reviewForm := new(form.PublishedReviewForm)
+	if err := c.ShouldBind(&reviewForm); err != nil {
+		slog.Warn("published review create", "error", err.Error())
+		pageData.Message = "Invalid form data"
+		pi.renderShow(c, 400, pageData, gin.H{"reviewForm": reviewForm, "tab": "reviews", "failed": "publishedReview"})
+		return
+	}
+	slog.Info("published review create", "data", reviewForm)
+
+	if err := common.Validate(reviewForm); len(err) > 0 {
+		slog.Warn("published review create", "error", "validation error")
+		pageData.Errors = err
+		pi.renderShow(c, 400, pageData, gin.H{"reviewForm": reviewForm, "tab": "reviews", "failed": "publishedReview"})
+		return
+	}
+
+	if _, err := pi.InvestigationService.AddPublishedReview(investigation, principal.Id, *reviewForm); err != nil {
+		pageData.Message = err.Error()
+		pi.renderShow(c, 409, pageData, gin.H{"reviewForm": reviewForm, "tab": "reviews", "failed": "publishedReview"})
+		return
+	}
+
+	c.Redirect(302, investigationUrl(review, investigation)+"?tab=reviews&message="+url.QueryEscape("Published review logged"))
+}
+
+func (pi *InvestigationHandler) DeletePublishedReview(c *gin.Context) {
+	review := c.MustGet("review").(*model.Review)
+	investigation := c.MustGet("investigation").(*model.Investigation)
+
+	publishedReviewId, err := uuid.Parse(c.Param("publishedReviewId"))
+	if err != nil {
+		c.Redirect(302, investigationUrl(review, investigation)+"?tab=reviews")
+		return
+	}
+
+	message := "Published review removed"
+	if err := pi.InvestigationService.DeletePublishedReview(investigation.Id, publishedReviewId); err != nil {
+		message = err.Error()
+	}
+
+	c.Redirect(302, investigationUrl(review, investigation)+"?tab=reviews&message="+url.QueryEscape(message))
+}
+
 func RegisterInvestigationHandler(
 	r *gin.Engine,
 	reviewService *service.ReviewService,
 	investigationService *service.InvestigationService,
 	authMiddleware gin.HandlerFunc,
 	reviewMiddleware gin.HandlerFunc,
+	reviewerMiddleware gin.HandlerFunc,
 	investigationMiddleware gin.HandlerFunc,
 ) {
 	investigationHandler := NewInvestigationHandler(reviewService, investigationService)
@@ -185,6 +736,7 @@ func RegisterInvestigationHandler(
 		"/reviews/:reviewId/investigations/create",
 		authMiddleware,
 		reviewMiddleware,
+		reviewerMiddleware,
 		investigationHandler.Create,
 	)
 	r.GET(
@@ -194,11 +746,123 @@ func RegisterInvestigationHandler(
 		investigationMiddleware,
 		investigationHandler.Show,
 	)
+	r.POST(
+		"/reviews/:reviewId/investigations/:investigationId/question",
+		authMiddleware,
+		reviewMiddleware,
+		reviewerMiddleware,
+		investigationMiddleware,
+		investigationHandler.UpdateQuestion,
+	)
+	r.POST(
+		"/reviews/:reviewId/investigations/:investigationId/status",
+		authMiddleware,
+		reviewMiddleware,
+		reviewerMiddleware,
+		investigationMiddleware,
+		investigationHandler.ChangeStatus,
+	)
+	r.GET(
+		"/reviews/:reviewId/investigations/:investigationId/search.txt",
+		authMiddleware,
+		reviewMiddleware,
+		investigationMiddleware,
+		investigationHandler.SearchStrings,
+	)
+	r.POST(
+		"/reviews/:reviewId/investigations/:investigationId/pilot-searches",
+		authMiddleware,
+		reviewMiddleware,
+		reviewerMiddleware,
+		investigationMiddleware,
+		investigationHandler.LogPilotSearch,
+	)
+	r.POST(
+		"/reviews/:reviewId/investigations/:investigationId/pilot-searches/:pilotSearchId/delete",
+		authMiddleware,
+		reviewMiddleware,
+		reviewerMiddleware,
+		investigationMiddleware,
+		investigationHandler.DeletePilotSearch,
+	)
+	r.POST(
+		"/reviews/:reviewId/investigations/:investigationId/protocols",
+		authMiddleware,
+		reviewMiddleware,
+		reviewerMiddleware,
+		investigationMiddleware,
+		investigationHandler.AddProtocol,
+	)
+	r.POST(
+		"/reviews/:reviewId/investigations/:investigationId/protocols/:protocolId/delete",
+		authMiddleware,
+		reviewMiddleware,
+		reviewerMiddleware,
+		investigationMiddleware,
+		investigationHandler.DeleteProtocol,
+	)
+	r.POST(
+		"/reviews/:reviewId/investigations/:investigationId/published-reviews",
+		authMiddleware,
+		reviewMiddleware,
+		reviewerMiddleware,
+		investigationMiddleware,
+		investigationHandler.AddPublishedReview,
+	)
+	r.POST(
+		"/reviews/:reviewId/investigations/:investigationId/published-reviews/:publishedReviewId/delete",
+		authMiddleware,
+		reviewMiddleware,
+		reviewerMiddleware,
+		investigationMiddleware,
+		investigationHandler.DeletePublishedReview,
+	)
 	r.POST(
 		"/reviews/:reviewId/investigations/:investigationId/keywords",
 		authMiddleware,
 		reviewMiddleware,
+		reviewerMiddleware,
 		investigationMiddleware,
 		investigationHandler.CreateKeyword,
 	)
+	r.POST(
+		"/reviews/:reviewId/investigations/:investigationId/keywords/:keywordId",
+		authMiddleware,
+		reviewMiddleware,
+		reviewerMiddleware,
+		investigationMiddleware,
+		investigationHandler.UpdateKeyword,
+	)
+	r.POST(
+		"/reviews/:reviewId/investigations/:investigationId/keywords/:keywordId/delete",
+		authMiddleware,
+		reviewMiddleware,
+		reviewerMiddleware,
+		investigationMiddleware,
+		investigationHandler.DeleteKeyword,
+	)
+	r.POST(
+		"/reviews/:reviewId/investigations/:investigationId/keywords/:keywordId/merge",
+		authMiddleware,
+		reviewMiddleware,
+		reviewerMiddleware,
+		investigationMiddleware,
+		investigationHandler.MergeKeyword,
+	)
+	r.POST(
+		"/reviews/:reviewId/investigations/:investigationId/keywords/:keywordId/move",
+		authMiddleware,
+		reviewMiddleware,
+		reviewerMiddleware,
+		investigationMiddleware,
+		investigationHandler.MoveKeyword,
+	)
+	r.POST(
+		"/reviews/:reviewId/investigations/:investigationId/synonyms",
+		authMiddleware,
+		reviewMiddleware,
+		reviewerMiddleware,
+		investigationMiddleware,
+		investigationHandler.AddSynonym,
+	)
 }
@@ -0,0 +1,338 @@
+package handler
+
+import (
+	"bytes"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"golang.org/x/exp/slog"
+	"html/template"
+	"net/url"
+	"sci-review/common"
+	"sci-review/form"
+	"sci-review/meta"
+	"sci-review/model"
+	"sci-review/service"
+)
+
+type MetaHandler struct {
+	MetaService *service.MetaService
+}
+
+func NewMetaHandler(metaService *service.MetaService) *MetaHandler {
+	return &MetaHandler{MetaService: metaService}
+}
+
+func metaUrl(review *model.Review, path string) string {
+	return "/reviews/" + review.Id.String() + "/meta" + path
+}
+
+func (mh *MetaHandler) renderIndex(c *gin.Context, status int, pageData common.PageData, dataForm *form.OutcomeDataForm) {
+	review := c.MustGet("review").(*model.Review)
+	reviewer := c.MustGet("reviewer").(*model.Reviewer)
+
+	studies, err := mh.MetaService.Studies(review)
+	if err != nil {
+		pageData.Message = err.Error()
+	}
+
+	outcomes, err := mh.MetaService.Outcomes(review)
+	if err != nil {
+		pageData.Message = err.Error()
+	}
+
+	c.HTML(status, "meta/index.html", gin.H{
+		"pageData":  pageData,
+		"review":    review,
+		"reviewer":  reviewer,
+		"studies":   studies,
+		"outcomes":  outcomes,
+		"dataForm":  dataForm,
+		"dataTypes": model.OutcomeDataTypes,
+		"measures":  meta.Measures,
+		"methods":   meta.Methods,
+		"medians":   meta.MedianMethods,
+	})
+}
+
+func (mh *MetaHandler) Index(c *gin.Context) {
+	pageData := common.PageData{
+		Title:   "Meta-analysis",
+		Active:  "reviews",
+		User:    c.MustGet("principal").(*model.Principal),
+		Message: c.Query("message"),
+	}
+
+	mh.renderIndex(c, 200, pageData, &form.OutcomeDataForm{DataType: string(model.OutcomeBinary)})
+}
+
+func (mh *MetaHandler) Save(c *gin.Context) {
+	review := c.MustGet("review").(*model.Review)
+	reviewer := c.MustGet("reviewer").(*model.Reviewer)
+	pageData := common.PageData{
+		Title:  "Meta-analysis",
+		Active: "reviews",
+		User:   c.MustGet("principal").(*model.Principal),
+	}
+
+	dataForm := new(form.OutcomeDataForm)
+	if err := c.ShouldBind(&dataForm); err != nil {
+		slog.Warn("meta save", "error", err.Error())
+		pageData.Message = "Invalid form data"
+		mh.renderIndex(c, 400, pageData, dataForm)
+		return
+	}
+	slog.Info("meta save", "data", dataForm)
+
+	if err := common.Validate(dataForm); len(err) > 0 {
+		slog.Warn("meta save", "error", "validation error")
+		pageData.Errors = err
+		mh.renderIndex(c, 400, pageData, dataForm)
+		return
+	}
+
+	if _, err := mh.MetaService.Save(review, reviewer, *dataForm); err != nil {
+		pageData.Message = err.Error()
+		mh.renderIndex(c, 409, pageData, dataForm)
+		return
+	}
+
+	c.Redirect(302, metaUrl(review, "")+"?message="+url.QueryEscape("Outcome data saved"))
+}
+
+// Calculate converts the data as saving it would, without saving it.
+func (mh *MetaHandler) Calculate(c *gin.Context) {
+	review := c.MustGet("review").(*model.Review)
+	reviewer := c.MustGet("reviewer").(*model.Reviewer)
+
+	dataForm := new(form.OutcomeDataForm)
+	if err := c.ShouldBind(&dataForm); err != nil {
+		slog.Warn("meta calculate", "error", err.Error())
+		c.JSON(400, gin.H{"error": "Invalid form data"})
+		return
+	}
+	if err := common.Validate(dataForm); len(err) > 0 {
+		c.JSON(400, gin.H{"errors": err})
+		return
+	}
+
+	row := model.NewOutcomeData(review.Id, uuid.Nil, dataForm.Outcome, model.OutcomeDataType(dataForm.DataType), reviewer.UserId)
+	if err := mh.MetaService.CalculatorService.Calculate(row, *dataForm); err != nil {
+		c.JSON(422, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(200, row)
+}
+
+func (mh *MetaHandler) Delete(c *gin.Context) {
+	review := c.MustGet("review").(*model.Review)
+	reviewer := c.MustGet("reviewer").(*model.Reviewer)
+
+	dataId, err := uuid.Parse(c.Param("dataId"))
+	if err != nil {
+		c.Redirect(302, metaUrl(review, ""))
+		return
+	}
+
+	message := "Outcome data removed"
+	if err := mh.MetaService.Delete(dataId, review, reviewer); err != nil {
+		message = err.Error()
+	}
+
+	c.Redirect(302, metaUrl(review, "")+"?message="+url.QueryEscape(message))
+}
+
+// analyze binds the analysis from the query string, at 95% confidence when
+// no level is given, and runs it.
+func (mh *MetaHandler) analyze(c *gin.Context) (*form.MetaAnalysisForm, *meta.Analysis, []common.ErrorResponse, error) {
+	review := c.MustGet("review").(*model.Review)
+
+	analysisForm := new(form.MetaAnalysisForm)
+	if err := c.ShouldBindQuery(analysisForm); err != nil {
+		slog.Warn("meta analyze", "error", err.Error())
+		return analysisForm, nil, []common.ErrorResponse{common.Message("level", "numeric", "")}, nil
+	}
+	if analysisForm.Level == 0 {
+		analysisForm.Level = 0.95
+	}
+	if errs := common.Validate(analysisForm); len(errs) > 0 {
+		return analysisForm, nil, errs, nil
+	}
+
+	analysis, err := mh.MetaService.Analyze(review, *analysisForm)
+	return analysisForm, analysis, nil, err
+}
+
+func (mh *MetaHandler) Analysis(c *gin.Context) {
+	review := c.MustGet("review").(*model.Review)
+	pageData := common.PageData{
+		Title:  "Meta-analysis",
+		Active: "reviews",
+		User:   c.MustGet("principal").(*model.Principal),
+	}
+
+	analysisForm, analysis, errs, err := mh.analyze(c)
+	pageData.Errors = errs
+	if err != nil {
+		pageData.Message = err.Error()
+	}
+
+	var forest, funnel bytes.Buffer
+	if analysis != nil && analysis.Result != nil {
+		if err := meta.WriteForest(&forest, analysis.Studies, analysis.Result); err != nil {
+			slog.Error("meta forest", "error", err.Error())
+		}
+	}
+	if analysis != nil && analysis.Bias != nil {
+		if err := meta.WriteFunnel(&funnel, analysis.Studies, analysis.Result, analysis.Bias.TrimFill); err != nil {
+			slog.Error("meta funnel", "error", err.Error())
+		}
+	}
+
+	outcomes, err := mh.MetaService.Outcomes(review)
+	if err != nil {
+		pageData.Message = err.Error()
+	}
+
+	moderators, err := mh.MetaService.Moderators(review)
+	if err != nil {
+		pageData.Message = err.Error()
+	}
+
+	c.HTML(200, "meta/analysis.html", gin.H{
+		"pageData":     pageData,
+		"review":       review,
+		"outcomes":     outcomes,
+		"measures":     meta.Measures,
+		"methods":      meta.Methods,
+		"moderators":   moderators,
+		"analysisForm": analysisForm,
+		"analysis":     analysis,
+		"query":        c.Request.URL.RawQuery,
+		"forest":       template.HTML(forest.String()),
+		"funnel":       template.HTML(funnel.String()),
+		"minStudies":   meta.MinBiasStudies,
+	})
+}
+
+func (mh *MetaHandler) Forest(c *gin.Context) {
+	_, analysis, errs, err := mh.analyze(c)
+	if len(errs) > 0 {
+		c.JSON(400, gin.H{"errors": errs})
+		return
+	}
+	if err != nil {
+		c.JSON(422, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.Header("Content-Disposition", "attachment; filename=forest.svg")
+	c.Header("Content-Type", "image/svg+xml")
+	if err := meta.WriteForest(c.Writer, analysis.Studies, analysis.Result); err != nil {
+		slog.Error("meta forest", "error", err.Error())
+		c.Status(500)
+	}
+}
+
+func (mh *MetaHandler) Funnel(c *gin.Context) {
+	_, analysis, errs, err := mh.analyze(c)
+	if len(errs) > 0 {
+		c.JSON(400, gin.H{"errors": errs})
+		return
+	}
+	if err != nil {
+		c.JSON(422, gin.H{"error": err.Error()})
+		return
+	}
+	if analysis.Bias == nil {
+		c.JSON(422, gin.H{"error": meta.ErrorFewStudies.Error()})
+		return
+	}
+
+	c.Header("Content-Disposition", "attachment; filename=funnel.svg")
+	c.Header("Content-Type", "image/svg+xml")
+	if err := meta.WriteFunnel(c.Writer, analysis.Studies, analysis.Result, analysis.Bias.TrimFill); err != nil {
+		slog.Error("meta funnel", "error", err.Error())
+		c.Status(500)
+	}
+}
+
+func (mh *MetaHandler) Json(c *gin.Context) {
+	_, analysis, errs, err := mh.analyze(c)
+	if len(errs) > 0 {
+		c.JSON(400, gin.H{"errors": errs})
+		return
+	}
+	if err != nil {
+		c.JSON(422, gin.H{"error": err.Error(), "analysis": analysis})
+		return
+	}
+
+	c.JSON(200, analysis)
+}
+
+func RegisterMetaHandler(
+	r *gin.Engine,
+	metaService *service.MetaService,
+	authMiddleware gin.HandlerFunc,
+	reviewMiddleware gin.HandlerFunc,
+	reviewerMiddleware gin.HandlerFunc,
+) {
+	metaHandler := NewMetaHandler(metaService)
+	r.GET(
+		"/reviews/:reviewId/meta",
+		authMiddleware,
+		reviewMiddleware,
+		reviewerMiddleware,
+		metaHandler.Index,
+	)
+	r.POST(
+		"/reviews/:reviewId/meta/data",
+		authMiddleware,
+		reviewMiddleware,
+		reviewerMiddleware,
+		metaHandler.Save,
+	)
+	r.POST(
+		"/reviews/:reviewId/meta/calculate",
+		authMiddleware,
+		reviewMiddleware,
+		reviewerMiddleware,
+		metaHandler.Calculate,
+	)
+	r.POST(
+		"/reviews/:reviewId/meta/data/:dataId/delete",
+		authMiddleware,
+		reviewMiddleware,
+		reviewerMiddleware,
+		metaHandler.Delete,
+	)
+	r.GET(
+		"/reviews/:reviewId/meta/analysis",
+		authMiddleware,
+		reviewMiddleware,
+		reviewerMiddleware,
+		metaHandler.Analysis,
+	)
+	r.GET(
+		"/reviews/:reviewId/meta/forest.svg",
+		authMiddleware,
+		reviewMiddleware,
+		reviewerMiddleware,
+		metaHandler.Forest,
+	)
+	r.GET(
+		"/reviews/:reviewId/meta/funnel.svg",
+		authMiddleware,
+		reviewMiddleware,
+		reviewerMiddleware,
+		metaHandler.Funnel,
+	)
+	r.GET(
+		"/reviews/:reviewId/meta/analysis.json",
+		authMiddleware,
+		reviewMiddleware,
+		reviewerMiddleware,
+		metaHandler.Json,
+	)
+}
@@ -0,0 +1,118 @@
+package handler
+
+import (
+	"bytes"
+	"github.com/gin-gonic/gin"
+	"golang.org/x/exp/slog"
+	"html/template"
+	"sci-review/common"
+	"sci-review/model"
+	"sci-review/prisma"
+	"sci-review/service"
+)
+
+type PrismaHandler struct {
+	PrismaService *service.PrismaService
+}
+
+func NewPrismaHandler(prismaService *service.PrismaService) *PrismaHandler {
+	return &PrismaHandler{PrismaService: prismaService}
+}
+
+func (ph *PrismaHandler) Show(c *gin.Context) {
+	principal := c.MustGet("principal").(*model.Principal)
+	review := c.MustGet("review").(*model.Review)
+	pageData := common.PageData{
+		Title:  "PRISMA flow diagram",
+		Active: "reviews",
+		User:   principal,
+	}
+
+	counts, err := ph.PrismaService.Counts(review)
+	if err != nil {
+		pageData.Message = err.Error()
+		c.HTML(500, "prisma/show.html", gin.H{
+			"pageData": pageData,
+			"review":   review,
+		})
+		return
+	}
+
+	var diagram bytes.Buffer
+	if err := prisma.WriteSvg(&diagram, *counts); err != nil {
+		slog.Error("prisma show", "error", err.Error())
+		pageData.Message = "Could not draw the flow diagram"
+	}
+
+	c.HTML(200, "prisma/show.html", gin.H{
+		"pageData": pageData,
+		"review":   review,
+		"counts":   counts,
+		"diagram":  template.HTML(diagram.String()),
+	})
+}
+
+func (ph *PrismaHandler) Svg(c *gin.Context) {
+	review := c.MustGet("review").(*model.Review)
+
+	counts, err := ph.PrismaService.Counts(review)
+	if err != nil {
+		c.JSON(500, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.Header("Content-Disposition", "attachment; filename=prisma.svg")
+	c.Header("Content-Type", "image/svg+xml")
+	if err := prisma.WriteSvg(c.Writer, *counts); err != nil {
+		slog.Error("prisma svg", "error", err.Error())
+		c.Status(500)
+	}
+}
+
+func (ph *PrismaHandler) Png(c *gin.Context) {
+	review := c.MustGet("review").(*model.Review)
+
+	counts, err := ph.PrismaService.Counts(review)
+	if err != nil {
+		c.JSON(500, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.Header("Content-Disposition", "attachment; filename=prisma.png")
+	c.Header("Content-Type", "image/png")
+	if err := prisma.WritePng(c.Writer, *counts); err != nil {
+		slog.Error("prisma png", "error", err.Error())
+		c.Status(500)
+	}
+}
+
+func RegisterPrismaHandler(
+	r *gin.Engine,
+	prismaService *service.PrismaService,
+	authMiddleware gin.HandlerFunc,
+	reviewMiddleware gin.HandlerFunc,
+	reviewerMiddleware gin.HandlerFunc,
+) {
+	prismaHandler := NewPrismaHandler(prismaService)
+	r.GET(
+		"/reviews/:reviewId/prisma",
+		authMiddleware,
+		reviewMiddleware,
+		reviewerMiddleware,
+		prismaHandler.Show,
+	)
+	r.GET(
+		"/reviews/:reviewId/prisma/diagram.svg",
+		authMiddleware,
+		reviewMiddleware,
+		reviewerMiddleware,
+		prismaHandler.Svg,
+	)
+	r.GET(
+		"/reviews/:reviewId/prisma/diagram.png",
+		authMiddleware,
+		reviewMiddleware,
+		reviewerMiddleware,
+		prismaHandler.Png,
+	)
+}
@@ -0,0 +1,253 @@
+package handler
+
+import (
+	"github.com/gin-gonic/gin"
+	"golang.org/x/exp/slog"
+	"html/template"
+	"net/url"
+	"sci-review/citation"
+	"sci-review/common"
+	"sci-review/form"
+	"sci-review/model"
+	"sci-review/service"
+	"strconv"
+)
+
+const referencesPerPage = 50
+
+type ReferenceHandler struct {
+	ReferenceService *service.ReferenceService
+}
+
+func NewReferenceHandler(referenceService *service.ReferenceService) *ReferenceHandler {
+	return &ReferenceHandler{ReferenceService: referenceService}
+}
+
+func (rh *ReferenceHandler) Index(c *gin.Context) {
+	principal := c.MustGet("principal").(*model.Principal)
+	review := c.MustGet("review").(*model.Review)
+
+	pageData := common.PageData{
+		Title:  "References",
+		Active: "reviews",
+		User:   principal,
+	}
+
+	page, err := strconv.Atoi(c.DefaultQuery("page", "1"))
+	if err != nil || page < 1 {
+		page = 1
+	}
+
+	filter := model.ReferenceFilter{}
+	if err := c.ShouldBindQuery(&filter); err != nil {
+		slog.Warn("reference index", "error", err.Error())
+		pageData.Message = "Invalid filter"
+	}
+
+	total, err := rh.ReferenceService.CountByFilter(review.Id, filter)
+	if err != nil {
+		return
+	}
+
+	references, err := rh.ReferenceService.FindAllByFilter(review.Id, filter, referencesPerPage, (page-1)*referencesPerPage)
+	if err != nil {
+		return
+	}
+
+	nextPage := 0
+	if page*referencesPerPage < total {
+		nextPage = page + 1
+	}
+
+	c.HTML(200, "references/index.html", gin.H{
+		"pageData":   pageData,
+		"review":     review,
+		"references": references,
+		"filter":     filter,
+		"query":      filterQuery(filter),
+		"total":      total,
+		"page":       page,
+		"prevPage":   page - 1,
+		"nextPage":   nextPage,
+	})
+}
+
+func (rh *ReferenceHandler) ImportForm(c *gin.Context) {
+	principal := c.MustGet("principal").(*model.Principal)
+	review := c.MustGet("review").(*model.Review)
+
+	pageData := common.PageData{
+		Title:  "Import References",
+		Active: "reviews",
+		User:   principal,
+	}
+
+	c.HTML(200, "references/import.html", gin.H{
+		"pageData": pageData,
+		"review":   review,
+	})
+}
+
+func (rh *ReferenceHandler) Import(c *gin.Context) {
+	principal := c.MustGet("principal").(*model.Principal)
+	review := c.MustGet("review").(*model.Review)
+
+	pageData := common.PageData{
+		Title:  "Import References",
+		Active: "reviews",
+		User:   principal,
+	}
+
+	importForm := new(form.ReferenceImportForm)
+	if err := c.ShouldBind(&importForm); err != nil {
+		slog.Warn("reference import", "error", err.Error())
+		pageData.Message = "Invalid form data"
+		c.HTML(200, "references/import.html", gin.H{
+			"pageData":   pageData,
+			"importForm": importForm,
+			"review":     review,
+		})
+		return
+	}
+	slog.Info("reference import", "data", importForm)
+
+	if err := common.Validate(importForm); len(err) > 0 {
+		slog.Warn("reference import", "error", "validation error")
+		pageData.Errors = err
+		c.HTML(400, "references/import.html", gin.H{
+			"pageData":   pageData,
+			"importForm": importForm,
+			"review":     review,
+		})
+		return
+	}
+
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		slog.Warn("reference import", "error", err.Error())
+		pageData.Message = "File is required"
+		c.HTML(400, "references/import.html", gin.H{
+			"pageData":   pageData,
+			"importForm": importForm,
+			"review":     review,
+		})
+		return
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		slog.Error("reference import", "error", err.Error())
+		pageData.Message = "Could not read the uploaded file"
+		c.HTML(400, "references/import.html", gin.H{
+			"pageData":   pageData,
+			"importForm": importForm,
+			"review":     review,
+		})
+		return
+	}
+	defer file.Close()
+
+	summary, err := rh.ReferenceService.Import(*importForm, file, review.Id, principal.Id)
+	if err != nil {
+		pageData.Message = err.Error()
+		c.HTML(409, "references/import.html", gin.H{
+			"pageData":   pageData,
+			"importForm": importForm,
+			"review":     review,
+		})
+		return
+	}
+
+	c.HTML(200, "references/import.html", gin.H{
+		"pageData":   pageData,
+		"importForm": importForm,
+		"review":     review,
+		"summary":    summary,
+	})
+}
+
+func (rh *ReferenceHandler) Export(c *gin.Context) {
+	review := c.MustGet("review").(*model.Review)
+
+	exportForm := new(form.ReferenceExportForm)
+	if err := c.ShouldBindQuery(exportForm); err != nil {
+		c.JSON(400, gin.H{"error": err.Error()})
+		return
+	}
+	if err := common.Validate(exportForm); len(err) > 0 {
+		c.JSON(400, gin.H{"errors": err})
+		return
+	}
+
+	filename := "references.bib"
+	contentType := "application/x-bibtex"
+	if exportForm.Format == citation.FormatCslJson {
+		filename = "references.json"
+		contentType = "application/vnd.citationstyles.csl+json"
+	}
+
+	c.Header("Content-Disposition", "attachment; filename="+filename)
+	c.Header("Content-Type", contentType)
+	err := rh.ReferenceService.Export(review.Id, exportForm.ReferenceFilter, exportForm.Format, c.Writer)
+	if err != nil {
+		slog.Error("reference export", "error", err.Error())
+		c.Status(500)
+	}
+}
+
+// filterQuery encodes the active filter so it survives pagination and export
+// links.
+func filterQuery(filter model.ReferenceFilter) template.URL {
+	values := url.Values{}
+	if filter.Query != "" {
+		values.Set("q", filter.Query)
+	}
+	if filter.SourceDatabase != "" {
+		values.Set("source", string(filter.SourceDatabase))
+	}
+	if filter.YearFrom != 0 {
+		values.Set("year_from", strconv.Itoa(filter.YearFrom))
+	}
+	if filter.YearTo != 0 {
+		values.Set("year_to", strconv.Itoa(filter.YearTo))
+	}
+	return template.URL(values.Encode())
+}
+
+func RegisterReferenceHandler(
+	r *gin.Engine,
+	referenceService *service.ReferenceService,
+	authMiddleware gin.HandlerFunc,
+	reviewMiddleware gin.HandlerFunc,
+	reviewerMiddleware gin.HandlerFunc,
+) {
+	referenceHandler := NewReferenceHandler(referenceService)
+	r.GET(
+		"/reviews/:reviewId/references",
+		authMiddleware,
+		reviewMiddleware,
+		reviewerMiddleware,
+		referenceHandler.Index,
+	)
+	r.GET(
+		"/reviews/:reviewId/references/import",
+		authMiddleware,
+		reviewMiddleware,
+		reviewerMiddleware,
+		referenceHandler.ImportForm,
+	)
+	r.POST(
+		"/reviews/:reviewId/references/import",
+		authMiddleware,
+		reviewMiddleware,
+		reviewerMiddleware,
+		referenceHandler.Import,
+	)
+	r.GET(
+		"/reviews/:reviewId/references/export",
+		authMiddleware,
+		reviewMiddleware,
+		reviewerMiddleware,
+		referenceHandler.Export,
+	)
+}
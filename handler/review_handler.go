@@ -12,10 +12,11 @@ import (
 type ReviewHandler struct {
 	ReviewService        *service.ReviewService
 	InvestigationService *service.InvestigationService
+	ScreeningService     *service.ScreeningService
 }
 
-func NewReviewHandler(reviewService *service.ReviewService, investigationService *service.InvestigationService) *ReviewHandler {
-	return &ReviewHandler{ReviewService: reviewService, InvestigationService: investigationService}
+func NewReviewHandler(reviewService *service.ReviewService, investigationService *service.InvestigationService, screeningService *service.ScreeningService) *ReviewHandler {
+	return &ReviewHandler{ReviewService: reviewService, InvestigationService: investigationService, ScreeningService: screeningService}
 }
 
 func (rh *ReviewHandler) CreateForm(c *gin.Context) {
@@ -107,10 +108,16 @@ func (rh *ReviewHandler) Show(c *gin.Context) {
 		User:   principal,
 	}
 
+	agreement, err := rh.ScreeningService.Agreement(review)
+	if err != nil {
+		pageData.Message = err.Error()
+	}
+
 	c.HTML(200, "reviews/show.html", gin.H{
 		"pageData":       pageData,
 		"review":         review,
 		"investigations": investigations,
+		"agreement":      agreement,
 	})
 }
 
@@ -118,11 +125,12 @@ func RegisterReviewHandler(
 	r *gin.Engine,
 	reviewService *service.ReviewService,
 	investigationService *service.InvestigationService,
+	screeningService *service.ScreeningService,
 	authMiddleware gin.HandlerFunc,
 	reviewMiddleware gin.HandlerFunc,
 	investigationMiddleware gin.HandlerFunc,
 ) {
-	reviewHandler := NewReviewHandler(reviewService, investigationService)
+	reviewHandler := NewReviewHandler(reviewService, investigationService, screeningService)
 	r.GET("/reviews", authMiddleware, reviewHandler.Index)
 	r.GET("/reviews/new", authMiddleware, reviewHandler.CreateForm)
 	r.POST("/reviews/new", authMiddleware, reviewHandler.Create)
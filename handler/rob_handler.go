@@ -0,0 +1,278 @@
+package handler
+
+import (
+	"bytes"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"golang.org/x/exp/slog"
+	"html/template"
+	"net/url"
+	"sci-review/common"
+	"sci-review/form"
+	"sci-review/model"
+	"sci-review/rob"
+	"sci-review/service"
+	"strings"
+)
+
+type RobHandler struct {
+	RobService *service.RobService
+}
+
+func NewRobHandler(robService *service.RobService) *RobHandler {
+	return &RobHandler{RobService: robService}
+}
+
+// robTool reads the tool from the query string, falling back to RoB 2.
+func robTool(c *gin.Context) rob.Tool {
+	tool, found := rob.FindTool(c.Query("tool"))
+	if !found {
+		return rob.RoB2
+	}
+	return tool
+}
+
+func robUrl(review *model.Review, path string, tool rob.Tool) string {
+	return "/reviews/" + review.Id.String() + "/rob" + path + "?tool=" + url.QueryEscape(tool.Id)
+}
+
+func (rh *RobHandler) Index(c *gin.Context) {
+	principal := c.MustGet("principal").(*model.Principal)
+	review := c.MustGet("review").(*model.Review)
+	tool := robTool(c)
+	weighted := c.Query("weighted") == "true"
+	pageData := common.PageData{
+		Title:   "Risk of bias",
+		Active:  "reviews",
+		User:    principal,
+		Message: c.Query("message"),
+	}
+
+	studies, err := rh.RobService.Studies(review, tool)
+	if err != nil {
+		pageData.Message = err.Error()
+	}
+
+	rows, err := rh.RobService.Summary(review, tool)
+	if err != nil {
+		pageData.Message = err.Error()
+	}
+
+	var trafficLight, summary bytes.Buffer
+	if len(rows) > 0 {
+		if err := rob.WriteTrafficLight(&trafficLight, tool, rows); err != nil {
+			slog.Error("rob traffic light", "error", err.Error())
+		}
+		if err := rob.WriteSummary(&summary, tool, rows, weighted); err != nil {
+			slog.Error("rob summary", "error", err.Error())
+		}
+	}
+
+	c.HTML(200, "rob/index.html", gin.H{
+		"pageData":     pageData,
+		"review":       review,
+		"tool":         tool,
+		"tools":        rob.Tools,
+		"weighted":     weighted,
+		"studies":      studies,
+		"trafficLight": template.HTML(trafficLight.String()),
+		"summary":      template.HTML(summary.String()),
+	})
+}
+
+func (rh *RobHandler) TrafficLight(c *gin.Context) {
+	review := c.MustGet("review").(*model.Review)
+	tool := robTool(c)
+
+	rows, err := rh.RobService.Summary(review, tool)
+	if err != nil {
+		c.JSON(500, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.Header("Content-Disposition", "attachment; filename=traffic-light.svg")
+	c.Header("Content-Type", "image/svg+xml")
+	if err := rob.WriteTrafficLight(c.Writer, tool, rows); err != nil {
+		slog.Error("rob traffic light", "error", err.Error())
+		c.Status(500)
+	}
+}
+
+func (rh *RobHandler) Summary(c *gin.Context) {
+	review := c.MustGet("review").(*model.Review)
+	tool := robTool(c)
+
+	rows, err := rh.RobService.Summary(review, tool)
+	if err != nil {
+		c.JSON(500, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.Header("Content-Disposition", "attachment; filename=summary.svg")
+	c.Header("Content-Type", "image/svg+xml")
+	if err := rob.WriteSummary(c.Writer, tool, rows, c.Query("weighted") == "true"); err != nil {
+		slog.Error("rob summary", "error", err.Error())
+		c.Status(500)
+	}
+}
+
+func (rh *RobHandler) renderRecord(c *gin.Context, status int, pageData common.PageData, reference *model.Reference, tool rob.Tool, answers rob.Answers, assessment *model.RobAssessment, robForm *form.RobAssessmentForm) {
+	review := c.MustGet("review").(*model.Review)
+
+	c.HTML(status, "rob/record.html", gin.H{
+		"pageData":   pageData,
+		"review":     review,
+		"reference":  reference,
+		"tool":       tool,
+		"choices":    rob.AnswerChoices,
+		"answers":    answers,
+		"assessment": assessment,
+		"robForm":    robForm,
+	})
+}
+
+func (rh *RobHandler) Show(c *gin.Context) {
+	principal := c.MustGet("principal").(*model.Principal)
+	review := c.MustGet("review").(*model.Review)
+	reviewer := c.MustGet("reviewer").(*model.Reviewer)
+	tool := robTool(c)
+	pageData := common.PageData{
+		Title:   "Assess risk of bias",
+		Active:  "reviews",
+		User:    principal,
+		Message: c.Query("message"),
+	}
+
+	referenceId, err := uuid.Parse(c.Param("referenceId"))
+	if err != nil {
+		c.Redirect(302, robUrl(review, "", tool))
+		return
+	}
+
+	reference, err := rh.RobService.FindStudy(review, referenceId)
+	if err != nil {
+		c.Redirect(302, robUrl(review, "", tool)+"&message="+url.QueryEscape(err.Error()))
+		return
+	}
+
+	assessment, err := rh.RobService.Find(referenceId, reviewer, tool)
+	if err != nil {
+		pageData.Message = err.Error()
+	}
+
+	answers := rob.Answers{}
+	robForm := &form.RobAssessmentForm{Weight: 1}
+	if assessment != nil {
+		for id, answer := range assessment.Answers {
+			answers[id] = rob.Answer(answer)
+		}
+		robForm.Weight = assessment.Weight
+		robForm.Note = assessment.Note
+	}
+
+	rh.renderRecord(c, 200, pageData, reference, tool, answers, assessment, robForm)
+}
+
+func (rh *RobHandler) Save(c *gin.Context) {
+	principal := c.MustGet("principal").(*model.Principal)
+	review := c.MustGet("review").(*model.Review)
+	reviewer := c.MustGet("reviewer").(*model.Reviewer)
+	tool := robTool(c)
+	pageData := common.PageData{
+		Title:  "Assess risk of bias",
+		Active: "reviews",
+		User:   principal,
+	}
+
+	referenceId, err := uuid.Parse(c.Param("referenceId"))
+	if err != nil {
+		c.Redirect(302, robUrl(review, "", tool))
+		return
+	}
+
+	reference, err := rh.RobService.FindStudy(review, referenceId)
+	if err != nil {
+		c.Redirect(302, robUrl(review, "", tool)+"&message="+url.QueryEscape(err.Error()))
+		return
+	}
+
+	robForm := new(form.RobAssessmentForm)
+	if err := c.ShouldBind(&robForm); err != nil {
+		slog.Warn("rob save", "error", err.Error())
+		pageData.Message = "Invalid form data"
+		rh.renderRecord(c, 400, pageData, reference, tool, rob.Answers{}, nil, robForm)
+		return
+	}
+	slog.Info("rob save", "data", robForm)
+
+	answers := rob.Answers{}
+	for input, values := range c.Request.PostForm {
+		if id, found := strings.CutPrefix(input, "q."); found && len(values) > 0 {
+			answers[id] = rob.Answer(values[0])
+		}
+	}
+
+	errs := common.Validate(robForm)
+	for _, question := range tool.Unanswered(answers) {
+		errs = append(errs, common.Message("Question "+question.Id, "required", ""))
+	}
+	if len(errs) > 0 {
+		slog.Warn("rob save", "error", "validation error")
+		pageData.Errors = errs
+		rh.renderRecord(c, 400, pageData, reference, tool, answers, nil, robForm)
+		return
+	}
+
+	if _, err := rh.RobService.Save(review, reviewer, referenceId, tool, answers, *robForm); err != nil {
+		pageData.Message = err.Error()
+		rh.renderRecord(c, 409, pageData, reference, tool, answers, nil, robForm)
+		return
+	}
+
+	c.Redirect(302, robUrl(review, "/"+referenceId.String(), tool)+"&message="+url.QueryEscape("Assessment saved"))
+}
+
+func RegisterRobHandler(
+	r *gin.Engine,
+	robService *service.RobService,
+	authMiddleware gin.HandlerFunc,
+	reviewMiddleware gin.HandlerFunc,
+	reviewerMiddleware gin.HandlerFunc,
+) {
+	robHandler := NewRobHandler(robService)
+	r.GET(
+		"/reviews/:reviewId/rob",
+		authMiddleware,
+		reviewMiddleware,
+		reviewerMiddleware,
+		robHandler.Index,
+	)
+	r.GET(
+		"/reviews/:reviewId/rob/traffic-light.svg",
+		authMiddleware,
+		reviewMiddleware,
+		reviewerMiddleware,
+		robHandler.TrafficLight,
+	)
+	r.GET(
+		"/reviews/:reviewId/rob/summary.svg",
+		authMiddleware,
+		reviewMiddleware,
+		reviewerMiddleware,
+		robHandler.Summary,
+	)
+	r.GET(
+		"/reviews/:reviewId/rob/:referenceId",
+		authMiddleware,
+		reviewMiddleware,
+		reviewerMiddleware,
+		robHandler.Show,
+	)
+	r.POST(
+		"/reviews/:reviewId/rob/:referenceId",
+		authMiddleware,
+		reviewMiddleware,
+		reviewerMiddleware,
+		robHandler.Save,
+	)
+}
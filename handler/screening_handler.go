@@ -0,0 +1,527 @@
+package handler
+
+import (
+	"errors"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"golang.org/x/exp/slog"
+	"net/url"
+	"sci-review/common"
+	"sci-review/form"
+	"sci-review/model"
+	"sci-review/service"
+)
+
+type ScreeningHandler struct {
+	ScreeningService       *service.ScreeningService
+	ReviewService          *service.ReviewService
+	ExclusionReasonService *service.ExclusionReasonService
+}
+
+func NewScreeningHandler(screeningService *service.ScreeningService, reviewService *service.ReviewService, exclusionReasonService *service.ExclusionReasonService) *ScreeningHandler {
+	return &ScreeningHandler{
+		ScreeningService:       screeningService,
+		ReviewService:          reviewService,
+		ExclusionReasonService: exclusionReasonService,
+	}
+}
+
+// screeningStage reads the stage from the query string, falling back to the
+// title/abstract stage.
+func screeningStage(c *gin.Context) model.ScreeningStage {
+	stage := model.ScreeningStage(c.Query("stage"))
+	for _, known := range model.ScreeningStages {
+		if stage == known {
+			return stage
+		}
+	}
+	return model.StageTitleAbstract
+}
+
+func screeningUrl(review *model.Review, path string, stage model.ScreeningStage) string {
+	return "/reviews/" + review.Id.String() + "/screening" + path + "?stage=" + string(stage)
+}
+
+func (sh *ScreeningHandler) Index(c *gin.Context) {
+	principal := c.MustGet("principal").(*model.Principal)
+	review := c.MustGet("review").(*model.Review)
+	reviewer := c.MustGet("reviewer").(*model.Reviewer)
+	stage := screeningStage(c)
+
+	pageData := common.PageData{
+		Title:   "Screening",
+		Active:  "reviews",
+		User:    principal,
+		Message: c.Query("message"),
+	}
+
+	progress, err := sh.ScreeningService.Progress(review, stage)
+	if err != nil {
+		pageData.Message = err.Error()
+		c.HTML(500, "screening/index.html", gin.H{
+			"pageData": pageData,
+			"review":   review,
+			"stage":    stage,
+		})
+		return
+	}
+
+	counts, err := sh.ScreeningService.StatusCounts(review, stage)
+	if err != nil {
+		pageData.Message = err.Error()
+	}
+
+	reviewers, err := sh.ReviewService.FindReviewers(review.Id)
+	if err != nil {
+		pageData.Message = err.Error()
+	}
+
+	reasonCounts, err := sh.ScreeningService.ReasonCounts(review, stage)
+	if err != nil {
+		pageData.Message = err.Error()
+	}
+
+	notRetrieved := []model.Reference{}
+	if stage == model.StageFullText {
+		notRetrieved, err = sh.ScreeningService.NotRetrieved(review)
+		if err != nil {
+			pageData.Message = err.Error()
+		}
+	}
+
+	c.HTML(200, "screening/index.html", gin.H{
+		"pageData":     pageData,
+		"review":       review,
+		"reviewer":     reviewer,
+		"stage":        stage,
+		"stages":       model.ScreeningStages,
+		"progress":     progress,
+		"counts":       counts,
+		"conflicts":    counts[model.StatusConflict],
+		"statuses":     model.ScreeningStatuses,
+		"required":     review.ReviewType.RequiredScreeners(),
+		"adjudicator":  model.Adjudicator(reviewers),
+		"reasonCounts": reasonCounts,
+		"notRetrieved": notRetrieved,
+	})
+}
+
+func (sh *ScreeningHandler) SetAdjudicator(c *gin.Context) {
+	review := c.MustGet("review").(*model.Review)
+	reviewer := c.MustGet("reviewer").(*model.Reviewer)
+
+	adjudicatorForm := new(form.AdjudicatorForm)
+	if err := c.ShouldBind(&adjudicatorForm); err != nil {
+		slog.Warn("screening adjudicator", "error", err.Error())
+		c.Redirect(302, screeningUrl(review, "", model.StageTitleAbstract)+"&message="+url.QueryEscape("Invalid form data"))
+		return
+	}
+	slog.Info("screening adjudicator", "data", adjudicatorForm)
+
+	if err := common.Validate(adjudicatorForm); len(err) > 0 {
+		slog.Warn("screening adjudicator", "error", "validation error")
+		c.Redirect(302, screeningUrl(review, "", model.StageTitleAbstract)+"&message="+url.QueryEscape("Invalid reviewer"))
+		return
+	}
+
+	err := sh.ReviewService.SetAdjudicator(review.Id, reviewer, uuid.MustParse(adjudicatorForm.ReviewerId))
+	if err != nil {
+		c.Redirect(302, screeningUrl(review, "", model.StageTitleAbstract)+"&message="+url.QueryEscape(err.Error()))
+		return
+	}
+
+	c.Redirect(302, screeningUrl(review, "", model.StageTitleAbstract)+"&message="+url.QueryEscape("Adjudicator updated"))
+}
+
+func (sh *ScreeningHandler) Conflicts(c *gin.Context) {
+	principal := c.MustGet("principal").(*model.Principal)
+	review := c.MustGet("review").(*model.Review)
+	reviewer := c.MustGet("reviewer").(*model.Reviewer)
+	stage := screeningStage(c)
+
+	pageData := common.PageData{
+		Title:   "Screening conflicts",
+		Active:  "reviews",
+		User:    principal,
+		Message: c.Query("message"),
+	}
+
+	conflicts, err := sh.ScreeningService.Conflicts(review, stage)
+	if err != nil {
+		pageData.Message = err.Error()
+	}
+
+	isAdjudicator, err := sh.ScreeningService.IsAdjudicator(review.Id, reviewer)
+	if err != nil {
+		pageData.Message = err.Error()
+	}
+
+	c.HTML(200, "screening/conflicts.html", gin.H{
+		"pageData":      pageData,
+		"review":        review,
+		"stage":         stage,
+		"conflicts":     conflicts,
+		"isAdjudicator": isAdjudicator,
+	})
+}
+
+func (sh *ScreeningHandler) Next(c *gin.Context) {
+	review := c.MustGet("review").(*model.Review)
+	reviewer := c.MustGet("reviewer").(*model.Reviewer)
+	stage := screeningStage(c)
+
+	reference, err := sh.ScreeningService.Next(review, reviewer, stage)
+	if errors.Is(err, service.ErrorNothingToScreen) {
+		c.Redirect(302, screeningUrl(review, "", stage)+"&message="+url.QueryEscape(err.Error()))
+		return
+	}
+	if err != nil {
+		c.Redirect(302, screeningUrl(review, "", stage))
+		return
+	}
+
+	c.Redirect(302, screeningUrl(review, "/"+reference.Id.String(), stage))
+}
+
+func (sh *ScreeningHandler) renderRecord(c *gin.Context, status int, pageData common.PageData, reference *model.Reference, decisionForm *form.ScreeningDecisionForm) {
+	review := c.MustGet("review").(*model.Review)
+	adjudication, err := sh.ScreeningService.FindAdjudication(reference.Id, decisionForm.Stage)
+	if err != nil {
+		pageData.Message = err.Error()
+	}
+	reasons, err := sh.ExclusionReasonService.FindAll(review.Id)
+	if err != nil {
+		pageData.Message = err.Error()
+	}
+	c.HTML(status, "screening/record.html", gin.H{
+		"pageData":     pageData,
+		"review":       review,
+		"reference":    reference,
+		"decisionForm": decisionForm,
+		"adjudication": adjudication,
+		"reasons":      reasons,
+	})
+}
+
+func (sh *ScreeningHandler) Show(c *gin.Context) {
+	principal := c.MustGet("principal").(*model.Principal)
+	review := c.MustGet("review").(*model.Review)
+	reviewer := c.MustGet("reviewer").(*model.Reviewer)
+	stage := screeningStage(c)
+
+	pageData := common.PageData{
+		Title:  "Screening",
+		Active: "reviews",
+		User:   principal,
+	}
+
+	referenceId, err := uuid.Parse(c.Param("referenceId"))
+	if err != nil {
+		c.Redirect(302, screeningUrl(review, "", stage))
+		return
+	}
+
+	reference, err := sh.ScreeningService.FindScreenable(review, referenceId, stage)
+	if err != nil {
+		c.Redirect(302, screeningUrl(review, "", stage)+"&message="+url.QueryEscape(err.Error()))
+		return
+	}
+
+	decisionForm := &form.ScreeningDecisionForm{Stage: stage}
+	decision, err := sh.ScreeningService.FindDecision(reference.Id, reviewer, stage)
+	if err != nil {
+		pageData.Message = err.Error()
+	}
+	if decision != nil {
+		decisionForm.Decision = decision.Decision
+		decisionForm.Note = decision.Note
+		if decision.ExclusionReasonId.Valid {
+			decisionForm.ExclusionReasonId = decision.ExclusionReasonId.UUID.String()
+		}
+	}
+
+	sh.renderRecord(c, 200, pageData, reference, decisionForm)
+}
+
+func (sh *ScreeningHandler) Decide(c *gin.Context) {
+	principal := c.MustGet("principal").(*model.Principal)
+	review := c.MustGet("review").(*model.Review)
+	reviewer := c.MustGet("reviewer").(*model.Reviewer)
+
+	pageData := common.PageData{
+		Title:  "Screening",
+		Active: "reviews",
+		User:   principal,
+	}
+
+	referenceId, err := uuid.Parse(c.Param("referenceId"))
+	if err != nil {
+		c.Redirect(302, screeningUrl(review, "", model.StageTitleAbstract))
+		return
+	}
+
+	reference, err := sh.ScreeningService.FindReference(referenceId, review.Id)
+	if err != nil {
+		c.Redirect(302, screeningUrl(review, "", model.StageTitleAbstract)+"&message="+url.QueryEscape(err.Error()))
+		return
+	}
+
+	decisionForm := new(form.ScreeningDecisionForm)
+	if err := c.ShouldBind(&decisionForm); err != nil {
+		slog.Warn("screening decide", "error", err.Error())
+		pageData.Message = "Invalid form data"
+		sh.renderRecord(c, 400, pageData, reference, decisionForm)
+		return
+	}
+	slog.Info("screening decide", "data", decisionForm)
+
+	if err := common.Validate(decisionForm); len(err) > 0 {
+		slog.Warn("screening decide", "error", "validation error")
+		pageData.Errors = err
+		sh.renderRecord(c, 400, pageData, reference, decisionForm)
+		return
+	}
+
+	if _, err := sh.ScreeningService.Decide(review, reviewer, reference.Id, *decisionForm); err != nil {
+		pageData.Message = err.Error()
+		sh.renderRecord(c, 409, pageData, reference, decisionForm)
+		return
+	}
+
+	c.Redirect(302, screeningUrl(review, "/next", decisionForm.Stage))
+}
+
+func (sh *ScreeningHandler) renderAdjudication(c *gin.Context, status int, pageData common.PageData, referenceId uuid.UUID, adjudicationForm *form.ScreeningAdjudicationForm) {
+	review := c.MustGet("review").(*model.Review)
+	reviewer := c.MustGet("reviewer").(*model.Reviewer)
+
+	record, err := sh.ScreeningService.FindRecord(review, referenceId, adjudicationForm.Stage)
+	if err != nil {
+		c.Redirect(302, screeningUrl(review, "/conflicts", adjudicationForm.Stage)+"&message="+url.QueryEscape(err.Error()))
+		return
+	}
+
+	history, err := sh.ScreeningService.History(referenceId)
+	if err != nil {
+		pageData.Message = err.Error()
+	}
+
+	reasons, err := sh.ExclusionReasonService.FindAll(review.Id)
+	if err != nil {
+		pageData.Message = err.Error()
+	}
+
+	isAdjudicator, err := sh.ScreeningService.IsAdjudicator(review.Id, reviewer)
+	if err != nil {
+		pageData.Message = err.Error()
+	}
+
+	c.HTML(status, "screening/adjudicate.html", gin.H{
+		"pageData":         pageData,
+		"review":           review,
+		"record":           record,
+		"history":          history,
+		"isAdjudicator":    isAdjudicator,
+		"adjudicationForm": adjudicationForm,
+		"reasons":          reasons,
+	})
+}
+
+func (sh *ScreeningHandler) AdjudicateForm(c *gin.Context) {
+	principal := c.MustGet("principal").(*model.Principal)
+	review := c.MustGet("review").(*model.Review)
+	stage := screeningStage(c)
+
+	pageData := common.PageData{
+		Title:  "Adjudication",
+		Active: "reviews",
+		User:   principal,
+	}
+
+	referenceId, err := uuid.Parse(c.Param("referenceId"))
+	if err != nil {
+		c.Redirect(302, screeningUrl(review, "/conflicts", stage))
+		return
+	}
+
+	adjudicationForm := &form.ScreeningAdjudicationForm{Stage: stage}
+	adjudication, err := sh.ScreeningService.FindAdjudication(referenceId, stage)
+	if err != nil {
+		pageData.Message = err.Error()
+	}
+	if adjudication != nil {
+		adjudicationForm.Decision = adjudication.Decision
+		adjudicationForm.Note = adjudication.Note
+		if adjudication.ExclusionReasonId.Valid {
+			adjudicationForm.ExclusionReasonId = adjudication.ExclusionReasonId.UUID.String()
+		}
+	}
+
+	sh.renderAdjudication(c, 200, pageData, referenceId, adjudicationForm)
+}
+
+func (sh *ScreeningHandler) Adjudicate(c *gin.Context) {
+	principal := c.MustGet("principal").(*model.Principal)
+	review := c.MustGet("review").(*model.Review)
+	reviewer := c.MustGet("reviewer").(*model.Reviewer)
+
+	pageData := common.PageData{
+		Title:  "Adjudication",
+		Active: "reviews",
+		User:   principal,
+	}
+
+	referenceId, err := uuid.Parse(c.Param("referenceId"))
+	if err != nil {
+		c.Redirect(302, screeningUrl(review, "/conflicts", model.StageTitleAbstract))
+		return
+	}
+
+	adjudicationForm := new(form.ScreeningAdjudicationForm)
+	if err := c.ShouldBind(&adjudicationForm); err != nil {
+		slog.Warn("screening adjudicate", "error", err.Error())
+		pageData.Message = "Invalid form data"
+		adjudicationForm.Stage = model.StageTitleAbstract
+		sh.renderAdjudication(c, 400, pageData, referenceId, adjudicationForm)
+		return
+	}
+	slog.Info("screening adjudicate", "data", adjudicationForm)
+
+	if err := common.Validate(adjudicationForm); len(err) > 0 {
+		slog.Warn("screening adjudicate", "error", "validation error")
+		pageData.Errors = err
+		sh.renderAdjudication(c, 400, pageData, referenceId, adjudicationForm)
+		return
+	}
+
+	if _, err := sh.ScreeningService.Adjudicate(review, reviewer, referenceId, *adjudicationForm); err != nil {
+		pageData.Message = err.Error()
+		sh.renderAdjudication(c, 409, pageData, referenceId, adjudicationForm)
+		return
+	}
+
+	c.Redirect(302, screeningUrl(review, "/conflicts", adjudicationForm.Stage))
+}
+
+func (sh *ScreeningHandler) Agreement(c *gin.Context) {
+	review := c.MustGet("review").(*model.Review)
+
+	agreement, err := sh.ScreeningService.Agreement(review)
+	if err != nil {
+		c.JSON(500, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(200, agreement)
+}
+
+func (sh *ScreeningHandler) Retrieval(c *gin.Context) {
+	review := c.MustGet("review").(*model.Review)
+
+	referenceId, err := uuid.Parse(c.Param("referenceId"))
+	if err != nil {
+		c.Redirect(302, screeningUrl(review, "", model.StageFullText))
+		return
+	}
+
+	retrievalForm := new(form.RetrievalForm)
+	if err := c.ShouldBind(&retrievalForm); err != nil {
+		slog.Warn("screening retrieval", "error", err.Error())
+		c.Redirect(302, screeningUrl(review, "", model.StageFullText)+"&message="+url.QueryEscape("Invalid form data"))
+		return
+	}
+	slog.Info("screening retrieval", "data", retrievalForm)
+
+	if err := sh.ScreeningService.SetNotRetrieved(review, referenceId, retrievalForm.NotRetrieved); err != nil {
+		c.Redirect(302, screeningUrl(review, "", model.StageFullText)+"&message="+url.QueryEscape(err.Error()))
+		return
+	}
+
+	if retrievalForm.NotRetrieved {
+		c.Redirect(302, screeningUrl(review, "/next", model.StageFullText))
+		return
+	}
+	c.Redirect(302, screeningUrl(review, "/"+referenceId.String(), model.StageFullText))
+}
+
+func RegisterScreeningHandler(
+	r *gin.Engine,
+	screeningService *service.ScreeningService,
+	reviewService *service.ReviewService,
+	exclusionReasonService *service.ExclusionReasonService,
+	authMiddleware gin.HandlerFunc,
+	reviewMiddleware gin.HandlerFunc,
+	reviewerMiddleware gin.HandlerFunc,
+) {
+	screeningHandler := NewScreeningHandler(screeningService, reviewService, exclusionReasonService)
+	r.GET(
+		"/reviews/:reviewId/screening",
+		authMiddleware,
+		reviewMiddleware,
+		reviewerMiddleware,
+		screeningHandler.Index,
+	)
+	r.GET(
+		"/reviews/:reviewId/screening/next",
+		authMiddleware,
+		reviewMiddleware,
+		reviewerMiddleware,
+		screeningHandler.Next,
+	)
+	r.GET(
+		"/reviews/:reviewId/screening/conflicts",
+		authMiddleware,
+		reviewMiddleware,
+		reviewerMiddleware,
+		screeningHandler.Conflicts,
+	)
+	r.GET(
+		"/reviews/:reviewId/screening/agreement",
+		authMiddleware,
+		reviewMiddleware,
+		reviewerMiddleware,
+		screeningHandler.Agreement,
+	)
+	r.POST(
+		"/reviews/:reviewId/screening/adjudicator",
+		authMiddleware,
+		reviewMiddleware,
+		reviewerMiddleware,
+		screeningHandler.SetAdjudicator,
+	)
+	r.GET(
+		"/reviews/:reviewId/screening/:referenceId/adjudicate",
+		authMiddleware,
+		reviewMiddleware,
+		reviewerMiddleware,
+		screeningHandler.AdjudicateForm,
+	)
+	r.POST(
+		"/reviews/:reviewId/screening/:referenceId/adjudicate",
+		authMiddleware,
+		reviewMiddleware,
+		reviewerMiddleware,
+		screeningHandler.Adjudicate,
+	)
+	r.POST(
+		"/reviews/:reviewId/screening/:referenceId/retrieval",
+		authMiddleware,
+		reviewMiddleware,
+		reviewerMiddleware,
+		screeningHandler.Retrieval,
+	)
+	r.GET(
+		"/reviews/:reviewId/screening/:referenceId",
+		authMiddleware,
+		reviewMiddleware,
+		reviewerMiddleware,
+		screeningHandler.Show,
+	)
+	r.POST(
+		"/reviews/:reviewId/screening/:referenceId",
+		authMiddleware,
+		reviewMiddleware,
+		reviewerMiddleware,
+		screeningHandler.Decide,
+	)
+}
@@ -72,7 +72,27 @@ func main() {
 	reviewService := service.NewReviewService(reviewRepoCache)
 	investigationRepoSql := repo.NewInvestigationRepoSql(db)
 	investigationRepoCache := cacheDecorator.NewInvestigationRepoCache(investigationRepoSql, appCache)
-	investigationService := service.NewInvestigationService(investigationRepoCache)
+	referenceRepoSql := repo.NewReferenceRepoSql(db)
+	investigationService := service.NewInvestigationService(investigationRepoCache, referenceRepoSql)
+	referenceService := service.NewReferenceService(referenceRepoSql)
+	duplicateClusterRepoSql := repo.NewDuplicateClusterRepoSql(db)
+	deduplicationService := service.NewDeduplicationService(referenceRepoSql, duplicateClusterRepoSql)
+	exclusionReasonRepoSql := repo.NewExclusionReasonRepoSql(db)
+	exclusionReasonService := service.NewExclusionReasonService(exclusionReasonRepoSql)
+	screeningRepoSql := repo.NewScreeningRepoSql(db)
+	screeningService := service.NewScreeningService(screeningRepoSql, referenceRepoSql, reviewRepoCache, exclusionReasonRepoSql)
+	prismaService := service.NewPrismaService(referenceRepoSql, screeningService)
+	extractionRepoSql := repo.NewExtractionRepoSql(db)
+	extractionService := service.NewExtractionService(extractionRepoSql, screeningService)
+	robRepoSql := repo.NewRobRepoSql(db)
+	robService := service.NewRobService(robRepoSql, screeningService)
+	checklistRepoSql := repo.NewChecklistRepoSql(db)
+	checklistService := service.NewChecklistService(checklistRepoSql, organizationRepo, screeningService)
+	gradeRepoSql := repo.NewGradeRepoSql(db)
+	gradeService := service.NewGradeService(gradeRepoSql)
+	metaRepoSql := repo.NewMetaRepoSql(db)
+	calculatorService := service.NewCalculatorService()
+	metaService := service.NewMetaService(metaRepoSql, screeningService, calculatorService, extractionService)
 	slog.Info("services initialized")
 
 	createAdminUser(userService)
@@ -81,6 +101,7 @@ func main() {
 	authMiddleware := handler.AuthMiddleware()
 	adminMiddleware := handler.AdminMiddleware()
 	reviewMiddleware := middleware.ReviewMiddleware(reviewService)
+	reviewerMiddleware := middleware.ReviewerMiddleware(reviewService)
 	investigationMiddleware := middleware.InvestigationMiddleware(investigationService)
 	slog.Info("middleware initialized")
 
@@ -96,8 +117,18 @@ func main() {
 	handler.RegisterUserHandler(r, userService)
 	handler.RegisterAdminHandler(r, userService, authMiddleware, adminMiddleware)
 	handler.RegisterOrganizationHandler(r, organizationService, authMiddleware)
-	handler.RegisterReviewHandler(r, reviewService, investigationService, authMiddleware, reviewMiddleware, investigationMiddleware)
-	handler.RegisterInvestigationHandler(r, reviewService, investigationService, authMiddleware, reviewMiddleware, investigationMiddleware)
+	handler.RegisterReviewHandler(r, reviewService, investigationService, screeningService, authMiddleware, reviewMiddleware, investigationMiddleware)
+	handler.RegisterInvestigationHandler(r, reviewService, investigationService, authMiddleware, reviewMiddleware, reviewerMiddleware, investigationMiddleware)
+	handler.RegisterReferenceHandler(r, referenceService, authMiddleware, reviewMiddleware, reviewerMiddleware)
+	handler.RegisterDuplicateHandler(r, deduplicationService, authMiddleware, reviewMiddleware, reviewerMiddleware)
+	handler.RegisterScreeningHandler(r, screeningService, reviewService, exclusionReasonService, authMiddleware, reviewMiddleware, reviewerMiddleware)
+	handler.RegisterExclusionReasonHandler(r, exclusionReasonService, authMiddleware, reviewMiddleware, reviewerMiddleware)
+	handler.RegisterPrismaHandler(r, prismaService, authMiddleware, reviewMiddleware, reviewerMiddleware)
+	handler.RegisterExtractionHandler(r, extractionService, authMiddleware, reviewMiddleware, reviewerMiddleware)
+	handler.RegisterRobHandler(r, robService, authMiddleware, reviewMiddleware, reviewerMiddleware)
+	handler.RegisterChecklistHandler(r, checklistService, authMiddleware, reviewMiddleware, reviewerMiddleware)
+	handler.RegisterGradeHandler(r, gradeService, authMiddleware, reviewMiddleware, reviewerMiddleware)
+	handler.RegisterMetaHandler(r, metaService, authMiddleware, reviewMiddleware, reviewerMiddleware)
 
 	slog.Info("routes registered")
 
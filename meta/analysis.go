@@ -0,0 +1,27 @@
+package meta
+
+// Excluded is a study left out of an analysis and the reason why.
+type Excluded struct {
+	Label  string `json:"label"`
+	Reason string `json:"reason"`
+}
+
+// Analysis is the meta-analysis of the studies reporting an outcome, with
+// the studies whose data could not be pooled with the measure and, when
+// there are enough studies, the tests for publication bias. The same
+// studies are split by a moderator into subgroups, or regressed on it, and
+// pooled again leaving one out and accumulating them. Studies without a
+// value of the moderator are listed in Unmoderated.
+type Analysis struct {
+	Outcome     string            `json:"outcome"`
+	Studies     []Study           `json:"studies"`
+	Excluded    []Excluded        `json:"excluded"`
+	Result      *Result           `json:"result"`
+	Bias        *PublicationBias  `json:"bias,omitempty"`
+	Moderator   string            `json:"moderator,omitempty"`
+	Subgroups   *SubgroupAnalysis `json:"subgroups,omitempty"`
+	Regression  *Regression       `json:"regression,omitempty"`
+	Unmoderated []string          `json:"unmoderated,omitempty"`
+	LeaveOneOut []Sensitivity     `json:"leaveOneOut,omitempty"`
+	Cumulative  []Sensitivity     `json:"cumulative,omitempty"`
+}
@@ -0,0 +1,270 @@
+package meta
+
+import (
+	"errors"
+	"math"
+	"sort"
+	"strconv"
+)
+
+// MinBiasStudies is the number of studies below which tests for funnel plot
+// asymmetry are too weak to tell chance from bias, as the Cochrane Handbook
+// advises.
+const MinBiasStudies = 10
+
+var (
+	ErrorFewStudies = errors.New("tests for funnel plot asymmetry need at least 10 studies")
+)
+
+// Egger is Egger's regression test: the regression of the standardized
+// effects on their precision, whose intercept departs from zero when the
+// funnel plot is asymmetric.
+type Egger struct {
+	Intercept float64 `json:"intercept"`
+	SE        float64 `json:"se"`
+	Slope     float64 `json:"slope"`
+	T         float64 `json:"t"`
+	Df        int     `json:"df"`
+	P         float64 `json:"p"`
+}
+
+// Begg is Begg and Mazumdar's rank correlation test: Kendall's tau between
+// the standardized effects and their variances, tested with the normal
+// approximation.
+type Begg struct {
+	Tau float64 `json:"tau"`
+	Z   float64 `json:"z"`
+	P   float64 `json:"p"`
+}
+
+// TrimFill is the trim-and-fill analysis of Duval and Tweedie with the L0
+// estimator: the studies missing on one side of the funnel plot, filled in
+// as mirror images of the most extreme studies of the other side, and the
+// pooled result with them.
+type TrimFill struct {
+	Side   string  `json:"side"`
+	K0     int     `json:"k0"`
+	Filled []Study `json:"filled"`
+	Result *Result `json:"result"`
+}
+
+// PublicationBias gathers the tests for funnel plot asymmetry of an
+// analysis.
+type PublicationBias struct {
+	Egger    *Egger    `json:"egger"`
+	Begg     *Begg     `json:"begg"`
+	TrimFill *TrimFill `json:"trimFill"`
+}
+
+// AssessBias runs the tests for funnel plot asymmetry when there are enough
+// studies, with the model of the analysis for trim-and-fill.
+func AssessBias(studies []Study, result *Result) (*PublicationBias, error) {
+	if len(studies) < MinBiasStudies {
+		return nil, ErrorFewStudies
+	}
+	egger, err := EggerTest(studies)
+	if err != nil {
+		return nil, err
+	}
+	begg, err := BeggTest(studies)
+	if err != nil {
+		return nil, err
+	}
+	trimFill, err := TrimAndFill(studies, result.Measure, result.Method, result.Level)
+	if err != nil {
+		return nil, err
+	}
+	return &PublicationBias{Egger: egger, Begg: begg, TrimFill: trimFill}, nil
+}
+
+// EggerTest fits the ordinary least squares regression of yi / sei on
+// 1 / sei and tests its intercept with k - 2 degrees of freedom.
+func EggerTest(studies []Study) (*Egger, error) {
+	k := len(studies)
+	if k < 3 {
+		return nil, ErrorNoStudies
+	}
+	var meanX, meanZ float64
+	for _, study := range studies {
+		se := math.Sqrt(study.Vi)
+		meanX += 1 / se
+		meanZ += study.Yi / se
+	}
+	meanX /= float64(k)
+	meanZ /= float64(k)
+
+	var sxx, sxz float64
+	for _, study := range studies {
+		se := math.Sqrt(study.Vi)
+		sxx += (1/se - meanX) * (1/se - meanX)
+		sxz += (1/se - meanX) * (study.Yi/se - meanZ)
+	}
+	if sxx == 0 {
+		return nil, ErrorNoInformation
+	}
+	slope := sxz / sxx
+	intercept := meanZ - slope*meanX
+
+	residuals := 0.0
+	for _, study := range studies {
+		se := math.Sqrt(study.Vi)
+		residual := study.Yi/se - intercept - slope/se
+		residuals += residual * residual
+	}
+	df := k - 2
+	se := math.Sqrt(residuals / float64(df) * (1/float64(k) + meanX*meanX/sxx))
+
+	egger := &Egger{Intercept: intercept, SE: se, Slope: slope, Df: df, P: 1}
+	if se > 0 {
+		egger.T = intercept / se
+		egger.P = studentTwoSidedP(egger.T, float64(df))
+	}
+	return egger, nil
+}
+
+// BeggTest correlates the effects, standardized by the fixed effect
+// estimate, with their variances. Tied pairs count as neither concordant
+// nor discordant.
+func BeggTest(studies []Study) (*Begg, error) {
+	k := len(studies)
+	if k < 3 {
+		return nil, ErrorNoStudies
+	}
+	estimate, se, _ := inverseVariance(studies, 0)
+	standardized := make([]float64, k)
+	for i, study := range studies {
+		standardized[i] = (study.Yi - estimate) / math.Sqrt(study.Vi-se*se)
+	}
+
+	s := 0.0
+	for i := 0; i < k; i++ {
+		for j := i + 1; j < k; j++ {
+			product := (standardized[i] - standardized[j]) * (studies[i].Vi - studies[j].Vi)
+			switch {
+			case product > 0:
+				s++
+			case product < 0:
+				s--
+			}
+		}
+	}
+	n := float64(k)
+	z := s / math.Sqrt(n*(n-1)*(2*n+5)/18)
+	return &Begg{Tau: s / (n * (n - 1) / 2), Z: z, P: twoSidedP(z)}, nil
+}
+
+// missingSide tells on which side of the funnel plot studies are missing
+// from the sign of the slope of the effects regressed on their standard
+// errors, with inverse variance weights: small studies with larger effects
+// leave a gap on the left.
+func missingSide(studies []Study) string {
+	var sw, sx, sy, sxx, sxy float64
+	for _, study := range studies {
+		w, x := 1/study.Vi, math.Sqrt(study.Vi)
+		sw += w
+		sx += w * x
+		sy += w * study.Yi
+		sxx += w * x * x
+		sxy += w * x * study.Yi
+	}
+	if (sw*sxy - sx*sy) < 0 {
+		return "right"
+	}
+	return "left"
+}
+
+// ranks returns the ranks of the values, averaged over ties.
+func ranks(values []float64) []float64 {
+	order := make([]int, len(values))
+	for i := range order {
+		order[i] = i
+	}
+	sort.Slice(order, func(i, j int) bool {
+		return values[order[i]] < values[order[j]]
+	})
+	result := make([]float64, len(values))
+	for i := 0; i < len(order); {
+		j := i
+		for j+1 < len(order) && values[order[j+1]] == values[order[i]] {
+			j++
+		}
+		for l := i; l <= j; l++ {
+			result[order[l]] = float64(i+j)/2 + 1
+		}
+		i = j + 1
+	}
+	return result
+}
+
+// TrimAndFill estimates the number of missing studies with the L0
+// estimator, trimming the most extreme studies until the estimate settles,
+// and pools the studies with the missing ones filled in. Studies missing on
+// the right are found by mirroring the effects. Filled studies have no
+// events, so Mantel-Haenszel analyses are trimmed and filled with inverse
+// variance weights.
+func TrimAndFill(studies []Study, measure Measure, method Method, level float64) (*TrimFill, error) {
+	k := len(studies)
+	if k < 3 {
+		return nil, ErrorNoStudies
+	}
+	side := missingSide(studies)
+	flip := 1.0
+	if side == "right" {
+		flip = -1
+	}
+
+	sorted := make([]Study, k)
+	for i, study := range studies {
+		sorted[i] = Study{Label: study.Label, Yi: flip * study.Yi, Vi: study.Vi}
+	}
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].Yi < sorted[j].Yi
+	})
+
+	pooling := method
+	if pooling == MantelHaenszel {
+		pooling = InverseVariance
+	}
+	k0, estimate := 0, 0.0
+	for iteration := 0; iteration < 100; iteration++ {
+		trimmed, err := Pool(sorted[:k-k0], measure, pooling, level)
+		if err != nil {
+			return nil, err
+		}
+		estimate = trimmed.Estimate
+
+		centred := make([]float64, k)
+		for i, study := range sorted {
+			centred[i] = math.Abs(study.Yi - estimate)
+		}
+		rank := ranks(centred)
+		sr := 0.0
+		for i, study := range sorted {
+			if study.Yi-estimate > 0 {
+				sr += rank[i]
+			}
+		}
+		n := float64(k)
+		next := int(math.Max(0, math.Round((4*sr-n*(n+1))/(2*n-1))))
+		next = min(next, k-1)
+		if next == k0 {
+			break
+		}
+		k0 = next
+	}
+
+	filled := []Study{}
+	for i := k - k0; i < k; i++ {
+		filled = append(filled, Study{
+			Label: "Filled " + strconv.Itoa(len(filled)+1),
+			Yi:    flip * (2*estimate - sorted[i].Yi),
+			Vi:    sorted[i].Vi,
+		})
+	}
+
+	result, err := Pool(append(append([]Study{}, studies...), filled...), measure, pooling, level)
+	if err != nil {
+		return nil, err
+	}
+	return &TrimFill{Side: side, K0: k0, Filled: filled, Result: result}, nil
+}
@@ -0,0 +1,134 @@
+package meta
+
+import (
+	"errors"
+	"math"
+)
+
+type MedianMethod string
+
+const (
+	WanIqr      MedianMethod = "WanIQR"
+	WanRange    MedianMethod = "WanRange"
+	WanRangeIqr MedianMethod = "WanRangeIQR"
+	Hozo        MedianMethod = "Hozo"
+)
+
+var MedianMethods = []MedianMethod{WanIqr, WanRange, WanRangeIqr, Hozo}
+
+var medianMethodNames = map[MedianMethod]string{
+	WanIqr:      "Wan et al. (2014), median and interquartile range",
+	WanRange:    "Wan et al. (2014), median and range",
+	WanRangeIqr: "Wan et al. (2014), median, interquartile range and range",
+	Hozo:        "Hozo et al. (2005), median and range",
+}
+
+func (m MedianMethod) Name() string {
+	return medianMethodNames[m]
+}
+
+// Median is the summary of a group reported as its median with its
+// quartiles, its range, or both. Which of them are read depends on the
+// method of estimation.
+type Median struct {
+	Median float64 `json:"median"`
+	Q1     float64 `json:"q1"`
+	Q3     float64 `json:"q3"`
+	Min    float64 `json:"min"`
+	Max    float64 `json:"max"`
+	N      float64 `json:"n"`
+}
+
+var (
+	ErrorMedianMethod = errors.New("unknown method to estimate the mean from the median")
+	ErrorQuantiles    = errors.New("the minimum, quartiles, median and maximum must be in order")
+	ErrorPValue       = errors.New("the p-value must be between 0 and 1, exclusive")
+)
+
+// wanRange and wanIqr are the expected standardized range and interquartile
+// range of a normal sample of size n, as approximated by Wan et al.
+func wanRange(n float64) float64 {
+	return 2 * normalQuantile((n-0.375)/(n+0.25))
+}
+
+func wanIqr(n float64) float64 {
+	return 2 * normalQuantile((0.75*n-0.125)/(n+0.25))
+}
+
+// EstimateMean estimates the mean and the standard deviation of a group
+// from its median. The Hozo method follows the authors' advice for larger
+// samples: the median stands for the mean above 25 participants, and the
+// range over 4, then over 6 above 70, for the standard deviation.
+func EstimateMean(method MedianMethod, data Median) (float64, float64, error) {
+	if data.N < 2 {
+		return 0, 0, ErrorSizes
+	}
+	iqr := data.Q1 <= data.Median && data.Median <= data.Q3 && data.Q1 < data.Q3
+	span := data.Min <= data.Median && data.Median <= data.Max && data.Min < data.Max
+
+	a, m, b, n := data.Min, data.Median, data.Max, data.N
+	switch method {
+	case WanIqr:
+		if !iqr {
+			return 0, 0, ErrorQuantiles
+		}
+		return (data.Q1 + m + data.Q3) / 3, (data.Q3 - data.Q1) / wanIqr(n), nil
+	case WanRange:
+		if !span {
+			return 0, 0, ErrorQuantiles
+		}
+		return (a + 2*m + b) / 4, (b - a) / wanRange(n), nil
+	case WanRangeIqr:
+		if !iqr || !span || data.Min > data.Q1 || data.Q3 > data.Max {
+			return 0, 0, ErrorQuantiles
+		}
+		mean := (a + 2*data.Q1 + 2*m + 2*data.Q3 + b) / 8
+		sd := (b-a)/(2*wanRange(n)) + (data.Q3-data.Q1)/(2*wanIqr(n))
+		return mean, sd, nil
+	case Hozo:
+		if !span {
+			return 0, 0, ErrorQuantiles
+		}
+		mean := m
+		if n <= 25 {
+			mean = (a+2*m+b)/4 + (a-2*m+b)/(4*n)
+		}
+		var sd float64
+		switch {
+		case n <= 15:
+			sd = math.Sqrt(((a-2*m+b)*(a-2*m+b)/4 + (b-a)*(b-a)) / 12)
+		case n <= 70:
+			sd = (b - a) / 4
+		default:
+			sd = (b - a) / 6
+		}
+		return mean, sd, nil
+	}
+	return 0, 0, ErrorMedianMethod
+}
+
+// TEffect computes Hedges' g from the t statistic of an independent samples
+// t-test and the size of both groups.
+func TEffect(label string, t float64, nT float64, nC float64) (Study, error) {
+	if nT < 2 || nC < 2 {
+		return Study{}, ErrorSizes
+	}
+	g, v := hedges(t*math.Sqrt(1/nT+1/nC), nT, nC)
+	return Study{Label: label, Yi: g, Vi: v}, nil
+}
+
+// PEffect computes Hedges' g from the two-sided p-value of an independent
+// samples t-test, which gives the size of the effect but not its direction.
+func PEffect(label string, p float64, nT float64, nC float64, negative bool) (Study, error) {
+	if p <= 0 || p >= 1 {
+		return Study{}, ErrorPValue
+	}
+	if nT < 2 || nC < 2 {
+		return Study{}, ErrorSizes
+	}
+	t := studentQuantile(1-p/2, nT+nC-2)
+	if negative {
+		t = -t
+	}
+	return TEffect(label, t, nT, nC)
+}
@@ -0,0 +1,208 @@
+package meta
+
+import "math"
+
+// normalCdf is the distribution function of the standard normal.
+func normalCdf(x float64) float64 {
+	return 0.5 * math.Erfc(-x/math.Sqrt2)
+}
+
+// normalQuantile inverts normalCdf with the rational approximation of
+// Acklam, refined by a step of Halley's method.
+func normalQuantile(p float64) float64 {
+	if p <= 0 {
+		return math.Inf(-1)
+	}
+	if p >= 1 {
+		return math.Inf(1)
+	}
+
+	a := []float64{-3.969683028665376e+01, 2.209460984245205e+02, -2.759285104469687e+02, 1.383577518672690e+02, -3.066479806614716e+01, 2.506628277459239e+00}
+	b := []float64{-5.447609879822406e+01, 1.615858368580409e+02, -1.556989798598866e+02, 6.680131188771972e+01, -1.328068155288572e+01}
+	c := []float64{-7.784894002430293e-03, -3.223964580411365e-01, -2.400758277161838e+00, -2.549732539343734e+00, 4.374664141464968e+00, 2.938163982698783e+00}
+	d := []float64{7.784695709041462e-03, 3.224671290700398e-01, 2.445134137142996e+00, 3.754408661907416e+00}
+
+	var x float64
+	switch {
+	case p < 0.02425:
+		q := math.Sqrt(-2 * math.Log(p))
+		x = (((((c[0]*q+c[1])*q+c[2])*q+c[3])*q+c[4])*q + c[5]) / ((((d[0]*q+d[1])*q+d[2])*q+d[3])*q + 1)
+	case p > 1-0.02425:
+		q := math.Sqrt(-2 * math.Log(1-p))
+		x = -(((((c[0]*q+c[1])*q+c[2])*q+c[3])*q+c[4])*q + c[5]) / ((((d[0]*q+d[1])*q+d[2])*q+d[3])*q + 1)
+	default:
+		q := p - 0.5
+		r := q * q
+		x = (((((a[0]*r+a[1])*r+a[2])*r+a[3])*r+a[4])*r + a[5]) * q / (((((b[0]*r+b[1])*r+b[2])*r+b[3])*r+b[4])*r + 1)
+	}
+
+	e := normalCdf(x) - p
+	u := e * math.Sqrt(2*math.Pi) * math.Exp(x*x/2)
+	return x - u/(1+x*u/2)
+}
+
+// twoSidedP is the two-sided p value of a standard normal statistic.
+func twoSidedP(z float64) float64 {
+	return math.Erfc(math.Abs(z) / math.Sqrt2)
+}
+
+// lowerGamma is the regularized lower incomplete gamma function P(a, x).
+func lowerGamma(a float64, x float64) float64 {
+	if x <= 0 {
+		return 0
+	}
+	lgamma, _ := math.Lgamma(a)
+	if x < a+1 {
+		sum, term := 1/a, 1/a
+		for n := 1; n < 500; n++ {
+			term *= x / (a + float64(n))
+			sum += term
+			if math.Abs(term) < math.Abs(sum)*1e-15 {
+				break
+			}
+		}
+		return sum * math.Exp(-x+a*math.Log(x)-lgamma)
+	}
+	return 1 - upperGammaFraction(a, x, lgamma)
+}
+
+// upperGammaFraction evaluates Q(a, x) by its continued fraction, with the
+// modified Lentz method.
+func upperGammaFraction(a float64, x float64, lgamma float64) float64 {
+	const tiny = 1e-300
+	b := x + 1 - a
+	c := 1 / tiny
+	d := 1 / b
+	h := d
+	for i := 1; i < 500; i++ {
+		an := -float64(i) * (float64(i) - a)
+		b += 2
+		d = an*d + b
+		if math.Abs(d) < tiny {
+			d = tiny
+		}
+		c = b + an/c
+		if math.Abs(c) < tiny {
+			c = tiny
+		}
+		d = 1 / d
+		delta := d * c
+		h *= delta
+		if math.Abs(delta-1) < 1e-15 {
+			break
+		}
+	}
+	return math.Exp(-x+a*math.Log(x)-lgamma) * h
+}
+
+// chiSquareSurvival is the probability that a chi-square variable with df
+// degrees of freedom exceeds x.
+func chiSquareSurvival(x float64, df float64) float64 {
+	if df <= 0 {
+		return math.NaN()
+	}
+	return 1 - lowerGamma(df/2, x/2)
+}
+
+// incompleteBeta is the regularized incomplete beta function I_x(a, b).
+func incompleteBeta(x float64, a float64, b float64) float64 {
+	if x <= 0 {
+		return 0
+	}
+	if x >= 1 {
+		return 1
+	}
+	la, _ := math.Lgamma(a)
+	lb, _ := math.Lgamma(b)
+	lab, _ := math.Lgamma(a + b)
+	front := math.Exp(lab - la - lb + a*math.Log(x) + b*math.Log(1-x))
+	if x < (a+1)/(a+b+2) {
+		return front * betaFraction(x, a, b) / a
+	}
+	return 1 - front*betaFraction(1-x, b, a)/b
+}
+
+// betaFraction evaluates the continued fraction of the incomplete beta
+// function with the modified Lentz method.
+func betaFraction(x float64, a float64, b float64) float64 {
+	const tiny = 1e-300
+	c := 1.0
+	d := 1 - (a+b)*x/(a+1)
+	if math.Abs(d) < tiny {
+		d = tiny
+	}
+	d = 1 / d
+	h := d
+	for m := 1; m < 500; m++ {
+		fm := float64(m)
+		an := fm * (b - fm) * x / ((a + 2*fm - 1) * (a + 2*fm))
+		d = 1 + an*d
+		if math.Abs(d) < tiny {
+			d = tiny
+		}
+		c = 1 + an/c
+		if math.Abs(c) < tiny {
+			c = tiny
+		}
+		d = 1 / d
+		h *= d * c
+
+		an = -(a + fm) * (a + b + fm) * x / ((a + 2*fm) * (a + 2*fm + 1))
+		d = 1 + an*d
+		if math.Abs(d) < tiny {
+			d = tiny
+		}
+		c = 1 + an/c
+		if math.Abs(c) < tiny {
+			c = tiny
+		}
+		d = 1 / d
+		delta := d * c
+		h *= delta
+		if math.Abs(delta-1) < 1e-15 {
+			break
+		}
+	}
+	return h
+}
+
+// studentCdf is the distribution function of Student's t with df degrees of
+// freedom.
+func studentCdf(t float64, df float64) float64 {
+	tail := 0.5 * incompleteBeta(df/(df+t*t), df/2, 0.5)
+	if t > 0 {
+		return 1 - tail
+	}
+	return tail
+}
+
+// studentTwoSidedP is the two-sided p value of a t statistic.
+func studentTwoSidedP(t float64, df float64) float64 {
+	return incompleteBeta(df/(df+t*t), df/2, 0.5)
+}
+
+// studentQuantile inverts studentCdf by bisection.
+func studentQuantile(p float64, df float64) float64 {
+	if p <= 0 {
+		return math.Inf(-1)
+	}
+	if p >= 1 {
+		return math.Inf(1)
+	}
+	low, high := -1.0, 1.0
+	for studentCdf(low, df) > p {
+		low *= 2
+	}
+	for studentCdf(high, df) < p {
+		high *= 2
+	}
+	for i := 0; i < 200 && high-low > 1e-12; i++ {
+		middle := (low + high) / 2
+		if studentCdf(middle, df) < p {
+			low = middle
+		} else {
+			high = middle
+		}
+	}
+	return (low + high) / 2
+}
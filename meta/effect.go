@@ -0,0 +1,169 @@
+// Package meta pools the effect sizes of studies in pairwise meta-analyses
+// and draws forest plots of them.
+package meta
+
+import (
+	"errors"
+	"math"
+)
+
+type Measure string
+
+const (
+	OddsRatio                  Measure = "OR"
+	RiskRatio                  Measure = "RR"
+	RiskDifference             Measure = "RD"
+	MeanDifference             Measure = "MD"
+	StandardizedMeanDifference Measure = "SMD"
+)
+
+var Measures = []Measure{OddsRatio, RiskRatio, RiskDifference, MeanDifference, StandardizedMeanDifference}
+
+var measureNames = map[Measure]string{
+	OddsRatio:                  "Odds ratio",
+	RiskRatio:                  "Risk ratio",
+	RiskDifference:             "Risk difference",
+	MeanDifference:             "Mean difference",
+	StandardizedMeanDifference: "Standardized mean difference (Hedges' g)",
+}
+
+func (m Measure) Name() string {
+	return measureNames[m]
+}
+
+// Ratio tells whether the measure is a ratio, analysed on the log scale.
+func (m Measure) Ratio() bool {
+	return m == OddsRatio || m == RiskRatio
+}
+
+// Binary tells whether the measure compares events in two groups.
+func (m Measure) Binary() bool {
+	return m == OddsRatio || m == RiskRatio || m == RiskDifference
+}
+
+// Report takes a value from the analysis scale to the scale the measure is
+// reported on.
+func (m Measure) Report(value float64) float64 {
+	if m.Ratio() {
+		return math.Exp(value)
+	}
+	return value
+}
+
+// Binary holds the events and the totals of the treatment and the control
+// group of a study.
+type Binary struct {
+	EventsT float64 `json:"eventsT"`
+	TotalT  float64 `json:"totalT"`
+	EventsC float64 `json:"eventsC"`
+	TotalC  float64 `json:"totalC"`
+}
+
+// Continuous holds the mean, the standard deviation and the size of the
+// treatment and the control group of a study.
+type Continuous struct {
+	MeanT float64 `json:"meanT"`
+	SdT   float64 `json:"sdT"`
+	NT    float64 `json:"nT"`
+	MeanC float64 `json:"meanC"`
+	SdC   float64 `json:"sdC"`
+	NC    float64 `json:"nC"`
+}
+
+// Study is the effect of a study on the analysis scale, Yi, and its
+// variance, Vi. Studies computed from events keep them for the
+// Mantel-Haenszel method; Conversion tells how effects were derived from
+// other statistics the study reported.
+type Study struct {
+	Label      string  `json:"label"`
+	Yi         float64 `json:"yi"`
+	Vi         float64 `json:"vi"`
+	Binary     *Binary `json:"binary,omitempty"`
+	Conversion string  `json:"conversion,omitempty"`
+}
+
+var (
+	ErrorMeasure       = errors.New("the measure does not apply to this kind of data")
+	ErrorTotals        = errors.New("group totals must be positive and at least the number of events")
+	ErrorNoEvents      = errors.New("no events, or only events, in both groups: the study gives no information on a ratio")
+	ErrorSizes         = errors.New("group sizes must be at least 2 and standard deviations positive")
+	ErrorStandardError = errors.New("the standard error must be positive")
+)
+
+// BinaryEffect computes the effect of a study from its events. Like RevMan
+// and metafor, 0.5 is added to every cell of a study with a zero cell.
+func BinaryEffect(label string, measure Measure, data Binary) (Study, error) {
+	if !measure.Binary() {
+		return Study{}, ErrorMeasure
+	}
+	if data.TotalT <= 0 || data.TotalC <= 0 || data.EventsT < 0 || data.EventsC < 0 || data.EventsT > data.TotalT || data.EventsC > data.TotalC {
+		return Study{}, ErrorTotals
+	}
+	noEvents := data.EventsT == 0 && data.EventsC == 0
+	allEvents := data.EventsT == data.TotalT && data.EventsC == data.TotalC
+	if measure.Ratio() && (noEvents || allEvents) {
+		return Study{}, ErrorNoEvents
+	}
+
+	a, b := data.EventsT, data.TotalT-data.EventsT
+	c, d := data.EventsC, data.TotalC-data.EventsC
+	if a == 0 || b == 0 || c == 0 || d == 0 {
+		a, b, c, d = a+0.5, b+0.5, c+0.5, d+0.5
+	}
+	n1, n2 := a+b, c+d
+
+	study := Study{Label: label, Binary: &data}
+	switch measure {
+	case OddsRatio:
+		study.Yi = math.Log(a * d / (b * c))
+		study.Vi = 1/a + 1/b + 1/c + 1/d
+	case RiskRatio:
+		study.Yi = math.Log((a / n1) / (c / n2))
+		study.Vi = 1/a - 1/n1 + 1/c - 1/n2
+	case RiskDifference:
+		p1, p2 := a/n1, c/n2
+		study.Yi = p1 - p2
+		study.Vi = p1*(1-p1)/n1 + p2*(1-p2)/n2
+	}
+	return study, nil
+}
+
+// ContinuousEffect computes the mean difference, or Hedges' g with its
+// large sample variance, of a study.
+func ContinuousEffect(label string, measure Measure, data Continuous) (Study, error) {
+	if measure != MeanDifference && measure != StandardizedMeanDifference {
+		return Study{}, ErrorMeasure
+	}
+	if data.NT < 2 || data.NC < 2 || data.SdT <= 0 || data.SdC <= 0 {
+		return Study{}, ErrorSizes
+	}
+
+	study := Study{Label: label}
+	switch measure {
+	case MeanDifference:
+		study.Yi = data.MeanT - data.MeanC
+		study.Vi = data.SdT*data.SdT/data.NT + data.SdC*data.SdC/data.NC
+	case StandardizedMeanDifference:
+		n := data.NT + data.NC
+		pooled := math.Sqrt(((data.NT-1)*data.SdT*data.SdT + (data.NC-1)*data.SdC*data.SdC) / (n - 2))
+		study.Yi, study.Vi = hedges((data.MeanT-data.MeanC)/pooled, data.NT, data.NC)
+	}
+	return study, nil
+}
+
+// hedges corrects Cohen's d for small samples and returns Hedges' g with
+// its large sample variance.
+func hedges(d float64, nT float64, nC float64) (float64, float64) {
+	n := nT + nC
+	g := (1 - 3/(4*n-9)) * d
+	return g, n/(nT*nC) + g*g/(2*n)
+}
+
+// GenericEffect takes an effect already on the analysis scale, such as a log
+// odds ratio, with its standard error.
+func GenericEffect(label string, effect float64, standardError float64) (Study, error) {
+	if standardError <= 0 || math.IsNaN(standardError) || math.IsInf(standardError, 0) {
+		return Study{}, ErrorStandardError
+	}
+	return Study{Label: label, Yi: effect, Vi: standardError * standardError}, nil
+}
@@ -0,0 +1,227 @@
+package meta
+
+import (
+	"bufio"
+	"fmt"
+	"html"
+	"io"
+	"math"
+)
+
+const (
+	charWidth  = 7
+	margin     = 20
+	rowHeight  = 22
+	maxLabel   = 40
+	plotWidth  = 300
+	boxSize    = 16
+	minBoxSize = 4
+)
+
+// ratioTicks are the values the axis of a ratio is labelled with.
+var ratioTicks = []float64{0.01, 0.02, 0.05, 0.1, 0.2, 0.5, 1, 2, 5, 10, 20, 50, 100}
+
+func truncate(text string, length int) string {
+	runes := []rune(text)
+	if len(runes) <= length {
+		return text
+	}
+	return string(runes[:length-1]) + "…"
+}
+
+func text(out *bufio.Writer, x float64, y float64, anchor string, value string) {
+	fmt.Fprintf(out, `<text x="%.2f" y="%.2f" text-anchor="%s" dominant-baseline="middle">%s</text>`+"\n", x, y, anchor, html.EscapeString(value))
+}
+
+// interval writes an estimate and its interval on the reported scale.
+func interval(measure Measure, estimate float64, lower float64, upper float64) string {
+	return fmt.Sprintf("%.2f [%.2f, %.2f]", measure.Report(estimate), measure.Report(lower), measure.Report(upper))
+}
+
+// FormatP writes a p-value as journals do, without going below 0.0001.
+func FormatP(p float64) string {
+	if p < 0.0001 {
+		return "< 0.0001"
+	}
+	return fmt.Sprintf("= %.4f", p)
+}
+
+// axis is the range of the plot on the analysis scale and its ticks.
+type axis struct {
+	min, max float64
+	ticks    []float64
+}
+
+func (a axis) x(left float64, value float64) float64 {
+	return left + (value-a.min)/(a.max-a.min)*plotWidth
+}
+
+// newAxis spans the values and the line of no effect. Ratios are labelled
+// with round ratios on the log scale, other measures with round steps.
+func newAxis(measure Measure, values []float64) axis {
+	a := axis{min: 0, max: 0}
+	for _, value := range values {
+		if math.IsInf(value, 0) || math.IsNaN(value) {
+			continue
+		}
+		a.min = math.Min(a.min, value)
+		a.max = math.Max(a.max, value)
+	}
+	if a.max-a.min < 1e-9 {
+		a.min, a.max = a.min-1, a.max+1
+	}
+
+	if measure.Ratio() {
+		first, last := -1, -1
+		for i, tick := range ratioTicks {
+			if math.Log(tick) <= a.min {
+				first = i
+			}
+			if last == -1 && math.Log(tick) >= a.max {
+				last = i
+			}
+		}
+		if first == -1 {
+			first = 0
+		}
+		if last == -1 {
+			last = len(ratioTicks) - 1
+		}
+		a.min = math.Min(a.min, math.Log(ratioTicks[first]))
+		a.max = math.Max(a.max, math.Log(ratioTicks[last]))
+		for _, tick := range ratioTicks[first : last+1] {
+			a.ticks = append(a.ticks, math.Log(tick))
+		}
+		return a
+	}
+
+	step := math.Pow(10, math.Floor(math.Log10((a.max-a.min)/4)))
+	for _, factor := range []float64{1, 2, 5, 10} {
+		if (a.max-a.min)/(step*factor) <= 6 {
+			step *= factor
+			break
+		}
+	}
+	a.min = math.Floor(a.min/step) * step
+	a.max = math.Ceil(a.max/step) * step
+	for tick := a.min; tick <= a.max+step/2; tick += step {
+		a.ticks = append(a.ticks, math.Round(tick/step)*step)
+	}
+	return a
+}
+
+func tickLabel(measure Measure, value float64) string {
+	if measure.Ratio() {
+		return fmt.Sprintf("%g", math.Round(math.Exp(value)*100)/100)
+	}
+	return fmt.Sprintf("%g", value)
+}
+
+// WriteForest writes the forest plot of the studies pooled in the result: a
+// row per study with its effect, its confidence interval and its weight, the
+// pooled effect as a diamond, the prediction interval of random-effects
+// models and the tests of heterogeneity and of the overall effect. Effects
+// are drawn on the analysis scale and labelled on the reported one.
+func WriteForest(w io.Writer, studies []Study, result *Result) error {
+	z := normalQuantile(1 - (1-result.Level)/2)
+	values := []float64{result.Lower, result.Upper}
+	if result.Prediction {
+		values = append(values, result.PredictionLower, result.PredictionUpper)
+	}
+	for _, study := range studies {
+		se := math.Sqrt(study.Vi)
+		values = append(values, study.Yi-z*se, study.Yi+z*se)
+	}
+	a := newAxis(result.Measure, values)
+
+	total := fmt.Sprintf("Total (%s)", result.Method.Name())
+	labelWidth := len(total) * charWidth
+	for _, study := range studies {
+		labelWidth = max(labelWidth, len([]rune(truncate(study.Label, maxLabel)))*charWidth)
+	}
+	effectHeader := fmt.Sprintf("%s [%g%% CI]", result.Measure, result.Level*100)
+	effectWidth := max(len(effectHeader), len("00.00 [00.00, 00.00]")) * charWidth
+	weightWidth := len("Weight") * charWidth
+
+	heterogeneity := fmt.Sprintf("Heterogeneity: Q = %.2f, df = %d (P %s); I² = %.0f%%", result.Q, result.Df, FormatP(result.PQ), result.I2)
+	if result.Method.Random() {
+		heterogeneity = fmt.Sprintf("Heterogeneity: Tau² = %.4f; Q = %.2f, df = %d (P %s); I² = %.0f%%", result.Tau2, result.Q, result.Df, FormatP(result.PQ), result.I2)
+	}
+	overall := fmt.Sprintf("Test for overall effect: Z = %.2f (P %s)", result.Z, FormatP(result.P))
+
+	plotLeft := float64(margin + labelWidth + margin)
+	effectRight := plotLeft + plotWidth + margin + float64(effectWidth)
+	weightRight := effectRight + margin + float64(weightWidth)
+	width := int(math.Max(weightRight+margin, float64(margin+len([]rune(heterogeneity))*charWidth+margin)))
+
+	rows := len(studies) + 1
+	if result.Prediction {
+		rows++
+	}
+	top := float64(margin + rowHeight)
+	bottom := top + float64(rows)*rowHeight
+	height := int(bottom) + 2*rowHeight + 2*rowHeight + margin
+
+	out := bufio.NewWriter(w)
+	fmt.Fprintf(out, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d" font-family="Helvetica, Arial, sans-serif" font-size="12">`+"\n", width, height, width, height)
+	fmt.Fprintf(out, `<rect width="%d" height="%d" fill="#ffffff"/>`+"\n", width, height)
+
+	text(out, margin, margin, "start", "Study")
+	text(out, effectRight, margin, "end", effectHeader)
+	text(out, weightRight, margin, "end", "Weight")
+
+	maxWeight := 0.0
+	for _, weight := range result.Weights {
+		maxWeight = math.Max(maxWeight, weight)
+	}
+
+	for i, study := range studies {
+		y := top + float64(i)*rowHeight + rowHeight/2
+		se := math.Sqrt(study.Vi)
+		lower, upper := study.Yi-z*se, study.Yi+z*se
+		text(out, margin, y, "start", truncate(study.Label, maxLabel))
+		fmt.Fprintf(out, `<line x1="%.2f" y1="%.2f" x2="%.2f" y2="%.2f" stroke="#000000"/>`+"\n", a.x(plotLeft, lower), y, a.x(plotLeft, upper), y)
+		size := float64(minBoxSize)
+		if i < len(result.Weights) && maxWeight > 0 {
+			size = math.Max(minBoxSize, boxSize*math.Sqrt(result.Weights[i]/maxWeight))
+		}
+		fmt.Fprintf(out, `<rect x="%.2f" y="%.2f" width="%.2f" height="%.2f" fill="#3b6ea5"/>`+"\n", a.x(plotLeft, study.Yi)-size/2, y-size/2, size, size)
+		text(out, effectRight, y, "end", interval(result.Measure, study.Yi, lower, upper))
+		if i < len(result.Weights) {
+			text(out, weightRight, y, "end", fmt.Sprintf("%.1f%%", result.Weights[i]))
+		}
+	}
+
+	y := top + float64(len(studies))*rowHeight + rowHeight/2
+	text(out, margin, y, "start", total)
+	fmt.Fprintf(out, `<polygon points="%.2f,%.2f %.2f,%.2f %.2f,%.2f %.2f,%.2f" fill="#000000"/>`+"\n",
+		a.x(plotLeft, result.Lower), y,
+		a.x(plotLeft, result.Estimate), y-boxSize/2,
+		a.x(plotLeft, result.Upper), y,
+		a.x(plotLeft, result.Estimate), y+boxSize/2)
+	text(out, effectRight, y, "end", interval(result.Measure, result.Estimate, result.Lower, result.Upper))
+	text(out, weightRight, y, "end", "100.0%")
+
+	if result.Prediction {
+		y += rowHeight
+		text(out, margin, y, "start", "Prediction interval")
+		fmt.Fprintf(out, `<line x1="%.2f" y1="%.2f" x2="%.2f" y2="%.2f" stroke="#000000" stroke-width="3"/>`+"\n", a.x(plotLeft, result.PredictionLower), y, a.x(plotLeft, result.PredictionUpper), y)
+		text(out, effectRight, y, "end", fmt.Sprintf("[%.2f, %.2f]", result.Measure.Report(result.PredictionLower), result.Measure.Report(result.PredictionUpper)))
+	}
+
+	null := a.x(plotLeft, 0)
+	fmt.Fprintf(out, `<line x1="%.2f" y1="%.2f" x2="%.2f" y2="%.2f" stroke="#808080" stroke-dasharray="4 3"/>`+"\n", null, top, null, bottom)
+	fmt.Fprintf(out, `<line x1="%.2f" y1="%.2f" x2="%.2f" y2="%.2f" stroke="#000000"/>`+"\n", plotLeft, bottom, plotLeft+plotWidth, bottom)
+	for _, tick := range a.ticks {
+		x := a.x(plotLeft, tick)
+		fmt.Fprintf(out, `<line x1="%.2f" y1="%.2f" x2="%.2f" y2="%.2f" stroke="#000000"/>`+"\n", x, bottom, x, bottom+5)
+		text(out, x, bottom+rowHeight/2+4, "middle", tickLabel(result.Measure, tick))
+	}
+	text(out, plotLeft+plotWidth/2, bottom+rowHeight+10, "middle", result.Measure.Name())
+
+	text(out, margin, bottom+2*rowHeight+10, "start", heterogeneity)
+	text(out, margin, bottom+3*rowHeight+10, "start", overall)
+
+	fmt.Fprint(out, "</svg>\n")
+	return out.Flush()
+}
@@ -0,0 +1,92 @@
+package meta
+
+import (
+	"bufio"
+	"fmt"
+	"html"
+	"io"
+	"math"
+)
+
+const (
+	funnelHeight = 300
+	funnelLeft   = 70
+	pointRadius  = 4
+)
+
+// WriteFunnel writes the funnel plot of the studies: their effects against
+// their standard errors, largest at the bottom, around the pooled estimate
+// with its pseudo confidence region. Studies filled in by trim-and-fill are
+// drawn hollow, with the estimate adjusted for them.
+func WriteFunnel(w io.Writer, studies []Study, result *Result, trimFill *TrimFill) error {
+	z := normalQuantile(1 - (1-result.Level)/2)
+	all := append([]Study{}, studies...)
+	if trimFill != nil {
+		all = append(all, trimFill.Filled...)
+	}
+
+	maxSe := 0.0
+	for _, study := range all {
+		maxSe = math.Max(maxSe, math.Sqrt(study.Vi))
+	}
+	values := []float64{result.Estimate - z*maxSe, result.Estimate + z*maxSe}
+	for _, study := range all {
+		values = append(values, study.Yi)
+	}
+	xAxis := newAxis(result.Measure, values)
+	yAxis := newAxis(MeanDifference, []float64{0, maxSe})
+	yAxis.min = 0
+
+	left, top := float64(funnelLeft), float64(margin)
+	bottom := top + funnelHeight
+	y := func(se float64) float64 {
+		return top + se/yAxis.max*funnelHeight
+	}
+
+	legend := "● Studies"
+	if trimFill != nil && trimFill.K0 > 0 {
+		legend = fmt.Sprintf("● Studies   ○ Filled in by trim-and-fill (%d)", trimFill.K0)
+	}
+	width := int(math.Max(left+plotWidth+margin, float64(margin+len([]rune(legend))*charWidth+margin)))
+	height := int(bottom) + 3*rowHeight + margin
+
+	out := bufio.NewWriter(w)
+	fmt.Fprintf(out, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d" font-family="Helvetica, Arial, sans-serif" font-size="12">`+"\n", width, height, width, height)
+	fmt.Fprintf(out, `<rect width="%d" height="%d" fill="#ffffff"/>`+"\n", width, height)
+
+	fmt.Fprintf(out, `<polygon points="%.2f,%.2f %.2f,%.2f %.2f,%.2f" fill="#f2f2f2" stroke="#808080" stroke-dasharray="4 3"/>`+"\n",
+		xAxis.x(left, result.Estimate-z*yAxis.max), bottom,
+		xAxis.x(left, result.Estimate), top,
+		xAxis.x(left, result.Estimate+z*yAxis.max), bottom)
+	fmt.Fprintf(out, `<line x1="%.2f" y1="%.2f" x2="%.2f" y2="%.2f" stroke="#000000"/>`+"\n", xAxis.x(left, result.Estimate), top, xAxis.x(left, result.Estimate), bottom)
+	if trimFill != nil && trimFill.K0 > 0 {
+		adjusted := xAxis.x(left, trimFill.Result.Estimate)
+		fmt.Fprintf(out, `<line x1="%.2f" y1="%.2f" x2="%.2f" y2="%.2f" stroke="#bf0000" stroke-dasharray="6 3"/>`+"\n", adjusted, top, adjusted, bottom)
+	}
+
+	for _, study := range studies {
+		fmt.Fprintf(out, `<circle cx="%.2f" cy="%.2f" r="%d" fill="#3b6ea5"><title>%s</title></circle>`+"\n", xAxis.x(left, study.Yi), y(math.Sqrt(study.Vi)), pointRadius, html.EscapeString(study.Label))
+	}
+	if trimFill != nil {
+		for _, study := range trimFill.Filled {
+			fmt.Fprintf(out, `<circle cx="%.2f" cy="%.2f" r="%d" fill="#ffffff" stroke="#bf0000"/>`+"\n", xAxis.x(left, study.Yi), y(math.Sqrt(study.Vi)), pointRadius)
+		}
+	}
+
+	fmt.Fprintf(out, `<rect x="%.2f" y="%.2f" width="%d" height="%d" fill="none" stroke="#000000"/>`+"\n", left, top, plotWidth, funnelHeight)
+	for _, tick := range xAxis.ticks {
+		x := xAxis.x(left, tick)
+		fmt.Fprintf(out, `<line x1="%.2f" y1="%.2f" x2="%.2f" y2="%.2f" stroke="#000000"/>`+"\n", x, bottom, x, bottom+5)
+		text(out, x, bottom+rowHeight/2+4, "middle", tickLabel(result.Measure, tick))
+	}
+	for _, tick := range yAxis.ticks {
+		fmt.Fprintf(out, `<line x1="%.2f" y1="%.2f" x2="%.2f" y2="%.2f" stroke="#000000"/>`+"\n", left-5, y(tick), left, y(tick))
+		text(out, left-8, y(tick), "end", fmt.Sprintf("%g", tick))
+	}
+	text(out, left+plotWidth/2, bottom+rowHeight+10, "middle", result.Measure.Name())
+	fmt.Fprintf(out, `<text x="%d" y="%.2f" text-anchor="middle" transform="rotate(-90 %d %.2f)">Standard error</text>`+"\n", margin, top+funnelHeight/2, margin, top+funnelHeight/2)
+	text(out, margin, bottom+2*rowHeight+10, "start", legend)
+
+	fmt.Fprint(out, "</svg>\n")
+	return out.Flush()
+}
@@ -0,0 +1,280 @@
+package meta
+
+import (
+	"errors"
+	"math"
+)
+
+type Method string
+
+const (
+	InverseVariance  Method = "IV"
+	MantelHaenszel   Method = "MH"
+	DerSimonianLaird Method = "DL"
+	RestrictedML     Method = "REML"
+)
+
+var Methods = []Method{InverseVariance, MantelHaenszel, DerSimonianLaird, RestrictedML}
+
+var methodNames = map[Method]string{
+	InverseVariance:  "Fixed effect, inverse variance",
+	MantelHaenszel:   "Fixed effect, Mantel-Haenszel",
+	DerSimonianLaird: "Random effects, DerSimonian-Laird",
+	RestrictedML:     "Random effects, REML",
+}
+
+func (m Method) Name() string {
+	return methodNames[m]
+}
+
+// Random tells whether the method fits a random-effects model.
+func (m Method) Random() bool {
+	return m == DerSimonianLaird || m == RestrictedML
+}
+
+// Result is a pooled effect on the analysis scale with its confidence
+// interval, the test of heterogeneity and, for random-effects models, the
+// between-study variance and the prediction interval, which needs three
+// studies. Weights are the percentages of the studies, in order.
+type Result struct {
+	Measure         Measure   `json:"measure"`
+	Method          Method    `json:"method"`
+	Level           float64   `json:"level"`
+	K               int       `json:"k"`
+	Estimate        float64   `json:"estimate"`
+	SE              float64   `json:"se"`
+	Lower           float64   `json:"lower"`
+	Upper           float64   `json:"upper"`
+	Z               float64   `json:"z"`
+	P               float64   `json:"p"`
+	Q               float64   `json:"q"`
+	Df              int       `json:"df"`
+	PQ              float64   `json:"pQ"`
+	I2              float64   `json:"i2"`
+	Tau2            float64   `json:"tau2"`
+	Prediction      bool      `json:"prediction"`
+	PredictionLower float64   `json:"predictionLower"`
+	PredictionUpper float64   `json:"predictionUpper"`
+	Weights         []float64 `json:"weights"`
+}
+
+var (
+	ErrorNoStudies     = errors.New("there are no studies to pool")
+	ErrorMethod        = errors.New("the Mantel-Haenszel method pools events only")
+	ErrorLevel         = errors.New("the confidence level must be between 0 and 1")
+	ErrorNoInformation = errors.New("the studies give no information on the pooled effect")
+	ErrorUnknown       = errors.New("unknown pooling method")
+)
+
+// Pool combines the studies with the method at the confidence level, such
+// as 0.95. Heterogeneity is measured with Cochran's Q on inverse variance
+// weights for every method, and I² is derived from Q as in RevMan.
+func Pool(studies []Study, measure Measure, method Method, level float64) (*Result, error) {
+	if len(studies) == 0 {
+		return nil, ErrorNoStudies
+	}
+	if level <= 0 || level >= 1 {
+		return nil, ErrorLevel
+	}
+
+	result := &Result{Measure: measure, Method: method, Level: level, K: len(studies), Df: len(studies) - 1}
+	_, _, q := inverseVariance(studies, 0)
+	switch method {
+	case MantelHaenszel:
+		estimate, variance, weights, err := mantelHaenszel(studies, measure)
+		if err != nil {
+			return nil, err
+		}
+		result.Estimate, result.SE, result.Weights = estimate, math.Sqrt(variance), weights
+		q = cochranQ(studies, estimate)
+	case InverseVariance:
+		result.Estimate, result.SE, _ = inverseVariance(studies, 0)
+		result.Weights = weights(studies, 0)
+	case DerSimonianLaird, RestrictedML:
+		result.Tau2 = derSimonianLaird(studies, q)
+		if method == RestrictedML {
+			result.Tau2 = restrictedMaximumLikelihood(studies, result.Tau2)
+		}
+		result.Estimate, result.SE, _ = inverseVariance(studies, result.Tau2)
+		result.Weights = weights(studies, result.Tau2)
+	default:
+		return nil, ErrorUnknown
+	}
+
+	result.Q, result.PQ = q, 1
+	if result.Df > 0 {
+		result.PQ = chiSquareSurvival(q, float64(result.Df))
+		if q > 0 {
+			result.I2 = math.Max(0, (q-float64(result.Df))/q) * 100
+		}
+	}
+
+	z := normalQuantile(1 - (1-level)/2)
+	result.Lower = result.Estimate - z*result.SE
+	result.Upper = result.Estimate + z*result.SE
+	result.Z = result.Estimate / result.SE
+	result.P = twoSidedP(result.Z)
+
+	if method.Random() && result.K >= 3 {
+		t := studentQuantile(1-(1-level)/2, float64(result.K-2))
+		spread := t * math.Sqrt(result.Tau2+result.SE*result.SE)
+		result.Prediction = true
+		result.PredictionLower = result.Estimate - spread
+		result.PredictionUpper = result.Estimate + spread
+	}
+	return result, nil
+}
+
+// cochranQ is the weighted sum of squared deviations of the studies from an
+// estimate, with inverse variance weights.
+func cochranQ(studies []Study, estimate float64) float64 {
+	q := 0.0
+	for _, study := range studies {
+		q += (study.Yi - estimate) * (study.Yi - estimate) / study.Vi
+	}
+	return q
+}
+
+// inverseVariance returns the estimate weighted by 1 / (vi + tau2), its
+// standard error and Cochran's Q around it.
+func inverseVariance(studies []Study, tau2 float64) (float64, float64, float64) {
+	sumWeights, sumEffects := 0.0, 0.0
+	for _, study := range studies {
+		weight := 1 / (study.Vi + tau2)
+		sumWeights += weight
+		sumEffects += weight * study.Yi
+	}
+	estimate := sumEffects / sumWeights
+	return estimate, math.Sqrt(1 / sumWeights), cochranQ(studies, estimate)
+}
+
+func weights(studies []Study, tau2 float64) []float64 {
+	result := make([]float64, len(studies))
+	total := 0.0
+	for i, study := range studies {
+		result[i] = 1 / (study.Vi + tau2)
+		total += result[i]
+	}
+	for i := range result {
+		result[i] *= 100 / total
+	}
+	return result
+}
+
+// derSimonianLaird is the method of moments estimate of the between-study
+// variance.
+func derSimonianLaird(studies []Study, q float64) float64 {
+	sumWeights, sumSquares := 0.0, 0.0
+	for _, study := range studies {
+		weight := 1 / study.Vi
+		sumWeights += weight
+		sumSquares += weight * weight
+	}
+	denominator := sumWeights - sumSquares/sumWeights
+	if denominator <= 0 {
+		return 0
+	}
+	return math.Max(0, (q-float64(len(studies)-1))/denominator)
+}
+
+// restrictedMaximumLikelihood finds the REML estimate of the between-study
+// variance by fixed-point iteration from a starting value.
+func restrictedMaximumLikelihood(studies []Study, tau2 float64) float64 {
+	for iteration := 0; iteration < 200; iteration++ {
+		estimate, _, _ := inverseVariance(studies, tau2)
+		sumWeights, sumSquares, numerator := 0.0, 0.0, 0.0
+		for _, study := range studies {
+			weight := 1 / (study.Vi + tau2)
+			sumWeights += weight
+			sumSquares += weight * weight
+			numerator += weight * weight * ((study.Yi-estimate)*(study.Yi-estimate) - study.Vi)
+		}
+		next := math.Max(0, numerator/sumSquares+1/sumWeights)
+		if math.Abs(next-tau2) < 1e-10 {
+			return next
+		}
+		tau2 = next
+	}
+	return tau2
+}
+
+// mantelHaenszel pools events without continuity correction. The variance
+// of the log odds ratio is that of Robins, Breslow and Greenland, those of
+// the log risk ratio and the risk difference that of Greenland and Robins.
+func mantelHaenszel(studies []Study, measure Measure) (float64, float64, []float64, error) {
+	if !measure.Binary() {
+		return 0, 0, nil, ErrorMethod
+	}
+	for _, study := range studies {
+		if study.Binary == nil {
+			return 0, 0, nil, ErrorMethod
+		}
+	}
+
+	studyWeights := make([]float64, len(studies))
+	var estimate, variance float64
+	switch measure {
+	case OddsRatio:
+		var sumR, sumS, sumPR, sumPSQR, sumQS float64
+		for i, study := range studies {
+			a, b, c, d, n := cells(study.Binary)
+			r, s := a*d/n, b*c/n
+			p, q := (a+d)/n, (b+c)/n
+			sumR += r
+			sumS += s
+			sumPR += p * r
+			sumPSQR += p*s + q*r
+			sumQS += q * s
+			studyWeights[i] = s
+		}
+		if sumR == 0 || sumS == 0 {
+			return 0, 0, nil, ErrorNoInformation
+		}
+		estimate = math.Log(sumR / sumS)
+		variance = sumPR/(2*sumR*sumR) + sumPSQR/(2*sumR*sumS) + sumQS/(2*sumS*sumS)
+	case RiskRatio:
+		var sumR, sumS, sumP float64
+		for i, study := range studies {
+			a, _, c, _, n := cells(study.Binary)
+			n1, n2 := study.Binary.TotalT, study.Binary.TotalC
+			sumR += a * n2 / n
+			sumS += c * n1 / n
+			sumP += (n1*n2*(a+c) - a*c*n) / (n * n)
+			studyWeights[i] = c * n1 / n
+		}
+		if sumR == 0 || sumS == 0 {
+			return 0, 0, nil, ErrorNoInformation
+		}
+		estimate = math.Log(sumR / sumS)
+		variance = sumP / (sumR * sumS)
+	case RiskDifference:
+		var sumWeights, sumDifferences, sumVariances float64
+		for i, study := range studies {
+			a, b, c, d, n := cells(study.Binary)
+			n1, n2 := study.Binary.TotalT, study.Binary.TotalC
+			weight := n1 * n2 / n
+			sumWeights += weight
+			sumDifferences += (a*n2 - c*n1) / n
+			sumVariances += (a*b*n2*n2*n2 + c*d*n1*n1*n1) / (n1 * n2 * n * n)
+			studyWeights[i] = weight
+		}
+		estimate = sumDifferences / sumWeights
+		variance = sumVariances / (sumWeights * sumWeights)
+	}
+	if variance <= 0 {
+		return 0, 0, nil, ErrorNoInformation
+	}
+
+	total := 0.0
+	for _, weight := range studyWeights {
+		total += weight
+	}
+	for i := range studyWeights {
+		studyWeights[i] *= 100 / total
+	}
+	return estimate, variance, studyWeights, nil
+}
+
+func cells(data *Binary) (float64, float64, float64, float64, float64) {
+	return data.EventsT, data.TotalT - data.EventsT, data.EventsC, data.TotalC - data.EventsC, data.TotalT + data.TotalC
+}
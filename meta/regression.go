@@ -0,0 +1,183 @@
+package meta
+
+import (
+	"errors"
+	"math"
+)
+
+var (
+	ErrorFewCovariates = errors.New("meta-regression needs at least three studies with different values of the covariate")
+)
+
+// Coefficient is an estimated coefficient of a meta-regression with its
+// confidence interval and Wald test.
+type Coefficient struct {
+	Estimate float64 `json:"estimate"`
+	SE       float64 `json:"se"`
+	Lower    float64 `json:"lower"`
+	Upper    float64 `json:"upper"`
+	Z        float64 `json:"z"`
+	P        float64 `json:"p"`
+}
+
+// Regression is the meta-regression of the effects on a single covariate:
+// the intercept and slope, the residual heterogeneity QE, and for
+// random-effects models the residual between-study variance and R², the
+// share of the between-study variance the covariate explains.
+type Regression struct {
+	Method    Method      `json:"method"`
+	Level     float64     `json:"level"`
+	K         int         `json:"k"`
+	Intercept Coefficient `json:"intercept"`
+	Slope     Coefficient `json:"slope"`
+	Tau2      float64     `json:"tau2"`
+	R2        float64     `json:"r2"`
+	QE        float64     `json:"qE"`
+	DfE       int         `json:"dfE"`
+	PQE       float64     `json:"pQE"`
+}
+
+// Regress fits the weighted least squares regression of the effects on the
+// covariate, with the between-study variance left after it estimated by the
+// method of moments for DerSimonian-Laird and by Fisher scoring for REML.
+// Fixed-effect methods, Mantel-Haenszel included, use inverse variance
+// weights.
+func Regress(studies []Study, covariate []float64, method Method, level float64) (*Regression, error) {
+	k := len(studies)
+	distinct := make(map[float64]bool)
+	for _, x := range covariate {
+		distinct[x] = true
+	}
+	if k < 3 || len(distinct) < 2 {
+		return nil, ErrorFewCovariates
+	}
+	if level <= 0 || level >= 1 {
+		return nil, ErrorLevel
+	}
+
+	regression := &Regression{Method: method, Level: level, K: k, DfE: k - 2}
+	fixed := fitRegression(studies, covariate, 0)
+	regression.QE = fixed.residual
+	regression.PQE = chiSquareSurvival(regression.QE, float64(regression.DfE))
+
+	switch method {
+	case InverseVariance, MantelHaenszel:
+	case DerSimonianLaird:
+		regression.Tau2 = math.Max(0, (fixed.residual-float64(k-2))/fixed.trace)
+	case RestrictedML:
+		regression.Tau2 = restrictedRegression(studies, covariate, math.Max(0, (fixed.residual-float64(k-2))/fixed.trace))
+	default:
+		return nil, ErrorUnknown
+	}
+
+	fit := fitRegression(studies, covariate, regression.Tau2)
+	if fit.determinant <= 0 {
+		return nil, ErrorNoInformation
+	}
+	z := normalQuantile(1 - (1-level)/2)
+	regression.Intercept = coefficient(fit.intercept, fit.invXX[0][0], z)
+	regression.Slope = coefficient(fit.slope, fit.invXX[1][1], z)
+
+	if method.Random() {
+		total, err := Pool(studies, MeanDifference, method, level)
+		if err != nil {
+			return nil, err
+		}
+		if total.Tau2 > 0 {
+			regression.R2 = math.Max(0, (total.Tau2-regression.Tau2)/total.Tau2) * 100
+		}
+	}
+	return regression, nil
+}
+
+func coefficient(estimate float64, variance float64, z float64) Coefficient {
+	se := math.Sqrt(variance)
+	result := Coefficient{Estimate: estimate, SE: se, Lower: estimate - z*se, Upper: estimate + z*se}
+	result.Z = estimate / se
+	result.P = twoSidedP(result.Z)
+	return result
+}
+
+// regressionFit is the weighted least squares fit with weights
+// 1 / (vi + tau2): its coefficients, the inverse of X'WX, the weighted
+// residual sum of squares y'Py and the traces of P and PP, where P is the
+// residual projection W - WX(X'WX)⁻¹X'W.
+type regressionFit struct {
+	intercept   float64
+	slope       float64
+	determinant float64
+	invXX       [2][2]float64
+	residual    float64
+	trace       float64
+	trace2      float64
+	p           [][]float64
+}
+
+func fitRegression(studies []Study, covariate []float64, tau2 float64) regressionFit {
+	k := len(studies)
+	w := make([]float64, k)
+	var s0, s1, s2, t0, t1 float64
+	for i, study := range studies {
+		w[i] = 1 / (study.Vi + tau2)
+		s0 += w[i]
+		s1 += w[i] * covariate[i]
+		s2 += w[i] * covariate[i] * covariate[i]
+		t0 += w[i] * study.Yi
+		t1 += w[i] * covariate[i] * study.Yi
+	}
+	fit := regressionFit{determinant: s0*s2 - s1*s1}
+	if fit.determinant <= 0 {
+		return fit
+	}
+	fit.invXX = [2][2]float64{{s2 / fit.determinant, -s1 / fit.determinant}, {-s1 / fit.determinant, s0 / fit.determinant}}
+	fit.intercept = fit.invXX[0][0]*t0 + fit.invXX[0][1]*t1
+	fit.slope = fit.invXX[1][0]*t0 + fit.invXX[1][1]*t1
+
+	fit.p = make([][]float64, k)
+	for i := range fit.p {
+		fit.p[i] = make([]float64, k)
+		for j := range fit.p[i] {
+			hat := fit.invXX[0][0] + fit.invXX[0][1]*(covariate[i]+covariate[j]) + fit.invXX[1][1]*covariate[i]*covariate[j]
+			fit.p[i][j] = -w[i] * w[j] * hat
+			if i == j {
+				fit.p[i][j] += w[i]
+			}
+		}
+	}
+	for i, study := range studies {
+		residual := study.Yi - fit.intercept - fit.slope*covariate[i]
+		fit.residual += w[i] * residual * residual
+		fit.trace += fit.p[i][i]
+		for j := range studies {
+			fit.trace2 += fit.p[i][j] * fit.p[j][i]
+		}
+	}
+	return fit
+}
+
+// restrictedRegression finds the REML estimate of the residual
+// between-study variance by Fisher scoring from a starting value.
+func restrictedRegression(studies []Study, covariate []float64, tau2 float64) float64 {
+	for iteration := 0; iteration < 200; iteration++ {
+		fit := fitRegression(studies, covariate, tau2)
+		if fit.determinant <= 0 || fit.trace2 <= 0 {
+			return tau2
+		}
+		py := make([]float64, len(studies))
+		for i := range studies {
+			for j, study := range studies {
+				py[i] += fit.p[i][j] * study.Yi
+			}
+		}
+		ppy := 0.0
+		for _, value := range py {
+			ppy += value * value
+		}
+		next := math.Max(0, tau2+(ppy-fit.trace)/fit.trace2)
+		if math.Abs(next-tau2) < 1e-10 {
+			return next
+		}
+		tau2 = next
+	}
+	return tau2
+}
@@ -0,0 +1,43 @@
+package meta
+
+// Sensitivity is an analysis repeated on part of the studies: without the
+// labelled study when leaving one out, or up to and including it when
+// accumulating.
+type Sensitivity struct {
+	Label  string  `json:"label"`
+	Result *Result `json:"result"`
+}
+
+// LeaveOneOut pools the studies again leaving out each of them in turn.
+func LeaveOneOut(studies []Study, measure Measure, method Method, level float64) ([]Sensitivity, error) {
+	if len(studies) < 2 {
+		return nil, ErrorNoStudies
+	}
+	results := []Sensitivity{}
+	for i, study := range studies {
+		rest := append(append([]Study{}, studies[:i]...), studies[i+1:]...)
+		result, err := Pool(rest, measure, method, level)
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, Sensitivity{Label: study.Label, Result: result})
+	}
+	return results, nil
+}
+
+// Cumulative pools the studies as they are added one at a time, in the
+// order given, usually that of publication.
+func Cumulative(studies []Study, measure Measure, method Method, level float64) ([]Sensitivity, error) {
+	if len(studies) == 0 {
+		return nil, ErrorNoStudies
+	}
+	results := []Sensitivity{}
+	for i, study := range studies {
+		result, err := Pool(studies[:i+1], measure, method, level)
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, Sensitivity{Label: study.Label, Result: result})
+	}
+	return results, nil
+}
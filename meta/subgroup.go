@@ -0,0 +1,65 @@
+package meta
+
+import (
+	"errors"
+	"math"
+	"sort"
+)
+
+var (
+	ErrorFewGroups = errors.New("subgroup analysis needs studies in at least two subgroups")
+)
+
+// Subgroup is the pooled result of the studies sharing a value of the
+// moderator.
+type Subgroup struct {
+	Name    string  `json:"name"`
+	Studies []Study `json:"studies"`
+	Result  *Result `json:"result"`
+}
+
+// SubgroupAnalysis pools the studies of each subgroup separately and tests
+// whether the subgroup estimates differ with Cochran's Q on them, weighted
+// by their inverse variances, as RevMan does. With a fixed-effect model the
+// test equals the total Q less the Q within the subgroups.
+type SubgroupAnalysis struct {
+	Groups []Subgroup `json:"groups"`
+	Q      float64    `json:"q"`
+	Df     int        `json:"df"`
+	P      float64    `json:"p"`
+}
+
+// Subgroups splits the studies by their group, in the order of the groups'
+// names, and pools each subgroup with the method.
+func Subgroups(studies []Study, groups []string, measure Measure, method Method, level float64) (*SubgroupAnalysis, error) {
+	if len(studies) == 0 {
+		return nil, ErrorNoStudies
+	}
+	byName := make(map[string][]Study)
+	names := []string{}
+	for i, study := range studies {
+		if _, found := byName[groups[i]]; !found {
+			names = append(names, groups[i])
+		}
+		byName[groups[i]] = append(byName[groups[i]], study)
+	}
+	if len(names) < 2 {
+		return nil, ErrorFewGroups
+	}
+	sort.Strings(names)
+
+	analysis := &SubgroupAnalysis{Groups: []Subgroup{}, Df: len(names) - 1}
+	estimates := []Study{}
+	for _, name := range names {
+		result, err := Pool(byName[name], measure, method, level)
+		if err != nil {
+			return nil, err
+		}
+		analysis.Groups = append(analysis.Groups, Subgroup{Name: name, Studies: byName[name], Result: result})
+		estimates = append(estimates, Study{Label: name, Yi: result.Estimate, Vi: result.SE * result.SE})
+	}
+
+	_, _, analysis.Q = inverseVariance(estimates, 0)
+	analysis.P = chiSquareSurvival(math.Max(0, analysis.Q), float64(analysis.Df))
+	return analysis, nil
+}
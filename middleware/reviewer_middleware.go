@@ -0,0 +1,26 @@
+package middleware
+
+import (
+	"github.com/gin-gonic/gin"
+	"sci-review/model"
+	"sci-review/service"
+)
+
+// ReviewerMiddleware must run after ReviewMiddleware. It sets the active
+// reviewer of the principal on the review, or sends the user back to it.
+func ReviewerMiddleware(reviewService *service.ReviewService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		principal := c.MustGet("principal").(*model.Principal)
+		review := c.MustGet("review").(*model.Review)
+
+		reviewer, err := reviewService.FindReviewer(review.Id, principal.Id)
+		if err != nil {
+			c.Redirect(302, "/reviews/"+review.Id.String())
+			c.Abort()
+			return
+		}
+
+		c.Set("reviewer", reviewer)
+		c.Next()
+	}
+}
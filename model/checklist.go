@@ -0,0 +1,174 @@
+package model
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"github.com/google/uuid"
+	"golang.org/x/exp/slog"
+	"time"
+)
+
+type ScoringBasis string
+
+const (
+	ScoringPoints  ScoringBasis = "points"
+	ScoringPercent              = "percent"
+)
+
+var ScoringBases = []ScoringBasis{ScoringPoints, ScoringPercent}
+
+// ChecklistOption is an allowed answer to a checklist question and the points
+// it scores. A not applicable answer takes the question out of the maximum
+// score instead.
+type ChecklistOption struct {
+	Value         string  `json:"value" yaml:"value"`
+	Score         float64 `json:"score" yaml:"score"`
+	NotApplicable bool    `json:"notApplicable,omitempty" yaml:"notApplicable,omitempty"`
+}
+
+// ChecklistQuestion is an item of a checklist. Questions without Options
+// take the options of the checklist.
+type ChecklistQuestion struct {
+	Id      string            `json:"id" yaml:"id"`
+	Text    string            `json:"text" yaml:"text"`
+	Section string            `json:"section,omitempty" yaml:"section,omitempty"`
+	Options []ChecklistOption `json:"options,omitempty" yaml:"options,omitempty"`
+}
+
+// ChecklistRating names the quality of a study whose score reaches Min.
+type ChecklistRating struct {
+	Min   float64 `json:"min" yaml:"min"`
+	Label string  `json:"label" yaml:"label"`
+}
+
+// ChecklistScoring tells whether studies are rated on their points or on the
+// percentage of the maximum score they reach. Checklists that do not rate
+// studies leave Ratings empty.
+type ChecklistScoring struct {
+	Basis   ScoringBasis      `json:"basis" yaml:"basis"`
+	Ratings []ChecklistRating `json:"ratings,omitempty" yaml:"ratings,omitempty"`
+}
+
+// ChecklistDefinition is a critical appraisal instrument as written in the
+// JSON or YAML file it is imported from.
+type ChecklistDefinition struct {
+	Name        string              `json:"name" yaml:"name"`
+	Description string              `json:"description,omitempty" yaml:"description,omitempty"`
+	Design      string              `json:"design,omitempty" yaml:"design,omitempty"`
+	Options     []ChecklistOption   `json:"options,omitempty" yaml:"options,omitempty"`
+	Questions   []ChecklistQuestion `json:"questions" yaml:"questions"`
+	Scoring     ChecklistScoring    `json:"scoring" yaml:"scoring"`
+}
+
+func (cd ChecklistDefinition) Value() (driver.Value, error) {
+	return json.Marshal(cd)
+}
+
+func (cd *ChecklistDefinition) Scan(src interface{}) error {
+	var data []byte
+	switch value := src.(type) {
+	case []byte:
+		data = value
+	case string:
+		data = []byte(value)
+	default:
+		return errors.New("Incompatible types")
+	}
+	return json.Unmarshal(data, cd)
+}
+
+// QuestionOptions returns the options a question can be answered with.
+func (cd ChecklistDefinition) QuestionOptions(question ChecklistQuestion) []ChecklistOption {
+	if len(question.Options) > 0 {
+		return question.Options
+	}
+	return cd.Options
+}
+
+// ChecklistTemplate is a checklist an organization defined or imported.
+// Templates shipped with the application belong to no organization and are
+// available to everyone.
+type ChecklistTemplate struct {
+	Id             uuid.UUID           `db:"id" json:"id"`
+	OrganizationId *uuid.UUID          `db:"organization_id" json:"organizationId"`
+	Name           string              `db:"name" json:"name"`
+	Definition     ChecklistDefinition `db:"definition" json:"definition"`
+	Archived       bool                `db:"archived" json:"archived"`
+	CreatedBy      *uuid.UUID          `db:"created_by" json:"createdBy"`
+	CreatedAt      time.Time           `db:"created_at" json:"createdAt"`
+	UpdatedAt      time.Time           `db:"updated_at" json:"updatedAt"`
+}
+
+func NewChecklistTemplate(organizationId uuid.UUID, definition ChecklistDefinition, createdBy uuid.UUID) *ChecklistTemplate {
+	return &ChecklistTemplate{
+		Id:             uuid.New(),
+		OrganizationId: &organizationId,
+		Name:           definition.Name,
+		Definition:     definition,
+		Archived:       false,
+		CreatedBy:      &createdBy,
+		CreatedAt:      time.Now(),
+		UpdatedAt:      time.Now(),
+	}
+}
+
+func (ct ChecklistTemplate) BuiltIn() bool {
+	return ct.OrganizationId == nil
+}
+
+func (ct ChecklistTemplate) LogValue() slog.Value {
+	return slog.GroupValue(
+		slog.String("id", ct.Id.String()),
+		slog.String("name", ct.Name),
+		slog.Int("questions", len(ct.Definition.Questions)),
+	)
+}
+
+// ChecklistAppraisal is the appraisal of a study by a reviewer with a
+// checklist template: the answer to each question, the score they add up to
+// and the rating it earns.
+type ChecklistAppraisal struct {
+	Id           uuid.UUID `db:"id" json:"id"`
+	ReviewId     uuid.UUID `db:"review_id" json:"reviewId"`
+	ReferenceId  uuid.UUID `db:"reference_id" json:"referenceId"`
+	TemplateId   uuid.UUID `db:"template_id" json:"templateId"`
+	ReviewerId   uuid.UUID `db:"reviewer_id" json:"reviewerId"`
+	ReviewerName string    `db:"reviewer_name" json:"reviewerName,omitempty"`
+	Answers      StringMap `db:"answers" json:"answers"`
+	Score        float64   `db:"score" json:"score"`
+	MaxScore     float64   `db:"max_score" json:"maxScore"`
+	Rating       string    `db:"rating" json:"rating"`
+	Note         string    `db:"note" json:"note"`
+	CreatedAt    time.Time `db:"created_at" json:"createdAt"`
+	UpdatedAt    time.Time `db:"updated_at" json:"updatedAt"`
+}
+
+func NewChecklistAppraisal(reviewId uuid.UUID, referenceId uuid.UUID, templateId uuid.UUID, reviewerId uuid.UUID) *ChecklistAppraisal {
+	return &ChecklistAppraisal{
+		Id:          uuid.New(),
+		ReviewId:    reviewId,
+		ReferenceId: referenceId,
+		TemplateId:  templateId,
+		ReviewerId:  reviewerId,
+		Answers:     StringMap{},
+		CreatedAt:   time.Now(),
+		UpdatedAt:   time.Now(),
+	}
+}
+
+func (ca ChecklistAppraisal) LogValue() slog.Value {
+	return slog.GroupValue(
+		slog.String("id", ca.Id.String()),
+		slog.String("reference_id", ca.ReferenceId.String()),
+		slog.String("template_id", ca.TemplateId.String()),
+		slog.String("reviewer_id", ca.ReviewerId.String()),
+		slog.Float64("score", ca.Score),
+	)
+}
+
+// ChecklistStudy is an included study with its appraisals.
+type ChecklistStudy struct {
+	Reference  Reference            `json:"reference"`
+	Appraisals []ChecklistAppraisal `json:"appraisals"`
+}
@@ -0,0 +1,9 @@
+package model
+
+type Decision string
+
+const (
+	DecisionInclude Decision = "Include"
+	DecisionExclude          = "Exclude"
+	DecisionMaybe            = "Maybe"
+)
@@ -0,0 +1,54 @@
+package model
+
+import (
+	"github.com/google/uuid"
+	"golang.org/x/exp/slog"
+	"time"
+)
+
+type DuplicateCluster struct {
+	Id        uuid.UUID       `db:"id" json:"id"`
+	ReviewId  uuid.UUID       `db:"review_id" json:"reviewId"`
+	PrimaryId uuid.UUID       `db:"primary_id" json:"primaryId"`
+	MatchType string          `db:"match_type" json:"matchType"`
+	Score     float64         `db:"score" json:"score"`
+	Status    DuplicateStatus `db:"status" json:"status"`
+	DecidedBy uuid.NullUUID   `db:"decided_by" json:"decidedBy"`
+	DecidedAt *time.Time      `db:"decided_at" json:"decidedAt"`
+	CreatedAt time.Time       `db:"created_at" json:"createdAt"`
+	UpdatedAt time.Time       `db:"updated_at" json:"updatedAt"`
+	Members   []Reference     `db:"-" json:"members"`
+}
+
+func NewDuplicateCluster(reviewId uuid.UUID, primaryId uuid.UUID, matchType string, score float64) *DuplicateCluster {
+	return &DuplicateCluster{
+		Id:        uuid.New(),
+		ReviewId:  reviewId,
+		PrimaryId: primaryId,
+		MatchType: matchType,
+		Score:     score,
+		Status:    DuplicateProposed,
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
+		Members:   []Reference{},
+	}
+}
+
+func (dc DuplicateCluster) MemberIds() []uuid.UUID {
+	ids := make([]uuid.UUID, 0, len(dc.Members))
+	for _, member := range dc.Members {
+		ids = append(ids, member.Id)
+	}
+	return ids
+}
+
+func (dc DuplicateCluster) LogValue() slog.Value {
+	return slog.GroupValue(
+		slog.String("id", dc.Id.String()),
+		slog.String("review_id", dc.ReviewId.String()),
+		slog.String("primary_id", dc.PrimaryId.String()),
+		slog.String("match_type", dc.MatchType),
+		slog.Float64("score", dc.Score),
+		slog.String("status", string(dc.Status)),
+	)
+}
@@ -0,0 +1,9 @@
+package model
+
+type DuplicateStatus string
+
+const (
+	DuplicateProposed  DuplicateStatus = "Proposed"
+	DuplicateConfirmed                 = "Confirmed"
+	DuplicateRejected                  = "Rejected"
+)
@@ -0,0 +1,55 @@
+package model
+
+import (
+	"github.com/google/uuid"
+	"golang.org/x/exp/slog"
+	"time"
+)
+
+// DefaultExclusionReasons seeds the reason list of a review on request.
+var DefaultExclusionReasons = []string{
+	"Wrong population",
+	"Wrong intervention",
+	"Wrong comparator",
+	"Wrong outcome",
+	"Wrong study design",
+	"Wrong publication type",
+	"Wrong setting",
+	"Language",
+}
+
+type ExclusionReason struct {
+	Id        uuid.UUID `db:"id" json:"id"`
+	ReviewId  uuid.UUID `db:"review_id" json:"reviewId"`
+	Label     string    `db:"label" json:"label"`
+	Position  int       `db:"position" json:"position"`
+	CreatedAt time.Time `db:"created_at" json:"createdAt"`
+	UpdatedAt time.Time `db:"updated_at" json:"updatedAt"`
+}
+
+func NewExclusionReason(reviewId uuid.UUID, label string, position int) *ExclusionReason {
+	return &ExclusionReason{
+		Id:        uuid.New(),
+		ReviewId:  reviewId,
+		Label:     label,
+		Position:  position,
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
+	}
+}
+
+func (er ExclusionReason) LogValue() slog.Value {
+	return slog.GroupValue(
+		slog.String("id", er.Id.String()),
+		slog.String("review_id", er.ReviewId.String()),
+		slog.String("label", er.Label),
+	)
+}
+
+// ReasonCount tells how often a reason was given in a stage: in screeners'
+// decisions, and as the reason of records finally excluded.
+type ReasonCount struct {
+	Reason    ExclusionReason `json:"reason"`
+	Decisions int             `json:"decisions"`
+	Records   int             `json:"records"`
+}
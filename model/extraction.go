@@ -0,0 +1,102 @@
+package model
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"github.com/google/uuid"
+	"golang.org/x/exp/slog"
+	"time"
+)
+
+// ExtractionValues holds the answers of an extraction by field key. Text,
+// choice and date answers are strings, numbers are float64, multiple choices
+// are lists of strings and tables are lists of rows keyed by column.
+type ExtractionValues map[string]any
+
+func (ev ExtractionValues) Value() (driver.Value, error) {
+	if ev == nil {
+		return []byte("{}"), nil
+	}
+	return json.Marshal(ev)
+}
+
+func (ev *ExtractionValues) Scan(src interface{}) error {
+	var data []byte
+	switch value := src.(type) {
+	case []byte:
+		data = value
+	case string:
+		data = []byte(value)
+	case nil:
+		*ev = ExtractionValues{}
+		return nil
+	default:
+		return errors.New("Incompatible types")
+	}
+	values := ExtractionValues{}
+	if err := json.Unmarshal(data, &values); err != nil {
+		return err
+	}
+	*ev = values
+	return nil
+}
+
+// Extraction is the data a reviewer extracted from an included study with
+// one version of the extraction form.
+type Extraction struct {
+	Id           uuid.UUID        `db:"id" json:"id"`
+	ReviewId     uuid.UUID        `db:"review_id" json:"reviewId"`
+	ReferenceId  uuid.UUID        `db:"reference_id" json:"referenceId"`
+	FormId       uuid.UUID        `db:"form_id" json:"formId"`
+	FormVersion  int              `db:"form_version" json:"formVersion"`
+	ReviewerId   uuid.UUID        `db:"reviewer_id" json:"reviewerId"`
+	ReviewerName string           `db:"reviewer_name" json:"reviewerName,omitempty"`
+	Data         ExtractionValues `db:"data" json:"data"`
+	CreatedAt    time.Time        `db:"created_at" json:"createdAt"`
+	UpdatedAt    time.Time        `db:"updated_at" json:"updatedAt"`
+}
+
+func NewExtraction(reviewId uuid.UUID, referenceId uuid.UUID, extractionForm *ExtractionForm, reviewerId uuid.UUID, data ExtractionValues) *Extraction {
+	return &Extraction{
+		Id:          uuid.New(),
+		ReviewId:    reviewId,
+		ReferenceId: referenceId,
+		FormId:      extractionForm.Id,
+		FormVersion: extractionForm.Version,
+		ReviewerId:  reviewerId,
+		Data:        data,
+		CreatedAt:   time.Now(),
+		UpdatedAt:   time.Now(),
+	}
+}
+
+func (e Extraction) LogValue() slog.Value {
+	return slog.GroupValue(
+		slog.String("id", e.Id.String()),
+		slog.String("reference_id", e.ReferenceId.String()),
+		slog.String("reviewer_id", e.ReviewerId.String()),
+		slog.Int("form_version", e.FormVersion),
+	)
+}
+
+// ExtractionStudy is an included study with the extractions made from it
+// and the consensus reached on them, if any.
+type ExtractionStudy struct {
+	Reference   Reference            `json:"reference"`
+	Extractions []Extraction         `json:"extractions"`
+	Consensus   *ExtractionConsensus `json:"consensus"`
+}
+
+// Comparable tells whether two extractions were made with the same form
+// version, so they can be compared.
+func (es ExtractionStudy) Comparable() bool {
+	versions := make(map[uuid.UUID]int)
+	for _, extraction := range es.Extractions {
+		versions[extraction.FormId]++
+		if versions[extraction.FormId] > 1 {
+			return true
+		}
+	}
+	return false
+}
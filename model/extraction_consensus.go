@@ -0,0 +1,73 @@
+package model
+
+import (
+	"github.com/google/uuid"
+	"golang.org/x/exp/slog"
+	"time"
+)
+
+// ExtractionConsensus is the data of a study agreed by a third reviewer from
+// two independent extractions, which are kept as they were.
+type ExtractionConsensus struct {
+	Id            uuid.UUID        `db:"id" json:"id"`
+	ReviewId      uuid.UUID        `db:"review_id" json:"reviewId"`
+	ReferenceId   uuid.UUID        `db:"reference_id" json:"referenceId"`
+	FormId        uuid.UUID        `db:"form_id" json:"formId"`
+	FormVersion   int              `db:"form_version" json:"formVersion"`
+	ReviewerId    uuid.UUID        `db:"reviewer_id" json:"reviewerId"`
+	ReviewerName  string           `db:"reviewer_name" json:"reviewerName,omitempty"`
+	LeftId        uuid.UUID        `db:"left_id" json:"leftId"`
+	RightId       uuid.UUID        `db:"right_id" json:"rightId"`
+	Discrepancies int              `db:"discrepancies" json:"discrepancies"`
+	Data          ExtractionValues `db:"data" json:"data"`
+	CreatedAt     time.Time        `db:"created_at" json:"createdAt"`
+	UpdatedAt     time.Time        `db:"updated_at" json:"updatedAt"`
+}
+
+func NewExtractionConsensus(comparison *ExtractionComparison, reviewerId uuid.UUID, data ExtractionValues) *ExtractionConsensus {
+	return &ExtractionConsensus{
+		Id:            uuid.New(),
+		ReviewId:      comparison.Left.ReviewId,
+		ReferenceId:   comparison.Left.ReferenceId,
+		FormId:        comparison.Form.Id,
+		FormVersion:   comparison.Form.Version,
+		ReviewerId:    reviewerId,
+		LeftId:        comparison.Left.Id,
+		RightId:       comparison.Right.Id,
+		Discrepancies: comparison.Discrepancies,
+		Data:          data,
+		CreatedAt:     time.Now(),
+		UpdatedAt:     time.Now(),
+	}
+}
+
+func (ec ExtractionConsensus) LogValue() slog.Value {
+	return slog.GroupValue(
+		slog.String("id", ec.Id.String()),
+		slog.String("reference_id", ec.ReferenceId.String()),
+		slog.String("reviewer_id", ec.ReviewerId.String()),
+		slog.Int("discrepancies", ec.Discrepancies),
+	)
+}
+
+// FieldComparison lines up the answers of two extractions to one field, or
+// to one cell of a table, written as they are typed.
+type FieldComparison struct {
+	Path  string    `json:"path"`
+	Label string    `json:"label"`
+	Type  FieldType `json:"type"`
+	Left  string    `json:"left"`
+	Right string    `json:"right"`
+	Agree bool      `json:"agree"`
+}
+
+// ExtractionComparison holds two extractions of a study made with the same
+// form version, compared field by field, and their consensus once reached.
+type ExtractionComparison struct {
+	Form          ExtractionForm       `json:"form"`
+	Left          Extraction           `json:"left"`
+	Right         Extraction           `json:"right"`
+	Fields        []FieldComparison    `json:"fields"`
+	Discrepancies int                  `json:"discrepancies"`
+	Consensus     *ExtractionConsensus `json:"consensus"`
+}
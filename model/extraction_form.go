@@ -0,0 +1,92 @@
+package model
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"github.com/google/uuid"
+	"golang.org/x/exp/slog"
+	"time"
+)
+
+type FieldType string
+
+const (
+	FieldText         FieldType = "Text"
+	FieldNumber                 = "Number"
+	FieldSingleChoice           = "SingleChoice"
+	FieldMultiChoice            = "MultiChoice"
+	FieldDate                   = "Date"
+	FieldTable                  = "Table"
+)
+
+var FieldTypes = []FieldType{FieldText, FieldNumber, FieldSingleChoice, FieldMultiChoice, FieldDate, FieldTable}
+
+// ExtractionField is a question of an extraction form. Choice fields list
+// their Options, number fields may bound their value and tell how far apart
+// two reviewers' answers can be and still agree, and table fields describe
+// each column of their rows with a field of another type.
+type ExtractionField struct {
+	Key       string            `json:"key"`
+	Label     string            `json:"label"`
+	Type      FieldType         `json:"type"`
+	Required  bool              `json:"required,omitempty"`
+	HelpText  string            `json:"helpText,omitempty"`
+	Options   []string          `json:"options,omitempty"`
+	Min       *float64          `json:"min,omitempty"`
+	Max       *float64          `json:"max,omitempty"`
+	Tolerance *float64          `json:"tolerance,omitempty"`
+	Columns   []ExtractionField `json:"columns,omitempty"`
+}
+
+type ExtractionSchema struct {
+	Fields []ExtractionField `json:"fields"`
+}
+
+func (es ExtractionSchema) Value() (driver.Value, error) {
+	return json.Marshal(es)
+}
+
+func (es *ExtractionSchema) Scan(src interface{}) error {
+	var data []byte
+	switch value := src.(type) {
+	case []byte:
+		data = value
+	case string:
+		data = []byte(value)
+	default:
+		return errors.New("Incompatible types")
+	}
+	return json.Unmarshal(data, es)
+}
+
+// ExtractionForm is one version of the extraction form of a review. A
+// version never changes once published; editing the form publishes the next
+// one, and every extraction keeps the version it was made with.
+type ExtractionForm struct {
+	Id        uuid.UUID        `db:"id" json:"id"`
+	ReviewId  uuid.UUID        `db:"review_id" json:"reviewId"`
+	Version   int              `db:"version" json:"version"`
+	Schema    ExtractionSchema `db:"schema" json:"schema"`
+	CreatedBy uuid.UUID        `db:"created_by" json:"createdBy"`
+	CreatedAt time.Time        `db:"created_at" json:"createdAt"`
+}
+
+func NewExtractionForm(reviewId uuid.UUID, version int, schema ExtractionSchema, createdBy uuid.UUID) *ExtractionForm {
+	return &ExtractionForm{
+		Id:        uuid.New(),
+		ReviewId:  reviewId,
+		Version:   version,
+		Schema:    schema,
+		CreatedBy: createdBy,
+		CreatedAt: time.Now(),
+	}
+}
+
+func (ef ExtractionForm) LogValue() slog.Value {
+	return slog.GroupValue(
+		slog.String("id", ef.Id.String()),
+		slog.String("review_id", ef.ReviewId.String()),
+		slog.Int("version", ef.Version),
+	)
+}
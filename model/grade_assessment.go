@@ -0,0 +1,125 @@
+package model
+
+import (
+	"github.com/google/uuid"
+	"golang.org/x/exp/slog"
+	"time"
+)
+
+type StudyDesign string
+
+const (
+	DesignRandomized    StudyDesign = "Randomized"
+	DesignObservational             = "Observational"
+)
+
+type OutcomeImportance string
+
+const (
+	ImportanceCritical  OutcomeImportance = "Critical"
+	ImportanceImportant                   = "Important"
+	ImportanceLimited                     = "Limited"
+)
+
+type Certainty string
+
+const (
+	CertaintyHigh     Certainty = "High"
+	CertaintyModerate           = "Moderate"
+	CertaintyLow                = "Low"
+	CertaintyVeryLow            = "Very low"
+)
+
+type GradeFactor string
+
+const (
+	FactorRiskOfBias      GradeFactor = "risk_of_bias"
+	FactorInconsistency               = "inconsistency"
+	FactorIndirectness                = "indirectness"
+	FactorImprecision                 = "imprecision"
+	FactorPublicationBias             = "publication_bias"
+	FactorLargeEffect                 = "large_effect"
+	FactorDoseResponse                = "dose_response"
+	FactorConfounding                 = "plausible_confounding"
+)
+
+// GradeAssessment rates the certainty of the evidence for one outcome of a
+// review. The five downgrading factors hold how many levels the evidence
+// goes down for them, the three upgrading ones how many levels it goes up;
+// Footnotes explain the ratings by factor. The anticipated absolute effects
+// and the relative effect are written as they appear in the Summary of
+// Findings table.
+type GradeAssessment struct {
+	Id               uuid.UUID         `db:"id" json:"id"`
+	ReviewId         uuid.UUID         `db:"review_id" json:"reviewId"`
+	Outcome          string            `db:"outcome" json:"outcome"`
+	Importance       OutcomeImportance `db:"importance" json:"importance"`
+	Design           StudyDesign       `db:"design" json:"design"`
+	Studies          int               `db:"studies" json:"studies"`
+	Participants     int               `db:"participants" json:"participants"`
+	ComparatorRisk   string            `db:"comparator_risk" json:"comparatorRisk"`
+	InterventionRisk string            `db:"intervention_risk" json:"interventionRisk"`
+	RelativeEffect   string            `db:"relative_effect" json:"relativeEffect"`
+	RiskOfBias       int               `db:"risk_of_bias" json:"riskOfBias"`
+	Inconsistency    int               `db:"inconsistency" json:"inconsistency"`
+	Indirectness     int               `db:"indirectness" json:"indirectness"`
+	Imprecision      int               `db:"imprecision" json:"imprecision"`
+	PublicationBias  int               `db:"publication_bias" json:"publicationBias"`
+	LargeEffect      int               `db:"large_effect" json:"largeEffect"`
+	DoseResponse     int               `db:"dose_response" json:"doseResponse"`
+	Confounding      int               `db:"plausible_confounding" json:"plausibleConfounding"`
+	Footnotes        StringMap         `db:"footnotes" json:"footnotes"`
+	Certainty        Certainty         `db:"certainty" json:"certainty"`
+	Comments         string            `db:"comments" json:"comments"`
+	Position         int               `db:"position" json:"position"`
+	CreatedBy        uuid.UUID         `db:"created_by" json:"createdBy"`
+	CreatedAt        time.Time         `db:"created_at" json:"createdAt"`
+	UpdatedAt        time.Time         `db:"updated_at" json:"updatedAt"`
+}
+
+func NewGradeAssessment(reviewId uuid.UUID, outcome string, createdBy uuid.UUID) *GradeAssessment {
+	return &GradeAssessment{
+		Id:         uuid.New(),
+		ReviewId:   reviewId,
+		Outcome:    outcome,
+		Importance: ImportanceCritical,
+		Design:     DesignRandomized,
+		Footnotes:  StringMap{},
+		Certainty:  CertaintyHigh,
+		CreatedBy:  createdBy,
+		CreatedAt:  time.Now(),
+		UpdatedAt:  time.Now(),
+	}
+}
+
+// Level returns the levels the evidence goes down or up for a factor.
+func (ga GradeAssessment) Level(factor GradeFactor) int {
+	switch factor {
+	case FactorRiskOfBias:
+		return ga.RiskOfBias
+	case FactorInconsistency:
+		return ga.Inconsistency
+	case FactorIndirectness:
+		return ga.Indirectness
+	case FactorImprecision:
+		return ga.Imprecision
+	case FactorPublicationBias:
+		return ga.PublicationBias
+	case FactorLargeEffect:
+		return ga.LargeEffect
+	case FactorDoseResponse:
+		return ga.DoseResponse
+	case FactorConfounding:
+		return ga.Confounding
+	}
+	return 0
+}
+
+func (ga GradeAssessment) LogValue() slog.Value {
+	return slog.GroupValue(
+		slog.String("id", ga.Id.String()),
+		slog.String("review_id", ga.ReviewId.String()),
+		slog.String("outcome", ga.Outcome),
+		slog.String("certainty", string(ga.Certainty)),
+	)
+}
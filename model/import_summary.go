@@ -0,0 +1,23 @@
+package model
+
+type ImportError struct {
+	Line    int    `json:"line"`
+	Key     string `json:"key"`
+	Message string `json:"message"`
+}
+
+type ImportSummary struct {
+	Parsed   int           `json:"parsed"`
+	Imported int           `json:"imported"`
+	Skipped  int           `json:"skipped"`
+	Errors   []ImportError `json:"errors"`
+}
+
+func NewImportSummary() *ImportSummary {
+	return &ImportSummary{Errors: []ImportError{}}
+}
+
+func (s *ImportSummary) AddError(line int, key string, message string) {
+	s.Skipped++
+	s.Errors = append(s.Errors, ImportError{Line: line, Key: key, Message: message})
+}
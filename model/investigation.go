@@ -2,27 +2,53 @@ package model
 
 import (
 	"github.com/google/uuid"
+	"strings"
 	"time"
 )
 
+// Investigation is the preliminary investigation of a review question. The
+// question may be broken down with a framework such as PICO, its Elements
+// holding the text of each part by key. Conclusion is the rationale given
+// for the latest change of status.
 type Investigation struct {
-	Id        uuid.UUID           `db:"id" json:"id"`
-	UserId    uuid.UUID           `db:"user_id" json:"userId"`
-	ReviewId  uuid.UUID           `db:"review_id" json:"reviewId"`
-	Question  string              `db:"question" json:"question"`
-	Status    InvestigationStatus `db:"status" json:"status"`
-	CreatedAt time.Time           `db:"created_at" json:"createdAt"`
-	UpdatedAt time.Time           `db:"updated_at" json:"updatedAt"`
+	Id         uuid.UUID           `db:"id" json:"id"`
+	UserId     uuid.UUID           `db:"user_id" json:"userId"`
+	ReviewId   uuid.UUID           `db:"review_id" json:"reviewId"`
+	Question   string              `db:"question" json:"question"`
+	Framework  QuestionFramework   `db:"framework" json:"framework"`
+	Elements   QuestionElements    `db:"elements" json:"elements"`
+	Status     InvestigationStatus `db:"status" json:"status"`
+	Conclusion string              `db:"conclusion" json:"conclusion"`
+	CreatedAt  time.Time           `db:"created_at" json:"createdAt"`
+	UpdatedAt  time.Time           `db:"updated_at" json:"updatedAt"`
 }
 
-func NewInvestigation(userId uuid.UUID, reviewId uuid.UUID, question string, status InvestigationStatus) *Investigation {
+func NewInvestigation(userId uuid.UUID, reviewId uuid.UUID, question string, framework QuestionFramework, elements QuestionElements, status InvestigationStatus) *Investigation {
 	return &Investigation{
 		Id:        uuid.New(),
 		UserId:    userId,
 		ReviewId:  reviewId,
 		Question:  question,
+		Framework: framework,
+		Elements:  elements,
 		Status:    status,
 		CreatedAt: time.Now(),
 		UpdatedAt: time.Now(),
 	}
 }
+
+// Criteria returns an eligibility criterion per element of the framework,
+// in its order, with the words of the keywords linked to the element.
+func (i Investigation) Criteria(keywords []InvestigationKeyword) []Criterion {
+	criteria := []Criterion{}
+	for _, element := range i.Framework.Elements() {
+		criterion := Criterion{Key: element.Key, Label: element.Label, Text: strings.TrimSpace(i.Elements[element.Key]), Keywords: []string{}}
+		for _, keyword := range keywords {
+			if keyword.Concept == element.Key {
+				criterion.Keywords = append(criterion.Keywords, keyword.Word)
+			}
+		}
+		criteria = append(criteria, criterion)
+	}
+	return criteria
+}
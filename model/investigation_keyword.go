@@ -1,56 +1,117 @@
 package model
 
 import (
-	"errors"
 	"github.com/google/uuid"
 	"strings"
 	"time"
 )
 
-type Strings []string
-
-func (s *Strings) Scan(src interface{}) (err error) {
-	var synonyms []string
-	switch src.(type) {
-	case string:
-		// convert this string "{word1,word2,word3}" to []string
-		//remove first and last character
-		src = src.(string)[1 : len(src.(string))-1]
-		// split by comma
-		synonyms = strings.Split(src.(string), ",")
-	case []byte:
-		src = string(src.([]byte))[1 : len(src.([]byte))-1]
-		synonyms = strings.Split(string(src.([]byte)), ",")
-	case nil:
-		synonyms = []string{}
-	default:
-		return errors.New("Incompatible types")
-	}
-	if err != nil {
-		return
-	}
-	*s = synonyms
-	return nil
-}
-
+// InvestigationKeyword is a search term of an investigation with its
+// synonyms. Concept is the key of the element of the structured question
+// the keyword searches for, empty when it is not linked to one.
 type InvestigationKeyword struct {
 	Id              uuid.UUID `db:"id" json:"id"`
 	UserId          uuid.UUID `db:"user_id" json:"userId"`
 	InvestigationId uuid.UUID `db:"investigation_id" json:"investigationId"`
 	Word            string    `db:"word" json:"word"`
 	Synonyms        Strings   `db:"synonyms" json:"synonyms"`
+	Concept         string    `db:"concept" json:"concept"`
+	Position        int       `db:"position" json:"position"`
 	CreatedAt       time.Time `db:"created_at" json:"createdAt"`
 	UpdatedAt       time.Time `db:"updated_at" json:"updatedAt"`
 }
 
-func NewInvestigationKeyword(userId uuid.UUID, investigationId uuid.UUID, word string, synonyms []string) *InvestigationKeyword {
+func NewInvestigationKeyword(userId uuid.UUID, investigationId uuid.UUID, word string, synonyms []string, concept string) *InvestigationKeyword {
 	return &InvestigationKeyword{
 		Id:              uuid.New(),
 		UserId:          userId,
 		InvestigationId: investigationId,
 		Word:            word,
 		Synonyms:        synonyms,
+		Concept:         concept,
 		CreatedAt:       time.Now(),
 		UpdatedAt:       time.Now(),
 	}
 }
+
+// CleanSynonyms trims the synonyms of the word and leaves out empty ones,
+// repetitions and the word itself, ignoring case.
+func CleanSynonyms(word string, synonyms []string) []string {
+	cleaned := []string{}
+	seen := map[string]bool{strings.ToLower(strings.TrimSpace(word)): true}
+	for _, synonym := range synonyms {
+		synonym = strings.TrimSpace(synonym)
+		key := strings.ToLower(synonym)
+		if synonym == "" || seen[key] {
+			continue
+		}
+		seen[key] = true
+		cleaned = append(cleaned, synonym)
+	}
+	return cleaned
+}
+
+// Merge adds the word and synonyms of the other keyword to the synonyms of
+// this one, and takes its concept when this one has none.
+func (ik *InvestigationKeyword) Merge(other InvestigationKeyword) {
+	ik.Synonyms = CleanSynonyms(ik.Word, append(append(append([]string{}, ik.Synonyms...), other.Word), other.Synonyms...))
+	if ik.Concept == "" {
+		ik.Concept = other.Concept
+	}
+}
+
+// Summary describes what changed from the previous version of the keyword,
+// naming concepts as the framework does.
+func (ik InvestigationKeyword) Summary(previous InvestigationKeyword, framework QuestionFramework) string {
+	changes := []string{}
+	if ik.Word != previous.Word {
+		changes = append(changes, "renamed from "+previous.Word)
+	}
+	for _, change := range NewKeywordSnapshot([]InvestigationKeyword{ik}).Diff(NewKeywordSnapshot([]InvestigationKeyword{{Word: ik.Word, Synonyms: previous.Synonyms}})) {
+		changes = append(changes, change.String())
+	}
+	if ik.Concept != previous.Concept {
+		label := func(key string) string {
+			if key == "" {
+				return "none"
+			}
+			if label := framework.Label(key); label != "" {
+				return label
+			}
+			return key
+		}
+		changes = append(changes, "concept "+label(previous.Concept)+" → "+label(ik.Concept))
+	}
+	return strings.Join(changes, "; ")
+}
+
+// MoveKeyword moves the keyword by offset places, keeping it within the
+// list, and numbers the positions of the keywords from 1 in their new
+// order. It tells whether the keyword was found and moved.
+func MoveKeyword(keywords []InvestigationKeyword, keywordId uuid.UUID, offset int) ([]InvestigationKeyword, bool) {
+	from := -1
+	for i, keyword := range keywords {
+		if keyword.Id == keywordId {
+			from = i
+		}
+	}
+	to := from + offset
+	if to < 0 {
+		to = 0
+	}
+	if to > len(keywords)-1 {
+		to = len(keywords) - 1
+	}
+	if from < 0 || from == to {
+		return keywords, false
+	}
+
+	moved := append([]InvestigationKeyword{}, keywords...)
+	keyword := moved[from]
+	moved = append(moved[:from], moved[from+1:]...)
+	moved = append(moved[:to], append([]InvestigationKeyword{keyword}, moved[to:]...)...)
+	for i := range moved {
+		moved[i].Position = i + 1
+	}
+	return moved, true
+}
@@ -0,0 +1,54 @@
+package model
+
+import (
+	"github.com/google/uuid"
+	"golang.org/x/exp/slog"
+	"time"
+)
+
+type KeywordAction string
+
+const (
+	KeywordCreated KeywordAction = "Created"
+	KeywordUpdated               = "Updated"
+	KeywordDeleted               = "Deleted"
+	KeywordMerged                = "Merged"
+	KeywordMoved                 = "Moved"
+)
+
+// InvestigationKeywordChange is an entry of the history of the keywords of
+// an investigation: who did what to which keyword. Word is the word of the
+// keyword when it changed, since it may be renamed or deleted later.
+type InvestigationKeywordChange struct {
+	Id              uuid.UUID     `db:"id" json:"id"`
+	InvestigationId uuid.UUID     `db:"investigation_id" json:"investigationId"`
+	KeywordId       uuid.UUID     `db:"keyword_id" json:"keywordId"`
+	UserId          uuid.UUID     `db:"user_id" json:"userId"`
+	UserName        string        `db:"user_name" json:"userName,omitempty"`
+	Action          KeywordAction `db:"action" json:"action"`
+	Word            string        `db:"word" json:"word"`
+	Detail          string        `db:"detail" json:"detail"`
+	CreatedAt       time.Time     `db:"created_at" json:"createdAt"`
+}
+
+func NewInvestigationKeywordChange(keyword *InvestigationKeyword, userId uuid.UUID, action KeywordAction, detail string) *InvestigationKeywordChange {
+	return &InvestigationKeywordChange{
+		Id:              uuid.New(),
+		InvestigationId: keyword.InvestigationId,
+		KeywordId:       keyword.Id,
+		UserId:          userId,
+		Action:          action,
+		Word:            keyword.Word,
+		Detail:          detail,
+		CreatedAt:       time.Now(),
+	}
+}
+
+func (ikc InvestigationKeywordChange) LogValue() slog.Value {
+	return slog.GroupValue(
+		slog.String("investigation_id", ikc.InvestigationId.String()),
+		slog.String("keyword_id", ikc.KeywordId.String()),
+		slog.String("user_id", ikc.UserId.String()),
+		slog.String("action", string(ikc.Action)),
+	)
+}
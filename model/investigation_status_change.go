@@ -0,0 +1,42 @@
+package model
+
+import (
+	"github.com/google/uuid"
+	"golang.org/x/exp/slog"
+	"time"
+)
+
+// InvestigationStatusChange is an entry of the status history of an
+// investigation, with the conclusion or rationale given for the change.
+// Changes are only ever appended.
+type InvestigationStatusChange struct {
+	Id              uuid.UUID           `db:"id" json:"id"`
+	InvestigationId uuid.UUID           `db:"investigation_id" json:"investigationId"`
+	UserId          uuid.UUID           `db:"user_id" json:"userId"`
+	UserName        string              `db:"user_name" json:"userName,omitempty"`
+	FromStatus      InvestigationStatus `db:"from_status" json:"fromStatus"`
+	ToStatus        InvestigationStatus `db:"to_status" json:"toStatus"`
+	Conclusion      string              `db:"conclusion" json:"conclusion"`
+	CreatedAt       time.Time           `db:"created_at" json:"createdAt"`
+}
+
+func NewInvestigationStatusChange(investigationId uuid.UUID, userId uuid.UUID, from InvestigationStatus, to InvestigationStatus, conclusion string) *InvestigationStatusChange {
+	return &InvestigationStatusChange{
+		Id:              uuid.New(),
+		InvestigationId: investigationId,
+		UserId:          userId,
+		FromStatus:      from,
+		ToStatus:        to,
+		Conclusion:      conclusion,
+		CreatedAt:       time.Now(),
+	}
+}
+
+func (isc InvestigationStatusChange) LogValue() slog.Value {
+	return slog.GroupValue(
+		slog.String("investigation_id", isc.InvestigationId.String()),
+		slog.String("user_id", isc.UserId.String()),
+		slog.String("from", string(isc.FromStatus)),
+		slog.String("to", string(isc.ToStatus)),
+	)
+}
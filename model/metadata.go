@@ -0,0 +1,39 @@
+package model
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+)
+
+// Metadata keeps fields of an imported record that have no column of their
+// own, so they can be written back on export.
+type Metadata map[string]any
+
+func (m Metadata) Value() (driver.Value, error) {
+	if m == nil {
+		return []byte("{}"), nil
+	}
+	return json.Marshal(m)
+}
+
+func (m *Metadata) Scan(src interface{}) error {
+	var data []byte
+	switch value := src.(type) {
+	case []byte:
+		data = value
+	case string:
+		data = []byte(value)
+	case nil:
+		*m = Metadata{}
+		return nil
+	default:
+		return errors.New("Incompatible types")
+	}
+	metadata := Metadata{}
+	if err := json.Unmarshal(data, &metadata); err != nil {
+		return err
+	}
+	*m = metadata
+	return nil
+}
@@ -0,0 +1,118 @@
+package model
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"github.com/google/uuid"
+	"golang.org/x/exp/slog"
+	"time"
+)
+
+type OutcomeDataType string
+
+const (
+	OutcomeBinary     OutcomeDataType = "Binary"
+	OutcomeContinuous                 = "Continuous"
+	OutcomeMedian                     = "Median"
+	OutcomeTStatistic                 = "TStatistic"
+	OutcomePValue                     = "PValue"
+	OutcomeGeneric                    = "Generic"
+)
+
+var OutcomeDataTypes = []OutcomeDataType{OutcomeBinary, OutcomeContinuous, OutcomeMedian, OutcomeTStatistic, OutcomePValue, OutcomeGeneric}
+
+// NumberMap is a JSON object of numbers, such as the statistics a study
+// reported by name.
+type NumberMap map[string]float64
+
+func (nm NumberMap) Value() (driver.Value, error) {
+	if nm == nil {
+		return []byte("{}"), nil
+	}
+	return json.Marshal(nm)
+}
+
+func (nm *NumberMap) Scan(src interface{}) error {
+	var data []byte
+	switch value := src.(type) {
+	case []byte:
+		data = value
+	case string:
+		data = []byte(value)
+	case nil:
+		*nm = NumberMap{}
+		return nil
+	default:
+		return errors.New("Incompatible types")
+	}
+	values := NumberMap{}
+	if err := json.Unmarshal(data, &values); err != nil {
+		return err
+	}
+	*nm = values
+	return nil
+}
+
+// OutcomeData is the result a study reports for an outcome, as extracted for
+// meta-analysis: the events and totals of both groups for binary outcomes,
+// their means, standard deviations and sizes for continuous ones, or an
+// effect with its standard error, on the analysis scale of Measure, when
+// the study only reports that. Medians, t statistics and p-values are kept
+// in Inputs as reported and converted to means or to an effect; Conversion
+// tells how.
+type OutcomeData struct {
+	Id           uuid.UUID       `db:"id" json:"id"`
+	ReviewId     uuid.UUID       `db:"review_id" json:"reviewId"`
+	ReferenceId  uuid.UUID       `db:"reference_id" json:"referenceId"`
+	Outcome      string          `db:"outcome" json:"outcome"`
+	DataType     OutcomeDataType `db:"data_type" json:"dataType"`
+	EventsT      float64         `db:"events_t" json:"eventsT"`
+	TotalT       float64         `db:"total_t" json:"totalT"`
+	EventsC      float64         `db:"events_c" json:"eventsC"`
+	TotalC       float64         `db:"total_c" json:"totalC"`
+	MeanT        float64         `db:"mean_t" json:"meanT"`
+	SdT          float64         `db:"sd_t" json:"sdT"`
+	NT           float64         `db:"n_t" json:"nT"`
+	MeanC        float64         `db:"mean_c" json:"meanC"`
+	SdC          float64         `db:"sd_c" json:"sdC"`
+	NC           float64         `db:"n_c" json:"nC"`
+	Measure      string          `db:"measure" json:"measure"`
+	Effect       float64         `db:"effect" json:"effect"`
+	SE           float64         `db:"se" json:"se"`
+	MedianMethod string          `db:"median_method" json:"medianMethod"`
+	Inputs       NumberMap       `db:"inputs" json:"inputs"`
+	Conversion   string          `db:"conversion" json:"conversion"`
+	CreatedBy    uuid.UUID       `db:"created_by" json:"createdBy"`
+	CreatedAt    time.Time       `db:"created_at" json:"createdAt"`
+	UpdatedAt    time.Time       `db:"updated_at" json:"updatedAt"`
+}
+
+func NewOutcomeData(reviewId uuid.UUID, referenceId uuid.UUID, outcome string, dataType OutcomeDataType, createdBy uuid.UUID) *OutcomeData {
+	return &OutcomeData{
+		Id:          uuid.New(),
+		ReviewId:    reviewId,
+		ReferenceId: referenceId,
+		Outcome:     outcome,
+		DataType:    dataType,
+		Inputs:      NumberMap{},
+		CreatedBy:   createdBy,
+		CreatedAt:   time.Now(),
+		UpdatedAt:   time.Now(),
+	}
+}
+
+func (od OutcomeData) LogValue() slog.Value {
+	return slog.GroupValue(
+		slog.String("id", od.Id.String()),
+		slog.String("reference_id", od.ReferenceId.String()),
+		slog.String("outcome", od.Outcome),
+		slog.String("data_type", string(od.DataType)),
+	)
+}
+
+// OutcomeStudy is an included study with the outcome data extracted from it.
+type OutcomeStudy struct {
+	Reference Reference     `json:"reference"`
+	Data      []OutcomeData `json:"data"`
+}
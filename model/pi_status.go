@@ -8,3 +8,44 @@ const (
 	PiStatusDoNotProceed                     = "DoNotProceed"
 	PiStatusCancelled                        = "Cancelled"
 )
+
+var investigationStatusNames = map[InvestigationStatus]string{
+	PiStatusInProgress:   "In Progress",
+	PiStatusProceed:      "Proceed with Review",
+	PiStatusDoNotProceed: "Do not Proceed with Review",
+	PiStatusCancelled:    "Cancelled",
+}
+
+// investigationTransitions are the statuses an investigation can move to
+// from each status. A concluded or cancelled investigation can only be
+// reopened.
+var investigationTransitions = map[InvestigationStatus][]InvestigationStatus{
+	PiStatusInProgress:   {PiStatusProceed, PiStatusDoNotProceed, PiStatusCancelled},
+	PiStatusProceed:      {PiStatusInProgress},
+	PiStatusDoNotProceed: {PiStatusInProgress},
+	PiStatusCancelled:    {PiStatusInProgress},
+}
+
+func (s InvestigationStatus) Name() string {
+	return investigationStatusNames[s]
+}
+
+// Transitions returns the statuses the investigation can move to next.
+func (s InvestigationStatus) Transitions() []InvestigationStatus {
+	return investigationTransitions[s]
+}
+
+func (s InvestigationStatus) CanMoveTo(next InvestigationStatus) bool {
+	for _, status := range s.Transitions() {
+		if status == next {
+			return true
+		}
+	}
+	return false
+}
+
+// RequiresConclusion tells whether moving to the status needs a written
+// conclusion: deciding to proceed with the review or not.
+func (s InvestigationStatus) RequiresConclusion() bool {
+	return s == PiStatusProceed || s == PiStatusDoNotProceed
+}
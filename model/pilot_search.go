@@ -0,0 +1,234 @@
+package model
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/google/uuid"
+	"golang.org/x/exp/slog"
+	"sort"
+	"strings"
+	"time"
+)
+
+// SnapshotKeyword is a keyword as it was when a pilot search was run.
+type SnapshotKeyword struct {
+	Word     string   `json:"word"`
+	Synonyms []string `json:"synonyms"`
+	Concept  string   `json:"concept,omitempty"`
+}
+
+// KeywordSnapshot holds the keywords of an investigation when a pilot
+// search was run, to tell which changes moved the hits between searches.
+type KeywordSnapshot []SnapshotKeyword
+
+func NewKeywordSnapshot(keywords []InvestigationKeyword) KeywordSnapshot {
+	snapshot := KeywordSnapshot{}
+	for _, keyword := range keywords {
+		synonyms := append([]string{}, keyword.Synonyms...)
+		snapshot = append(snapshot, SnapshotKeyword{Word: keyword.Word, Synonyms: synonyms, Concept: keyword.Concept})
+	}
+	return snapshot
+}
+
+func (ks KeywordSnapshot) Value() (driver.Value, error) {
+	if ks == nil {
+		return []byte("[]"), nil
+	}
+	return json.Marshal(ks)
+}
+
+func (ks *KeywordSnapshot) Scan(src interface{}) error {
+	var data []byte
+	switch value := src.(type) {
+	case []byte:
+		data = value
+	case string:
+		data = []byte(value)
+	case nil:
+		*ks = KeywordSnapshot{}
+		return nil
+	default:
+		return errors.New("Incompatible types")
+	}
+	values := KeywordSnapshot{}
+	if err := json.Unmarshal(data, &values); err != nil {
+		return err
+	}
+	*ks = values
+	return nil
+}
+
+type KeywordChangeKind string
+
+const (
+	KeywordAdded   KeywordChangeKind = "KeywordAdded"
+	KeywordRemoved                   = "KeywordRemoved"
+	SynonymAdded                     = "SynonymAdded"
+	SynonymRemoved                   = "SynonymRemoved"
+)
+
+// KeywordChange is a keyword, or a synonym of it, added or removed between
+// two pilot searches.
+type KeywordChange struct {
+	Kind    KeywordChangeKind `json:"kind"`
+	Keyword string            `json:"keyword"`
+	Synonym string            `json:"synonym,omitempty"`
+}
+
+func (kc KeywordChange) String() string {
+	switch kc.Kind {
+	case KeywordAdded:
+		return "+ " + kc.Keyword
+	case KeywordRemoved:
+		return "− " + kc.Keyword
+	case SynonymAdded:
+		return "+ " + kc.Synonym + " (" + kc.Keyword + ")"
+	default:
+		return "− " + kc.Synonym + " (" + kc.Keyword + ")"
+	}
+}
+
+// Diff returns the changes from the previous snapshot to this one. Keywords
+// and synonyms are matched ignoring case.
+func (ks KeywordSnapshot) Diff(previous KeywordSnapshot) []KeywordChange {
+	key := func(text string) string {
+		return strings.ToLower(strings.TrimSpace(text))
+	}
+	index := func(snapshot KeywordSnapshot) map[string]SnapshotKeyword {
+		keywords := make(map[string]SnapshotKeyword)
+		for _, keyword := range snapshot {
+			keywords[key(keyword.Word)] = keyword
+		}
+		return keywords
+	}
+	synonyms := func(keyword SnapshotKeyword) map[string]bool {
+		set := make(map[string]bool)
+		for _, synonym := range keyword.Synonyms {
+			set[key(synonym)] = true
+		}
+		return set
+	}
+	before, after := index(previous), index(ks)
+
+	changes := []KeywordChange{}
+	for _, keyword := range previous {
+		if _, found := after[key(keyword.Word)]; !found {
+			changes = append(changes, KeywordChange{Kind: KeywordRemoved, Keyword: keyword.Word})
+		}
+	}
+	for _, keyword := range ks {
+		old, found := before[key(keyword.Word)]
+		if !found {
+			changes = append(changes, KeywordChange{Kind: KeywordAdded, Keyword: keyword.Word})
+			continue
+		}
+		oldSynonyms, newSynonyms := synonyms(old), synonyms(keyword)
+		for _, synonym := range old.Synonyms {
+			if !newSynonyms[key(synonym)] {
+				changes = append(changes, KeywordChange{Kind: SynonymRemoved, Keyword: keyword.Word, Synonym: synonym})
+			}
+		}
+		for _, synonym := range keyword.Synonyms {
+			if !oldSynonyms[key(synonym)] {
+				changes = append(changes, KeywordChange{Kind: SynonymAdded, Keyword: keyword.Word, Synonym: synonym})
+			}
+		}
+	}
+	return changes
+}
+
+// PilotSearch is a search run in a database while investigating a
+// question, with the number of records it found and the keywords the
+// investigation had then.
+type PilotSearch struct {
+	Id              uuid.UUID       `db:"id" json:"id"`
+	InvestigationId uuid.UUID       `db:"investigation_id" json:"investigationId"`
+	UserId          uuid.UUID       `db:"user_id" json:"userId"`
+	UserName        string          `db:"user_name" json:"userName,omitempty"`
+	SourceDatabase  SourceDatabase  `db:"source_database" json:"sourceDatabase"`
+	Query           string          `db:"query" json:"query"`
+	RunOn           time.Time       `db:"run_on" json:"runOn"`
+	Hits            int             `db:"hits" json:"hits"`
+	Notes           string          `db:"notes" json:"notes"`
+	Keywords        KeywordSnapshot `db:"keywords" json:"keywords"`
+	CreatedAt       time.Time       `db:"created_at" json:"createdAt"`
+}
+
+func NewPilotSearch(investigationId uuid.UUID, userId uuid.UUID, sourceDatabase SourceDatabase, query string, runOn time.Time, hits int, notes string, keywords KeywordSnapshot) *PilotSearch {
+	return &PilotSearch{
+		Id:              uuid.New(),
+		InvestigationId: investigationId,
+		UserId:          userId,
+		SourceDatabase:  sourceDatabase,
+		Query:           query,
+		RunOn:           runOn,
+		Hits:            hits,
+		Notes:           notes,
+		Keywords:        keywords,
+		CreatedAt:       time.Now(),
+	}
+}
+
+func (ps PilotSearch) LogValue() slog.Value {
+	return slog.GroupValue(
+		slog.String("id", ps.Id.String()),
+		slog.String("investigation_id", ps.InvestigationId.String()),
+		slog.String("source_database", string(ps.SourceDatabase)),
+		slog.Int("hits", ps.Hits),
+	)
+}
+
+// PilotComparison is a pilot search compared with the previous one run in
+// the same database: the change in hits, whether the query was rewritten
+// and the keywords changed between them.
+type PilotComparison struct {
+	Search       PilotSearch     `json:"search"`
+	Previous     *PilotSearch    `json:"previous"`
+	Delta        int             `json:"delta"`
+	QueryChanged bool            `json:"queryChanged"`
+	Changes      []KeywordChange `json:"changes"`
+}
+
+// DeltaText writes the change in hits with its sign and, when the previous
+// search found anything, as a percentage of its hits.
+func (pc PilotComparison) DeltaText() string {
+	if pc.Previous == nil {
+		return ""
+	}
+	text := fmt.Sprintf("%+d", pc.Delta)
+	if pc.Previous.Hits > 0 {
+		text += fmt.Sprintf(" (%+.1f%%)", 100*float64(pc.Delta)/float64(pc.Previous.Hits))
+	}
+	return text
+}
+
+// ComparePilotSearches compares each search with the one run before it in
+// the same database. Searches are ordered by run date, then by when they
+// were logged; comparisons are returned most recent first.
+func ComparePilotSearches(searches []PilotSearch) []PilotComparison {
+	ordered := append([]PilotSearch{}, searches...)
+	sort.SliceStable(ordered, func(i, j int) bool {
+		if !ordered[i].RunOn.Equal(ordered[j].RunOn) {
+			return ordered[i].RunOn.Before(ordered[j].RunOn)
+		}
+		return ordered[i].CreatedAt.Before(ordered[j].CreatedAt)
+	})
+
+	last := make(map[SourceDatabase]int)
+	comparisons := make([]PilotComparison, len(ordered))
+	for i, search := range ordered {
+		comparison := PilotComparison{Search: search, Changes: []KeywordChange{}}
+		if j, found := last[search.SourceDatabase]; found {
+			previous := ordered[j]
+			comparison.Previous = &previous
+			comparison.Delta = search.Hits - previous.Hits
+			comparison.QueryChanged = strings.TrimSpace(search.Query) != strings.TrimSpace(previous.Query)
+			comparison.Changes = search.Keywords.Diff(previous.Keywords)
+		}
+		last[search.SourceDatabase] = i
+		comparisons[len(ordered)-1-i] = comparison
+	}
+	return comparisons
+}
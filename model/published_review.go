@@ -0,0 +1,107 @@
+package model
+
+import (
+	"github.com/google/uuid"
+	"golang.org/x/exp/slog"
+	"time"
+)
+
+// ReviewOverlap is how much a published review answers the question
+// investigated.
+type ReviewOverlap string
+
+const (
+	OverlapNone    ReviewOverlap = "None"
+	OverlapPartial               = "Partial"
+	OverlapFull                  = "Full"
+)
+
+var ReviewOverlaps = []ReviewOverlap{OverlapNone, OverlapPartial, OverlapFull}
+
+var reviewOverlapNames = map[ReviewOverlap]string{
+	OverlapNone:    "No overlap",
+	OverlapPartial: "Partial overlap",
+	OverlapFull:    "Answers the same question",
+}
+
+func (ro ReviewOverlap) Name() string {
+	return reviewOverlapNames[ro]
+}
+
+// PublishedReview is a review already published on a similar question,
+// with an assessment of how much it overlaps the one investigated.
+type PublishedReview struct {
+	Id              uuid.UUID     `db:"id" json:"id"`
+	InvestigationId uuid.UUID     `db:"investigation_id" json:"investigationId"`
+	UserId          uuid.UUID     `db:"user_id" json:"userId"`
+	UserName        string        `db:"user_name" json:"userName,omitempty"`
+	Citation        string        `db:"citation" json:"citation"`
+	Doi             string        `db:"doi" json:"doi"`
+	Year            int           `db:"year" json:"year"`
+	Overlap         ReviewOverlap `db:"overlap" json:"overlap"`
+	Notes           string        `db:"notes" json:"notes"`
+	CreatedAt       time.Time     `db:"created_at" json:"createdAt"`
+}
+
+func NewPublishedReview(investigationId uuid.UUID, userId uuid.UUID, citation string, doi string, year int, overlap ReviewOverlap, notes string) *PublishedReview {
+	return &PublishedReview{
+		Id:              uuid.New(),
+		InvestigationId: investigationId,
+		UserId:          userId,
+		Citation:        citation,
+		Doi:             doi,
+		Year:            year,
+		Overlap:         overlap,
+		Notes:           notes,
+		CreatedAt:       time.Now(),
+	}
+}
+
+func (pr PublishedReview) LogValue() slog.Value {
+	return slog.GroupValue(
+		slog.String("id", pr.Id.String()),
+		slog.String("investigation_id", pr.InvestigationId.String()),
+		slog.String("doi", pr.Doi),
+		slog.String("overlap", string(pr.Overlap)),
+	)
+}
+
+// PriorWork sums up the protocols and reviews found on the question, to
+// weigh whether a new review is needed.
+type PriorWork struct {
+	Protocols       int `json:"protocols"`
+	ActiveProtocols int `json:"activeProtocols"`
+	Reviews         int `json:"reviews"`
+	PartialReviews  int `json:"partialReviews"`
+	FullReviews     int `json:"fullReviews"`
+	// LatestFullYear is the year of the most recent review answering the
+	// same question, 0 when there is none or its year is unknown.
+	LatestFullYear int `json:"latestFullYear"`
+}
+
+func NewPriorWork(protocols []RegisteredProtocol, reviews []PublishedReview) PriorWork {
+	work := PriorWork{Protocols: len(protocols), Reviews: len(reviews)}
+	for _, protocol := range protocols {
+		if protocol.Status.Active() {
+			work.ActiveProtocols++
+		}
+	}
+	for _, review := range reviews {
+		switch review.Overlap {
+		case OverlapPartial:
+			work.PartialReviews++
+		case OverlapFull:
+			work.FullReviews++
+			if review.Year > work.LatestFullYear {
+				work.LatestFullYear = review.Year
+			}
+		}
+	}
+	return work
+}
+
+// Duplicates tells whether a review on the same question is published or
+// may be underway, which a decision to proceed should justify.
+func (pw PriorWork) Duplicates() bool {
+	return pw.ActiveProtocols > 0 || pw.FullReviews > 0
+}
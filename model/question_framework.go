@@ -0,0 +1,165 @@
+package model
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"strings"
+)
+
+type QuestionFramework string
+
+const (
+	FrameworkPICO   QuestionFramework = "PICO"
+	FrameworkPICOS                    = "PICOS"
+	FrameworkPCC                      = "PCC"
+	FrameworkSPIDER                   = "SPIDER"
+)
+
+var QuestionFrameworks = []QuestionFramework{FrameworkPICO, FrameworkPICOS, FrameworkPCC, FrameworkSPIDER}
+
+// FrameworkElement is a part of a structured question, such as the
+// population of PICO.
+type FrameworkElement struct {
+	Key   string `json:"key"`
+	Label string `json:"label"`
+	Help  string `json:"help"`
+}
+
+var (
+	elementPopulation   = FrameworkElement{Key: "population", Label: "Population", Help: "Who the studies are about"}
+	elementIntervention = FrameworkElement{Key: "intervention", Label: "Intervention", Help: "The treatment or exposure studied"}
+	elementComparison   = FrameworkElement{Key: "comparison", Label: "Comparison", Help: "What the intervention is compared with"}
+	elementOutcome      = FrameworkElement{Key: "outcome", Label: "Outcome", Help: "The effects measured"}
+	elementStudyDesign  = FrameworkElement{Key: "study_design", Label: "Study design", Help: "The kinds of study eligible"}
+	elementConcept      = FrameworkElement{Key: "concept", Label: "Concept", Help: "The idea or phenomenon mapped"}
+	elementContext      = FrameworkElement{Key: "context", Label: "Context", Help: "The setting, such as the place or culture"}
+	elementSample       = FrameworkElement{Key: "sample", Label: "Sample", Help: "The group of people studied"}
+	elementPhenomenon   = FrameworkElement{Key: "phenomenon", Label: "Phenomenon of interest", Help: "The experience or behaviour studied"}
+	elementDesign       = FrameworkElement{Key: "design", Label: "Design", Help: "How the data was collected, such as interviews"}
+	elementEvaluation   = FrameworkElement{Key: "evaluation", Label: "Evaluation", Help: "The views, attitudes or experiences reported"}
+	elementResearchType = FrameworkElement{Key: "research_type", Label: "Research type", Help: "Qualitative, quantitative or mixed methods"}
+)
+
+var frameworkElements = map[QuestionFramework][]FrameworkElement{
+	FrameworkPICO:   {elementPopulation, elementIntervention, elementComparison, elementOutcome},
+	FrameworkPICOS:  {elementPopulation, elementIntervention, elementComparison, elementOutcome, elementStudyDesign},
+	FrameworkPCC:    {elementPopulation, elementConcept, elementContext},
+	FrameworkSPIDER: {elementSample, elementPhenomenon, elementDesign, elementEvaluation, elementResearchType},
+}
+
+var frameworkNames = map[QuestionFramework]string{
+	FrameworkPICO:   "PICO",
+	FrameworkPICOS:  "PICOS",
+	FrameworkPCC:    "PCC, for scoping reviews",
+	FrameworkSPIDER: "SPIDER, for qualitative evidence",
+}
+
+func (qf QuestionFramework) Name() string {
+	return frameworkNames[qf]
+}
+
+// Elements returns the parts of a question written with the framework, in
+// the order of its acronym. A question without a framework has none.
+func (qf QuestionFramework) Elements() []FrameworkElement {
+	return frameworkElements[qf]
+}
+
+// Has tells whether the framework has an element with the key.
+func (qf QuestionFramework) Has(key string) bool {
+	for _, element := range qf.Elements() {
+		if element.Key == key {
+			return true
+		}
+	}
+	return false
+}
+
+// Label returns the label of the element with the key, or an empty string
+// when the framework has no such element.
+func (qf QuestionFramework) Label(key string) string {
+	for _, element := range qf.Elements() {
+		if element.Key == key {
+			return element.Label
+		}
+	}
+	return ""
+}
+
+// AllElements returns the elements of every framework once, in the order
+// they first appear.
+func AllElements() []FrameworkElement {
+	elements := []FrameworkElement{}
+	seen := make(map[string]bool)
+	for _, framework := range QuestionFrameworks {
+		for _, element := range framework.Elements() {
+			if !seen[element.Key] {
+				seen[element.Key] = true
+				elements = append(elements, element)
+			}
+		}
+	}
+	return elements
+}
+
+// Frameworks returns the frameworks with the element, separated by spaces,
+// for forms that show the elements of the framework chosen.
+func (fe FrameworkElement) Frameworks() string {
+	frameworks := []string{}
+	for _, framework := range QuestionFrameworks {
+		if framework.Has(fe.Key) {
+			frameworks = append(frameworks, string(framework))
+		}
+	}
+	return strings.Join(frameworks, " ")
+}
+
+// DefaultFramework is the framework a question of the review is usually
+// written with: PCC for scoping reviews, PICO otherwise.
+func (rt ReviewType) DefaultFramework() QuestionFramework {
+	if rt == ScopingReview {
+		return FrameworkPCC
+	}
+	return FrameworkPICO
+}
+
+// QuestionElements holds the text of each element of a structured question
+// by element key.
+type QuestionElements map[string]string
+
+func (qe QuestionElements) Value() (driver.Value, error) {
+	if qe == nil {
+		return []byte("{}"), nil
+	}
+	return json.Marshal(qe)
+}
+
+func (qe *QuestionElements) Scan(src interface{}) error {
+	var data []byte
+	switch value := src.(type) {
+	case []byte:
+		data = value
+	case string:
+		data = []byte(value)
+	case nil:
+		*qe = QuestionElements{}
+		return nil
+	default:
+		return errors.New("Incompatible types")
+	}
+	values := QuestionElements{}
+	if err := json.Unmarshal(data, &values); err != nil {
+		return err
+	}
+	*qe = values
+	return nil
+}
+
+// Criterion is an eligibility criterion derived from an element of a
+// structured question, with the keywords linked to it.
+type Criterion struct {
+	Key      string   `json:"key"`
+	Label    string   `json:"label"`
+	Text     string   `json:"text"`
+	Keywords []string `json:"keywords"`
+}
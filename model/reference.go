@@ -0,0 +1,88 @@
+package model
+
+import (
+	"github.com/google/uuid"
+	"golang.org/x/exp/slog"
+	"time"
+)
+
+type Reference struct {
+	Id               uuid.UUID      `db:"id" json:"id"`
+	ReviewId         uuid.UUID      `db:"review_id" json:"reviewId"`
+	UserId           uuid.UUID      `db:"user_id" json:"userId"`
+	Title            string         `db:"title" json:"title"`
+	Abstract         string         `db:"abstract" json:"abstract"`
+	Authors          Strings        `db:"authors" json:"authors"`
+	Year             int            `db:"year" json:"year"`
+	Journal          string         `db:"journal" json:"journal"`
+	Doi              string         `db:"doi" json:"doi"`
+	Keywords         Strings        `db:"keywords" json:"keywords"`
+	Pmid             string         `db:"pmid" json:"pmid"`
+	MeshTerms        Strings        `db:"mesh_terms" json:"meshTerms"`
+	PublicationTypes Strings        `db:"publication_types" json:"publicationTypes"`
+	SourceDatabase   SourceDatabase `db:"source_database" json:"sourceDatabase"`
+	// SourceDatabases lists every database the record was found in once its
+	// duplicates have been merged into it.
+	SourceDatabases Strings       `db:"source_databases" json:"sourceDatabases"`
+	DuplicateOf     uuid.NullUUID `db:"duplicate_of" json:"duplicateOf"`
+	EntryType       string        `db:"entry_type" json:"entryType"`
+	CitationKey     string        `db:"citation_key" json:"citationKey"`
+	RawMetadata     Metadata      `db:"raw_metadata" json:"rawMetadata"`
+	// NotRetrieved is set when the full text of an included record could
+	// not be obtained.
+	NotRetrieved bool      `db:"not_retrieved" json:"notRetrieved"`
+	CreatedAt    time.Time `db:"created_at" json:"createdAt"`
+	UpdatedAt    time.Time `db:"updated_at" json:"updatedAt"`
+}
+
+func NewReference(reviewId uuid.UUID, userId uuid.UUID, sourceDatabase SourceDatabase) *Reference {
+	return &Reference{
+		Id:               uuid.New(),
+		ReviewId:         reviewId,
+		UserId:           userId,
+		Authors:          Strings{},
+		Keywords:         Strings{},
+		MeshTerms:        Strings{},
+		PublicationTypes: Strings{},
+		RawMetadata:      Metadata{},
+		SourceDatabase:   sourceDatabase,
+		SourceDatabases:  Strings{string(sourceDatabase)},
+		CreatedAt:        time.Now(),
+		UpdatedAt:        time.Now(),
+	}
+}
+
+func (r Reference) FirstAuthor() string {
+	if len(r.Authors) == 0 {
+		return ""
+	}
+	return r.Authors[0]
+}
+
+func (r Reference) IsDuplicate() bool {
+	return r.DuplicateOf.Valid
+}
+
+// Completeness counts the filled descriptive fields; the most complete record
+// of a duplicate cluster is kept as the primary one.
+func (r Reference) Completeness() int {
+	score := 0
+	for _, filled := range []bool{r.Abstract != "", r.Doi != "", r.Pmid != "", r.Journal != "", r.Year != 0, len(r.Authors) > 0, len(r.MeshTerms) > 0} {
+		if filled {
+			score++
+		}
+	}
+	return score
+}
+
+func (r Reference) LogValue() slog.Value {
+	return slog.GroupValue(
+		slog.String("id", r.Id.String()),
+		slog.String("review_id", r.ReviewId.String()),
+		slog.String("title", r.Title),
+		slog.Int("year", r.Year),
+		slog.String("doi", r.Doi),
+		slog.String("pmid", r.Pmid),
+		slog.String("source_database", string(r.SourceDatabase)),
+	)
+}
@@ -0,0 +1,8 @@
+package model
+
+type ReferenceFilter struct {
+	Query          string         `json:"q" form:"q"`
+	SourceDatabase SourceDatabase `json:"source" form:"source"`
+	YearFrom       int            `json:"year_from" form:"year_from"`
+	YearTo         int            `json:"year_to" form:"year_to"`
+}
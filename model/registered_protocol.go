@@ -0,0 +1,101 @@
+package model
+
+import (
+	"github.com/google/uuid"
+	"golang.org/x/exp/slog"
+	"time"
+)
+
+type Registry string
+
+const (
+	RegistryPROSPERO         Registry = "PROSPERO"
+	RegistryOSF                       = "OSF"
+	RegistryINPLASY                   = "INPLASY"
+	RegistryResearchRegistry          = "ResearchRegistry"
+	RegistryOther                     = "Other"
+)
+
+var Registries = []Registry{RegistryPROSPERO, RegistryOSF, RegistryINPLASY, RegistryResearchRegistry, RegistryOther}
+
+var registryNames = map[Registry]string{
+	RegistryPROSPERO:         "PROSPERO",
+	RegistryOSF:              "OSF Registries",
+	RegistryINPLASY:          "INPLASY",
+	RegistryResearchRegistry: "Research Registry",
+	RegistryOther:            "Other",
+}
+
+func (r Registry) Name() string {
+	return registryNames[r]
+}
+
+type ProtocolStatus string
+
+const (
+	ProtocolOngoing   ProtocolStatus = "Ongoing"
+	ProtocolCompleted                = "Completed"
+	ProtocolPublished                = "Published"
+	ProtocolAbandoned                = "Abandoned"
+	ProtocolUnknown                  = "Unknown"
+)
+
+var ProtocolStatuses = []ProtocolStatus{ProtocolOngoing, ProtocolCompleted, ProtocolPublished, ProtocolAbandoned, ProtocolUnknown}
+
+var protocolStatusNames = map[ProtocolStatus]string{
+	ProtocolOngoing:   "Ongoing",
+	ProtocolCompleted: "Completed, not published",
+	ProtocolPublished: "Review published",
+	ProtocolAbandoned: "Abandoned",
+	ProtocolUnknown:   "Unknown",
+}
+
+func (ps ProtocolStatus) Name() string {
+	return protocolStatusNames[ps]
+}
+
+// Active tells whether the review of the protocol may still be underway or
+// about to be published, and so may duplicate the one investigated.
+func (ps ProtocolStatus) Active() bool {
+	return ps == ProtocolOngoing || ps == ProtocolCompleted
+}
+
+// RegisteredProtocol is the protocol of a review on a similar question
+// found in a registry during the preliminary investigation.
+type RegisteredProtocol struct {
+	Id              uuid.UUID      `db:"id" json:"id"`
+	InvestigationId uuid.UUID      `db:"investigation_id" json:"investigationId"`
+	UserId          uuid.UUID      `db:"user_id" json:"userId"`
+	UserName        string         `db:"user_name" json:"userName,omitempty"`
+	Registry        Registry       `db:"registry" json:"registry"`
+	RegistrationId  string         `db:"registration_id" json:"registrationId"`
+	Title           string         `db:"title" json:"title"`
+	Status          ProtocolStatus `db:"status" json:"status"`
+	Url             string         `db:"url" json:"url"`
+	Notes           string         `db:"notes" json:"notes"`
+	CreatedAt       time.Time      `db:"created_at" json:"createdAt"`
+}
+
+func NewRegisteredProtocol(investigationId uuid.UUID, userId uuid.UUID, registry Registry, registrationId string, title string, status ProtocolStatus, url string, notes string) *RegisteredProtocol {
+	return &RegisteredProtocol{
+		Id:              uuid.New(),
+		InvestigationId: investigationId,
+		UserId:          userId,
+		Registry:        registry,
+		RegistrationId:  registrationId,
+		Title:           title,
+		Status:          status,
+		Url:             url,
+		Notes:           notes,
+		CreatedAt:       time.Now(),
+	}
+}
+
+func (rp RegisteredProtocol) LogValue() slog.Value {
+	return slog.GroupValue(
+		slog.String("id", rp.Id.String()),
+		slog.String("investigation_id", rp.InvestigationId.String()),
+		slog.String("registry", string(rp.Registry)),
+		slog.String("registration_id", rp.RegistrationId),
+	)
+}
@@ -7,3 +7,12 @@ const (
 	ScopingReview               = "ScopingReview"
 	RapidReview                 = "RapidReview"
 )
+
+// RequiredScreeners is the number of independent decisions a record needs
+// before its screening status is settled.
+func (rt ReviewType) RequiredScreeners() int {
+	if rt == SystematicReview {
+		return 2
+	}
+	return 1
+}
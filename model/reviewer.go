@@ -26,3 +26,21 @@ func NewReviewer(userId uuid.UUID, reviewId uuid.UUID, reviewerRole ReviewerRole
 		UpdatedAt:    time.Now(),
 	}
 }
+
+// Adjudicator returns the active reviewer who resolves screening conflicts:
+// the one holding the adjudicator role, or the owner when nobody does.
+func Adjudicator(reviewers []Reviewer) *Reviewer {
+	var owner *Reviewer
+	for i := range reviewers {
+		if !reviewers[i].Active {
+			continue
+		}
+		switch reviewers[i].ReviewerRole {
+		case ReviewerAdjudicator:
+			return &reviewers[i]
+		case ReviewerOwner:
+			owner = &reviewers[i]
+		}
+	}
+	return owner
+}
@@ -3,6 +3,7 @@ package model
 type ReviewerRole string
 
 const (
-	ReviewerOwner  ReviewerRole = "ReviewerOwner"
-	ReviewerMember              = "ReviewerMember"
+	ReviewerOwner       ReviewerRole = "ReviewerOwner"
+	ReviewerMember                   = "ReviewerMember"
+	ReviewerAdjudicator              = "ReviewerAdjudicator"
 )
@@ -0,0 +1,93 @@
+package model
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"github.com/google/uuid"
+	"golang.org/x/exp/slog"
+	"time"
+)
+
+// StringMap is a JSON object of strings, such as the answers to signalling
+// questions by question id.
+type StringMap map[string]string
+
+func (sm StringMap) Value() (driver.Value, error) {
+	if sm == nil {
+		return []byte("{}"), nil
+	}
+	return json.Marshal(sm)
+}
+
+func (sm *StringMap) Scan(src interface{}) error {
+	var data []byte
+	switch value := src.(type) {
+	case []byte:
+		data = value
+	case string:
+		data = []byte(value)
+	case nil:
+		*sm = StringMap{}
+		return nil
+	default:
+		return errors.New("Incompatible types")
+	}
+	values := StringMap{}
+	if err := json.Unmarshal(data, &values); err != nil {
+		return err
+	}
+	*sm = values
+	return nil
+}
+
+// RobAssessment is the risk of bias assessment of a study by a reviewer with
+// one tool: the answers to its signalling questions and the domain and
+// overall judgements derived from them. Weight sets the share of the study
+// in the weighted summary chart.
+type RobAssessment struct {
+	Id           uuid.UUID `db:"id" json:"id"`
+	ReviewId     uuid.UUID `db:"review_id" json:"reviewId"`
+	ReferenceId  uuid.UUID `db:"reference_id" json:"referenceId"`
+	ReviewerId   uuid.UUID `db:"reviewer_id" json:"reviewerId"`
+	ReviewerName string    `db:"reviewer_name" json:"reviewerName,omitempty"`
+	Tool         string    `db:"tool" json:"tool"`
+	Answers      StringMap `db:"answers" json:"answers"`
+	Judgements   StringMap `db:"judgements" json:"judgements"`
+	Overall      string    `db:"overall" json:"overall"`
+	Weight       float64   `db:"weight" json:"weight"`
+	Note         string    `db:"note" json:"note"`
+	CreatedAt    time.Time `db:"created_at" json:"createdAt"`
+	UpdatedAt    time.Time `db:"updated_at" json:"updatedAt"`
+}
+
+func NewRobAssessment(reviewId uuid.UUID, referenceId uuid.UUID, reviewerId uuid.UUID, tool string) *RobAssessment {
+	return &RobAssessment{
+		Id:          uuid.New(),
+		ReviewId:    reviewId,
+		ReferenceId: referenceId,
+		ReviewerId:  reviewerId,
+		Tool:        tool,
+		Answers:     StringMap{},
+		Judgements:  StringMap{},
+		Weight:      1,
+		CreatedAt:   time.Now(),
+		UpdatedAt:   time.Now(),
+	}
+}
+
+func (ra RobAssessment) LogValue() slog.Value {
+	return slog.GroupValue(
+		slog.String("id", ra.Id.String()),
+		slog.String("reference_id", ra.ReferenceId.String()),
+		slog.String("reviewer_id", ra.ReviewerId.String()),
+		slog.String("tool", ra.Tool),
+		slog.String("overall", ra.Overall),
+	)
+}
+
+// RobStudy is an included study with its risk of bias assessments.
+type RobStudy struct {
+	Reference   Reference       `json:"reference"`
+	Assessments []RobAssessment `json:"assessments"`
+}
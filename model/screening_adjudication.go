@@ -0,0 +1,46 @@
+package model
+
+import (
+	"github.com/google/uuid"
+	"golang.org/x/exp/slog"
+	"time"
+)
+
+type ScreeningAdjudication struct {
+	Id                uuid.UUID      `db:"id" json:"id"`
+	ReviewId          uuid.UUID      `db:"review_id" json:"reviewId"`
+	ReferenceId       uuid.UUID      `db:"reference_id" json:"referenceId"`
+	AdjudicatorId     uuid.UUID      `db:"adjudicator_id" json:"adjudicatorId"`
+	Stage             ScreeningStage `db:"stage" json:"stage"`
+	Decision          Decision       `db:"decision" json:"decision"`
+	ExclusionReasonId uuid.NullUUID  `db:"exclusion_reason_id" json:"exclusionReasonId"`
+	ExclusionReason   string         `db:"exclusion_reason" json:"exclusionReason,omitempty"`
+	Note              string         `db:"note" json:"note"`
+	CreatedAt         time.Time      `db:"created_at" json:"createdAt"`
+	UpdatedAt         time.Time      `db:"updated_at" json:"updatedAt"`
+}
+
+func NewScreeningAdjudication(reviewId uuid.UUID, referenceId uuid.UUID, adjudicatorId uuid.UUID, stage ScreeningStage, decision Decision, exclusionReasonId uuid.NullUUID, note string) *ScreeningAdjudication {
+	return &ScreeningAdjudication{
+		Id:                uuid.New(),
+		ReviewId:          reviewId,
+		ReferenceId:       referenceId,
+		AdjudicatorId:     adjudicatorId,
+		Stage:             stage,
+		Decision:          decision,
+		ExclusionReasonId: exclusionReasonId,
+		Note:              note,
+		CreatedAt:         time.Now(),
+		UpdatedAt:         time.Now(),
+	}
+}
+
+func (sa ScreeningAdjudication) LogValue() slog.Value {
+	return slog.GroupValue(
+		slog.String("id", sa.Id.String()),
+		slog.String("reference_id", sa.ReferenceId.String()),
+		slog.String("adjudicator_id", sa.AdjudicatorId.String()),
+		slog.String("stage", string(sa.Stage)),
+		slog.String("decision", string(sa.Decision)),
+	)
+}
@@ -0,0 +1,30 @@
+package model
+
+import (
+	"github.com/google/uuid"
+	"sci-review/agreement"
+)
+
+// RaterAgreement compares two reviewers on the records both of them
+// screened. Kappa is nil when it cannot be computed.
+type RaterAgreement struct {
+	FirstId    uuid.UUID           `json:"firstId"`
+	FirstName  string              `json:"firstName"`
+	SecondId   uuid.UUID           `json:"secondId"`
+	SecondName string              `json:"secondName"`
+	Items      int                 `json:"items"`
+	Percent    float64             `json:"percent"`
+	Kappa      *agreement.Estimate `json:"kappa"`
+}
+
+// ScreeningAgreement sums up the agreement between the screeners of a
+// stage. Fleiss' kappa is only given when three or more reviewers screened,
+// on the largest group of records with the same number of ratings, Raters.
+type ScreeningAgreement struct {
+	Stage   ScreeningStage      `json:"stage"`
+	Items   int                 `json:"items"`
+	Percent float64             `json:"percent"`
+	Pairs   []RaterAgreement    `json:"pairs"`
+	Raters  int                 `json:"raters"`
+	Fleiss  *agreement.Estimate `json:"fleiss"`
+}
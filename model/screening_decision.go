@@ -0,0 +1,60 @@
+package model
+
+import (
+	"github.com/google/uuid"
+	"golang.org/x/exp/slog"
+	"time"
+)
+
+type ScreeningDecision struct {
+	Id                uuid.UUID      `db:"id" json:"id"`
+	ReviewId          uuid.UUID      `db:"review_id" json:"reviewId"`
+	ReferenceId       uuid.UUID      `db:"reference_id" json:"referenceId"`
+	ReviewerId        uuid.UUID      `db:"reviewer_id" json:"reviewerId"`
+	ReviewerName      string         `db:"reviewer_name" json:"reviewerName,omitempty"`
+	Stage             ScreeningStage `db:"stage" json:"stage"`
+	Decision          Decision       `db:"decision" json:"decision"`
+	ExclusionReasonId uuid.NullUUID  `db:"exclusion_reason_id" json:"exclusionReasonId"`
+	ExclusionReason   string         `db:"exclusion_reason" json:"exclusionReason,omitempty"`
+	Note              string         `db:"note" json:"note"`
+	CreatedAt         time.Time      `db:"created_at" json:"createdAt"`
+	UpdatedAt         time.Time      `db:"updated_at" json:"updatedAt"`
+}
+
+func NewScreeningDecision(reviewId uuid.UUID, referenceId uuid.UUID, reviewerId uuid.UUID, stage ScreeningStage, decision Decision, exclusionReasonId uuid.NullUUID, note string) *ScreeningDecision {
+	return &ScreeningDecision{
+		Id:                uuid.New(),
+		ReviewId:          reviewId,
+		ReferenceId:       referenceId,
+		ReviewerId:        reviewerId,
+		Stage:             stage,
+		Decision:          decision,
+		ExclusionReasonId: exclusionReasonId,
+		Note:              note,
+		CreatedAt:         time.Now(),
+		UpdatedAt:         time.Now(),
+	}
+}
+
+func (sd ScreeningDecision) LogValue() slog.Value {
+	return slog.GroupValue(
+		slog.String("id", sd.Id.String()),
+		slog.String("reference_id", sd.ReferenceId.String()),
+		slog.String("reviewer_id", sd.ReviewerId.String()),
+		slog.String("stage", string(sd.Stage)),
+		slog.String("decision", string(sd.Decision)),
+	)
+}
+
+// AcceptsDecision reports whether the reviewer may decide on a record with
+// the decisions it already has. Reviewers may always revise their own
+// decision, but once the record has the independent decisions it requires
+// no other screener adds one.
+func AcceptsDecision(decisions []ScreeningDecision, reviewerId uuid.UUID, required int) bool {
+	for _, decision := range decisions {
+		if decision.ReviewerId == reviewerId {
+			return true
+		}
+	}
+	return len(decisions) < required
+}
@@ -0,0 +1,47 @@
+package model
+
+import (
+	"github.com/google/uuid"
+	"time"
+)
+
+type ScreeningAction string
+
+const (
+	ActionDecided     ScreeningAction = "Decided"
+	ActionRevised                     = "Revised"
+	ActionConflict                    = "ConflictDetected"
+	ActionAdjudicated                 = "Adjudicated"
+)
+
+// ScreeningEvent is an entry of the screening history of a record. Events
+// are only ever appended.
+type ScreeningEvent struct {
+	Id                uuid.UUID       `db:"id" json:"id"`
+	ReviewId          uuid.UUID       `db:"review_id" json:"reviewId"`
+	ReferenceId       uuid.UUID       `db:"reference_id" json:"referenceId"`
+	ReviewerId        uuid.UUID       `db:"reviewer_id" json:"reviewerId"`
+	ReviewerName      string          `db:"reviewer_name" json:"reviewerName,omitempty"`
+	Stage             ScreeningStage  `db:"stage" json:"stage"`
+	Action            ScreeningAction `db:"action" json:"action"`
+	Decision          Decision        `db:"decision" json:"decision"`
+	ExclusionReasonId uuid.NullUUID   `db:"exclusion_reason_id" json:"exclusionReasonId"`
+	ExclusionReason   string          `db:"exclusion_reason" json:"exclusionReason,omitempty"`
+	Note              string          `db:"note" json:"note"`
+	CreatedAt         time.Time       `db:"created_at" json:"createdAt"`
+}
+
+func NewScreeningEvent(reviewId uuid.UUID, referenceId uuid.UUID, reviewerId uuid.UUID, stage ScreeningStage, action ScreeningAction, decision Decision, exclusionReasonId uuid.NullUUID, note string) *ScreeningEvent {
+	return &ScreeningEvent{
+		Id:                uuid.New(),
+		ReviewId:          reviewId,
+		ReferenceId:       referenceId,
+		ReviewerId:        reviewerId,
+		Stage:             stage,
+		Action:            action,
+		Decision:          decision,
+		ExclusionReasonId: exclusionReasonId,
+		Note:              note,
+		CreatedAt:         time.Now(),
+	}
+}
@@ -0,0 +1,25 @@
+package model
+
+import "github.com/google/uuid"
+
+type ReviewerProgress struct {
+	ReviewerId uuid.UUID `db:"reviewer_id" json:"reviewerId"`
+	UserId     uuid.UUID `db:"user_id" json:"userId"`
+	Name       string    `db:"name" json:"name"`
+	Included   int       `db:"included" json:"included"`
+	Excluded   int       `db:"excluded" json:"excluded"`
+	Maybe      int       `db:"maybe" json:"maybe"`
+	Screened   int       `db:"screened" json:"screened"`
+	Total      int       `db:"-" json:"total"`
+}
+
+func (rp ReviewerProgress) Remaining() int {
+	return rp.Total - rp.Screened
+}
+
+func (rp ReviewerProgress) Percent() int {
+	if rp.Total == 0 {
+		return 0
+	}
+	return rp.Screened * 100 / rp.Total
+}
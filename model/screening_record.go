@@ -0,0 +1,10 @@
+package model
+
+// ScreeningRecord is a record with the decisions taken on it in one stage
+// and the status derived from them.
+type ScreeningRecord struct {
+	Reference    Reference              `json:"reference"`
+	Status       ScreeningStatus        `json:"status"`
+	Decisions    []ScreeningDecision    `json:"decisions"`
+	Adjudication *ScreeningAdjudication `json:"adjudication"`
+}
@@ -0,0 +1,16 @@
+package model
+
+type ScreeningStage string
+
+const (
+	StageTitleAbstract ScreeningStage = "TitleAbstract"
+	StageFullText                     = "FullText"
+)
+
+var ScreeningStages = []ScreeningStage{StageTitleAbstract, StageFullText}
+
+// RequiresReason tells whether excluding a record in the stage needs an
+// exclusion reason.
+func (s ScreeningStage) RequiresReason() bool {
+	return s == StageFullText
+}
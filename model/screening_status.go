@@ -0,0 +1,41 @@
+package model
+
+type ScreeningStatus string
+
+const (
+	StatusPending  ScreeningStatus = "Pending"
+	StatusIncluded                 = "Included"
+	StatusExcluded                 = "Excluded"
+	StatusConflict                 = "Conflict"
+)
+
+var ScreeningStatuses = []ScreeningStatus{StatusPending, StatusIncluded, StatusExcluded, StatusConflict}
+
+// DeriveScreeningStatus computes the status of a record from the decisions
+// of its screeners. An adjudication is final. Otherwise the record stays
+// pending until it has the required number of decisions, and any
+// disagreement or "maybe" is a conflict.
+func DeriveScreeningStatus(decisions []Decision, required int, adjudication *Decision) ScreeningStatus {
+	if adjudication != nil {
+		return statusOf(*adjudication)
+	}
+	if len(decisions) == 0 || len(decisions) < required {
+		return StatusPending
+	}
+	for _, decision := range decisions {
+		if decision == DecisionMaybe || decision != decisions[0] {
+			return StatusConflict
+		}
+	}
+	return statusOf(decisions[0])
+}
+
+func statusOf(decision Decision) ScreeningStatus {
+	switch decision {
+	case DecisionInclude:
+		return StatusIncluded
+	case DecisionExclude:
+		return StatusExcluded
+	}
+	return StatusConflict
+}
@@ -0,0 +1,17 @@
+package model
+
+type SourceDatabase string
+
+const (
+	SourcePubMed       SourceDatabase = "PubMed"
+	SourceScopus                      = "Scopus"
+	SourceWebOfScience                = "WebOfScience"
+	SourceEmbase                      = "Embase"
+	SourceCINAHL                      = "CINAHL"
+	SourceOther                       = "Other"
+)
+
+type SourceCount struct {
+	SourceDatabase SourceDatabase `db:"source_database" json:"sourceDatabase"`
+	Records        int            `db:"records" json:"records"`
+}
@@ -0,0 +1,50 @@
+package model
+
+import (
+	"database/sql/driver"
+	"errors"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// Strings is a Postgres text array. Elements are quoted and escaped as
+// Postgres does, so they may hold commas, quotes, braces and backslashes;
+// NULL elements are left out.
+type Strings []string
+
+func (s Strings) Value() (driver.Value, error) {
+	if s == nil {
+		return "{}", nil
+	}
+	buf, err := pgtype.NewMap().Encode(pgtype.TextArrayOID, pgtype.TextFormatCode, []string(s), nil)
+	if err != nil {
+		return nil, err
+	}
+	return string(buf), nil
+}
+
+func (s *Strings) Scan(src interface{}) error {
+	var data []byte
+	switch value := src.(type) {
+	case string:
+		data = []byte(value)
+	case []byte:
+		data = value
+	case nil:
+		*s = Strings{}
+		return nil
+	default:
+		return errors.New("Incompatible types")
+	}
+	elements := []pgtype.Text{}
+	if err := pgtype.NewMap().Scan(pgtype.TextArrayOID, pgtype.TextFormatCode, data, &elements); err != nil {
+		return err
+	}
+	values := Strings{}
+	for _, element := range elements {
+		if element.Valid {
+			values = append(values, element.String)
+		}
+	}
+	*s = values
+	return nil
+}
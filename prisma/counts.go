@@ -0,0 +1,46 @@
+// Package prisma draws the PRISMA 2020 flow diagram of a review.
+package prisma
+
+// Source is the number of records identified in one database.
+type Source struct {
+	Name    string `json:"name"`
+	Records int    `json:"records"`
+}
+
+// Reason is the number of full-text reports excluded for one reason.
+type Reason struct {
+	Label   string `json:"label"`
+	Reports int    `json:"reports"`
+}
+
+// Counts are the numbers shown in the diagram. Records still waiting for a
+// decision are counted in Pending and left out of every box below the one
+// they wait in.
+type Counts struct {
+	Sources        []Source `json:"sources"`
+	Duplicates     int      `json:"duplicates"`
+	Screened       int      `json:"screened"`
+	ScreenExcluded int      `json:"screenExcluded"`
+	Sought         int      `json:"sought"`
+	NotRetrieved   int      `json:"notRetrieved"`
+	Assessed       int      `json:"assessed"`
+	Reasons        []Reason `json:"reasons"`
+	Included       int      `json:"included"`
+	Pending        int      `json:"pending"`
+}
+
+func (c Counts) Identified() int {
+	total := 0
+	for _, source := range c.Sources {
+		total += source.Records
+	}
+	return total
+}
+
+func (c Counts) ReportsExcluded() int {
+	total := 0
+	for _, reason := range c.Reasons {
+		total += reason.Reports
+	}
+	return total
+}
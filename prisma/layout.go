@@ -0,0 +1,170 @@
+package prisma
+
+import (
+	"fmt"
+	"strings"
+)
+
+const (
+	charWidth  = 7
+	lineHeight = 16
+	padding    = 10
+	margin     = 20
+	phaseWidth = 30
+	columnGap  = 60
+	rowGap     = 40
+	wrapAt     = 44
+	minChars   = 30
+)
+
+type box struct {
+	X, Y, W, H int
+	Lines      []string
+	Phase      bool
+}
+
+type arrow struct {
+	X1, Y1, X2, Y2 int
+}
+
+type diagram struct {
+	Width, Height int
+	Boxes         []box
+	Arrows        []arrow
+}
+
+// wrap breaks text into lines of at most width characters, keeping its
+// indent and indenting the continuation lines a little further.
+func wrap(text string, width int) []string {
+	indent := text[:len(text)-len(strings.TrimLeft(text, " "))]
+	lines := []string{}
+	line := ""
+	for _, word := range strings.Fields(text) {
+		if line != "" && len(line)+1+len(word) > width {
+			lines = append(lines, line)
+			line = indent + "  " + word
+			continue
+		}
+		if line == "" {
+			line = indent + word
+		} else {
+			line += " " + word
+		}
+	}
+	if line != "" {
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+func count(label string, n int) []string {
+	return wrap(fmt.Sprintf("%s (n = %d)", label, n), wrapAt)
+}
+
+func textWidth(lines []string) int {
+	width := minChars
+	for _, line := range lines {
+		if len(line) > width {
+			width = len(line)
+		}
+	}
+	return width*charWidth + 2*padding
+}
+
+func textHeight(lines []string) int {
+	return len(lines)*lineHeight + 2*padding
+}
+
+// layout places the boxes of the diagram: the main flow on the left, the
+// records leaving it on the right, and the phase labels in a side bar.
+func layout(c Counts) diagram {
+	identified := []string{"Records identified from:"}
+	identified = append(identified, count("Databases", c.Identified())...)
+	for _, source := range c.Sources {
+		identified = append(identified, count("  "+source.Name, source.Records)...)
+	}
+
+	removed := []string{"Records removed before screening:"}
+	removed = append(removed, count("Duplicate records removed", c.Duplicates)...)
+
+	excludedReports := count("Reports excluded", c.ReportsExcluded())
+	for _, reason := range c.Reasons {
+		excludedReports = append(excludedReports, count("  "+reason.Label, reason.Reports)...)
+	}
+
+	included := count("Studies included in review", c.Included)
+	if c.Pending > 0 {
+		included = append(included, count("Records awaiting a decision", c.Pending)...)
+	}
+
+	rows := [][2][]string{
+		{identified, removed},
+		{count("Records screened", c.Screened), count("Records excluded", c.ScreenExcluded)},
+		{count("Reports sought for retrieval", c.Sought), count("Reports not retrieved", c.NotRetrieved)},
+		{count("Reports assessed for eligibility", c.Assessed), excludedReports},
+		{included, nil},
+	}
+
+	leftWidth, rightWidth := 0, 0
+	for _, row := range rows {
+		leftWidth = max(leftWidth, textWidth(row[0]))
+		if row[1] != nil {
+			rightWidth = max(rightWidth, textWidth(row[1]))
+		}
+	}
+
+	phases := []struct {
+		label       string
+		first, last int
+	}{
+		{"Identification", 0, 0},
+		{"Screening", 1, 3},
+		{"Included", 4, 4},
+	}
+
+	heights := make([]int, len(rows))
+	for i, row := range rows {
+		heights[i] = textHeight(row[0])
+		if row[1] != nil {
+			heights[i] = max(heights[i], textHeight(row[1]))
+		}
+	}
+	// a phase of a single row must be tall enough for its turned label
+	for _, phase := range phases {
+		if phase.first == phase.last {
+			heights[phase.first] = max(heights[phase.first], len(phase.label)*charWidth+2*padding)
+		}
+	}
+
+	leftX := margin + phaseWidth + padding
+	rightX := leftX + leftWidth + columnGap
+
+	d := diagram{}
+	y := margin
+	tops := make([]int, len(rows))
+	for i, row := range rows {
+		height := heights[i]
+		tops[i] = y
+
+		d.Boxes = append(d.Boxes, box{X: leftX, Y: y, W: leftWidth, H: height, Lines: row[0]})
+		if row[1] != nil {
+			d.Boxes = append(d.Boxes, box{X: rightX, Y: y, W: rightWidth, H: height, Lines: row[1]})
+			d.Arrows = append(d.Arrows, arrow{X1: leftX + leftWidth, Y1: y + height/2, X2: rightX, Y2: y + height/2})
+		}
+		if i > 0 {
+			center := leftX + leftWidth/2
+			d.Arrows = append(d.Arrows, arrow{X1: center, Y1: tops[i-1] + heights[i-1], X2: center, Y2: y})
+		}
+		y += height + rowGap
+	}
+
+	for _, phase := range phases {
+		top := tops[phase.first]
+		bottom := tops[phase.last] + heights[phase.last]
+		d.Boxes = append(d.Boxes, box{X: margin, Y: top, W: phaseWidth, H: bottom - top, Lines: []string{phase.label}, Phase: true})
+	}
+
+	d.Width = rightX + rightWidth + margin
+	d.Height = y - rowGap + margin
+	return d
+}
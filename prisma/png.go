@@ -0,0 +1,114 @@
+package prisma
+
+import (
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+	"io"
+
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/basicfont"
+	"golang.org/x/image/math/fixed"
+)
+
+var (
+	white = color.RGBA{R: 0xff, G: 0xff, B: 0xff, A: 0xff}
+	black = color.RGBA{A: 0xff}
+	phase = color.RGBA{R: 0xc5, G: 0xdf, B: 0xf8, A: 0xff}
+)
+
+// arrowSize is the length of the arrow heads, in pixels.
+const arrowSize = 7
+
+// WritePng renders the flow diagram as a PNG image, with the same layout as
+// the SVG.
+func WritePng(w io.Writer, c Counts) error {
+	d := layout(c)
+	img := image.NewRGBA(image.Rect(0, 0, d.Width, d.Height))
+	draw.Draw(img, img.Bounds(), image.NewUniform(white), image.Point{}, draw.Src)
+
+	for _, b := range d.Boxes {
+		rect := image.Rect(b.X, b.Y, b.X+b.W, b.Y+b.H)
+		if b.Phase {
+			draw.Draw(img, rect, image.NewUniform(phase), image.Point{}, draw.Src)
+			drawVertical(img, b.Lines[0], b.X+b.W/2, b.Y+b.H/2)
+			continue
+		}
+		strokeRect(img, rect)
+		for i, line := range b.Lines {
+			drawText(img, line, b.X+padding, b.Y+padding+(i+1)*lineHeight-3)
+		}
+	}
+
+	for _, a := range d.Arrows {
+		drawArrow(img, a)
+	}
+
+	return png.Encode(w, img)
+}
+
+func drawText(img draw.Image, text string, x int, baseline int) {
+	drawer := font.Drawer{
+		Dst:  img,
+		Src:  image.NewUniform(black),
+		Face: basicfont.Face7x13,
+		Dot:  fixed.P(x, baseline),
+	}
+	drawer.DrawString(text)
+}
+
+// drawVertical draws text turned a quarter left, centred on (cx, cy).
+func drawVertical(img *image.RGBA, text string, cx int, cy int) {
+	width := len(text) * charWidth
+	height := basicfont.Face7x13.Height
+	flat := image.NewRGBA(image.Rect(0, 0, width, height))
+	drawText(flat, text, 0, basicfont.Face7x13.Ascent)
+
+	left := cx - height/2
+	top := cy - width/2
+	for x := 0; x < width; x++ {
+		for y := 0; y < height; y++ {
+			if _, _, _, a := flat.At(x, y).RGBA(); a > 0x8000 {
+				img.Set(left+y, top+width-1-x, black)
+			}
+		}
+	}
+}
+
+func strokeRect(img *image.RGBA, r image.Rectangle) {
+	for x := r.Min.X; x < r.Max.X; x++ {
+		img.Set(x, r.Min.Y, black)
+		img.Set(x, r.Max.Y-1, black)
+	}
+	for y := r.Min.Y; y < r.Max.Y; y++ {
+		img.Set(r.Min.X, y, black)
+		img.Set(r.Max.X-1, y, black)
+	}
+}
+
+// drawArrow draws a horizontal or vertical arrow with a filled head at its
+// end.
+func drawArrow(img *image.RGBA, a arrow) {
+	if a.Y1 == a.Y2 {
+		for x := a.X1; x < a.X2; x++ {
+			img.Set(x, a.Y1, black)
+		}
+		for i := 0; i < arrowSize; i++ {
+			half := (arrowSize - i) / 2
+			for y := a.Y1 - half; y <= a.Y1+half; y++ {
+				img.Set(a.X2-arrowSize+i, y, black)
+			}
+		}
+		return
+	}
+	for y := a.Y1; y < a.Y2; y++ {
+		img.Set(a.X1, y, black)
+	}
+	for i := 0; i < arrowSize; i++ {
+		half := (arrowSize - i) / 2
+		for x := a.X1 - half; x <= a.X1+half; x++ {
+			img.Set(x, a.Y2-arrowSize+i, black)
+		}
+	}
+}
@@ -0,0 +1,45 @@
+package prisma
+
+import (
+	"bufio"
+	"fmt"
+	"html"
+	"io"
+)
+
+const (
+	phaseColor  = "#c5dff8"
+	borderColor = "#000000"
+)
+
+// WriteSvg writes the flow diagram as an SVG document.
+func WriteSvg(w io.Writer, c Counts) error {
+	d := layout(c)
+	out := bufio.NewWriter(w)
+
+	fmt.Fprintf(out, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d" font-family="Helvetica, Arial, sans-serif" font-size="12">`+"\n", d.Width, d.Height, d.Width, d.Height)
+	fmt.Fprint(out, `<defs><marker id="arrow" viewBox="0 0 10 10" refX="10" refY="5" markerWidth="8" markerHeight="8" orient="auto-start-reverse"><path d="M 0 0 L 10 5 L 0 10 z"/></marker></defs>`+"\n")
+	fmt.Fprintf(out, `<rect width="%d" height="%d" fill="#ffffff"/>`+"\n", d.Width, d.Height)
+
+	for _, b := range d.Boxes {
+		if b.Phase {
+			fmt.Fprintf(out, `<rect x="%d" y="%d" width="%d" height="%d" rx="6" fill="%s"/>`+"\n", b.X, b.Y, b.W, b.H, phaseColor)
+			cx, cy := b.X+b.W/2, b.Y+b.H/2
+			fmt.Fprintf(out, `<text x="%d" y="%d" transform="rotate(-90 %d %d)" text-anchor="middle" dominant-baseline="middle" font-weight="bold">%s</text>`+"\n", cx, cy, cx, cy, html.EscapeString(b.Lines[0]))
+			continue
+		}
+		fmt.Fprintf(out, `<rect x="%d" y="%d" width="%d" height="%d" fill="#ffffff" stroke="%s"/>`+"\n", b.X, b.Y, b.W, b.H, borderColor)
+		fmt.Fprintf(out, `<text x="%d" y="%d" xml:space="preserve">`, b.X+padding, b.Y+padding)
+		for _, line := range b.Lines {
+			fmt.Fprintf(out, `<tspan x="%d" dy="%d">%s</tspan>`, b.X+padding, lineHeight, html.EscapeString(line))
+		}
+		fmt.Fprint(out, "</text>\n")
+	}
+
+	for _, a := range d.Arrows {
+		fmt.Fprintf(out, `<line x1="%d" y1="%d" x2="%d" y2="%d" stroke="%s" marker-end="url(#arrow)"/>`+"\n", a.X1, a.Y1, a.X2, a.Y2, borderColor)
+	}
+
+	fmt.Fprint(out, "</svg>\n")
+	return out.Flush()
+}
@@ -0,0 +1,147 @@
+package repo
+
+import (
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"sci-review/model"
+)
+
+type ChecklistRepo interface {
+	CreateTemplate(template *model.ChecklistTemplate) error
+	ArchiveTemplate(id uuid.UUID) error
+	FindTemplateById(id uuid.UUID) (*model.ChecklistTemplate, error)
+	FindTemplatesByOrganizationId(organizationId uuid.UUID) ([]model.ChecklistTemplate, error)
+	FindAvailableTemplates(userId uuid.UUID) ([]model.ChecklistTemplate, error)
+	SaveAppraisal(appraisal *model.ChecklistAppraisal) error
+	FindAppraisal(referenceId uuid.UUID, templateId uuid.UUID, reviewerId uuid.UUID) (*model.ChecklistAppraisal, error)
+	FindAppraisalsByReviewId(reviewId uuid.UUID, templateId uuid.UUID) ([]model.ChecklistAppraisal, error)
+	GetDB() *sqlx.DB
+}
+
+type ChecklistRepoSql struct {
+	DB *sqlx.DB
+}
+
+func NewChecklistRepoSql(DB *sqlx.DB) *ChecklistRepoSql {
+	return &ChecklistRepoSql{DB: DB}
+}
+
+func (r *ChecklistRepoSql) CreateTemplate(template *model.ChecklistTemplate) error {
+	query := `
+		INSERT INTO checklist_templates (id, organization_id, name, definition, archived, created_by, created_at, updated_at)
+		VALUES (:id, :organization_id, :name, :definition, :archived, :created_by, :created_at, :updated_at)
+	`
+	_, err := r.DB.NamedExec(query, template)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func (r *ChecklistRepoSql) ArchiveTemplate(id uuid.UUID) error {
+	query := `UPDATE checklist_templates SET archived = true, updated_at = NOW() WHERE id = $1`
+	_, err := r.DB.Exec(query, id)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func (r *ChecklistRepoSql) FindTemplateById(id uuid.UUID) (*model.ChecklistTemplate, error) {
+	template := model.ChecklistTemplate{}
+	query := `SELECT * FROM checklist_templates WHERE id = $1`
+	err := r.DB.Get(&template, query, id)
+	if err != nil {
+		if err.Error() == "sql: no rows in result set" {
+			return nil, NotFoundInRepo
+		}
+		return nil, err
+	}
+	return &template, nil
+}
+
+func (r *ChecklistRepoSql) FindTemplatesByOrganizationId(organizationId uuid.UUID) ([]model.ChecklistTemplate, error) {
+	templates := []model.ChecklistTemplate{}
+	query := `SELECT * FROM checklist_templates WHERE organization_id = $1 AND NOT archived ORDER BY name`
+	err := r.DB.Select(&templates, query, organizationId)
+	if err != nil {
+		return nil, err
+	}
+	return templates, nil
+}
+
+// FindAvailableTemplates returns the built-in templates and those of the
+// organizations the user is an active member of, built-in ones first.
+func (r *ChecklistRepoSql) FindAvailableTemplates(userId uuid.UUID) ([]model.ChecklistTemplate, error) {
+	templates := []model.ChecklistTemplate{}
+	query := `
+		SELECT * FROM checklist_templates
+		WHERE NOT archived AND (
+			organization_id IS NULL OR
+			organization_id IN (SELECT organization_id FROM members WHERE user_id = $1 AND active = true)
+		)
+		ORDER BY organization_id NULLS FIRST, name
+	`
+	err := r.DB.Select(&templates, query, userId)
+	if err != nil {
+		return nil, err
+	}
+	return templates, nil
+}
+
+// SaveAppraisal creates the appraisal of a reviewer with a template, or
+// replaces its answers and score when there is one already.
+func (r *ChecklistRepoSql) SaveAppraisal(appraisal *model.ChecklistAppraisal) error {
+	query := `
+		INSERT INTO checklist_appraisals (id, review_id, reference_id, template_id, reviewer_id, answers, score, max_score, rating, note, created_at, updated_at)
+		VALUES (:id, :review_id, :reference_id, :template_id, :reviewer_id, :answers, :score, :max_score, :rating, :note, :created_at, :updated_at)
+		ON CONFLICT (reference_id, template_id, reviewer_id)
+		DO UPDATE SET answers = EXCLUDED.answers, score = EXCLUDED.score, max_score = EXCLUDED.max_score,
+			rating = EXCLUDED.rating, note = EXCLUDED.note, updated_at = EXCLUDED.updated_at
+	`
+	_, err := r.DB.NamedExec(query, appraisal)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func (r *ChecklistRepoSql) FindAppraisal(referenceId uuid.UUID, templateId uuid.UUID, reviewerId uuid.UUID) (*model.ChecklistAppraisal, error) {
+	appraisal := model.ChecklistAppraisal{}
+	query := `
+		SELECT a.*, u.name AS reviewer_name
+		FROM checklist_appraisals a
+		INNER JOIN reviewers rv ON rv.id = a.reviewer_id
+		INNER JOIN users u ON u.id = rv.user_id
+		WHERE a.reference_id = $1 AND a.template_id = $2 AND a.reviewer_id = $3
+	`
+	err := r.DB.Get(&appraisal, query, referenceId, templateId, reviewerId)
+	if err != nil {
+		if err.Error() == "sql: no rows in result set" {
+			return nil, NotFoundInRepo
+		}
+		return nil, err
+	}
+	return &appraisal, nil
+}
+
+func (r *ChecklistRepoSql) FindAppraisalsByReviewId(reviewId uuid.UUID, templateId uuid.UUID) ([]model.ChecklistAppraisal, error) {
+	appraisals := []model.ChecklistAppraisal{}
+	query := `
+		SELECT a.*, u.name AS reviewer_name
+		FROM checklist_appraisals a
+		INNER JOIN reviewers rv ON rv.id = a.reviewer_id
+		INNER JOIN users u ON u.id = rv.user_id
+		WHERE a.review_id = $1 AND a.template_id = $2
+		ORDER BY a.updated_at DESC
+	`
+	err := r.DB.Select(&appraisals, query, reviewId, templateId)
+	if err != nil {
+		return nil, err
+	}
+	return appraisals, nil
+}
+
+func (r *ChecklistRepoSql) GetDB() *sqlx.DB {
+	return r.DB
+}
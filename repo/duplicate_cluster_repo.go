@@ -0,0 +1,149 @@
+package repo
+
+import (
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"sci-review/model"
+)
+
+type DuplicateClusterRepo interface {
+	Create(cluster *model.DuplicateCluster, tx *sqlx.Tx) error
+	FindAllByReviewId(reviewId uuid.UUID, status model.DuplicateStatus) ([]model.DuplicateCluster, error)
+	FindById(id uuid.UUID) (*model.DuplicateCluster, error)
+	UpdateDecision(cluster *model.DuplicateCluster, tx *sqlx.Tx) error
+	DeleteProposed(reviewId uuid.UUID, tx *sqlx.Tx) error
+	GetDB() *sqlx.DB
+}
+
+type DuplicateClusterRepoSql struct {
+	DB *sqlx.DB
+}
+
+func NewDuplicateClusterRepoSql(DB *sqlx.DB) *DuplicateClusterRepoSql {
+	return &DuplicateClusterRepoSql{DB: DB}
+}
+
+type clusterMember struct {
+	ClusterId uuid.UUID `db:"cluster_id"`
+	model.Reference
+}
+
+func (r *DuplicateClusterRepoSql) Create(cluster *model.DuplicateCluster, tx *sqlx.Tx) error {
+	query := `
+		INSERT INTO duplicate_clusters (id, review_id, primary_id, match_type, score, status, decided_by, decided_at,
+			created_at, updated_at)
+		VALUES (:id, :review_id, :primary_id, :match_type, :score, :status, :decided_by, :decided_at,
+			:created_at, :updated_at)
+	`
+	_, err := tx.NamedExec(query, cluster)
+	if err != nil {
+		return err
+	}
+
+	for _, member := range cluster.Members {
+		query = `INSERT INTO duplicate_cluster_members (cluster_id, reference_id) VALUES ($1, $2)`
+		_, err = tx.Exec(query, cluster.Id, member.Id)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *DuplicateClusterRepoSql) FindAllByReviewId(reviewId uuid.UUID, status model.DuplicateStatus) ([]model.DuplicateCluster, error) {
+	clusters := []model.DuplicateCluster{}
+	query := `SELECT * FROM duplicate_clusters WHERE review_id = $1 AND status = $2 ORDER BY score, created_at`
+	err := r.DB.Select(&clusters, query, reviewId, status)
+	if err != nil {
+		return nil, err
+	}
+
+	members := []clusterMember{}
+	query = `
+		SELECT m.cluster_id, r.*
+		FROM duplicate_cluster_members m
+		INNER JOIN duplicate_clusters c ON c.id = m.cluster_id
+		INNER JOIN review_references r ON r.id = m.reference_id
+		WHERE c.review_id = $1 AND c.status = $2
+		ORDER BY r.created_at
+	`
+	err = r.DB.Select(&members, query, reviewId, status)
+	if err != nil {
+		return nil, err
+	}
+
+	return attachMembers(clusters, members), nil
+}
+
+func (r *DuplicateClusterRepoSql) FindById(id uuid.UUID) (*model.DuplicateCluster, error) {
+	cluster := model.DuplicateCluster{}
+	query := `SELECT * FROM duplicate_clusters WHERE id = $1`
+	err := r.DB.Get(&cluster, query, id)
+	if err != nil {
+		if err.Error() == "sql: no rows in result set" {
+			return nil, NotFoundInRepo
+		}
+		return nil, err
+	}
+
+	members := []clusterMember{}
+	query = `
+		SELECT m.cluster_id, r.*
+		FROM duplicate_cluster_members m
+		INNER JOIN review_references r ON r.id = m.reference_id
+		WHERE m.cluster_id = $1
+		ORDER BY r.created_at
+	`
+	err = r.DB.Select(&members, query, id)
+	if err != nil {
+		return nil, err
+	}
+
+	clusters := attachMembers([]model.DuplicateCluster{cluster}, members)
+	return &clusters[0], nil
+}
+
+func attachMembers(clusters []model.DuplicateCluster, members []clusterMember) []model.DuplicateCluster {
+	index := map[uuid.UUID]int{}
+	for i := range clusters {
+		clusters[i].Members = []model.Reference{}
+		index[clusters[i].Id] = i
+	}
+	for _, member := range members {
+		if i, ok := index[member.ClusterId]; ok {
+			clusters[i].Members = append(clusters[i].Members, member.Reference)
+		}
+	}
+	return clusters
+}
+
+func (r *DuplicateClusterRepoSql) UpdateDecision(cluster *model.DuplicateCluster, tx *sqlx.Tx) error {
+	query := `
+		UPDATE duplicate_clusters
+		SET
+			primary_id = :primary_id,
+			status = :status,
+			decided_by = :decided_by,
+			decided_at = :decided_at,
+			updated_at = :updated_at
+		WHERE id = :id
+	`
+	_, err := tx.NamedExec(query, cluster)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func (r *DuplicateClusterRepoSql) DeleteProposed(reviewId uuid.UUID, tx *sqlx.Tx) error {
+	query := `DELETE FROM duplicate_clusters WHERE review_id = $1 AND status = $2`
+	_, err := tx.Exec(query, reviewId, model.DuplicateProposed)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func (r *DuplicateClusterRepoSql) GetDB() *sqlx.DB {
+	return r.DB
+}
@@ -0,0 +1,127 @@
+package repo
+
+import (
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"sci-review/model"
+)
+
+type ExclusionReasonRepo interface {
+	Create(reason *model.ExclusionReason, tx *sqlx.Tx) error
+	FindAllByReviewId(reviewId uuid.UUID) ([]model.ExclusionReason, error)
+	FindById(id uuid.UUID) (*model.ExclusionReason, error)
+	Update(reason *model.ExclusionReason) error
+	Delete(id uuid.UUID) error
+	CountUsage(id uuid.UUID) (int, error)
+	CountByStage(reviewId uuid.UUID, stage model.ScreeningStage) (map[uuid.UUID]int, error)
+	GetDB() *sqlx.DB
+}
+
+type ExclusionReasonRepoSql struct {
+	DB *sqlx.DB
+}
+
+func NewExclusionReasonRepoSql(DB *sqlx.DB) *ExclusionReasonRepoSql {
+	return &ExclusionReasonRepoSql{DB: DB}
+}
+
+func (r *ExclusionReasonRepoSql) Create(reason *model.ExclusionReason, tx *sqlx.Tx) error {
+	query := `
+		INSERT INTO exclusion_reasons (id, review_id, label, position, created_at, updated_at)
+		VALUES (:id, :review_id, :label, :position, :created_at, :updated_at)
+	`
+	_, err := tx.NamedExec(query, reason)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func (r *ExclusionReasonRepoSql) FindAllByReviewId(reviewId uuid.UUID) ([]model.ExclusionReason, error) {
+	reasons := []model.ExclusionReason{}
+	query := `SELECT * FROM exclusion_reasons WHERE review_id = $1 ORDER BY position, created_at`
+	err := r.DB.Select(&reasons, query, reviewId)
+	if err != nil {
+		return nil, err
+	}
+	return reasons, nil
+}
+
+func (r *ExclusionReasonRepoSql) FindById(id uuid.UUID) (*model.ExclusionReason, error) {
+	reason := model.ExclusionReason{}
+	query := `SELECT * FROM exclusion_reasons WHERE id = $1`
+	err := r.DB.Get(&reason, query, id)
+	if err != nil {
+		if err.Error() == "sql: no rows in result set" {
+			return nil, NotFoundInRepo
+		}
+		return nil, err
+	}
+	return &reason, nil
+}
+
+func (r *ExclusionReasonRepoSql) Update(reason *model.ExclusionReason) error {
+	query := `UPDATE exclusion_reasons SET label = :label, position = :position, updated_at = :updated_at WHERE id = :id`
+	_, err := r.DB.NamedExec(query, reason)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func (r *ExclusionReasonRepoSql) Delete(id uuid.UUID) error {
+	_, err := r.DB.Exec(`DELETE FROM exclusion_reasons WHERE id = $1`, id)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// CountUsage counts the decisions, adjudications and history entries that
+// refer to the reason.
+func (r *ExclusionReasonRepoSql) CountUsage(id uuid.UUID) (int, error) {
+	var count int
+	query := `
+		SELECT
+			(SELECT COUNT(*) FROM screening_decisions WHERE exclusion_reason_id = $1) +
+			(SELECT COUNT(*) FROM screening_adjudications WHERE exclusion_reason_id = $1) +
+			(SELECT COUNT(*) FROM screening_history WHERE exclusion_reason_id = $1)
+	`
+	err := r.DB.Get(&count, query, id)
+	if err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+type reasonCount struct {
+	ReasonId uuid.UUID `db:"exclusion_reason_id"`
+	Count    int       `db:"count"`
+}
+
+// CountByStage counts the exclusion decisions of unique records that cite
+// each reason in the stage.
+func (r *ExclusionReasonRepoSql) CountByStage(reviewId uuid.UUID, stage model.ScreeningStage) (map[uuid.UUID]int, error) {
+	rows := []reasonCount{}
+	query := `
+		SELECT d.exclusion_reason_id, COUNT(*) AS count
+		FROM screening_decisions d
+		INNER JOIN review_references r ON r.id = d.reference_id AND r.duplicate_of IS NULL
+		WHERE d.review_id = $1 AND d.stage = $2 AND d.decision = 'Exclude' AND d.exclusion_reason_id IS NOT NULL
+		GROUP BY d.exclusion_reason_id
+	`
+	err := r.DB.Select(&rows, query, reviewId, stage)
+	if err != nil {
+		return nil, err
+	}
+
+	counts := make(map[uuid.UUID]int, len(rows))
+	for _, row := range rows {
+		counts[row.ReasonId] = row.Count
+	}
+	return counts, nil
+}
+
+func (r *ExclusionReasonRepoSql) GetDB() *sqlx.DB {
+	return r.DB
+}
@@ -0,0 +1,207 @@
+package repo
+
+import (
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"sci-review/model"
+)
+
+type ExtractionRepo interface {
+	CreateForm(extractionForm *model.ExtractionForm) error
+	FindFormsByReviewId(reviewId uuid.UUID) ([]model.ExtractionForm, error)
+	FindLatestForm(reviewId uuid.UUID) (*model.ExtractionForm, error)
+	FindFormById(id uuid.UUID) (*model.ExtractionForm, error)
+	Save(extraction *model.Extraction) error
+	FindAllByReviewId(reviewId uuid.UUID) ([]model.Extraction, error)
+	FindAllByReference(referenceId uuid.UUID) ([]model.Extraction, error)
+	FindLatest(referenceId uuid.UUID, reviewerId uuid.UUID) (*model.Extraction, error)
+	SaveConsensus(consensus *model.ExtractionConsensus) error
+	FindConsensus(referenceId uuid.UUID, formId uuid.UUID) (*model.ExtractionConsensus, error)
+	FindConsensusByReviewId(reviewId uuid.UUID) ([]model.ExtractionConsensus, error)
+	GetDB() *sqlx.DB
+}
+
+type ExtractionRepoSql struct {
+	DB *sqlx.DB
+}
+
+func NewExtractionRepoSql(DB *sqlx.DB) *ExtractionRepoSql {
+	return &ExtractionRepoSql{DB: DB}
+}
+
+func (r *ExtractionRepoSql) CreateForm(extractionForm *model.ExtractionForm) error {
+	query := `
+		INSERT INTO extraction_forms (id, review_id, version, schema, created_by, created_at)
+		VALUES (:id, :review_id, :version, :schema, :created_by, :created_at)
+	`
+	_, err := r.DB.NamedExec(query, extractionForm)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func (r *ExtractionRepoSql) FindFormsByReviewId(reviewId uuid.UUID) ([]model.ExtractionForm, error) {
+	forms := []model.ExtractionForm{}
+	query := `SELECT * FROM extraction_forms WHERE review_id = $1 ORDER BY version DESC`
+	err := r.DB.Select(&forms, query, reviewId)
+	if err != nil {
+		return nil, err
+	}
+	return forms, nil
+}
+
+func (r *ExtractionRepoSql) FindLatestForm(reviewId uuid.UUID) (*model.ExtractionForm, error) {
+	extractionForm := model.ExtractionForm{}
+	query := `SELECT * FROM extraction_forms WHERE review_id = $1 ORDER BY version DESC LIMIT 1`
+	err := r.DB.Get(&extractionForm, query, reviewId)
+	if err != nil {
+		if err.Error() == "sql: no rows in result set" {
+			return nil, NotFoundInRepo
+		}
+		return nil, err
+	}
+	return &extractionForm, nil
+}
+
+func (r *ExtractionRepoSql) FindFormById(id uuid.UUID) (*model.ExtractionForm, error) {
+	extractionForm := model.ExtractionForm{}
+	query := `SELECT * FROM extraction_forms WHERE id = $1`
+	err := r.DB.Get(&extractionForm, query, id)
+	if err != nil {
+		if err.Error() == "sql: no rows in result set" {
+			return nil, NotFoundInRepo
+		}
+		return nil, err
+	}
+	return &extractionForm, nil
+}
+
+// Save creates the extraction of a reviewer for a form version, or replaces
+// its data when there is one already.
+func (r *ExtractionRepoSql) Save(extraction *model.Extraction) error {
+	query := `
+		INSERT INTO extractions (id, review_id, reference_id, form_id, form_version, reviewer_id, data, created_at, updated_at)
+		VALUES (:id, :review_id, :reference_id, :form_id, :form_version, :reviewer_id, :data, :created_at, :updated_at)
+		ON CONFLICT (reference_id, form_id, reviewer_id)
+		DO UPDATE SET data = EXCLUDED.data, updated_at = EXCLUDED.updated_at
+	`
+	_, err := r.DB.NamedExec(query, extraction)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func (r *ExtractionRepoSql) FindAllByReviewId(reviewId uuid.UUID) ([]model.Extraction, error) {
+	extractions := []model.Extraction{}
+	query := `
+		SELECT e.*, u.name AS reviewer_name
+		FROM extractions e
+		INNER JOIN reviewers rv ON rv.id = e.reviewer_id
+		INNER JOIN users u ON u.id = rv.user_id
+		WHERE e.review_id = $1
+		ORDER BY e.form_version DESC, e.created_at
+	`
+	err := r.DB.Select(&extractions, query, reviewId)
+	if err != nil {
+		return nil, err
+	}
+	return extractions, nil
+}
+
+func (r *ExtractionRepoSql) FindAllByReference(referenceId uuid.UUID) ([]model.Extraction, error) {
+	extractions := []model.Extraction{}
+	query := `
+		SELECT e.*, u.name AS reviewer_name
+		FROM extractions e
+		INNER JOIN reviewers rv ON rv.id = e.reviewer_id
+		INNER JOIN users u ON u.id = rv.user_id
+		WHERE e.reference_id = $1
+		ORDER BY e.form_version DESC, e.created_at
+	`
+	err := r.DB.Select(&extractions, query, referenceId)
+	if err != nil {
+		return nil, err
+	}
+	return extractions, nil
+}
+
+// FindLatest returns the extraction of a reviewer made with the most recent
+// form version.
+func (r *ExtractionRepoSql) FindLatest(referenceId uuid.UUID, reviewerId uuid.UUID) (*model.Extraction, error) {
+	extraction := model.Extraction{}
+	query := `
+		SELECT e.*, u.name AS reviewer_name
+		FROM extractions e
+		INNER JOIN reviewers rv ON rv.id = e.reviewer_id
+		INNER JOIN users u ON u.id = rv.user_id
+		WHERE e.reference_id = $1 AND e.reviewer_id = $2
+		ORDER BY e.form_version DESC
+		LIMIT 1
+	`
+	err := r.DB.Get(&extraction, query, referenceId, reviewerId)
+	if err != nil {
+		if err.Error() == "sql: no rows in result set" {
+			return nil, NotFoundInRepo
+		}
+		return nil, err
+	}
+	return &extraction, nil
+}
+
+// SaveConsensus creates the consensus of a study for a form version, or
+// replaces it when the extractions were reconciled before.
+func (r *ExtractionRepoSql) SaveConsensus(consensus *model.ExtractionConsensus) error {
+	query := `
+		INSERT INTO extraction_consensus (id, review_id, reference_id, form_id, form_version, reviewer_id, left_id, right_id, discrepancies, data, created_at, updated_at)
+		VALUES (:id, :review_id, :reference_id, :form_id, :form_version, :reviewer_id, :left_id, :right_id, :discrepancies, :data, :created_at, :updated_at)
+		ON CONFLICT (reference_id, form_id)
+		DO UPDATE SET reviewer_id = EXCLUDED.reviewer_id, data = EXCLUDED.data, updated_at = EXCLUDED.updated_at
+	`
+	_, err := r.DB.NamedExec(query, consensus)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func (r *ExtractionRepoSql) FindConsensus(referenceId uuid.UUID, formId uuid.UUID) (*model.ExtractionConsensus, error) {
+	consensus := model.ExtractionConsensus{}
+	query := `
+		SELECT c.*, u.name AS reviewer_name
+		FROM extraction_consensus c
+		INNER JOIN reviewers rv ON rv.id = c.reviewer_id
+		INNER JOIN users u ON u.id = rv.user_id
+		WHERE c.reference_id = $1 AND c.form_id = $2
+	`
+	err := r.DB.Get(&consensus, query, referenceId, formId)
+	if err != nil {
+		if err.Error() == "sql: no rows in result set" {
+			return nil, NotFoundInRepo
+		}
+		return nil, err
+	}
+	return &consensus, nil
+}
+
+func (r *ExtractionRepoSql) FindConsensusByReviewId(reviewId uuid.UUID) ([]model.ExtractionConsensus, error) {
+	consensus := []model.ExtractionConsensus{}
+	query := `
+		SELECT c.*, u.name AS reviewer_name
+		FROM extraction_consensus c
+		INNER JOIN reviewers rv ON rv.id = c.reviewer_id
+		INNER JOIN users u ON u.id = rv.user_id
+		WHERE c.review_id = $1
+		ORDER BY c.form_version DESC
+	`
+	err := r.DB.Select(&consensus, query, reviewId)
+	if err != nil {
+		return nil, err
+	}
+	return consensus, nil
+}
+
+func (r *ExtractionRepoSql) GetDB() *sqlx.DB {
+	return r.DB
+}
@@ -0,0 +1,97 @@
+package repo
+
+import (
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"sci-review/model"
+)
+
+type GradeRepo interface {
+	Create(assessment *model.GradeAssessment) error
+	Update(assessment *model.GradeAssessment) error
+	Delete(id uuid.UUID) error
+	FindById(id uuid.UUID) (*model.GradeAssessment, error)
+	FindAllByReviewId(reviewId uuid.UUID) ([]model.GradeAssessment, error)
+	GetDB() *sqlx.DB
+}
+
+type GradeRepoSql struct {
+	DB *sqlx.DB
+}
+
+func NewGradeRepoSql(DB *sqlx.DB) *GradeRepoSql {
+	return &GradeRepoSql{DB: DB}
+}
+
+func (r *GradeRepoSql) Create(assessment *model.GradeAssessment) error {
+	query := `
+		INSERT INTO grade_assessments (id, review_id, outcome, importance, design, studies, participants,
+			comparator_risk, intervention_risk, relative_effect, risk_of_bias, inconsistency, indirectness,
+			imprecision, publication_bias, large_effect, dose_response, plausible_confounding, footnotes,
+			certainty, comments, position, created_by, created_at, updated_at)
+		VALUES (:id, :review_id, :outcome, :importance, :design, :studies, :participants,
+			:comparator_risk, :intervention_risk, :relative_effect, :risk_of_bias, :inconsistency, :indirectness,
+			:imprecision, :publication_bias, :large_effect, :dose_response, :plausible_confounding, :footnotes,
+			:certainty, :comments, :position, :created_by, :created_at, :updated_at)
+	`
+	_, err := r.DB.NamedExec(query, assessment)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func (r *GradeRepoSql) Update(assessment *model.GradeAssessment) error {
+	query := `
+		UPDATE grade_assessments SET outcome = :outcome, importance = :importance, design = :design,
+			studies = :studies, participants = :participants, comparator_risk = :comparator_risk,
+			intervention_risk = :intervention_risk, relative_effect = :relative_effect,
+			risk_of_bias = :risk_of_bias, inconsistency = :inconsistency, indirectness = :indirectness,
+			imprecision = :imprecision, publication_bias = :publication_bias, large_effect = :large_effect,
+			dose_response = :dose_response, plausible_confounding = :plausible_confounding,
+			footnotes = :footnotes, certainty = :certainty, comments = :comments, position = :position,
+			updated_at = :updated_at
+		WHERE id = :id
+	`
+	_, err := r.DB.NamedExec(query, assessment)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func (r *GradeRepoSql) Delete(id uuid.UUID) error {
+	query := `DELETE FROM grade_assessments WHERE id = $1`
+	_, err := r.DB.Exec(query, id)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func (r *GradeRepoSql) FindById(id uuid.UUID) (*model.GradeAssessment, error) {
+	assessment := model.GradeAssessment{}
+	query := `SELECT * FROM grade_assessments WHERE id = $1`
+	err := r.DB.Get(&assessment, query, id)
+	if err != nil {
+		if err.Error() == "sql: no rows in result set" {
+			return nil, NotFoundInRepo
+		}
+		return nil, err
+	}
+	return &assessment, nil
+}
+
+func (r *GradeRepoSql) FindAllByReviewId(reviewId uuid.UUID) ([]model.GradeAssessment, error) {
+	assessments := []model.GradeAssessment{}
+	query := `SELECT * FROM grade_assessments WHERE review_id = $1 ORDER BY position, created_at`
+	err := r.DB.Select(&assessments, query, reviewId)
+	if err != nil {
+		return nil, err
+	}
+	return assessments, nil
+}
+
+func (r *GradeRepoSql) GetDB() *sqlx.DB {
+	return r.DB
+}
@@ -11,8 +11,29 @@ type InvestigationRepo interface {
 	Create(model *model.Investigation) error
 	FindAll(reviewID uuid.UUID) ([]model.Investigation, error)
 	FindOne(investigationId uuid.UUID) (*model.Investigation, error)
-	SaveKeyword(investigationKeyword *model.InvestigationKeyword) error
+	UpdateQuestion(investigation *model.Investigation) error
+	UpdateStatus(investigation *model.Investigation, tx *sqlx.Tx) error
+	CreateStatusChange(change *model.InvestigationStatusChange, tx *sqlx.Tx) error
+	FindStatusChanges(investigationId uuid.UUID) ([]model.InvestigationStatusChange, error)
+	SaveKeyword(investigationKeyword *model.InvestigationKeyword, tx *sqlx.Tx) error
 	GetKeywordsByInvestigationId(investigationId uuid.UUID) ([]model.InvestigationKeyword, error)
+	FindKeyword(investigationId uuid.UUID, keywordId uuid.UUID) (*model.InvestigationKeyword, error)
+	UpdateKeyword(investigationKeyword *model.InvestigationKeyword, tx *sqlx.Tx) error
+	DeleteKeyword(investigationKeyword *model.InvestigationKeyword, tx *sqlx.Tx) error
+	UpdateKeywordPositions(keywords []model.InvestigationKeyword, tx *sqlx.Tx) error
+	CreateKeywordChange(change *model.InvestigationKeywordChange, tx *sqlx.Tx) error
+	FindKeywordChanges(investigationId uuid.UUID) ([]model.InvestigationKeywordChange, error)
+	CreatePilotSearch(pilotSearch *model.PilotSearch) error
+	FindPilotSearches(investigationId uuid.UUID) ([]model.PilotSearch, error)
+	DeletePilotSearch(investigationId uuid.UUID, pilotSearchId uuid.UUID) error
+	CreateProtocol(protocol *model.RegisteredProtocol) error
+	FindProtocols(investigationId uuid.UUID) ([]model.RegisteredProtocol, error)
+	DeleteProtocol(investigationId uuid.UUID, protocolId uuid.UUID) error
+	CreatePublishedReview(review *model.PublishedReview) error
+	FindPublishedReviews(investigationId uuid.UUID) ([]model.PublishedReview, error)
+	DeletePublishedReview(investigationId uuid.UUID, publishedReviewId uuid.UUID) error
+	Invalidate(investigation *model.Investigation)
+	GetDB() *sqlx.DB
 }
 
 type InvestigationRepoSql struct {
@@ -25,8 +46,8 @@ func NewInvestigationRepoSql(DB *sqlx.DB) *InvestigationRepoSql {
 
 func (pr *InvestigationRepoSql) Create(model *model.Investigation) error {
 	query := `
-		INSERT INTO investigations (id, user_id, review_id, question, status, created_at, updated_at)
-		VALUES (:id, :user_id, :review_id, :question, :status, :created_at, :updated_at)
+		INSERT INTO investigations (id, user_id, review_id, question, framework, elements, status, created_at, updated_at)
+		VALUES (:id, :user_id, :review_id, :question, :framework, :elements, :status, :created_at, :updated_at)
 	`
 	_, err := pr.DB.NamedExec(query, model)
 	if err != nil {
@@ -59,12 +80,68 @@ func (pr *InvestigationRepoSql) FindOne(investigationId uuid.UUID) (*model.Inves
 	return &investigation, nil
 }
 
-func (pr *InvestigationRepoSql) SaveKeyword(investigationKeyword *model.InvestigationKeyword) error {
+func (pr *InvestigationRepoSql) UpdateQuestion(investigation *model.Investigation) error {
 	query := `
-		INSERT INTO investigation_keywords (id, user_id, investigation_id, word, synonyms, created_at, updated_at)
-		VALUES (:id, :user_id, :investigation_id, :word, :synonyms, :created_at, :updated_at)
+		UPDATE investigations
+		SET question = :question, framework = :framework, elements = :elements, updated_at = :updated_at
+		WHERE id = :id
 	`
-	_, err := pr.DB.NamedExec(query, investigationKeyword)
+	_, err := pr.DB.NamedExec(query, investigation)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func (pr *InvestigationRepoSql) UpdateStatus(investigation *model.Investigation, tx *sqlx.Tx) error {
+	query := `
+		UPDATE investigations
+		SET status = :status, conclusion = :conclusion, updated_at = :updated_at
+		WHERE id = :id
+	`
+	_, err := tx.NamedExec(query, investigation)
+	return err
+}
+
+func (pr *InvestigationRepoSql) CreateStatusChange(change *model.InvestigationStatusChange, tx *sqlx.Tx) error {
+	query := `
+		INSERT INTO investigation_status_changes (id, investigation_id, user_id, from_status, to_status, conclusion, created_at)
+		VALUES (:id, :investigation_id, :user_id, :from_status, :to_status, :conclusion, :created_at)
+	`
+	_, err := tx.NamedExec(query, change)
+	return err
+}
+
+// FindStatusChanges returns the status history of the investigation, most
+// recent first, with the names of the users who changed it.
+func (pr *InvestigationRepoSql) FindStatusChanges(investigationId uuid.UUID) ([]model.InvestigationStatusChange, error) {
+	changes := []model.InvestigationStatusChange{}
+	query := `
+		SELECT c.*, u.name AS user_name
+		FROM investigation_status_changes c
+		INNER JOIN users u ON u.id = c.user_id
+		WHERE c.investigation_id = $1
+		ORDER BY c.created_at DESC
+	`
+	err := pr.DB.Select(&changes, query, investigationId)
+	if err != nil {
+		return nil, err
+	}
+	return changes, nil
+}
+
+// SaveKeyword inserts the keyword after the other keywords of the
+// investigation.
+func (pr *InvestigationRepoSql) SaveKeyword(investigationKeyword *model.InvestigationKeyword, tx *sqlx.Tx) error {
+	query := `
+		INSERT INTO investigation_keywords (id, user_id, investigation_id, word, synonyms, concept, position, created_at, updated_at)
+		VALUES (
+			:id, :user_id, :investigation_id, :word, :synonyms, :concept,
+			(SELECT COALESCE(MAX(position), 0) + 1 FROM investigation_keywords WHERE investigation_id = :investigation_id),
+			:created_at, :updated_at
+		)
+	`
+	_, err := tx.NamedExec(query, investigationKeyword)
 	if err != nil {
 		return err
 	}
@@ -73,7 +150,7 @@ func (pr *InvestigationRepoSql) SaveKeyword(investigationKeyword *model.Investig
 
 func (pr *InvestigationRepoSql) GetKeywordsByInvestigationId(investigationId uuid.UUID) ([]model.InvestigationKeyword, error) {
 	var keywords []model.InvestigationKeyword
-	query := `SELECT * FROM investigation_keywords WHERE investigation_id = $1`
+	query := `SELECT * FROM investigation_keywords WHERE investigation_id = $1 ORDER BY position, created_at`
 	err := pr.DB.Select(&keywords, query, investigationId)
 	if err != nil {
 		slog.Error("error", "error", err)
@@ -81,3 +158,182 @@ func (pr *InvestigationRepoSql) GetKeywordsByInvestigationId(investigationId uui
 	}
 	return keywords, nil
 }
+
+func (pr *InvestigationRepoSql) FindKeyword(investigationId uuid.UUID, keywordId uuid.UUID) (*model.InvestigationKeyword, error) {
+	keyword := model.InvestigationKeyword{}
+	query := `SELECT * FROM investigation_keywords WHERE id = $1 AND investigation_id = $2`
+	err := pr.DB.Get(&keyword, query, keywordId, investigationId)
+	if err != nil {
+		if err.Error() == "sql: no rows in result set" {
+			return nil, NotFoundInRepo
+		}
+		return nil, err
+	}
+	return &keyword, nil
+}
+
+func (pr *InvestigationRepoSql) UpdateKeyword(investigationKeyword *model.InvestigationKeyword, tx *sqlx.Tx) error {
+	query := `
+		UPDATE investigation_keywords
+		SET word = :word, synonyms = :synonyms, concept = :concept, updated_at = :updated_at
+		WHERE id = :id
+	`
+	_, err := tx.NamedExec(query, investigationKeyword)
+	return err
+}
+
+func (pr *InvestigationRepoSql) DeleteKeyword(investigationKeyword *model.InvestigationKeyword, tx *sqlx.Tx) error {
+	_, err := tx.Exec(`DELETE FROM investigation_keywords WHERE id = $1`, investigationKeyword.Id)
+	return err
+}
+
+func (pr *InvestigationRepoSql) UpdateKeywordPositions(keywords []model.InvestigationKeyword, tx *sqlx.Tx) error {
+	for _, keyword := range keywords {
+		_, err := tx.Exec(`UPDATE investigation_keywords SET position = $1 WHERE id = $2`, keyword.Position, keyword.Id)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (pr *InvestigationRepoSql) CreateKeywordChange(change *model.InvestigationKeywordChange, tx *sqlx.Tx) error {
+	query := `
+		INSERT INTO investigation_keyword_changes (id, investigation_id, keyword_id, user_id, action, word, detail, created_at)
+		VALUES (:id, :investigation_id, :keyword_id, :user_id, :action, :word, :detail, :created_at)
+	`
+	_, err := tx.NamedExec(query, change)
+	return err
+}
+
+// FindKeywordChanges returns the history of the keywords of the
+// investigation, most recent first, with the names of the users who changed
+// them.
+func (pr *InvestigationRepoSql) FindKeywordChanges(investigationId uuid.UUID) ([]model.InvestigationKeywordChange, error) {
+	changes := []model.InvestigationKeywordChange{}
+	query := `
+		SELECT c.*, u.name AS user_name
+		FROM investigation_keyword_changes c
+		INNER JOIN users u ON u.id = c.user_id
+		WHERE c.investigation_id = $1
+		ORDER BY c.created_at DESC
+	`
+	err := pr.DB.Select(&changes, query, investigationId)
+	if err != nil {
+		return nil, err
+	}
+	return changes, nil
+}
+
+func (pr *InvestigationRepoSql) CreatePilotSearch(pilotSearch *model.PilotSearch) error {
+	query := `
+		INSERT INTO pilot_searches (id, investigation_id, user_id, source_database, query, run_on, hits, notes, keywords, created_at)
+		VALUES (:id, :investigation_id, :user_id, :source_database, :query, :run_on, :hits, :notes, :keywords, :created_at)
+	`
+	_, err := pr.DB.NamedExec(query, pilotSearch)
+	return err
+}
+
+// FindPilotSearches returns the pilot searches of the investigation in the
+// order they were run, with the names of the users who logged them.
+func (pr *InvestigationRepoSql) FindPilotSearches(investigationId uuid.UUID) ([]model.PilotSearch, error) {
+	searches := []model.PilotSearch{}
+	query := `
+		SELECT s.*, u.name AS user_name
+		FROM pilot_searches s
+		INNER JOIN users u ON u.id = s.user_id
+		WHERE s.investigation_id = $1
+		ORDER BY s.run_on, s.created_at
+	`
+	err := pr.DB.Select(&searches, query, investigationId)
+	if err != nil {
+		return nil, err
+	}
+	return searches, nil
+}
+
+func (pr *InvestigationRepoSql) DeletePilotSearch(investigationId uuid.UUID, pilotSearchId uuid.UUID) error {
+	_, err := pr.DB.Exec(`DELETE FROM pilot_searches WHERE id = $1 AND investigation_id = $2`, pilotSearchId, investigationId)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func (pr *InvestigationRepoSql) CreateProtocol(protocol *model.RegisteredProtocol) error {
+	query := `
+		INSERT INTO registered_protocols (id, investigation_id, user_id, registry, registration_id, title, status, url, notes, created_at)
+		VALUES (:id, :investigation_id, :user_id, :registry, :registration_id, :title, :status, :url, :notes, :created_at)
+	`
+	_, err := pr.DB.NamedExec(query, protocol)
+	return err
+}
+
+// FindProtocols returns the protocols found for the investigation in the
+// order they were logged, with the names of the users who logged them.
+func (pr *InvestigationRepoSql) FindProtocols(investigationId uuid.UUID) ([]model.RegisteredProtocol, error) {
+	protocols := []model.RegisteredProtocol{}
+	query := `
+		SELECT p.*, u.name AS user_name
+		FROM registered_protocols p
+		INNER JOIN users u ON u.id = p.user_id
+		WHERE p.investigation_id = $1
+		ORDER BY p.created_at
+	`
+	err := pr.DB.Select(&protocols, query, investigationId)
+	if err != nil {
+		return nil, err
+	}
+	return protocols, nil
+}
+
+func (pr *InvestigationRepoSql) DeleteProtocol(investigationId uuid.UUID, protocolId uuid.UUID) error {
+	_, err := pr.DB.Exec(`DELETE FROM registered_protocols WHERE id = $1 AND investigation_id = $2`, protocolId, investigationId)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func (pr *InvestigationRepoSql) CreatePublishedReview(review *model.PublishedReview) error {
+	query := `
+		INSERT INTO published_reviews (id, investigation_id, user_id, citation, doi, year, overlap, notes, created_at)
+		VALUES (:id, :investigation_id, :user_id, :citation, :doi, :year, :overlap, :notes, :created_at)
+	`
+	_, err := pr.DB.NamedExec(query, review)
+	return err
+}
+
+// FindPublishedReviews returns the reviews found for the investigation,
+// most recently published first.
+func (pr *InvestigationRepoSql) FindPublishedReviews(investigationId uuid.UUID) ([]model.PublishedReview, error) {
+	reviews := []model.PublishedReview{}
+	query := `
+		SELECT r.*, u.name AS user_name
+		FROM published_reviews r
+		INNER JOIN users u ON u.id = r.user_id
+		WHERE r.investigation_id = $1
+		ORDER BY r.year DESC, r.created_at
+	`
+	err := pr.DB.Select(&reviews, query, investigationId)
+	if err != nil {
+		return nil, err
+	}
+	return reviews, nil
+}
+
+func (pr *InvestigationRepoSql) DeletePublishedReview(investigationId uuid.UUID, publishedReviewId uuid.UUID) error {
+	_, err := pr.DB.Exec(`DELETE FROM published_reviews WHERE id = $1 AND investigation_id = $2`, publishedReviewId, investigationId)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// Invalidate is called once a transaction that changed the investigation has
+// committed. The database is always current, so there is nothing to do.
+func (pr *InvestigationRepoSql) Invalidate(investigation *model.Investigation) {}
+
+func (pr *InvestigationRepoSql) GetDB() *sqlx.DB {
+	return pr.DB
+}
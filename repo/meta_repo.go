@@ -0,0 +1,83 @@
+package repo
+
+import (
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"sci-review/model"
+)
+
+type MetaRepo interface {
+	Save(data *model.OutcomeData) error
+	Delete(id uuid.UUID) error
+	FindById(id uuid.UUID) (*model.OutcomeData, error)
+	FindAllByReviewId(reviewId uuid.UUID) ([]model.OutcomeData, error)
+	GetDB() *sqlx.DB
+}
+
+type MetaRepoSql struct {
+	DB *sqlx.DB
+}
+
+func NewMetaRepoSql(DB *sqlx.DB) *MetaRepoSql {
+	return &MetaRepoSql{DB: DB}
+}
+
+// Save creates the data of a study for an outcome, or replaces it when the
+// study has data for the outcome already.
+func (r *MetaRepoSql) Save(data *model.OutcomeData) error {
+	query := `
+		INSERT INTO outcome_data (id, review_id, reference_id, outcome, data_type, events_t, total_t, events_c, total_c,
+			mean_t, sd_t, n_t, mean_c, sd_c, n_c, measure, effect, se, median_method, inputs, conversion,
+			created_by, created_at, updated_at)
+		VALUES (:id, :review_id, :reference_id, :outcome, :data_type, :events_t, :total_t, :events_c, :total_c,
+			:mean_t, :sd_t, :n_t, :mean_c, :sd_c, :n_c, :measure, :effect, :se, :median_method, :inputs, :conversion,
+			:created_by, :created_at, :updated_at)
+		ON CONFLICT (reference_id, outcome)
+		DO UPDATE SET data_type = EXCLUDED.data_type, events_t = EXCLUDED.events_t, total_t = EXCLUDED.total_t,
+			events_c = EXCLUDED.events_c, total_c = EXCLUDED.total_c, mean_t = EXCLUDED.mean_t, sd_t = EXCLUDED.sd_t,
+			n_t = EXCLUDED.n_t, mean_c = EXCLUDED.mean_c, sd_c = EXCLUDED.sd_c, n_c = EXCLUDED.n_c,
+			measure = EXCLUDED.measure, effect = EXCLUDED.effect, se = EXCLUDED.se, median_method = EXCLUDED.median_method,
+			inputs = EXCLUDED.inputs, conversion = EXCLUDED.conversion, updated_at = EXCLUDED.updated_at
+	`
+	_, err := r.DB.NamedExec(query, data)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func (r *MetaRepoSql) Delete(id uuid.UUID) error {
+	query := `DELETE FROM outcome_data WHERE id = $1`
+	_, err := r.DB.Exec(query, id)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func (r *MetaRepoSql) FindById(id uuid.UUID) (*model.OutcomeData, error) {
+	data := model.OutcomeData{}
+	query := `SELECT * FROM outcome_data WHERE id = $1`
+	err := r.DB.Get(&data, query, id)
+	if err != nil {
+		if err.Error() == "sql: no rows in result set" {
+			return nil, NotFoundInRepo
+		}
+		return nil, err
+	}
+	return &data, nil
+}
+
+func (r *MetaRepoSql) FindAllByReviewId(reviewId uuid.UUID) ([]model.OutcomeData, error) {
+	data := []model.OutcomeData{}
+	query := `SELECT * FROM outcome_data WHERE review_id = $1 ORDER BY outcome, created_at`
+	err := r.DB.Select(&data, query, reviewId)
+	if err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+func (r *MetaRepoSql) GetDB() *sqlx.DB {
+	return r.DB
+}
@@ -0,0 +1,186 @@
+package repo
+
+import (
+	"fmt"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"sci-review/model"
+	"time"
+)
+
+type ReferenceRepo interface {
+	CreateBatch(references []model.Reference, tx *sqlx.Tx) error
+	FindAllByFilter(reviewId uuid.UUID, filter model.ReferenceFilter, limit int, offset int) ([]model.Reference, error)
+	FindById(id uuid.UUID) (*model.Reference, error)
+	CountByFilter(reviewId uuid.UUID, filter model.ReferenceFilter) (int, error)
+	FindUniqueByReviewId(reviewId uuid.UUID) ([]model.Reference, error)
+	MarkDuplicate(id uuid.UUID, primaryId uuid.UUID, tx *sqlx.Tx) error
+	UpdateSourceDatabases(id uuid.UUID, sourceDatabases model.Strings, tx *sqlx.Tx) error
+	CountDuplicates(reviewId uuid.UUID) (int, error)
+	CountBySource(reviewId uuid.UUID) ([]model.SourceCount, error)
+	UpdateNotRetrieved(id uuid.UUID, notRetrieved bool) error
+	GetDB() *sqlx.DB
+}
+
+type ReferenceRepoSql struct {
+	DB *sqlx.DB
+}
+
+func NewReferenceRepoSql(DB *sqlx.DB) *ReferenceRepoSql {
+	return &ReferenceRepoSql{DB: DB}
+}
+
+func (r *ReferenceRepoSql) CreateBatch(references []model.Reference, tx *sqlx.Tx) error {
+	if len(references) == 0 {
+		return nil
+	}
+	query := `
+		INSERT INTO review_references (id, review_id, user_id, title, abstract, authors, year, journal, doi, keywords,
+			pmid, mesh_terms, publication_types, source_database, source_databases, entry_type, citation_key,
+			raw_metadata, created_at, updated_at)
+		VALUES (:id, :review_id, :user_id, :title, :abstract, :authors, :year, :journal, :doi, :keywords,
+			:pmid, :mesh_terms, :publication_types, :source_database, :source_databases, :entry_type, :citation_key,
+			:raw_metadata, :created_at, :updated_at)
+	`
+	_, err := tx.NamedExec(query, references)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// referenceFilterWhere builds the WHERE clause shared by the listing, count
+// and export queries.
+func referenceFilterWhere(reviewId uuid.UUID, filter model.ReferenceFilter) (string, []interface{}) {
+	where := "review_id = $1"
+	args := []interface{}{reviewId}
+	if filter.Query != "" {
+		args = append(args, "%"+filter.Query+"%")
+		where += fmt.Sprintf(" AND (title ILIKE $%d OR abstract ILIKE $%d)", len(args), len(args))
+	}
+	if filter.SourceDatabase != "" {
+		args = append(args, filter.SourceDatabase)
+		where += fmt.Sprintf(" AND source_database = $%d", len(args))
+	}
+	if filter.YearFrom != 0 {
+		args = append(args, filter.YearFrom)
+		where += fmt.Sprintf(" AND year >= $%d", len(args))
+	}
+	if filter.YearTo != 0 {
+		args = append(args, filter.YearTo)
+		where += fmt.Sprintf(" AND year <= $%d", len(args))
+	}
+	return where, args
+}
+
+func (r *ReferenceRepoSql) FindAllByFilter(reviewId uuid.UUID, filter model.ReferenceFilter, limit int, offset int) ([]model.Reference, error) {
+	references := []model.Reference{}
+	where, args := referenceFilterWhere(reviewId, filter)
+	query := "SELECT * FROM review_references WHERE " + where + " ORDER BY created_at, title"
+	if limit > 0 {
+		args = append(args, limit, offset)
+		query += fmt.Sprintf(" LIMIT $%d OFFSET $%d", len(args)-1, len(args))
+	}
+	err := r.DB.Select(&references, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	return references, nil
+}
+
+func (r *ReferenceRepoSql) FindById(id uuid.UUID) (*model.Reference, error) {
+	reference := model.Reference{}
+	query := `SELECT * FROM review_references WHERE id = $1`
+	err := r.DB.Get(&reference, query, id)
+	if err != nil {
+		if err.Error() == "sql: no rows in result set" {
+			return nil, NotFoundInRepo
+		}
+		return nil, err
+	}
+	return &reference, nil
+}
+
+func (r *ReferenceRepoSql) CountByFilter(reviewId uuid.UUID, filter model.ReferenceFilter) (int, error) {
+	var count int
+	where, args := referenceFilterWhere(reviewId, filter)
+	query := "SELECT COUNT(*) FROM review_references WHERE " + where
+	err := r.DB.Get(&count, query, args...)
+	if err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+// FindUniqueByReviewId returns the references that have not been merged into
+// another one.
+func (r *ReferenceRepoSql) FindUniqueByReviewId(reviewId uuid.UUID) ([]model.Reference, error) {
+	references := []model.Reference{}
+	query := `
+		SELECT * FROM review_references WHERE review_id = $1 AND duplicate_of IS NULL ORDER BY created_at, title
+	`
+	err := r.DB.Select(&references, query, reviewId)
+	if err != nil {
+		return nil, err
+	}
+	return references, nil
+}
+
+func (r *ReferenceRepoSql) MarkDuplicate(id uuid.UUID, primaryId uuid.UUID, tx *sqlx.Tx) error {
+	query := `UPDATE review_references SET duplicate_of = $2, updated_at = $3 WHERE id = $1`
+	_, err := tx.Exec(query, id, primaryId, time.Now())
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func (r *ReferenceRepoSql) UpdateSourceDatabases(id uuid.UUID, sourceDatabases model.Strings, tx *sqlx.Tx) error {
+	query := `UPDATE review_references SET source_databases = $2, updated_at = $3 WHERE id = $1`
+	_, err := tx.Exec(query, id, sourceDatabases, time.Now())
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func (r *ReferenceRepoSql) CountDuplicates(reviewId uuid.UUID) (int, error) {
+	var count int
+	query := `SELECT COUNT(*) FROM review_references WHERE review_id = $1 AND duplicate_of IS NOT NULL`
+	err := r.DB.Get(&count, query, reviewId)
+	if err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+// CountBySource counts every imported record, duplicates included, by the
+// database it was imported from.
+func (r *ReferenceRepoSql) CountBySource(reviewId uuid.UUID) ([]model.SourceCount, error) {
+	counts := []model.SourceCount{}
+	query := `
+		SELECT source_database, COUNT(*) AS records
+		FROM review_references
+		WHERE review_id = $1
+		GROUP BY source_database
+		ORDER BY records DESC, source_database
+	`
+	err := r.DB.Select(&counts, query, reviewId)
+	if err != nil {
+		return nil, err
+	}
+	return counts, nil
+}
+
+func (r *ReferenceRepoSql) UpdateNotRetrieved(id uuid.UUID, notRetrieved bool) error {
+	query := `UPDATE review_references SET not_retrieved = $2, updated_at = $3 WHERE id = $1`
+	_, err := r.DB.Exec(query, id, notRetrieved, time.Now())
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func (r *ReferenceRepoSql) GetDB() *sqlx.DB {
+	return r.DB
+}
@@ -11,6 +11,9 @@ type ReviewRepo interface {
 	AddReviewer(reviewer *model.Reviewer, tx *sqlx.Tx) error
 	FindAllByUserId(userId uuid.UUID) (*[]model.Review, error)
 	FindById(id uuid.UUID) (*model.Review, error)
+	FindReviewers(reviewId uuid.UUID) ([]model.Reviewer, error)
+	UpdateReviewerRole(reviewer *model.Reviewer, tx *sqlx.Tx) error
+	InvalidateReviewers(reviewId uuid.UUID)
 	GetDB() *sqlx.DB
 }
 
@@ -80,6 +83,31 @@ func (r *ReviewRepoSql) FindById(id uuid.UUID) (*model.Review, error) {
 	return &review, nil
 }
 
+func (r *ReviewRepoSql) FindReviewers(reviewId uuid.UUID) ([]model.Reviewer, error) {
+	reviewers := []model.Reviewer{}
+	query := `SELECT * FROM reviewers WHERE review_id = $1 ORDER BY created_at`
+	err := r.DB.Select(&reviewers, query, reviewId)
+	if err != nil {
+		return nil, err
+	}
+
+	return reviewers, nil
+}
+
+func (r *ReviewRepoSql) UpdateReviewerRole(reviewer *model.Reviewer, tx *sqlx.Tx) error {
+	query := `UPDATE reviewers SET role = :role, updated_at = :updated_at WHERE id = :id`
+	_, err := tx.NamedExec(query, reviewer)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// InvalidateReviewers is called once a transaction that changed the reviewers
+// has committed. The database is always current, so there is nothing to do.
+func (r *ReviewRepoSql) InvalidateReviewers(reviewId uuid.UUID) {}
+
 func (r *ReviewRepoSql) GetDB() *sqlx.DB {
 	return r.DB
 }
@@ -0,0 +1,81 @@
+package repo
+
+import (
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"sci-review/model"
+)
+
+type RobRepo interface {
+	Save(assessment *model.RobAssessment) error
+	Find(referenceId uuid.UUID, reviewerId uuid.UUID, tool string) (*model.RobAssessment, error)
+	FindAllByReviewId(reviewId uuid.UUID, tool string) ([]model.RobAssessment, error)
+	GetDB() *sqlx.DB
+}
+
+type RobRepoSql struct {
+	DB *sqlx.DB
+}
+
+func NewRobRepoSql(DB *sqlx.DB) *RobRepoSql {
+	return &RobRepoSql{DB: DB}
+}
+
+// Save creates the assessment of a reviewer with a tool, or replaces its
+// answers and judgements when there is one already.
+func (r *RobRepoSql) Save(assessment *model.RobAssessment) error {
+	query := `
+		INSERT INTO rob_assessments (id, review_id, reference_id, reviewer_id, tool, answers, judgements, overall, weight, note, created_at, updated_at)
+		VALUES (:id, :review_id, :reference_id, :reviewer_id, :tool, :answers, :judgements, :overall, :weight, :note, :created_at, :updated_at)
+		ON CONFLICT (reference_id, reviewer_id, tool)
+		DO UPDATE SET answers = EXCLUDED.answers, judgements = EXCLUDED.judgements, overall = EXCLUDED.overall,
+			weight = EXCLUDED.weight, note = EXCLUDED.note, updated_at = EXCLUDED.updated_at
+	`
+	_, err := r.DB.NamedExec(query, assessment)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func (r *RobRepoSql) Find(referenceId uuid.UUID, reviewerId uuid.UUID, tool string) (*model.RobAssessment, error) {
+	assessment := model.RobAssessment{}
+	query := `
+		SELECT a.*, u.name AS reviewer_name
+		FROM rob_assessments a
+		INNER JOIN reviewers rv ON rv.id = a.reviewer_id
+		INNER JOIN users u ON u.id = rv.user_id
+		WHERE a.reference_id = $1 AND a.reviewer_id = $2 AND a.tool = $3
+	`
+	err := r.DB.Get(&assessment, query, referenceId, reviewerId, tool)
+	if err != nil {
+		if err.Error() == "sql: no rows in result set" {
+			return nil, NotFoundInRepo
+		}
+		return nil, err
+	}
+	return &assessment, nil
+}
+
+// FindAllByReviewId returns the assessments of the review made with a tool,
+// the most recently updated first.
+func (r *RobRepoSql) FindAllByReviewId(reviewId uuid.UUID, tool string) ([]model.RobAssessment, error) {
+	assessments := []model.RobAssessment{}
+	query := `
+		SELECT a.*, u.name AS reviewer_name
+		FROM rob_assessments a
+		INNER JOIN reviewers rv ON rv.id = a.reviewer_id
+		INNER JOIN users u ON u.id = rv.user_id
+		WHERE a.review_id = $1 AND a.tool = $2
+		ORDER BY a.updated_at DESC
+	`
+	err := r.DB.Select(&assessments, query, reviewId, tool)
+	if err != nil {
+		return nil, err
+	}
+	return assessments, nil
+}
+
+func (r *RobRepoSql) GetDB() *sqlx.DB {
+	return r.DB
+}
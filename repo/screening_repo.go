@@ -0,0 +1,248 @@
+package repo
+
+import (
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"sci-review/model"
+)
+
+type ScreeningRepo interface {
+	SaveDecision(decision *model.ScreeningDecision, tx *sqlx.Tx) error
+	FindDecision(referenceId uuid.UUID, reviewerId uuid.UUID, stage model.ScreeningStage) (*model.ScreeningDecision, error)
+	FindDecisions(reviewId uuid.UUID, stage model.ScreeningStage) ([]model.ScreeningDecision, error)
+	FindDecisionsByReference(referenceId uuid.UUID, stage model.ScreeningStage) ([]model.ScreeningDecision, error)
+	FindNextUnscreened(reviewId uuid.UUID, reviewerId uuid.UUID, stage model.ScreeningStage, maxScreeners int, eligible []uuid.UUID) (*model.Reference, error)
+	SaveAdjudication(adjudication *model.ScreeningAdjudication, tx *sqlx.Tx) error
+	FindAdjudication(referenceId uuid.UUID, stage model.ScreeningStage) (*model.ScreeningAdjudication, error)
+	FindAdjudications(reviewId uuid.UUID, stage model.ScreeningStage) ([]model.ScreeningAdjudication, error)
+	AddEvent(event *model.ScreeningEvent, tx *sqlx.Tx) error
+	FindHistory(referenceId uuid.UUID) ([]model.ScreeningEvent, error)
+	CountScreenable(reviewId uuid.UUID, eligible []uuid.UUID) (int, error)
+	Progress(reviewId uuid.UUID, stage model.ScreeningStage) ([]model.ReviewerProgress, error)
+	GetDB() *sqlx.DB
+}
+
+type ScreeningRepoSql struct {
+	DB *sqlx.DB
+}
+
+func NewScreeningRepoSql(DB *sqlx.DB) *ScreeningRepoSql {
+	return &ScreeningRepoSql{DB: DB}
+}
+
+// SaveDecision inserts the reviewer's decision or replaces the one already
+// recorded for the same record and stage.
+func (sr *ScreeningRepoSql) SaveDecision(decision *model.ScreeningDecision, tx *sqlx.Tx) error {
+	query := `
+		INSERT INTO screening_decisions (id, review_id, reference_id, reviewer_id, stage, decision, exclusion_reason_id, note, created_at, updated_at)
+		VALUES (:id, :review_id, :reference_id, :reviewer_id, :stage, :decision, :exclusion_reason_id, :note, :created_at, :updated_at)
+		ON CONFLICT (reference_id, reviewer_id, stage)
+		DO UPDATE SET decision = EXCLUDED.decision, exclusion_reason_id = EXCLUDED.exclusion_reason_id, note = EXCLUDED.note, updated_at = EXCLUDED.updated_at
+	`
+	_, err := tx.NamedExec(query, decision)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func (sr *ScreeningRepoSql) FindDecision(referenceId uuid.UUID, reviewerId uuid.UUID, stage model.ScreeningStage) (*model.ScreeningDecision, error) {
+	decision := model.ScreeningDecision{}
+	query := `SELECT * FROM screening_decisions WHERE reference_id = $1 AND reviewer_id = $2 AND stage = $3`
+	err := sr.DB.Get(&decision, query, referenceId, reviewerId, stage)
+	if err != nil {
+		if err.Error() == "sql: no rows in result set" {
+			return nil, NotFoundInRepo
+		}
+		return nil, err
+	}
+	return &decision, nil
+}
+
+func (sr *ScreeningRepoSql) FindDecisions(reviewId uuid.UUID, stage model.ScreeningStage) ([]model.ScreeningDecision, error) {
+	decisions := []model.ScreeningDecision{}
+	query := `SELECT * FROM screening_decisions WHERE review_id = $1 AND stage = $2 ORDER BY created_at`
+	err := sr.DB.Select(&decisions, query, reviewId, stage)
+	if err != nil {
+		return nil, err
+	}
+	return decisions, nil
+}
+
+func (sr *ScreeningRepoSql) FindDecisionsByReference(referenceId uuid.UUID, stage model.ScreeningStage) ([]model.ScreeningDecision, error) {
+	decisions := []model.ScreeningDecision{}
+	query := `
+		SELECT d.*, u.name AS reviewer_name, COALESCE(er.label, '') AS exclusion_reason
+		FROM screening_decisions d
+		INNER JOIN reviewers rv ON rv.id = d.reviewer_id
+		INNER JOIN users u ON u.id = rv.user_id
+		LEFT JOIN exclusion_reasons er ON er.id = d.exclusion_reason_id
+		WHERE d.reference_id = $1 AND d.stage = $2
+		ORDER BY d.created_at
+	`
+	err := sr.DB.Select(&decisions, query, referenceId, stage)
+	if err != nil {
+		return nil, err
+	}
+	return decisions, nil
+}
+
+// eligibleIds turns the records a stage is limited to into a query
+// argument. A nil list stands for every record of the review.
+func eligibleIds(eligible []uuid.UUID) []string {
+	if eligible == nil {
+		return nil
+	}
+	ids := make([]string, len(eligible))
+	for i, id := range eligible {
+		ids[i] = id.String()
+	}
+	return ids
+}
+
+// FindNextUnscreened returns the oldest eligible record the reviewer has not
+// decided on yet. When maxScreeners is positive, records that already have
+// that many decisions are left out.
+func (sr *ScreeningRepoSql) FindNextUnscreened(reviewId uuid.UUID, reviewerId uuid.UUID, stage model.ScreeningStage, maxScreeners int, eligible []uuid.UUID) (*model.Reference, error) {
+	reference := model.Reference{}
+	query := `
+		SELECT r.* FROM review_references r
+		WHERE r.review_id = $1 AND r.duplicate_of IS NULL
+		AND NOT EXISTS (
+			SELECT 1 FROM screening_decisions d
+			WHERE d.reference_id = r.id AND d.reviewer_id = $2 AND d.stage = $3
+		)
+		AND ($4 = 0 OR (
+			SELECT COUNT(*) FROM screening_decisions d
+			WHERE d.reference_id = r.id AND d.stage = $3
+		) < $4)
+		AND ($5::uuid[] IS NULL OR r.id = ANY($5::uuid[]))
+		ORDER BY r.created_at, r.id
+		LIMIT 1
+	`
+	err := sr.DB.Get(&reference, query, reviewId, reviewerId, stage, maxScreeners, eligibleIds(eligible))
+	if err != nil {
+		if err.Error() == "sql: no rows in result set" {
+			return nil, NotFoundInRepo
+		}
+		return nil, err
+	}
+	return &reference, nil
+}
+
+// SaveAdjudication inserts the adjudicator's decision or replaces the one
+// already recorded for the same record and stage.
+func (sr *ScreeningRepoSql) SaveAdjudication(adjudication *model.ScreeningAdjudication, tx *sqlx.Tx) error {
+	query := `
+		INSERT INTO screening_adjudications (id, review_id, reference_id, adjudicator_id, stage, decision, exclusion_reason_id, note, created_at, updated_at)
+		VALUES (:id, :review_id, :reference_id, :adjudicator_id, :stage, :decision, :exclusion_reason_id, :note, :created_at, :updated_at)
+		ON CONFLICT (reference_id, stage)
+		DO UPDATE SET adjudicator_id = EXCLUDED.adjudicator_id, decision = EXCLUDED.decision, exclusion_reason_id = EXCLUDED.exclusion_reason_id, note = EXCLUDED.note, updated_at = EXCLUDED.updated_at
+	`
+	_, err := tx.NamedExec(query, adjudication)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func (sr *ScreeningRepoSql) FindAdjudication(referenceId uuid.UUID, stage model.ScreeningStage) (*model.ScreeningAdjudication, error) {
+	adjudication := model.ScreeningAdjudication{}
+	query := `
+		SELECT a.*, COALESCE(er.label, '') AS exclusion_reason
+		FROM screening_adjudications a
+		LEFT JOIN exclusion_reasons er ON er.id = a.exclusion_reason_id
+		WHERE a.reference_id = $1 AND a.stage = $2
+	`
+	err := sr.DB.Get(&adjudication, query, referenceId, stage)
+	if err != nil {
+		if err.Error() == "sql: no rows in result set" {
+			return nil, NotFoundInRepo
+		}
+		return nil, err
+	}
+	return &adjudication, nil
+}
+
+func (sr *ScreeningRepoSql) FindAdjudications(reviewId uuid.UUID, stage model.ScreeningStage) ([]model.ScreeningAdjudication, error) {
+	adjudications := []model.ScreeningAdjudication{}
+	query := `SELECT * FROM screening_adjudications WHERE review_id = $1 AND stage = $2`
+	err := sr.DB.Select(&adjudications, query, reviewId, stage)
+	if err != nil {
+		return nil, err
+	}
+	return adjudications, nil
+}
+
+func (sr *ScreeningRepoSql) AddEvent(event *model.ScreeningEvent, tx *sqlx.Tx) error {
+	query := `
+		INSERT INTO screening_history (id, review_id, reference_id, reviewer_id, stage, action, decision, exclusion_reason_id, note, created_at)
+		VALUES (:id, :review_id, :reference_id, :reviewer_id, :stage, :action, :decision, :exclusion_reason_id, :note, :created_at)
+	`
+	_, err := tx.NamedExec(query, event)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func (sr *ScreeningRepoSql) FindHistory(referenceId uuid.UUID) ([]model.ScreeningEvent, error) {
+	events := []model.ScreeningEvent{}
+	query := `
+		SELECT h.*, u.name AS reviewer_name, COALESCE(er.label, '') AS exclusion_reason
+		FROM screening_history h
+		INNER JOIN reviewers rv ON rv.id = h.reviewer_id
+		INNER JOIN users u ON u.id = rv.user_id
+		LEFT JOIN exclusion_reasons er ON er.id = h.exclusion_reason_id
+		WHERE h.reference_id = $1
+		ORDER BY h.created_at
+	`
+	err := sr.DB.Select(&events, query, referenceId)
+	if err != nil {
+		return nil, err
+	}
+	return events, nil
+}
+
+func (sr *ScreeningRepoSql) CountScreenable(reviewId uuid.UUID, eligible []uuid.UUID) (int, error) {
+	var count int
+	query := `
+		SELECT COUNT(*) FROM review_references
+		WHERE review_id = $1 AND duplicate_of IS NULL
+		AND ($2::uuid[] IS NULL OR id = ANY($2::uuid[]))
+	`
+	err := sr.DB.Get(&count, query, reviewId, eligibleIds(eligible))
+	if err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+func (sr *ScreeningRepoSql) Progress(reviewId uuid.UUID, stage model.ScreeningStage) ([]model.ReviewerProgress, error) {
+	progress := []model.ReviewerProgress{}
+	query := `
+		SELECT rv.id AS reviewer_id, u.id AS user_id, u.name,
+			COUNT(d.id) FILTER (WHERE d.decision = 'Include') AS included,
+			COUNT(d.id) FILTER (WHERE d.decision = 'Exclude') AS excluded,
+			COUNT(d.id) FILTER (WHERE d.decision = 'Maybe') AS maybe,
+			COUNT(d.id) AS screened
+		FROM reviewers rv
+		INNER JOIN users u ON u.id = rv.user_id
+		LEFT JOIN (
+			screening_decisions d
+			INNER JOIN review_references r ON r.id = d.reference_id AND r.duplicate_of IS NULL
+		) ON d.reviewer_id = rv.id AND d.stage = $2
+		WHERE rv.review_id = $1 AND rv.active = true
+		GROUP BY rv.id, u.id, u.name
+		ORDER BY u.name
+	`
+	err := sr.DB.Select(&progress, query, reviewId, stage)
+	if err != nil {
+		return nil, err
+	}
+	return progress, nil
+}
+
+func (sr *ScreeningRepoSql) GetDB() *sqlx.DB {
+	return sr.DB
+}
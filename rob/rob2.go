@@ -0,0 +1,163 @@
+package rob
+
+// RoB2 is the Cochrane risk of bias tool for randomized trials (version of
+// 22 August 2019), for individually randomized parallel-group trials and the
+// effect of assignment to intervention.
+var RoB2 = Tool{
+	Id:         "RoB2",
+	Name:       "RoB 2",
+	Design:     "Randomized trials",
+	Judgements: rob2Order,
+	Domains: []Domain{
+		{
+			Id:   "D1",
+			Name: "Bias arising from the randomization process",
+			Questions: []Question{
+				{Id: "1.1", Text: "Was the allocation sequence random?"},
+				{Id: "1.2", Text: "Was the allocation sequence concealed until participants were enrolled and assigned to interventions?"},
+				{Id: "1.3", Text: "Did baseline differences between intervention groups suggest a problem with the randomization process?"},
+			},
+			judge: rob2Randomization,
+		},
+		{
+			Id:   "D2",
+			Name: "Bias due to deviations from intended interventions",
+			Questions: []Question{
+				{Id: "2.1", Text: "Were participants aware of their assigned intervention during the trial?"},
+				{Id: "2.2", Text: "Were carers and people delivering the interventions aware of participants' assigned intervention during the trial?"},
+				{Id: "2.3", Text: "Were there deviations from the intended intervention that arose because of the trial context?", Condition: "Y/PY/NI to 2.1 or 2.2"},
+				{Id: "2.4", Text: "Were these deviations likely to have affected the outcome?", Condition: "Y/PY to 2.3"},
+				{Id: "2.5", Text: "Were these deviations from intended intervention balanced between groups?", Condition: "Y/PY/NI to 2.4"},
+				{Id: "2.6", Text: "Was an appropriate analysis used to estimate the effect of assignment to intervention?"},
+				{Id: "2.7", Text: "Was there potential for a substantial impact (on the result) of the failure to analyse participants in the group to which they were randomized?", Condition: "N/PN/NI to 2.6"},
+			},
+			judge: rob2Deviations,
+		},
+		{
+			Id:   "D3",
+			Name: "Bias due to missing outcome data",
+			Questions: []Question{
+				{Id: "3.1", Text: "Were data for this outcome available for all, or nearly all, participants randomized?"},
+				{Id: "3.2", Text: "Is there evidence that the result was not biased by missing outcome data?", Condition: "N/PN/NI to 3.1"},
+				{Id: "3.3", Text: "Could missingness in the outcome depend on its true value?", Condition: "N/PN to 3.2"},
+				{Id: "3.4", Text: "Is it likely that missingness in the outcome depended on its true value?", Condition: "Y/PY/NI to 3.3"},
+			},
+			judge: rob2MissingData,
+		},
+		{
+			Id:   "D4",
+			Name: "Bias in measurement of the outcome",
+			Questions: []Question{
+				{Id: "4.1", Text: "Was the method of measuring the outcome inappropriate?"},
+				{Id: "4.2", Text: "Could measurement or ascertainment of the outcome have differed between intervention groups?"},
+				{Id: "4.3", Text: "Were outcome assessors aware of the intervention received by study participants?", Condition: "N/PN/NI to 4.1 and 4.2"},
+				{Id: "4.4", Text: "Could assessment of the outcome have been influenced by knowledge of intervention received?", Condition: "Y/PY/NI to 4.3"},
+				{Id: "4.5", Text: "Is it likely that assessment of the outcome was influenced by knowledge of intervention received?", Condition: "Y/PY/NI to 4.4"},
+			},
+			judge: rob2Measurement,
+		},
+		{
+			Id:   "D5",
+			Name: "Bias in selection of the reported result",
+			Questions: []Question{
+				{Id: "5.1", Text: "Were the data that produced this result analysed in accordance with a pre-specified analysis plan that was finalized before unblinded outcome data were available for analysis?"},
+				{Id: "5.2", Text: "Is the numerical result being assessed likely to have been selected, on the basis of the results, from multiple eligible outcome measurements (e.g. scales, definitions, time points) within the outcome domain?"},
+				{Id: "5.3", Text: "Is the numerical result being assessed likely to have been selected, on the basis of the results, from multiple eligible analyses of the data?"},
+			},
+			judge: rob2Reporting,
+		},
+	},
+	overall: rob2Overall,
+}
+
+func rob2Randomization(a Answers) Judgement {
+	switch {
+	case a.no("1.2"):
+		return High
+	case a.ni("1.2"):
+		if a.yes("1.3") {
+			return High
+		}
+		return SomeConcerns
+	case a.no("1.1"), a.yes("1.3"):
+		return SomeConcerns
+	}
+	return Low
+}
+
+// rob2Deviations judges both parts of the domain, the deviations arising
+// from the trial context and the analysis, and keeps the worse.
+func rob2Deviations(a Answers) Judgement {
+	context := Low
+	if !a.no("2.1") || !a.no("2.2") {
+		switch {
+		case a.ni("2.3"):
+			context = SomeConcerns
+		case a.yes("2.3"):
+			if a.no("2.4") || a.yes("2.5") {
+				context = SomeConcerns
+			} else {
+				context = High
+			}
+		}
+	}
+
+	analysis := Low
+	if !a.yes("2.6") {
+		if a.no("2.7") {
+			analysis = SomeConcerns
+		} else {
+			analysis = High
+		}
+	}
+
+	return worst(rob2Order, context, analysis)
+}
+
+func rob2MissingData(a Answers) Judgement {
+	switch {
+	case a.yes("3.1"), a.yes("3.2"), a.no("3.3"):
+		return Low
+	case a.no("3.4"):
+		return SomeConcerns
+	}
+	return High
+}
+
+// rob2Measurement takes no information on whether measurement differed
+// between groups as some concerns, where it would otherwise be low.
+func rob2Measurement(a Answers) Judgement {
+	if a.yes("4.1") || a.yes("4.2") {
+		return High
+	}
+	low := Low
+	if a.ni("4.2") {
+		low = SomeConcerns
+	}
+	switch {
+	case a.no("4.3"), a.no("4.4"):
+		return low
+	case a.no("4.5"):
+		return SomeConcerns
+	}
+	return High
+}
+
+func rob2Reporting(a Answers) Judgement {
+	switch {
+	case a.yes("5.2"), a.yes("5.3"):
+		return High
+	case a.no("5.2") && a.no("5.3") && a.yes("5.1"):
+		return Low
+	}
+	return SomeConcerns
+}
+
+// rob2Order ranks the RoB 2 judgements from the lowest risk.
+var rob2Order = []Judgement{Low, SomeConcerns, High}
+
+// rob2Overall is low when every domain is, high when any domain is, and
+// some concerns otherwise.
+func rob2Overall(domains []Judgement) Judgement {
+	return worst(rob2Order, domains...)
+}
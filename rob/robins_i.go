@@ -0,0 +1,236 @@
+package rob
+
+// RobinsI is the risk of bias tool for non-randomized studies of
+// interventions (version of 19 September 2016), for the effect of assignment
+// to intervention. Its guidance states criteria rather than flowcharts for
+// the domain judgements; the algorithms below follow those criteria. Critical
+// risk, which the guidance mostly leaves to the assessor's view of how severe
+// a problem is, is only derived for confounding that was not controlled for
+// while post-intervention variables were.
+var RobinsI = Tool{
+	Id:         "ROBINS-I",
+	Name:       "ROBINS-I",
+	Design:     "Non-randomized studies of interventions",
+	Judgements: append(append([]Judgement{}, robinsOrder...), NoInformation),
+	Domains: []Domain{
+		{
+			Id:   "D1",
+			Name: "Bias due to confounding",
+			Questions: []Question{
+				{Id: "1.1", Text: "Is there potential for confounding of the effect of intervention in this study?"},
+				{Id: "1.2", Text: "Was the analysis based on splitting participants' follow up time according to intervention received?", Condition: "Y/PY to 1.1"},
+				{Id: "1.3", Text: "Were intervention discontinuations or switches likely to be related to factors that are prognostic for the outcome?", Condition: "Y/PY to 1.2"},
+				{Id: "1.4", Text: "Did the authors use an appropriate analysis method that controlled for all the important confounding domains?", Condition: "N/PN to 1.2, or N/PN to 1.3"},
+				{Id: "1.5", Text: "Were confounding domains that were controlled for measured validly and reliably by the variables available in this study?", Condition: "Y/PY to 1.4"},
+				{Id: "1.6", Text: "Did the authors control for any post-intervention variables that could have been affected by the intervention?", Condition: "Y/PY to 1.1"},
+				{Id: "1.7", Text: "Did the authors use an appropriate analysis method that controlled for all the important confounding domains and for time-varying confounding?", Condition: "Y/PY to 1.3"},
+				{Id: "1.8", Text: "Were confounding domains that were controlled for measured validly and reliably by the variables available in this study?", Condition: "Y/PY to 1.7"},
+			},
+			judge: robinsConfounding,
+		},
+		{
+			Id:   "D2",
+			Name: "Bias in selection of participants into the study",
+			Questions: []Question{
+				{Id: "2.1", Text: "Was selection of participants into the study (or into the analysis) based on participant characteristics observed after the start of intervention?"},
+				{Id: "2.2", Text: "Were the post-intervention variables that influenced selection likely to be associated with intervention?", Condition: "Y/PY to 2.1"},
+				{Id: "2.3", Text: "Were the post-intervention variables that influenced selection likely to be influenced by the outcome or a cause of the outcome?", Condition: "Y/PY to 2.2"},
+				{Id: "2.4", Text: "Do start of follow-up and start of intervention coincide for most participants?"},
+				{Id: "2.5", Text: "Were adjustment techniques used that are likely to correct for the presence of selection biases?", Condition: "Y/PY to 2.2 and 2.3, or N/PN to 2.4"},
+			},
+			judge: robinsSelection,
+		},
+		{
+			Id:   "D3",
+			Name: "Bias in classification of interventions",
+			Questions: []Question{
+				{Id: "3.1", Text: "Were intervention groups clearly defined?"},
+				{Id: "3.2", Text: "Was the information used to define intervention groups recorded at the start of the intervention?"},
+				{Id: "3.3", Text: "Could classification of intervention status have been affected by knowledge of the outcome or risk of the outcome?"},
+			},
+			judge: robinsClassification,
+		},
+		{
+			Id:   "D4",
+			Name: "Bias due to deviations from intended interventions",
+			Questions: []Question{
+				{Id: "4.1", Text: "Were there deviations from the intended intervention beyond what would be expected in usual practice?"},
+				{Id: "4.2", Text: "Were these deviations from intended intervention unbalanced between groups and likely to have affected the outcome?", Condition: "Y/PY to 4.1"},
+			},
+			judge: robinsDeviations,
+		},
+		{
+			Id:   "D5",
+			Name: "Bias due to missing data",
+			Questions: []Question{
+				{Id: "5.1", Text: "Were outcome data available for all, or nearly all, participants?"},
+				{Id: "5.2", Text: "Were participants excluded due to missing data on intervention status?"},
+				{Id: "5.3", Text: "Were participants excluded due to missing data on other variables needed for the analysis?"},
+				{Id: "5.4", Text: "Are the proportion of participants and reasons for missing data similar across interventions?", Condition: "N/PN to 5.1, or Y/PY to 5.2 or 5.3"},
+				{Id: "5.5", Text: "Is there evidence that results were robust to the presence of missing data?", Condition: "N/PN to 5.1, or Y/PY to 5.2 or 5.3"},
+			},
+			judge: robinsMissingData,
+		},
+		{
+			Id:   "D6",
+			Name: "Bias in measurement of outcomes",
+			Questions: []Question{
+				{Id: "6.1", Text: "Could the outcome measure have been influenced by knowledge of the intervention received?"},
+				{Id: "6.2", Text: "Were outcome assessors aware of the intervention received by study participants?"},
+				{Id: "6.3", Text: "Were the methods of outcome assessment comparable across intervention groups?"},
+				{Id: "6.4", Text: "Were any systematic errors in measurement of the outcome related to intervention received?"},
+			},
+			judge: robinsMeasurement,
+		},
+		{
+			Id:   "D7",
+			Name: "Bias in selection of the reported result",
+			Questions: []Question{
+				{Id: "7.1", Text: "Is the reported effect estimate likely to be selected, on the basis of the results, from multiple outcome measurements within the outcome domain?"},
+				{Id: "7.2", Text: "Is the reported effect estimate likely to be selected, on the basis of the results, from multiple analyses of the intervention-outcome relationship?"},
+				{Id: "7.3", Text: "Is the reported effect estimate likely to be selected, on the basis of the results, from different subgroups?"},
+			},
+			judge: robinsReporting,
+		},
+	},
+	overall: robinsOverall,
+}
+
+// robinsOrder ranks the ROBINS-I judgements from the lowest risk.
+var robinsOrder = []Judgement{Low, Moderate, Serious, Critical}
+
+// robinsConfounding reads questions 1.7 and 1.8 instead of 1.4 and 1.5 when
+// the analysis had to deal with time-varying confounding.
+func robinsConfounding(a Answers) Judgement {
+	if a.no("1.1") {
+		return Low
+	}
+	controlled, valid := "1.4", "1.5"
+	if a.yes("1.2") && a.yes("1.3") {
+		controlled, valid = "1.7", "1.8"
+	}
+	switch {
+	case a.no(controlled) && a.yes("1.6"):
+		return Critical
+	case a.no(controlled), a.no(valid), a.yes("1.6"):
+		return Serious
+	case a.ni(controlled):
+		return NoInformation
+	}
+	return Moderate
+}
+
+func robinsSelection(a Answers) Judgement {
+	selected := a.yes("2.1") && a.yes("2.2") && a.yes("2.3")
+	if selected || a.no("2.4") {
+		switch {
+		case a.yes("2.5"):
+			return Moderate
+		case a.no("2.5"):
+			return Serious
+		}
+		return NoInformation
+	}
+	if a.ni("2.1") || a.ni("2.4") || (a.yes("2.1") && (a.ni("2.2") || (a.yes("2.2") && a.ni("2.3")))) {
+		return NoInformation
+	}
+	return Low
+}
+
+func robinsClassification(a Answers) Judgement {
+	switch {
+	case a.yes("3.3"), a.no("3.1"):
+		return Serious
+	case a.ni("3.1"), a.ni("3.2"), a.ni("3.3"):
+		return NoInformation
+	case a.no("3.2"):
+		return Moderate
+	}
+	return Low
+}
+
+func robinsDeviations(a Answers) Judgement {
+	switch {
+	case a.no("4.1"):
+		return Low
+	case a.ni("4.1"), a.ni("4.2"):
+		return NoInformation
+	case a.no("4.2"):
+		return Moderate
+	}
+	return Serious
+}
+
+func robinsMissingData(a Answers) Judgement {
+	missing := a.no("5.1") || a.yes("5.2") || a.yes("5.3")
+	if !missing {
+		if a.ni("5.1") || a.ni("5.2") || a.ni("5.3") {
+			return NoInformation
+		}
+		return Low
+	}
+	switch {
+	case a.yes("5.4"), a.yes("5.5"):
+		return Moderate
+	case a.no("5.4") && a.no("5.5"):
+		return Serious
+	}
+	return NoInformation
+}
+
+// robinsMeasurement takes a measure that could only probably be influenced
+// by knowledge of the intervention, assessed by aware assessors, as
+// moderate risk, and a measure that could definitely be as serious.
+func robinsMeasurement(a Answers) Judgement {
+	switch {
+	case a.no("6.3"), a.yes("6.4"):
+		return Serious
+	case a.ni("6.3"), a.ni("6.4"):
+		return NoInformation
+	case a.no("6.1"), a.no("6.2"):
+		return Low
+	case a.ni("6.1"), a.ni("6.2"):
+		return NoInformation
+	case a["6.1"] == AnswerProbablyYes:
+		return Moderate
+	}
+	return Serious
+}
+
+// robinsReporting takes a definite yes as serious risk and a probable yes
+// as moderate risk.
+func robinsReporting(a Answers) Judgement {
+	ids := []string{"7.1", "7.2", "7.3"}
+	judgement := Low
+	for _, id := range ids {
+		switch {
+		case a[id] == AnswerYes:
+			return Serious
+		case a[id] == AnswerProbablyYes:
+			judgement = Moderate
+		case a.ni(id) && judgement == Low:
+			judgement = NoInformation
+		}
+	}
+	return judgement
+}
+
+// robinsOverall takes the most severe domain judgement. Missing information
+// only decides the overall judgement when no domain is at serious or
+// critical risk.
+func robinsOverall(domains []Judgement) Judgement {
+	known := []Judgement{}
+	noInformation := false
+	for _, judgement := range domains {
+		if judgement == NoInformation {
+			noInformation = true
+			continue
+		}
+		known = append(known, judgement)
+	}
+	overall := worst(robinsOrder, known...)
+	if noInformation && (overall == Low || overall == Moderate) {
+		return NoInformation
+	}
+	return overall
+}
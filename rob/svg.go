@@ -0,0 +1,222 @@
+package rob
+
+import (
+	"bufio"
+	"fmt"
+	"html"
+	"io"
+)
+
+// Row is the assessment of one study in the summary charts.
+type Row struct {
+	Study   string
+	Weight  float64
+	Domains []Judgement
+	Overall Judgement
+}
+
+type style struct {
+	color  string
+	symbol string
+}
+
+var styles = map[Judgement]style{
+	Low:           {"#02c100", "+"},
+	SomeConcerns:  {"#e2df07", "-"},
+	Moderate:      {"#e2df07", "-"},
+	High:          {"#bf0000", "x"},
+	Serious:       {"#bf0000", "x"},
+	Critical:      {"#820000", "!"},
+	NoInformation: {"#4ea1f7", "?"},
+}
+
+const (
+	charWidth  = 7
+	margin     = 20
+	cellSize   = 40
+	radius     = 14
+	lineHeight = 18
+	maxStudy   = 40
+	barWidth   = 400
+	barHeight  = 24
+)
+
+func truncate(text string, length int) string {
+	runes := []rune(text)
+	if len(runes) <= length {
+		return text
+	}
+	return string(runes[:length-1]) + "…"
+}
+
+func header(out *bufio.Writer, width int, height int) {
+	fmt.Fprintf(out, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d" font-family="Helvetica, Arial, sans-serif" font-size="12">`+"\n", width, height, width, height)
+	fmt.Fprintf(out, `<rect width="%d" height="%d" fill="#ffffff"/>`+"\n", width, height)
+}
+
+func text(out *bufio.Writer, x int, y int, anchor string, value string) {
+	fmt.Fprintf(out, `<text x="%d" y="%d" text-anchor="%s" dominant-baseline="middle">%s</text>`+"\n", x, y, anchor, html.EscapeString(value))
+}
+
+func judgementLegendWidth(tool Tool) int {
+	width := 0
+	for _, judgement := range tool.Judgements {
+		width += 20 + (len(judgement)+3)*charWidth
+	}
+	return width
+}
+
+// judgementLegend draws a circle for each judgement of the tool on a line.
+func judgementLegend(out *bufio.Writer, tool Tool, x int, y int) {
+	for _, judgement := range tool.Judgements {
+		s := styles[judgement]
+		fmt.Fprintf(out, `<circle cx="%d" cy="%d" r="7" fill="%s"/>`+"\n", x+7, y, s.color)
+		text(out, x+20, y, "start", string(judgement))
+		x += 20 + (len(judgement)+3)*charWidth
+	}
+}
+
+// WriteTrafficLight writes the traffic-light table of the assessments: a row
+// per study and a coloured symbol per domain and for the overall judgement.
+func WriteTrafficLight(w io.Writer, tool Tool, rows []Row) error {
+	studyWidth := len("Study") * charWidth
+	for _, row := range rows {
+		studyWidth = max(studyWidth, len([]rune(truncate(row.Study, maxStudy)))*charWidth)
+	}
+	legendWidth := judgementLegendWidth(tool)
+	for _, domain := range tool.Domains {
+		legendWidth = max(legendWidth, (len(domain.Id)+2+len(domain.Name))*charWidth)
+	}
+
+	columns := len(tool.Domains) + 1
+	tableTop := margin + lineHeight
+	legendTop := tableTop + len(rows)*cellSize + margin
+	width := max(margin+studyWidth+margin+columns*cellSize+margin, margin+legendWidth+margin)
+	height := legendTop + (len(tool.Domains)+1)*lineHeight + margin
+
+	out := bufio.NewWriter(w)
+	header(out, width, height)
+
+	left := margin + studyWidth + margin
+	text(out, margin, margin, "start", "Study")
+	for i, domain := range tool.Domains {
+		text(out, left+i*cellSize+cellSize/2, margin, "middle", domain.Id)
+	}
+	text(out, left+len(tool.Domains)*cellSize+cellSize/2, margin, "middle", "Overall")
+
+	for i, row := range rows {
+		y := tableTop + i*cellSize + cellSize/2
+		text(out, margin, y, "start", truncate(row.Study, maxStudy))
+		judgements := append(append([]Judgement{}, row.Domains...), row.Overall)
+		for j, judgement := range judgements {
+			s, found := styles[judgement]
+			if !found {
+				continue
+			}
+			x := left + j*cellSize + cellSize/2
+			fmt.Fprintf(out, `<circle cx="%d" cy="%d" r="%d" fill="%s"/>`+"\n", x, y, radius, s.color)
+			fmt.Fprintf(out, `<text x="%d" y="%d" text-anchor="middle" dominant-baseline="central" font-weight="bold" font-size="14">%s</text>`+"\n", x, y, s.symbol)
+		}
+	}
+
+	y := legendTop
+	for _, domain := range tool.Domains {
+		text(out, margin, y, "start", domain.Id+": "+domain.Name)
+		y += lineHeight
+	}
+	judgementLegend(out, tool, margin, y)
+
+	fmt.Fprint(out, "</svg>\n")
+	return out.Flush()
+}
+
+// Proportions tells, for each judgement of the tool, the share of the
+// weight of the studies given that judgement, for each domain and overall.
+func Proportions(tool Tool, rows []Row, weighted bool) [][]float64 {
+	proportions := make([][]float64, len(tool.Domains)+1)
+	for i := range proportions {
+		proportions[i] = make([]float64, len(tool.Judgements))
+	}
+
+	total := 0.0
+	for _, row := range rows {
+		weight := 1.0
+		if weighted {
+			weight = row.Weight
+		}
+		total += weight
+		judgements := append(append([]Judgement{}, row.Domains...), row.Overall)
+		for i, judgement := range judgements {
+			for j, known := range tool.Judgements {
+				if judgement == known {
+					proportions[i][j] += weight
+				}
+			}
+		}
+	}
+
+	if total > 0 {
+		for i := range proportions {
+			for j := range proportions[i] {
+				proportions[i][j] /= total
+			}
+		}
+	}
+	return proportions
+}
+
+// WriteSummary writes the summary bar chart of the assessments: for each
+// domain and overall, a bar split by the share of studies given each
+// judgement, optionally weighted by the weight of the studies.
+func WriteSummary(w io.Writer, tool Tool, rows []Row, weighted bool) error {
+	labels := []string{}
+	for _, domain := range tool.Domains {
+		labels = append(labels, domain.Name)
+	}
+	labels = append(labels, "Overall risk of bias")
+
+	labelWidth := 0
+	for _, label := range labels {
+		labelWidth = max(labelWidth, len(label)*charWidth)
+	}
+
+	left := margin + labelWidth + margin
+	axisTop := margin + len(labels)*(barHeight+8)
+	width := max(left+barWidth, left+judgementLegendWidth(tool)) + margin
+	height := axisTop + 2*lineHeight + lineHeight + margin
+
+	out := bufio.NewWriter(w)
+	header(out, width, height)
+
+	proportions := Proportions(tool, rows, weighted)
+	for i, label := range labels {
+		y := margin + i*(barHeight+8)
+		text(out, left-10, y+barHeight/2, "end", label)
+		x := float64(left)
+		for j, share := range proportions[i] {
+			if share == 0 {
+				continue
+			}
+			segment := share * barWidth
+			fmt.Fprintf(out, `<rect x="%.2f" y="%d" width="%.2f" height="%d" fill="%s"/>`+"\n", x, y, segment, barHeight, styles[tool.Judgements[j]].color)
+			x += segment
+		}
+		fmt.Fprintf(out, `<rect x="%d" y="%d" width="%d" height="%d" fill="none" stroke="#000000"/>`+"\n", left, y, barWidth, barHeight)
+	}
+
+	fmt.Fprintf(out, `<line x1="%d" y1="%d" x2="%d" y2="%d" stroke="#000000"/>`+"\n", left, axisTop, left+barWidth, axisTop)
+	for tick := 0; tick <= 100; tick += 25 {
+		x := left + tick*barWidth/100
+		fmt.Fprintf(out, `<line x1="%d" y1="%d" x2="%d" y2="%d" stroke="#000000"/>`+"\n", x, axisTop, x, axisTop+5)
+		text(out, x, axisTop+lineHeight/2+6, "middle", fmt.Sprintf("%d%%", tick))
+	}
+	caption := "Proportion of studies"
+	if weighted {
+		caption = "Proportion of studies (weighted)"
+	}
+	text(out, left+barWidth/2, axisTop+lineHeight+10, "middle", caption)
+	judgementLegend(out, tool, left, axisTop+2*lineHeight+10)
+
+	fmt.Fprint(out, "</svg>\n")
+	return out.Flush()
+}
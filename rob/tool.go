@@ -0,0 +1,135 @@
+// Package rob holds the risk of bias instruments, the algorithms that turn
+// answers to their signalling questions into judgements, and the charts
+// that summarise them.
+package rob
+
+import "slices"
+
+type Answer string
+
+const (
+	AnswerYes           Answer = "Y"
+	AnswerProbablyYes   Answer = "PY"
+	AnswerProbablyNo    Answer = "PN"
+	AnswerNo            Answer = "N"
+	AnswerNoInformation Answer = "NI"
+	AnswerNotApplicable Answer = "NA"
+)
+
+var AnswerChoices = []Answer{AnswerYes, AnswerProbablyYes, AnswerProbablyNo, AnswerNo, AnswerNoInformation, AnswerNotApplicable}
+
+type Judgement string
+
+const (
+	Low           Judgement = "Low"
+	SomeConcerns  Judgement = "Some concerns"
+	High          Judgement = "High"
+	Moderate      Judgement = "Moderate"
+	Serious       Judgement = "Serious"
+	Critical      Judgement = "Critical"
+	NoInformation Judgement = "No information"
+)
+
+// Answers are the answers to the signalling questions by question id.
+type Answers map[string]Answer
+
+// yes tells whether the question was answered yes or probably yes.
+func (a Answers) yes(id string) bool {
+	return a[id] == AnswerYes || a[id] == AnswerProbablyYes
+}
+
+// no tells whether the question was answered no or probably no.
+func (a Answers) no(id string) bool {
+	return a[id] == AnswerNo || a[id] == AnswerProbablyNo
+}
+
+// ni tells whether nothing is known about the question. A question that is
+// not applicable where the algorithm needs it counts as no information.
+func (a Answers) ni(id string) bool {
+	return !a.yes(id) && !a.no(id)
+}
+
+type Question struct {
+	Id   string
+	Text string
+	// Condition tells when the question applies; empty when it always does.
+	Condition string
+}
+
+type Domain struct {
+	Id        string
+	Name      string
+	Questions []Question
+	judge     func(Answers) Judgement
+}
+
+// Tool is a risk of bias instrument. Judgements lists its judgements from
+// the lowest risk to the highest, then the absence of information.
+type Tool struct {
+	Id         string
+	Name       string
+	Design     string
+	Domains    []Domain
+	Judgements []Judgement
+	overall    func([]Judgement) Judgement
+}
+
+// Result holds the judgement of every domain, in order, and the overall one.
+type Result struct {
+	Domains []Judgement
+	Overall Judgement
+}
+
+// Assess derives the domain and overall judgements from the answers.
+func (t Tool) Assess(answers Answers) Result {
+	result := Result{Domains: make([]Judgement, len(t.Domains))}
+	for i, domain := range t.Domains {
+		result.Domains[i] = domain.judge(answers)
+	}
+	result.Overall = t.overall(result.Domains)
+	return result
+}
+
+// Questions lists the signalling questions of every domain, in order.
+func (t Tool) Questions() []Question {
+	questions := []Question{}
+	for _, domain := range t.Domains {
+		questions = append(questions, domain.Questions...)
+	}
+	return questions
+}
+
+// Unanswered lists the questions with no valid answer. Every question must
+// be answered, not applicable ones included.
+func (t Tool) Unanswered(answers Answers) []Question {
+	unanswered := []Question{}
+	for _, question := range t.Questions() {
+		if !slices.Contains(AnswerChoices, answers[question.Id]) {
+			unanswered = append(unanswered, question)
+		}
+	}
+	return unanswered
+}
+
+var Tools = []Tool{RoB2, RobinsI}
+
+func FindTool(id string) (Tool, bool) {
+	for _, tool := range Tools {
+		if tool.Id == id {
+			return tool, true
+		}
+	}
+	return Tool{}, false
+}
+
+// worst returns the judgement of the highest risk among the given ones,
+// following the order of the tool.
+func worst(order []Judgement, judgements ...Judgement) Judgement {
+	result := order[0]
+	for _, judgement := range judgements {
+		if slices.Index(order, judgement) > slices.Index(order, result) {
+			result = judgement
+		}
+	}
+	return result
+}
@@ -0,0 +1,170 @@
+package search
+
+import (
+	"fmt"
+	"strings"
+)
+
+type Database string
+
+const (
+	PubMed       Database = "PubMed"
+	Scopus                = "Scopus"
+	WebOfScience          = "WebOfScience"
+	Embase                = "Embase"
+	CINAHL                = "CINAHL"
+)
+
+var Databases = []Database{PubMed, Scopus, WebOfScience, Embase, CINAHL}
+
+var databaseNames = map[Database]string{
+	PubMed:       "PubMed",
+	Scopus:       "Scopus",
+	WebOfScience: "Web of Science Core Collection",
+	Embase:       "Embase (Ovid)",
+	CINAHL:       "CINAHL (EBSCOhost)",
+}
+
+func (d Database) Name() string {
+	return databaseNames[d]
+}
+
+// Query is the search string of a review for one database.
+type Query struct {
+	Database Database `json:"database"`
+	Name     string   `json:"name"`
+	Text     string   `json:"text"`
+}
+
+// syntax is how a database writes terms, joins them and limits them to the
+// title, abstract and keywords, or to a single field. A field the database
+// lacks is searched in the closest one it has.
+type syntax struct {
+	or    string
+	and   string
+	term  func(term string) string
+	group func(terms string) string
+	field map[Field]func(term Term) string
+}
+
+var syntaxes = map[Database]syntax{
+	PubMed: {
+		or:    " OR ",
+		and:   " AND ",
+		term:  func(term string) string { return term + "[tiab]" },
+		group: func(terms string) string { return "(" + terms + ")" },
+		field: map[Field]func(term Term) string{
+			FieldTitle:    func(term Term) string { return write(term) + "[ti]" },
+			FieldAbstract: func(term Term) string { return write(term) + "[tiab]" },
+			FieldKeywords: func(term Term) string { return write(term) + "[ot]" },
+			FieldSubject:  func(term Term) string { return write(term) + "[mh]" },
+		},
+	},
+	Scopus: {
+		or:    " OR ",
+		and:   " AND ",
+		term:  func(term string) string { return term },
+		group: func(terms string) string { return "TITLE-ABS-KEY(" + terms + ")" },
+		field: map[Field]func(term Term) string{
+			FieldTitle:    func(term Term) string { return "TITLE(" + write(term) + ")" },
+			FieldAbstract: func(term Term) string { return "ABS(" + write(term) + ")" },
+			FieldKeywords: func(term Term) string { return "AUTHKEY(" + write(term) + ")" },
+			FieldSubject:  func(term Term) string { return "INDEXTERMS(" + write(term) + ")" },
+		},
+	},
+	WebOfScience: {
+		or:    " OR ",
+		and:   " AND ",
+		term:  func(term string) string { return term },
+		group: func(terms string) string { return "TS=(" + terms + ")" },
+		field: map[Field]func(term Term) string{
+			FieldTitle:    func(term Term) string { return "TI=(" + write(term) + ")" },
+			FieldAbstract: func(term Term) string { return "AB=(" + write(term) + ")" },
+			FieldKeywords: func(term Term) string { return "AK=(" + write(term) + ")" },
+			FieldSubject:  func(term Term) string { return "KP=(" + write(term) + ")" },
+		},
+	},
+	Embase: {
+		or:    " or ",
+		and:   " and ",
+		term:  func(term string) string { return term },
+		group: func(terms string) string { return "(" + terms + ").ti,ab,kw." },
+		field: map[Field]func(term Term) string{
+			FieldTitle:    func(term Term) string { return write(term) + ".ti." },
+			FieldAbstract: func(term Term) string { return write(term) + ".ab." },
+			FieldKeywords: func(term Term) string { return write(term) + ".kw." },
+			// Emtree headings are written bare and exploded to their
+			// narrower terms.
+			FieldSubject: func(term Term) string { return "exp " + term.Text + "/" },
+		},
+	},
+	CINAHL: {
+		or:    " OR ",
+		and:   " AND ",
+		term:  func(term string) string { return term },
+		group: func(terms string) string { return fmt.Sprintf("(TI (%s) OR AB (%s))", terms, terms) },
+		field: map[Field]func(term Term) string{
+			FieldTitle:    func(term Term) string { return "TI " + write(term) },
+			FieldAbstract: func(term Term) string { return "AB " + write(term) },
+			FieldKeywords: func(term Term) string { return "SU " + write(term) },
+			FieldSubject:  func(term Term) string { return `(MH "` + term.Text + `")` },
+		},
+	},
+}
+
+// write quotes phrases and marks truncation with *, which every supported
+// database reads, inside phrases too.
+func write(term Term) string {
+	text := term.Text
+	if term.Truncated {
+		text += "*"
+	}
+	if term.Phrase {
+		text = `"` + text + `"`
+	}
+	return text
+}
+
+// Build writes the search string for the database: the terms of a concept
+// are OR-ed, the concepts AND-ed. Terms without a field are grouped in the
+// title, abstract and keywords; terms limited to a field are OR-ed with the
+// group one by one. Concepts without terms are left out. An empty string is
+// returned when there is nothing to search.
+func Build(database Database, concepts []Concept) string {
+	syntax, found := syntaxes[database]
+	if !found {
+		return ""
+	}
+	groups := []string{}
+	for _, concept := range concepts {
+		terms := []string{}
+		fielded := []string{}
+		for _, term := range concept.Terms {
+			if field, found := syntax.field[term.Field]; found {
+				fielded = append(fielded, field(term))
+			} else {
+				terms = append(terms, syntax.term(write(term)))
+			}
+		}
+		parts := fielded
+		if len(terms) > 0 {
+			parts = append([]string{syntax.group(strings.Join(terms, syntax.or))}, fielded...)
+		}
+		switch {
+		case len(parts) == 1:
+			groups = append(groups, parts[0])
+		case len(parts) > 1:
+			groups = append(groups, "("+strings.Join(parts, syntax.or)+")")
+		}
+	}
+	return strings.Join(groups, syntax.and)
+}
+
+// BuildAll writes the search string for every supported database.
+func BuildAll(concepts []Concept) []Query {
+	queries := []Query{}
+	for _, database := range Databases {
+		queries = append(queries, Query{Database: database, Name: database.Name(), Text: Build(database, concepts)})
+	}
+	return queries
+}
@@ -0,0 +1,70 @@
+package search
+
+import (
+	"sort"
+	"strings"
+)
+
+// Heading is a MeSH descriptor of the records found so far, with the number
+// of records indexed with it.
+type Heading struct {
+	Text    string `json:"text"`
+	Records int    `json:"records"`
+}
+
+// Term writes the heading as a term searched in the subject headings.
+func (h Heading) Term() string {
+	return Term{Text: h.Text, Phrase: strings.Contains(h.Text, " "), Field: FieldSubject}.String()
+}
+
+// MeshDescriptor returns the descriptor of a MeSH term in MEDLINE notation,
+// such as "Lung Neoplasms/*drug therapy", without its qualifiers and major
+// topic marks.
+func MeshDescriptor(term string) string {
+	descriptor, _, _ := strings.Cut(term, "/")
+	return strings.TrimSpace(strings.TrimLeft(strings.TrimSpace(descriptor), "*"))
+}
+
+// SuggestHeadings counts the MeSH descriptors of the records, once per
+// record, and returns the most frequent ones that no term of the concepts
+// searches for yet, at most limit of them. Ties are in alphabetical order.
+func SuggestHeadings(records [][]string, concepts []Concept, limit int) []Heading {
+	searched := make(map[string]bool)
+	for _, concept := range concepts {
+		for _, term := range concept.Terms {
+			searched[strings.ToLower(term.Text)] = true
+		}
+	}
+
+	counts := make(map[string]*Heading)
+	for _, terms := range records {
+		seen := make(map[string]bool)
+		for _, term := range terms {
+			descriptor := MeshDescriptor(term)
+			key := strings.ToLower(descriptor)
+			if descriptor == "" || seen[key] || searched[key] {
+				continue
+			}
+			seen[key] = true
+			if counts[key] == nil {
+				counts[key] = &Heading{Text: descriptor}
+			}
+			counts[key].Records++
+		}
+	}
+
+	headings := make([]Heading, 0, len(counts))
+	for _, heading := range counts {
+		headings = append(headings, *heading)
+	}
+	sort.Slice(headings, func(i, j int) bool {
+		if headings[i].Records != headings[j].Records {
+			return headings[i].Records > headings[j].Records
+		}
+		return headings[i].Text < headings[j].Text
+	})
+	if len(headings) > limit {
+		headings = headings[:limit]
+	}
+	return headings
+}
@@ -0,0 +1,115 @@
+// Package search writes the Boolean search strings of a review for the
+// bibliographic databases from its concepts and their synonyms.
+package search
+
+import "strings"
+
+// Field is where a term is searched. Terms without a field are searched in
+// titles, abstracts and keywords.
+type Field string
+
+const (
+	FieldDefault  Field = ""
+	FieldTitle          = "ti"
+	FieldAbstract       = "ab"
+	FieldKeywords       = "kw"
+	FieldSubject        = "sh"
+)
+
+// fieldTags are the tags a reviewer may end a term with, in square
+// brackets, to search it in one field only.
+var fieldTags = map[string]Field{
+	"tiab":     FieldDefault,
+	"ti":       FieldTitle,
+	"title":    FieldTitle,
+	"ab":       FieldAbstract,
+	"abstract": FieldAbstract,
+	"kw":       FieldKeywords,
+	"keyword":  FieldKeywords,
+	"keywords": FieldKeywords,
+	"sh":       FieldSubject,
+	"mh":       FieldSubject,
+	"mesh":     FieldSubject,
+	"subject":  FieldSubject,
+}
+
+// Term is a search term as a reviewer typed it: a single word or a phrase,
+// optionally truncated to find every word it starts, and optionally
+// limited to a field.
+type Term struct {
+	Text      string `json:"text"`
+	Phrase    bool   `json:"phrase"`
+	Truncated bool   `json:"truncated"`
+	Field     Field  `json:"field"`
+}
+
+// ParseTerm reads a term. Quotes around it, or spaces in it, make a phrase;
+// a trailing * or $, inside or outside the quotes, truncates it; a trailing
+// tag such as [ti] or [mesh] limits it to a field. Other quotes and
+// truncation marks are dropped, since no database reads them in the middle
+// of a term. Unknown tags are kept as text.
+func ParseTerm(text string) Term {
+	text = strings.TrimSpace(text)
+	term := Term{}
+	if open := strings.LastIndex(text, "["); open > 0 && strings.HasSuffix(text, "]") {
+		if field, found := fieldTags[strings.ToLower(strings.TrimSpace(text[open+1:len(text)-1]))]; found {
+			term.Field = field
+			text = strings.TrimSpace(text[:open])
+		}
+	}
+	truncated := func() {
+		if strings.HasSuffix(text, "*") || strings.HasSuffix(text, "$") {
+			term.Truncated = true
+			text = strings.TrimSpace(text[:len(text)-1])
+		}
+	}
+	truncated()
+	if len(text) > 1 && strings.HasPrefix(text, `"`) && strings.HasSuffix(text, `"`) {
+		term.Phrase = true
+		text = strings.TrimSpace(text[1 : len(text)-1])
+	}
+	truncated()
+	text = strings.NewReplacer(`"`, "", "*", "", "$", "").Replace(text)
+	term.Text = strings.Join(strings.Fields(text), " ")
+	term.Phrase = term.Phrase || strings.Contains(term.Text, " ")
+	return term
+}
+
+// String writes the term back as ParseTerm reads it.
+func (t Term) String() string {
+	text := t.Text
+	if t.Phrase {
+		text = `"` + text + `"`
+	}
+	if t.Truncated {
+		text += "*"
+	}
+	if t.Field != FieldDefault {
+		text += "[" + string(t.Field) + "]"
+	}
+	return text
+}
+
+// Concept is one idea of the question, searched as any of its terms.
+type Concept struct {
+	Name  string `json:"name"`
+	Terms []Term `json:"terms"`
+}
+
+// NewConcept parses the word and its synonyms into a concept, leaving out
+// empty and repeated terms.
+func NewConcept(word string, synonyms []string) Concept {
+	concept := Concept{Name: strings.TrimSpace(word), Terms: []Term{}}
+	seen := make(map[Term]bool)
+	for _, text := range append([]string{word}, synonyms...) {
+		term := ParseTerm(text)
+		key := term
+		key.Text = strings.ToLower(term.Text)
+		if term.Text == "" || seen[key] {
+			continue
+		}
+		seen[key] = true
+		concept.Terms = append(concept.Terms, term)
+	}
+	return concept
+}
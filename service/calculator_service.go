@@ -0,0 +1,83 @@
+package service
+
+import (
+	"fmt"
+	"math"
+	"sci-review/form"
+	"sci-review/meta"
+	"sci-review/model"
+)
+
+// CalculatorService turns what studies report into the numbers pooled by
+// the meta-analysis, and records the conversion it used.
+type CalculatorService struct {
+}
+
+func NewCalculatorService() *CalculatorService {
+	return &CalculatorService{}
+}
+
+const (
+	conversionMedian = "%s, to mean and SD"
+	conversionT      = "Hedges' g from the t statistic of an independent samples t-test"
+	conversionP      = "Hedges' g from the two-sided p-value of an independent samples t-test"
+)
+
+// Calculate copies into the data the numbers of its data type, keeps the
+// statistics reported instead of means or effects in its inputs and
+// converts them. Events, means and effects are pooled as they are.
+func (cs *CalculatorService) Calculate(row *model.OutcomeData, data form.OutcomeDataForm) error {
+	row.Inputs = model.NumberMap{}
+	row.MedianMethod, row.Conversion = "", ""
+	switch row.DataType {
+	case model.OutcomeBinary:
+		row.EventsT, row.TotalT, row.EventsC, row.TotalC = data.EventsT, data.TotalT, data.EventsC, data.TotalC
+	case model.OutcomeContinuous:
+		row.MeanT, row.SdT, row.NT = data.MeanT, data.SdT, data.NT
+		row.MeanC, row.SdC, row.NC = data.MeanC, data.SdC, data.NC
+	case model.OutcomeMedian:
+		method := meta.MedianMethod(data.MedianMethod)
+		treatment := meta.Median{Median: data.MedianT, Q1: data.Q1T, Q3: data.Q3T, Min: data.MinT, Max: data.MaxT, N: data.NT}
+		control := meta.Median{Median: data.MedianC, Q1: data.Q1C, Q3: data.Q3C, Min: data.MinC, Max: data.MaxC, N: data.NC}
+		meanT, sdT, err := meta.EstimateMean(method, treatment)
+		if err != nil {
+			return err
+		}
+		meanC, sdC, err := meta.EstimateMean(method, control)
+		if err != nil {
+			return err
+		}
+		row.MeanT, row.SdT, row.NT = meanT, sdT, data.NT
+		row.MeanC, row.SdC, row.NC = meanC, sdC, data.NC
+		row.MedianMethod = data.MedianMethod
+		row.Inputs = model.NumberMap{
+			"median_t": data.MedianT, "q1_t": data.Q1T, "q3_t": data.Q3T, "min_t": data.MinT, "max_t": data.MaxT,
+			"median_c": data.MedianC, "q1_c": data.Q1C, "q3_c": data.Q3C, "min_c": data.MinC, "max_c": data.MaxC,
+		}
+		row.Conversion = fmt.Sprintf(conversionMedian, method.Name())
+	case model.OutcomeTStatistic:
+		study, err := meta.TEffect("", data.T, data.NT, data.NC)
+		if err != nil {
+			return err
+		}
+		row.NT, row.NC = data.NT, data.NC
+		row.Measure, row.Effect, row.SE = string(meta.StandardizedMeanDifference), study.Yi, math.Sqrt(study.Vi)
+		row.Inputs = model.NumberMap{"t": data.T}
+		row.Conversion = conversionT
+	case model.OutcomePValue:
+		study, err := meta.PEffect("", data.P, data.NT, data.NC, data.Negative)
+		if err != nil {
+			return err
+		}
+		row.NT, row.NC = data.NT, data.NC
+		row.Measure, row.Effect, row.SE = string(meta.StandardizedMeanDifference), study.Yi, math.Sqrt(study.Vi)
+		row.Inputs = model.NumberMap{"p": data.P}
+		if data.Negative {
+			row.Inputs["negative"] = 1
+		}
+		row.Conversion = conversionP
+	case model.OutcomeGeneric:
+		row.Measure, row.Effect, row.SE = data.Measure, data.Effect, data.SE
+	}
+	return nil
+}
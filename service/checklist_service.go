@@ -0,0 +1,222 @@
+package service
+
+import (
+	"errors"
+	"github.com/google/uuid"
+	"golang.org/x/exp/slog"
+	"sci-review/checklist"
+	"sci-review/common"
+	"sci-review/form"
+	"sci-review/model"
+	"sci-review/repo"
+	"time"
+)
+
+type ChecklistService struct {
+	ChecklistRepo    repo.ChecklistRepo
+	OrganizationRepo *repo.OrganizationRepo
+	ScreeningService *ScreeningService
+}
+
+func NewChecklistService(checklistRepo repo.ChecklistRepo, organizationRepo *repo.OrganizationRepo, screeningService *ScreeningService) *ChecklistService {
+	return &ChecklistService{ChecklistRepo: checklistRepo, OrganizationRepo: organizationRepo, ScreeningService: screeningService}
+}
+
+var (
+	ErrorChecklistNotFound = errors.New("checklist not found")
+	ErrorNotAppraisable    = errors.New("only studies included at full text are appraised")
+)
+
+// findOrganization returns the organization when the user is an active
+// member of it.
+func (cs *ChecklistService) findOrganization(organizationId uuid.UUID, userId uuid.UUID) (*model.Organization, error) {
+	organization, err := cs.OrganizationRepo.GetById(organizationId)
+	if err != nil {
+		slog.Error("checklist organization", "error", err.Error())
+		return nil, common.DbInternalError
+	}
+	if !organization.IsActiveMember(userId) {
+		slog.Error("checklist organization", "error", "user is not a active member of the organization")
+		return nil, common.ForbiddenError
+	}
+	return organization, nil
+}
+
+// OrganizationTemplates returns the organization and the templates it
+// defined.
+func (cs *ChecklistService) OrganizationTemplates(organizationId uuid.UUID, userId uuid.UUID) (*model.Organization, []model.ChecklistTemplate, error) {
+	organization, err := cs.findOrganization(organizationId, userId)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	templates, err := cs.ChecklistRepo.FindTemplatesByOrganizationId(organizationId)
+	if err != nil {
+		slog.Error("checklist organization templates", "error", err.Error())
+		return nil, nil, common.DbInternalError
+	}
+	return organization, templates, nil
+}
+
+// Import adds a checked definition to the templates of the organization.
+// Only its owner can do it.
+func (cs *ChecklistService) Import(organizationId uuid.UUID, userId uuid.UUID, definition model.ChecklistDefinition) (*model.ChecklistTemplate, error) {
+	organization, err := cs.findOrganization(organizationId, userId)
+	if err != nil {
+		return nil, err
+	}
+	if !organization.IsOwner(userId) {
+		slog.Error("checklist import", "error", "user is not a owner of the organization")
+		return nil, common.ForbiddenError
+	}
+
+	template := model.NewChecklistTemplate(organizationId, definition, userId)
+	if err := cs.ChecklistRepo.CreateTemplate(template); err != nil {
+		slog.Error("checklist import", "error", err.Error())
+		return nil, common.DbInternalError
+	}
+
+	slog.Info("checklist import", "result", "success", "template", template)
+	return template, nil
+}
+
+// Archive hides a template of the organization from new appraisals; the
+// appraisals already made with it are kept.
+func (cs *ChecklistService) Archive(organizationId uuid.UUID, templateId uuid.UUID, userId uuid.UUID) error {
+	organization, err := cs.findOrganization(organizationId, userId)
+	if err != nil {
+		return err
+	}
+	if !organization.IsOwner(userId) {
+		slog.Error("checklist archive", "error", "user is not a owner of the organization")
+		return common.ForbiddenError
+	}
+
+	template, err := cs.ChecklistRepo.FindTemplateById(templateId)
+	if err != nil {
+		if errors.Is(err, repo.NotFoundInRepo) {
+			return ErrorChecklistNotFound
+		}
+		slog.Error("checklist archive", "error", err.Error())
+		return common.DbInternalError
+	}
+	if template.BuiltIn() || *template.OrganizationId != organizationId {
+		return ErrorChecklistNotFound
+	}
+
+	if err := cs.ChecklistRepo.ArchiveTemplate(templateId); err != nil {
+		slog.Error("checklist archive", "error", err.Error())
+		return common.DbInternalError
+	}
+
+	slog.Info("checklist archive", "result", "success", "template", template)
+	return nil
+}
+
+// Available returns the templates the user can appraise studies with.
+func (cs *ChecklistService) Available(userId uuid.UUID) ([]model.ChecklistTemplate, error) {
+	templates, err := cs.ChecklistRepo.FindAvailableTemplates(userId)
+	if err != nil {
+		slog.Error("checklist available", "error", err.Error())
+		return nil, common.DbInternalError
+	}
+	return templates, nil
+}
+
+// FindTemplate returns one of the templates available to the user, the
+// first one when no id is given.
+func (cs *ChecklistService) FindTemplate(templateId *uuid.UUID, userId uuid.UUID) (*model.ChecklistTemplate, error) {
+	templates, err := cs.Available(userId)
+	if err != nil {
+		return nil, err
+	}
+	for _, template := range templates {
+		if templateId == nil || template.Id == *templateId {
+			return &template, nil
+		}
+	}
+	return nil, ErrorChecklistNotFound
+}
+
+// Studies returns the included studies with their appraisals made with the
+// template.
+func (cs *ChecklistService) Studies(review *model.Review, template *model.ChecklistTemplate) ([]model.ChecklistStudy, error) {
+	included, err := cs.ScreeningService.Included(review)
+	if err != nil {
+		return nil, err
+	}
+
+	appraisals, err := cs.ChecklistRepo.FindAppraisalsByReviewId(review.Id, template.Id)
+	if err != nil {
+		slog.Error("checklist studies", "error", err.Error())
+		return nil, common.DbInternalError
+	}
+
+	byReference := make(map[uuid.UUID][]model.ChecklistAppraisal)
+	for _, appraisal := range appraisals {
+		byReference[appraisal.ReferenceId] = append(byReference[appraisal.ReferenceId], appraisal)
+	}
+
+	studies := make([]model.ChecklistStudy, len(included))
+	for i, reference := range included {
+		studies[i] = model.ChecklistStudy{Reference: reference, Appraisals: byReference[reference.Id]}
+		if studies[i].Appraisals == nil {
+			studies[i].Appraisals = []model.ChecklistAppraisal{}
+		}
+	}
+	return studies, nil
+}
+
+func (cs *ChecklistService) FindStudy(review *model.Review, referenceId uuid.UUID) (*model.Reference, error) {
+	included, err := cs.ScreeningService.Included(review)
+	if err != nil {
+		return nil, err
+	}
+	for _, reference := range included {
+		if reference.Id == referenceId {
+			return &reference, nil
+		}
+	}
+	return nil, ErrorNotAppraisable
+}
+
+// FindAppraisal returns the appraisal of the reviewer with the template, or
+// nil when there is none.
+func (cs *ChecklistService) FindAppraisal(referenceId uuid.UUID, template *model.ChecklistTemplate, reviewer *model.Reviewer) (*model.ChecklistAppraisal, error) {
+	appraisal, err := cs.ChecklistRepo.FindAppraisal(referenceId, template.Id, reviewer.Id)
+	if err != nil {
+		if errors.Is(err, repo.NotFoundInRepo) {
+			return nil, nil
+		}
+		slog.Error("checklist find appraisal", "error", err.Error())
+		return nil, common.DbInternalError
+	}
+	return appraisal, nil
+}
+
+// Save stores the answers of the reviewer with the score and rating they
+// earn. Every question must have been answered.
+func (cs *ChecklistService) Save(review *model.Review, reviewer *model.Reviewer, referenceId uuid.UUID, template *model.ChecklistTemplate, answers map[string]string, data form.ChecklistAppraisalForm) (*model.ChecklistAppraisal, error) {
+	if _, err := cs.FindStudy(review, referenceId); err != nil {
+		return nil, err
+	}
+
+	appraisal := model.NewChecklistAppraisal(review.Id, referenceId, template.Id, reviewer.Id)
+	for id, answer := range answers {
+		appraisal.Answers[id] = answer
+	}
+	result := checklist.Score(template.Definition, answers)
+	appraisal.Score = result.Score
+	appraisal.MaxScore = result.Max
+	appraisal.Rating = result.Rating
+	appraisal.Note = data.Note
+	appraisal.UpdatedAt = time.Now()
+
+	if err := cs.ChecklistRepo.SaveAppraisal(appraisal); err != nil {
+		slog.Error("checklist save", "error", err.Error())
+		return nil, common.DbInternalError
+	}
+
+	slog.Info("checklist save", "result", "success", "appraisal", appraisal)
+	return appraisal, nil
+}
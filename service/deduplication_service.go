@@ -0,0 +1,248 @@
+package service
+
+import (
+	"errors"
+	"github.com/google/uuid"
+	"golang.org/x/exp/slog"
+	"sci-review/common"
+	"sci-review/dedup"
+	"sci-review/form"
+	"sci-review/model"
+	"sci-review/repo"
+	"sort"
+	"strings"
+	"time"
+)
+
+const DefaultDuplicateThreshold = 0.9
+
+type DeduplicationService struct {
+	ReferenceRepo        repo.ReferenceRepo
+	DuplicateClusterRepo repo.DuplicateClusterRepo
+}
+
+func NewDeduplicationService(referenceRepo repo.ReferenceRepo, duplicateClusterRepo repo.DuplicateClusterRepo) *DeduplicationService {
+	return &DeduplicationService{ReferenceRepo: referenceRepo, DuplicateClusterRepo: duplicateClusterRepo}
+}
+
+var (
+	ErrorClusterNotFound      = errors.New("duplicate cluster not found")
+	ErrorClusterAlreadyClosed = errors.New("duplicate cluster was already decided")
+	ErrorPrimaryNotInCluster  = errors.New("the record to keep must belong to the cluster")
+)
+
+// Detect replaces the open proposals of a review with freshly computed
+// duplicate clusters. Clusters a reviewer already rejected are not proposed
+// again.
+func (ds *DeduplicationService) Detect(reviewId uuid.UUID, data form.DuplicateDetectForm) ([]model.DuplicateCluster, error) {
+	references, err := ds.ReferenceRepo.FindUniqueByReviewId(reviewId)
+	if err != nil {
+		slog.Error("duplicate detect", "error", err.Error())
+		return nil, common.DbInternalError
+	}
+
+	rejected, err := ds.DuplicateClusterRepo.FindAllByReviewId(reviewId, model.DuplicateRejected)
+	if err != nil {
+		slog.Error("duplicate detect", "error", err.Error())
+		return nil, common.DbInternalError
+	}
+	rejectedSets := map[string]bool{}
+	for _, cluster := range rejected {
+		rejectedSets[memberSetKey(cluster.MemberIds())] = true
+	}
+
+	byId := map[uuid.UUID]model.Reference{}
+	candidates := make([]dedup.Candidate, 0, len(references))
+	for _, reference := range references {
+		byId[reference.Id] = reference
+		candidates = append(candidates, dedup.Candidate{
+			Id:          reference.Id,
+			Title:       reference.Title,
+			Year:        reference.Year,
+			FirstAuthor: reference.FirstAuthor(),
+			Doi:         reference.Doi,
+			Pmid:        reference.Pmid,
+		})
+	}
+
+	tx := ds.DuplicateClusterRepo.GetDB().MustBegin()
+	defer tx.Rollback()
+
+	if err := ds.DuplicateClusterRepo.DeleteProposed(reviewId, tx); err != nil {
+		slog.Error("duplicate detect", "error", err.Error())
+		return nil, common.DbInternalError
+	}
+
+	clusters := []model.DuplicateCluster{}
+	for _, found := range dedup.FindClusters(candidates, data.Threshold) {
+		if rejectedSets[memberSetKey(found.Ids)] {
+			continue
+		}
+		members := make([]model.Reference, 0, len(found.Ids))
+		for _, id := range found.Ids {
+			members = append(members, byId[id])
+		}
+		cluster := model.NewDuplicateCluster(reviewId, mostComplete(members).Id, string(found.Match), found.Score)
+		cluster.Members = members
+		if err := ds.DuplicateClusterRepo.Create(cluster, tx); err != nil {
+			slog.Error("duplicate detect", "error", err.Error())
+			return nil, common.DbInternalError
+		}
+		clusters = append(clusters, *cluster)
+	}
+
+	if err := tx.Commit(); err != nil {
+		slog.Error("duplicate detect", "error", err.Error())
+		return nil, common.DbInternalError
+	}
+
+	slog.Info("duplicate detect", "reviewId", reviewId, "threshold", data.Threshold, "clusters", len(clusters))
+	return clusters, nil
+}
+
+func memberSetKey(ids []uuid.UUID) string {
+	keys := make([]string, 0, len(ids))
+	for _, id := range ids {
+		keys = append(keys, id.String())
+	}
+	sort.Strings(keys)
+	return strings.Join(keys, ",")
+}
+
+func mostComplete(references []model.Reference) model.Reference {
+	best := references[0]
+	for _, reference := range references[1:] {
+		if reference.Completeness() > best.Completeness() {
+			best = reference
+		}
+	}
+	return best
+}
+
+func (ds *DeduplicationService) FindProposed(reviewId uuid.UUID) ([]model.DuplicateCluster, error) {
+	clusters, err := ds.DuplicateClusterRepo.FindAllByReviewId(reviewId, model.DuplicateProposed)
+	if err != nil {
+		slog.Error("duplicate list", "error", err.Error())
+		return nil, common.DbInternalError
+	}
+	return clusters, nil
+}
+
+func (ds *DeduplicationService) findOpenCluster(clusterId uuid.UUID, reviewId uuid.UUID) (*model.DuplicateCluster, error) {
+	cluster, err := ds.DuplicateClusterRepo.FindById(clusterId)
+	if err != nil || cluster.ReviewId != reviewId {
+		return nil, ErrorClusterNotFound
+	}
+	if cluster.Status != model.DuplicateProposed {
+		return nil, ErrorClusterAlreadyClosed
+	}
+	return cluster, nil
+}
+
+// Confirm merges the cluster into the chosen primary record: the others are
+// marked as its duplicates and their source databases are added to it.
+func (ds *DeduplicationService) Confirm(clusterId uuid.UUID, reviewId uuid.UUID, userId uuid.UUID, data form.DuplicateConfirmForm) error {
+	cluster, err := ds.findOpenCluster(clusterId, reviewId)
+	if err != nil {
+		return err
+	}
+
+	primaryId := uuid.MustParse(data.PrimaryId)
+	var primary *model.Reference
+	for i := range cluster.Members {
+		if cluster.Members[i].Id == primaryId {
+			primary = &cluster.Members[i]
+		}
+	}
+	if primary == nil {
+		return ErrorPrimaryNotInCluster
+	}
+
+	tx := ds.DuplicateClusterRepo.GetDB().MustBegin()
+	defer tx.Rollback()
+
+	sources := map[string]bool{}
+	sourceDatabases := model.Strings{}
+	addSources := func(reference model.Reference) {
+		for _, source := range append(model.Strings{string(reference.SourceDatabase)}, reference.SourceDatabases...) {
+			if !sources[source] {
+				sources[source] = true
+				sourceDatabases = append(sourceDatabases, source)
+			}
+		}
+	}
+	addSources(*primary)
+
+	for _, member := range cluster.Members {
+		if member.Id == primary.Id {
+			continue
+		}
+		addSources(member)
+		if err := ds.ReferenceRepo.MarkDuplicate(member.Id, primary.Id, tx); err != nil {
+			slog.Error("duplicate confirm", "error", err.Error())
+			return common.DbInternalError
+		}
+	}
+
+	if err := ds.ReferenceRepo.UpdateSourceDatabases(primary.Id, sourceDatabases, tx); err != nil {
+		slog.Error("duplicate confirm", "error", err.Error())
+		return common.DbInternalError
+	}
+
+	ds.decide(cluster, model.DuplicateConfirmed, userId)
+	cluster.PrimaryId = primary.Id
+	if err := ds.DuplicateClusterRepo.UpdateDecision(cluster, tx); err != nil {
+		slog.Error("duplicate confirm", "error", err.Error())
+		return common.DbInternalError
+	}
+
+	if err := tx.Commit(); err != nil {
+		slog.Error("duplicate confirm", "error", err.Error())
+		return common.DbInternalError
+	}
+
+	slog.Info("duplicate confirm", "result", "success", "cluster", cluster)
+	return nil
+}
+
+func (ds *DeduplicationService) Reject(clusterId uuid.UUID, reviewId uuid.UUID, userId uuid.UUID) error {
+	cluster, err := ds.findOpenCluster(clusterId, reviewId)
+	if err != nil {
+		return err
+	}
+
+	tx := ds.DuplicateClusterRepo.GetDB().MustBegin()
+	defer tx.Rollback()
+
+	ds.decide(cluster, model.DuplicateRejected, userId)
+	if err := ds.DuplicateClusterRepo.UpdateDecision(cluster, tx); err != nil {
+		slog.Error("duplicate reject", "error", err.Error())
+		return common.DbInternalError
+	}
+
+	if err := tx.Commit(); err != nil {
+		slog.Error("duplicate reject", "error", err.Error())
+		return common.DbInternalError
+	}
+
+	slog.Info("duplicate reject", "result", "success", "cluster", cluster)
+	return nil
+}
+
+func (ds *DeduplicationService) decide(cluster *model.DuplicateCluster, status model.DuplicateStatus, userId uuid.UUID) {
+	now := time.Now()
+	cluster.Status = status
+	cluster.DecidedBy = uuid.NullUUID{UUID: userId, Valid: true}
+	cluster.DecidedAt = &now
+	cluster.UpdatedAt = now
+}
+
+// DuplicatesRemoved is the PRISMA "duplicate records removed" count.
+func (ds *DeduplicationService) DuplicatesRemoved(reviewId uuid.UUID) (int, error) {
+	count, err := ds.ReferenceRepo.CountDuplicates(reviewId)
+	if err != nil {
+		slog.Error("duplicate count", "error", err.Error())
+		return 0, common.DbInternalError
+	}
+	return count, nil
+}
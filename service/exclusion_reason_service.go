@@ -0,0 +1,186 @@
+package service
+
+import (
+	"errors"
+	"github.com/google/uuid"
+	"golang.org/x/exp/slog"
+	"sci-review/common"
+	"sci-review/form"
+	"sci-review/model"
+	"sci-review/repo"
+	"strings"
+	"time"
+)
+
+type ExclusionReasonService struct {
+	ExclusionReasonRepo repo.ExclusionReasonRepo
+}
+
+func NewExclusionReasonService(exclusionReasonRepo repo.ExclusionReasonRepo) *ExclusionReasonService {
+	return &ExclusionReasonService{ExclusionReasonRepo: exclusionReasonRepo}
+}
+
+var (
+	ErrorReasonNotFound = errors.New("exclusion reason not found")
+	ErrorReasonExists   = errors.New("the review already has this exclusion reason")
+	ErrorReasonInUse    = errors.New("the exclusion reason was already given and cannot be deleted")
+)
+
+func (s *ExclusionReasonService) FindAll(reviewId uuid.UUID) ([]model.ExclusionReason, error) {
+	reasons, err := s.ExclusionReasonRepo.FindAllByReviewId(reviewId)
+	if err != nil {
+		slog.Error("exclusion reasons", "error", err.Error())
+		return nil, common.DbInternalError
+	}
+	return reasons, nil
+}
+
+func (s *ExclusionReasonService) FindById(id uuid.UUID, reviewId uuid.UUID) (*model.ExclusionReason, error) {
+	reason, err := s.ExclusionReasonRepo.FindById(id)
+	if err != nil || reason.ReviewId != reviewId {
+		return nil, ErrorReasonNotFound
+	}
+	return reason, nil
+}
+
+func hasLabel(reasons []model.ExclusionReason, label string, except uuid.UUID) bool {
+	for _, reason := range reasons {
+		if reason.Id != except && strings.EqualFold(reason.Label, label) {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *ExclusionReasonService) Create(reviewId uuid.UUID, owner *model.Reviewer, data form.ExclusionReasonForm) (*model.ExclusionReason, error) {
+	if owner.ReviewerRole != model.ReviewerOwner {
+		return nil, ErrorNotOwner
+	}
+
+	reasons, err := s.FindAll(reviewId)
+	if err != nil {
+		return nil, err
+	}
+	label := strings.TrimSpace(data.Label)
+	if hasLabel(reasons, label, uuid.Nil) {
+		return nil, ErrorReasonExists
+	}
+
+	position := data.Position
+	if position == 0 {
+		position = len(reasons) + 1
+	}
+	reason := model.NewExclusionReason(reviewId, label, position)
+
+	tx := s.ExclusionReasonRepo.GetDB().MustBegin()
+	defer tx.Rollback()
+
+	if err := s.ExclusionReasonRepo.Create(reason, tx); err != nil {
+		slog.Error("exclusion reason create", "error", err.Error())
+		return nil, common.DbInternalError
+	}
+	if err := tx.Commit(); err != nil {
+		slog.Error("exclusion reason create", "error", err.Error())
+		return nil, common.DbInternalError
+	}
+
+	slog.Info("exclusion reason create", "result", "success", "reason", reason)
+	return reason, nil
+}
+
+// CreateDefaults adds the default reasons the review does not have yet.
+func (s *ExclusionReasonService) CreateDefaults(reviewId uuid.UUID, owner *model.Reviewer) (int, error) {
+	if owner.ReviewerRole != model.ReviewerOwner {
+		return 0, ErrorNotOwner
+	}
+
+	reasons, err := s.FindAll(reviewId)
+	if err != nil {
+		return 0, err
+	}
+
+	tx := s.ExclusionReasonRepo.GetDB().MustBegin()
+	defer tx.Rollback()
+
+	created := 0
+	for _, label := range model.DefaultExclusionReasons {
+		if hasLabel(reasons, label, uuid.Nil) {
+			continue
+		}
+		reason := model.NewExclusionReason(reviewId, label, len(reasons)+created+1)
+		if err := s.ExclusionReasonRepo.Create(reason, tx); err != nil {
+			slog.Error("exclusion reason defaults", "error", err.Error())
+			return 0, common.DbInternalError
+		}
+		created++
+	}
+
+	if err := tx.Commit(); err != nil {
+		slog.Error("exclusion reason defaults", "error", err.Error())
+		return 0, common.DbInternalError
+	}
+
+	slog.Info("exclusion reason defaults", "result", "success", "created", created)
+	return created, nil
+}
+
+func (s *ExclusionReasonService) Update(id uuid.UUID, reviewId uuid.UUID, owner *model.Reviewer, data form.ExclusionReasonForm) (*model.ExclusionReason, error) {
+	if owner.ReviewerRole != model.ReviewerOwner {
+		return nil, ErrorNotOwner
+	}
+
+	reason, err := s.FindById(id, reviewId)
+	if err != nil {
+		return nil, err
+	}
+
+	reasons, err := s.FindAll(reviewId)
+	if err != nil {
+		return nil, err
+	}
+	label := strings.TrimSpace(data.Label)
+	if hasLabel(reasons, label, reason.Id) {
+		return nil, ErrorReasonExists
+	}
+
+	reason.Label = label
+	reason.Position = data.Position
+	reason.UpdatedAt = time.Now()
+	if err := s.ExclusionReasonRepo.Update(reason); err != nil {
+		slog.Error("exclusion reason update", "error", err.Error())
+		return nil, common.DbInternalError
+	}
+
+	slog.Info("exclusion reason update", "result", "success", "reason", reason)
+	return reason, nil
+}
+
+// Delete removes a reason nobody has given yet. Reasons in use can only be
+// renamed, so that past decisions keep their meaning.
+func (s *ExclusionReasonService) Delete(id uuid.UUID, reviewId uuid.UUID, owner *model.Reviewer) error {
+	if owner.ReviewerRole != model.ReviewerOwner {
+		return ErrorNotOwner
+	}
+
+	reason, err := s.FindById(id, reviewId)
+	if err != nil {
+		return err
+	}
+
+	usage, err := s.ExclusionReasonRepo.CountUsage(reason.Id)
+	if err != nil {
+		slog.Error("exclusion reason delete", "error", err.Error())
+		return common.DbInternalError
+	}
+	if usage > 0 {
+		return ErrorReasonInUse
+	}
+
+	if err := s.ExclusionReasonRepo.Delete(reason.Id); err != nil {
+		slog.Error("exclusion reason delete", "error", err.Error())
+		return common.DbInternalError
+	}
+
+	slog.Info("exclusion reason delete", "result", "success", "reason", reason)
+	return nil
+}
@@ -0,0 +1,290 @@
+package service
+
+import (
+	"encoding/json"
+	"errors"
+	"github.com/google/uuid"
+	"golang.org/x/exp/slog"
+	"sci-review/common"
+	"sci-review/extraction"
+	"sci-review/model"
+	"sci-review/repo"
+)
+
+type ExtractionService struct {
+	ExtractionRepo   repo.ExtractionRepo
+	ScreeningService *ScreeningService
+}
+
+func NewExtractionService(extractionRepo repo.ExtractionRepo, screeningService *ScreeningService) *ExtractionService {
+	return &ExtractionService{ExtractionRepo: extractionRepo, ScreeningService: screeningService}
+}
+
+var (
+	ErrorNoExtractionForm       = errors.New("the review has no extraction form yet")
+	ErrorExtractionFormNotFound = errors.New("extraction form not found")
+	ErrorSchemaUnchanged        = errors.New("the form is the same as the current version")
+	ErrorFormOutdated           = errors.New("a newer version of the form was published, reload the page")
+	ErrorNotIncluded            = errors.New("data is only extracted from studies included at full text")
+	ErrorNotDual                = errors.New("the study needs two extractions made with the same form version")
+	ErrorOwnExtraction          = errors.New("the consensus must be reached by a reviewer who did not extract the study")
+	ErrorReconciled             = errors.New("a consensus was reached on this study and its extractions are kept as they are")
+)
+
+func (es *ExtractionService) FindForms(reviewId uuid.UUID) ([]model.ExtractionForm, error) {
+	forms, err := es.ExtractionRepo.FindFormsByReviewId(reviewId)
+	if err != nil {
+		slog.Error("extraction forms", "error", err.Error())
+		return nil, common.DbInternalError
+	}
+	return forms, nil
+}
+
+func (es *ExtractionService) FindLatestForm(reviewId uuid.UUID) (*model.ExtractionForm, error) {
+	extractionForm, err := es.ExtractionRepo.FindLatestForm(reviewId)
+	if err != nil {
+		if errors.Is(err, repo.NotFoundInRepo) {
+			return nil, ErrorNoExtractionForm
+		}
+		slog.Error("extraction latest form", "error", err.Error())
+		return nil, common.DbInternalError
+	}
+	return extractionForm, nil
+}
+
+func (es *ExtractionService) FindForm(reviewId uuid.UUID, formId uuid.UUID) (*model.ExtractionForm, error) {
+	extractionForm, err := es.ExtractionRepo.FindFormById(formId)
+	if err != nil {
+		if errors.Is(err, repo.NotFoundInRepo) {
+			return nil, ErrorExtractionFormNotFound
+		}
+		slog.Error("extraction form", "error", err.Error())
+		return nil, common.DbInternalError
+	}
+	if extractionForm.ReviewId != reviewId {
+		return nil, ErrorExtractionFormNotFound
+	}
+	return extractionForm, nil
+}
+
+// Publish makes a checked schema the next version of the extraction form of
+// the review. Only the owner of the review can change the form.
+func (es *ExtractionService) Publish(review *model.Review, reviewer *model.Reviewer, schema model.ExtractionSchema) (*model.ExtractionForm, error) {
+	if reviewer.ReviewerRole != model.ReviewerOwner {
+		return nil, ErrorNotOwner
+	}
+
+	version := 1
+	latest, err := es.FindLatestForm(review.Id)
+	switch {
+	case err == nil:
+		if sameSchema(latest.Schema, schema) {
+			return nil, ErrorSchemaUnchanged
+		}
+		version = latest.Version + 1
+	case !errors.Is(err, ErrorNoExtractionForm):
+		return nil, err
+	}
+
+	extractionForm := model.NewExtractionForm(review.Id, version, schema, reviewer.UserId)
+	if err := es.ExtractionRepo.CreateForm(extractionForm); err != nil {
+		slog.Error("extraction publish", "error", err.Error())
+		return nil, common.DbInternalError
+	}
+
+	slog.Info("extraction publish", "result", "success", "form", extractionForm)
+	return extractionForm, nil
+}
+
+func sameSchema(a model.ExtractionSchema, b model.ExtractionSchema) bool {
+	aJson, aErr := json.Marshal(a)
+	bJson, bErr := json.Marshal(b)
+	return aErr == nil && bErr == nil && string(aJson) == string(bJson)
+}
+
+// Studies returns the included studies with the extractions made from each.
+func (es *ExtractionService) Studies(review *model.Review) ([]model.ExtractionStudy, error) {
+	included, err := es.ScreeningService.Included(review)
+	if err != nil {
+		return nil, err
+	}
+
+	extractions, err := es.ExtractionRepo.FindAllByReviewId(review.Id)
+	if err != nil {
+		slog.Error("extraction studies", "error", err.Error())
+		return nil, common.DbInternalError
+	}
+
+	consensus, err := es.ExtractionRepo.FindConsensusByReviewId(review.Id)
+	if err != nil {
+		slog.Error("extraction studies", "error", err.Error())
+		return nil, common.DbInternalError
+	}
+
+	byReference := make(map[uuid.UUID][]model.Extraction)
+	for _, extraction := range extractions {
+		byReference[extraction.ReferenceId] = append(byReference[extraction.ReferenceId], extraction)
+	}
+	consensusByReference := make(map[uuid.UUID]*model.ExtractionConsensus)
+	for i := range consensus {
+		if _, found := consensusByReference[consensus[i].ReferenceId]; !found {
+			consensusByReference[consensus[i].ReferenceId] = &consensus[i]
+		}
+	}
+
+	studies := make([]model.ExtractionStudy, len(included))
+	for i, reference := range included {
+		studies[i] = model.ExtractionStudy{
+			Reference:   reference,
+			Extractions: byReference[reference.Id],
+			Consensus:   consensusByReference[reference.Id],
+		}
+		if studies[i].Extractions == nil {
+			studies[i].Extractions = []model.Extraction{}
+		}
+	}
+	return studies, nil
+}
+
+// FindStudy returns a study of the review, when it was included at full
+// text.
+func (es *ExtractionService) FindStudy(review *model.Review, referenceId uuid.UUID) (*model.Reference, error) {
+	included, err := es.ScreeningService.Included(review)
+	if err != nil {
+		return nil, err
+	}
+	for _, reference := range included {
+		if reference.Id == referenceId {
+			return &reference, nil
+		}
+	}
+	return nil, ErrorNotIncluded
+}
+
+// FindExtraction returns the most recent extraction of the reviewer from a
+// study, whatever form version it was made with, or nil when there is none.
+func (es *ExtractionService) FindExtraction(referenceId uuid.UUID, reviewer *model.Reviewer) (*model.Extraction, error) {
+	extraction, err := es.ExtractionRepo.FindLatest(referenceId, reviewer.Id)
+	if err != nil {
+		if errors.Is(err, repo.NotFoundInRepo) {
+			return nil, nil
+		}
+		slog.Error("extraction find", "error", err.Error())
+		return nil, common.DbInternalError
+	}
+	return extraction, nil
+}
+
+// Save stores the answers of the reviewer for a study. The answers must have
+// been checked against the form, which has to be the current version.
+func (es *ExtractionService) Save(review *model.Review, reviewer *model.Reviewer, referenceId uuid.UUID, extractionForm *model.ExtractionForm, data model.ExtractionValues) (*model.Extraction, error) {
+	if _, err := es.FindStudy(review, referenceId); err != nil {
+		return nil, err
+	}
+
+	latest, err := es.FindLatestForm(review.Id)
+	if err != nil {
+		return nil, err
+	}
+	if latest.Id != extractionForm.Id {
+		return nil, ErrorFormOutdated
+	}
+
+	consensus, err := es.findConsensus(referenceId, extractionForm.Id)
+	if err != nil {
+		return nil, err
+	}
+	if consensus != nil {
+		return nil, ErrorReconciled
+	}
+
+	saved := model.NewExtraction(review.Id, referenceId, extractionForm, reviewer.Id, data)
+	if err := es.ExtractionRepo.Save(saved); err != nil {
+		slog.Error("extraction save", "error", err.Error())
+		return nil, common.DbInternalError
+	}
+
+	slog.Info("extraction save", "result", "success", "extraction", saved)
+	return saved, nil
+}
+
+func (es *ExtractionService) findConsensus(referenceId uuid.UUID, formId uuid.UUID) (*model.ExtractionConsensus, error) {
+	consensus, err := es.ExtractionRepo.FindConsensus(referenceId, formId)
+	if err != nil {
+		if errors.Is(err, repo.NotFoundInRepo) {
+			return nil, nil
+		}
+		slog.Error("extraction consensus", "error", err.Error())
+		return nil, common.DbInternalError
+	}
+	return consensus, nil
+}
+
+// Compare lines up two extractions of a study field by field. It takes the
+// first two extractions of the most recent form version that has at least
+// two of them.
+func (es *ExtractionService) Compare(review *model.Review, referenceId uuid.UUID) (*model.ExtractionComparison, error) {
+	if _, err := es.FindStudy(review, referenceId); err != nil {
+		return nil, err
+	}
+
+	extractions, err := es.ExtractionRepo.FindAllByReference(referenceId)
+	if err != nil {
+		slog.Error("extraction compare", "error", err.Error())
+		return nil, common.DbInternalError
+	}
+
+	var pair []model.Extraction
+	byForm := make(map[uuid.UUID][]model.Extraction)
+	for _, extraction := range extractions {
+		byForm[extraction.FormId] = append(byForm[extraction.FormId], extraction)
+		if len(byForm[extraction.FormId]) == 2 {
+			pair = byForm[extraction.FormId]
+			break
+		}
+	}
+	if pair == nil {
+		return nil, ErrorNotDual
+	}
+
+	extractionForm, err := es.FindForm(review.Id, pair[0].FormId)
+	if err != nil {
+		return nil, err
+	}
+
+	consensus, err := es.findConsensus(referenceId, extractionForm.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	fields := extraction.Compare(extractionForm.Schema, pair[0].Data, pair[1].Data)
+	return &model.ExtractionComparison{
+		Form:          *extractionForm,
+		Left:          pair[0],
+		Right:         pair[1],
+		Fields:        fields,
+		Discrepancies: extraction.Discrepancies(fields),
+		Consensus:     consensus,
+	}, nil
+}
+
+// Reconcile records the consensus of a third reviewer on two extractions of
+// a study. The answers must have been checked against the form of the
+// extractions; the extractions themselves are left untouched.
+func (es *ExtractionService) Reconcile(reviewer *model.Reviewer, comparison *model.ExtractionComparison, formId uuid.UUID, data model.ExtractionValues) (*model.ExtractionConsensus, error) {
+	if comparison.Form.Id != formId {
+		return nil, ErrorFormOutdated
+	}
+	if reviewer.Id == comparison.Left.ReviewerId || reviewer.Id == comparison.Right.ReviewerId {
+		return nil, ErrorOwnExtraction
+	}
+
+	consensus := model.NewExtractionConsensus(comparison, reviewer.Id, data)
+	if err := es.ExtractionRepo.SaveConsensus(consensus); err != nil {
+		slog.Error("extraction reconcile", "error", err.Error())
+		return nil, common.DbInternalError
+	}
+
+	slog.Info("extraction reconcile", "result", "success", "consensus", consensus)
+	return consensus, nil
+}
@@ -0,0 +1,142 @@
+package service
+
+import (
+	"errors"
+	"github.com/google/uuid"
+	"golang.org/x/exp/slog"
+	"sci-review/common"
+	"sci-review/form"
+	"sci-review/grade"
+	"sci-review/model"
+	"sci-review/repo"
+	"strings"
+	"time"
+)
+
+type GradeService struct {
+	GradeRepo repo.GradeRepo
+}
+
+func NewGradeService(gradeRepo repo.GradeRepo) *GradeService {
+	return &GradeService{GradeRepo: gradeRepo}
+}
+
+var (
+	ErrorGradeNotFound = errors.New("GRADE assessment not found")
+	ErrorGradeUpgrade  = errors.New("only evidence from observational studies is rated up")
+)
+
+func (gs *GradeService) FindAll(reviewId uuid.UUID) ([]model.GradeAssessment, error) {
+	assessments, err := gs.GradeRepo.FindAllByReviewId(reviewId)
+	if err != nil {
+		slog.Error("grade assessments", "error", err.Error())
+		return nil, common.DbInternalError
+	}
+	return assessments, nil
+}
+
+func (gs *GradeService) FindById(id uuid.UUID, reviewId uuid.UUID) (*model.GradeAssessment, error) {
+	assessment, err := gs.GradeRepo.FindById(id)
+	if err != nil || assessment.ReviewId != reviewId {
+		return nil, ErrorGradeNotFound
+	}
+	return assessment, nil
+}
+
+// fill copies the form and the footnotes, by factor, into the assessment and
+// rates its certainty.
+func fill(assessment *model.GradeAssessment, data form.GradeAssessmentForm, footnotes map[string]string) error {
+	assessment.Outcome = strings.TrimSpace(data.Outcome)
+	assessment.Importance = model.OutcomeImportance(data.Importance)
+	assessment.Design = model.StudyDesign(data.Design)
+	assessment.Studies = data.Studies
+	assessment.Participants = data.Participants
+	assessment.ComparatorRisk = strings.TrimSpace(data.ComparatorRisk)
+	assessment.InterventionRisk = strings.TrimSpace(data.InterventionRisk)
+	assessment.RelativeEffect = strings.TrimSpace(data.RelativeEffect)
+	assessment.RiskOfBias = data.RiskOfBias
+	assessment.Inconsistency = data.Inconsistency
+	assessment.Indirectness = data.Indirectness
+	assessment.Imprecision = data.Imprecision
+	assessment.PublicationBias = data.PublicationBias
+	assessment.LargeEffect = data.LargeEffect
+	assessment.DoseResponse = data.DoseResponse
+	assessment.Confounding = data.Confounding
+	assessment.Comments = data.Comments
+	assessment.Position = data.Position
+
+	assessment.Footnotes = model.StringMap{}
+	for _, factor := range grade.Factors {
+		if note := strings.TrimSpace(footnotes[string(factor.Id)]); note != "" {
+			assessment.Footnotes[string(factor.Id)] = note
+		}
+	}
+
+	if errs := grade.Check(*assessment); len(errs) > 0 {
+		return ErrorGradeUpgrade
+	}
+	assessment.Certainty = grade.Rate(*assessment)
+	return nil
+}
+
+func (gs *GradeService) Create(review *model.Review, reviewer *model.Reviewer, data form.GradeAssessmentForm, footnotes map[string]string) (*model.GradeAssessment, error) {
+	assessment := model.NewGradeAssessment(review.Id, data.Outcome, reviewer.UserId)
+	if err := fill(assessment, data, footnotes); err != nil {
+		return nil, err
+	}
+
+	if err := gs.GradeRepo.Create(assessment); err != nil {
+		slog.Error("grade create", "error", err.Error())
+		return nil, common.DbInternalError
+	}
+
+	slog.Info("grade create", "result", "success", "assessment", assessment)
+	return assessment, nil
+}
+
+func (gs *GradeService) Update(id uuid.UUID, review *model.Review, data form.GradeAssessmentForm, footnotes map[string]string) (*model.GradeAssessment, error) {
+	assessment, err := gs.FindById(id, review.Id)
+	if err != nil {
+		return nil, err
+	}
+	if err := fill(assessment, data, footnotes); err != nil {
+		return nil, err
+	}
+	assessment.UpdatedAt = time.Now()
+
+	if err := gs.GradeRepo.Update(assessment); err != nil {
+		slog.Error("grade update", "error", err.Error())
+		return nil, common.DbInternalError
+	}
+
+	slog.Info("grade update", "result", "success", "assessment", assessment)
+	return assessment, nil
+}
+
+func (gs *GradeService) Delete(id uuid.UUID, review *model.Review, owner *model.Reviewer) error {
+	if owner.ReviewerRole != model.ReviewerOwner {
+		return ErrorNotOwner
+	}
+	assessment, err := gs.FindById(id, review.Id)
+	if err != nil {
+		return err
+	}
+
+	if err := gs.GradeRepo.Delete(assessment.Id); err != nil {
+		slog.Error("grade delete", "error", err.Error())
+		return common.DbInternalError
+	}
+
+	slog.Info("grade delete", "result", "success", "assessment", assessment)
+	return nil
+}
+
+// SummaryOfFindings lays out the assessments of the review as a Summary of
+// Findings table.
+func (gs *GradeService) SummaryOfFindings(review *model.Review) (grade.Table, error) {
+	assessments, err := gs.FindAll(review.Id)
+	if err != nil {
+		return grade.Table{}, err
+	}
+	return grade.NewTable(review.Title, assessments), nil
+}
@@ -1,23 +1,48 @@
 package service
 
 import (
+	"errors"
 	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"golang.org/x/exp/slog"
+	"sci-review/citation"
+	"sci-review/common"
 	"sci-review/form"
 	"sci-review/model"
 	"sci-review/repo"
+	"sci-review/search"
 	"strings"
+	"time"
 )
 
 type InvestigationService struct {
 	InvestigationRepo repo.InvestigationRepo
+	ReferenceRepo     repo.ReferenceRepo
 }
 
-func NewInvestigationService(investigationRepo repo.InvestigationRepo) *InvestigationService {
-	return &InvestigationService{InvestigationRepo: investigationRepo}
+func NewInvestigationService(investigationRepo repo.InvestigationRepo, referenceRepo repo.ReferenceRepo) *InvestigationService {
+	return &InvestigationService{InvestigationRepo: investigationRepo, ReferenceRepo: referenceRepo}
 }
 
+// meshSuggestionLimit is the number of MeSH headings suggested as keywords.
+const meshSuggestionLimit = 15
+
+var (
+	ErrorConceptNotInFramework = errors.New("keywords are linked to an element of the structured question")
+	ErrorStatusTransition      = errors.New("the investigation cannot move to this status from its current one")
+	ErrorConclusionRequired    = errors.New("a conclusion is required to decide whether to proceed with the review")
+	ErrorParseRunDate          = errors.New("run date must be in format YYYY-MM-DD")
+	ErrorRunDateInFuture       = errors.New("run date cannot be in the future")
+	ErrorQueryRequired         = errors.New("a query is required when it cannot be generated from the keywords for the database")
+	ErrorKeywordNotFound       = errors.New("keyword not found")
+	ErrorKeywordExists         = errors.New("another keyword already has this word; add synonyms to it or merge the two instead")
+	ErrorMergeSameKeyword      = errors.New("a keyword cannot be merged into itself")
+	ErrorProtocolLogged        = errors.New("this protocol is already logged for the investigation")
+	ErrorPublishedReviewLogged = errors.New("a review with this DOI is already logged for the investigation")
+)
+
 func (ps *InvestigationService) Create(data form.InvestigationForm, reviewId uuid.UUID, userId uuid.UUID) (*model.Investigation, error) {
-	investigation := model.NewInvestigation(userId, reviewId, data.Question, model.PiStatusInProgress)
+	investigation := model.NewInvestigation(userId, reviewId, data.Question, data.Framework, data.Elements(), model.PiStatusInProgress)
 
 	err := ps.InvestigationRepo.Create(investigation)
 	if err != nil {
@@ -35,22 +60,475 @@ func (ps *InvestigationService) FindOneById(investigationId uuid.UUID, userId uu
 	return ps.InvestigationRepo.FindOne(investigationId)
 }
 
-func (ps *InvestigationService) SaveKeyword(investigationId uuid.UUID, userId uuid.UUID, keywordForm form.KeywordForm) error {
-	formSynonyms := strings.Split(keywordForm.Synonyms, "\n")
-	var synonyms []string
+// UpdateQuestion rewrites the question and its breakdown. Keywords linked to
+// an element the new framework does not have are searched on their own.
+func (ps *InvestigationService) UpdateQuestion(investigation *model.Investigation, data form.InvestigationForm) error {
+	updated := *investigation
+	updated.Question = data.Question
+	updated.Framework = data.Framework
+	updated.Elements = data.Elements()
+	updated.UpdatedAt = time.Now()
+	if err := ps.InvestigationRepo.UpdateQuestion(&updated); err != nil {
+		return err
+	}
+	*investigation = updated
+	return nil
+}
+
+// ChangeStatus moves the investigation to the status if its current one
+// allows it, recording who changed it and why. Deciding whether to proceed
+// with the review needs a written conclusion.
+func (ps *InvestigationService) ChangeStatus(investigation *model.Investigation, userId uuid.UUID, data form.InvestigationStatusForm) error {
+	if !investigation.Status.CanMoveTo(data.Status) {
+		return ErrorStatusTransition
+	}
+	conclusion := strings.TrimSpace(data.Conclusion)
+	if data.Status.RequiresConclusion() && conclusion == "" {
+		return ErrorConclusionRequired
+	}
+
+	change := model.NewInvestigationStatusChange(investigation.Id, userId, investigation.Status, data.Status, conclusion)
+	updated := *investigation
+	updated.Status = data.Status
+	updated.Conclusion = conclusion
+	updated.UpdatedAt = change.CreatedAt
+
+	tx := ps.InvestigationRepo.GetDB().MustBegin()
+	defer tx.Rollback()
+
+	if err := ps.InvestigationRepo.UpdateStatus(&updated, tx); err != nil {
+		slog.Error("investigation status", "error", err.Error())
+		return common.DbInternalError
+	}
+	if err := ps.InvestigationRepo.CreateStatusChange(change, tx); err != nil {
+		slog.Error("investigation status", "error", err.Error())
+		return common.DbInternalError
+	}
+	if err := tx.Commit(); err != nil {
+		slog.Error("investigation status", "error", err.Error())
+		return common.DbInternalError
+	}
+	ps.InvestigationRepo.Invalidate(&updated)
+
+	*investigation = updated
+	slog.Info("investigation status", "result", "success", "change", change)
+	return nil
+}
+
+func (ps *InvestigationService) StatusHistory(investigationId uuid.UUID) ([]model.InvestigationStatusChange, error) {
+	changes, err := ps.InvestigationRepo.FindStatusChanges(investigationId)
+	if err != nil {
+		slog.Error("investigation status history", "error", err.Error())
+		return nil, common.DbInternalError
+	}
+	return changes, nil
+}
+
+func (ps *InvestigationService) SaveKeyword(investigation *model.Investigation, userId uuid.UUID, keywordForm form.KeywordForm) error {
+	if keywordForm.Concept != "" && !investigation.Framework.Has(keywordForm.Concept) {
+		return ErrorConceptNotInFramework
+	}
+
+	keywords, err := ps.InvestigationRepo.GetKeywordsByInvestigationId(investigation.Id)
+	if err != nil {
+		slog.Error("keyword create", "error", err.Error())
+		return common.DbInternalError
+	}
+	word := strings.TrimSpace(keywordForm.Word)
+	for _, other := range keywords {
+		if strings.EqualFold(other.Word, word) {
+			return ErrorKeywordExists
+		}
+	}
+
+	synonyms := model.CleanSynonyms(word, strings.Split(keywordForm.Synonyms, "\n"))
+	keyword := model.NewInvestigationKeyword(userId, investigation.Id, word, synonyms, keywordForm.Concept)
+	change := model.NewInvestigationKeywordChange(keyword, userId, model.KeywordCreated, strings.Join(synonyms, ", "))
+
+	tx := ps.InvestigationRepo.GetDB().MustBegin()
+	defer tx.Rollback()
+
+	if err := ps.InvestigationRepo.SaveKeyword(keyword, tx); err != nil {
+		slog.Error("keyword create", "error", err.Error())
+		return common.DbInternalError
+	}
+	if err := ps.InvestigationRepo.CreateKeywordChange(change, tx); err != nil {
+		slog.Error("keyword create", "error", err.Error())
+		return common.DbInternalError
+	}
+	if err := tx.Commit(); err != nil {
+		slog.Error("keyword create", "error", err.Error())
+		return common.DbInternalError
+	}
+
+	slog.Info("keyword create", "result", "success", "change", change)
+	return nil
+}
+
+func (ps *InvestigationService) findKeyword(investigationId uuid.UUID, keywordId uuid.UUID) (*model.InvestigationKeyword, error) {
+	keyword, err := ps.InvestigationRepo.FindKeyword(investigationId, keywordId)
+	if err != nil {
+		if errors.Is(err, repo.NotFoundInRepo) {
+			return nil, ErrorKeywordNotFound
+		}
+		slog.Error("keyword find", "error", err.Error())
+		return nil, common.DbInternalError
+	}
+	return keyword, nil
+}
+
+// UpdateKeyword rewrites the word, synonyms and concept of a keyword. A
+// word may not be renamed to another keyword's, which should be merged
+// instead.
+func (ps *InvestigationService) UpdateKeyword(investigation *model.Investigation, keywordId uuid.UUID, userId uuid.UUID, data form.KeywordUpdateForm) error {
+	if data.Concept != "" && !investigation.Framework.Has(data.Concept) {
+		return ErrorConceptNotInFramework
+	}
+	keyword, err := ps.findKeyword(investigation.Id, keywordId)
+	if err != nil {
+		return err
+	}
+	keywords, err := ps.InvestigationRepo.GetKeywordsByInvestigationId(investigation.Id)
+	if err != nil {
+		slog.Error("keyword update", "error", err.Error())
+		return common.DbInternalError
+	}
+	word := strings.TrimSpace(data.Word)
+	for _, other := range keywords {
+		if other.Id != keyword.Id && strings.EqualFold(other.Word, word) {
+			return ErrorKeywordExists
+		}
+	}
+
+	updated := *keyword
+	updated.Word = word
+	updated.Synonyms = model.CleanSynonyms(word, data.Synonyms)
+	updated.Concept = data.Concept
+	updated.UpdatedAt = time.Now()
+	summary := updated.Summary(*keyword, investigation.Framework)
+	if summary == "" {
+		return nil
+	}
+
+	return ps.changeKeywords(model.NewInvestigationKeywordChange(&updated, userId, model.KeywordUpdated, summary), func(tx *sqlx.Tx) error {
+		return ps.InvestigationRepo.UpdateKeyword(&updated, tx)
+	})
+}
 
-	// trim spaces and remove empty synonyms
-	for _, synonym := range formSynonyms {
-		synonym = strings.TrimSpace(synonym)
-		if synonym != "" {
-			synonyms = append(synonyms, synonym)
+func (ps *InvestigationService) DeleteKeyword(investigation *model.Investigation, keywordId uuid.UUID, userId uuid.UUID) error {
+	keyword, err := ps.findKeyword(investigation.Id, keywordId)
+	if err != nil {
+		return err
+	}
+
+	return ps.changeKeywords(model.NewInvestigationKeywordChange(keyword, userId, model.KeywordDeleted, strings.Join(keyword.Synonyms, ", ")), func(tx *sqlx.Tx) error {
+		return ps.InvestigationRepo.DeleteKeyword(keyword, tx)
+	})
+}
+
+// MergeKeywords merges a keyword into another: its word and synonyms become
+// synonyms of the other, and it is deleted.
+func (ps *InvestigationService) MergeKeywords(investigation *model.Investigation, keywordId uuid.UUID, intoId uuid.UUID, userId uuid.UUID) error {
+	if keywordId == intoId {
+		return ErrorMergeSameKeyword
+	}
+	keyword, err := ps.findKeyword(investigation.Id, keywordId)
+	if err != nil {
+		return err
+	}
+	into, err := ps.findKeyword(investigation.Id, intoId)
+	if err != nil {
+		return err
+	}
+
+	merged := *into
+	merged.Merge(*keyword)
+	merged.UpdatedAt = time.Now()
+	detail := "merged " + keyword.Word
+	if summary := merged.Summary(*into, investigation.Framework); summary != "" {
+		detail += ": " + summary
+	}
+
+	return ps.changeKeywords(model.NewInvestigationKeywordChange(&merged, userId, model.KeywordMerged, detail), func(tx *sqlx.Tx) error {
+		if err := ps.InvestigationRepo.UpdateKeyword(&merged, tx); err != nil {
+			return err
 		}
+		return ps.InvestigationRepo.DeleteKeyword(keyword, tx)
+	})
+}
+
+// MoveKeyword moves a keyword one place up or down in the order the
+// keywords are listed and searched.
+func (ps *InvestigationService) MoveKeyword(investigation *model.Investigation, keywordId uuid.UUID, userId uuid.UUID, direction string) error {
+	keyword, err := ps.findKeyword(investigation.Id, keywordId)
+	if err != nil {
+		return err
+	}
+	keywords, err := ps.InvestigationRepo.GetKeywordsByInvestigationId(investigation.Id)
+	if err != nil {
+		slog.Error("keyword move", "error", err.Error())
+		return common.DbInternalError
+	}
+
+	offset := 1
+	if direction == "up" {
+		offset = -1
+	}
+	moved, ok := model.MoveKeyword(keywords, keyword.Id, offset)
+	if !ok {
+		return nil
+	}
+
+	return ps.changeKeywords(model.NewInvestigationKeywordChange(keyword, userId, model.KeywordMoved, direction), func(tx *sqlx.Tx) error {
+		return ps.InvestigationRepo.UpdateKeywordPositions(moved, tx)
+	})
+}
+
+// AddSynonym adds a synonym to a keyword, such as a MeSH heading suggested
+// from the records of the review.
+func (ps *InvestigationService) AddSynonym(investigation *model.Investigation, keywordId uuid.UUID, userId uuid.UUID, synonym string) error {
+	keyword, err := ps.findKeyword(investigation.Id, keywordId)
+	if err != nil {
+		return err
+	}
+
+	updated := *keyword
+	updated.Synonyms = model.CleanSynonyms(keyword.Word, append(append([]string{}, keyword.Synonyms...), synonym))
+	updated.UpdatedAt = time.Now()
+	summary := updated.Summary(*keyword, investigation.Framework)
+	if summary == "" {
+		return nil
+	}
+
+	return ps.changeKeywords(model.NewInvestigationKeywordChange(&updated, userId, model.KeywordUpdated, summary), func(tx *sqlx.Tx) error {
+		return ps.InvestigationRepo.UpdateKeyword(&updated, tx)
+	})
+}
+
+// changeKeywords applies a change to the keywords and records it in their
+// history in one transaction.
+func (ps *InvestigationService) changeKeywords(change *model.InvestigationKeywordChange, apply func(tx *sqlx.Tx) error) error {
+	area := "keyword " + strings.ToLower(string(change.Action))
+
+	tx := ps.InvestigationRepo.GetDB().MustBegin()
+	defer tx.Rollback()
+
+	if err := apply(tx); err != nil {
+		slog.Error(area, "error", err.Error())
+		return common.DbInternalError
+	}
+	if err := ps.InvestigationRepo.CreateKeywordChange(change, tx); err != nil {
+		slog.Error(area, "error", err.Error())
+		return common.DbInternalError
+	}
+	if err := tx.Commit(); err != nil {
+		slog.Error(area, "error", err.Error())
+		return common.DbInternalError
 	}
 
-	keyword := model.NewInvestigationKeyword(userId, investigationId, keywordForm.Word, synonyms)
-	return ps.InvestigationRepo.SaveKeyword(keyword)
+	slog.Info(area, "result", "success", "change", change)
+	return nil
+}
+
+func (ps *InvestigationService) KeywordHistory(investigationId uuid.UUID) ([]model.InvestigationKeywordChange, error) {
+	changes, err := ps.InvestigationRepo.FindKeywordChanges(investigationId)
+	if err != nil {
+		slog.Error("keyword history", "error", err.Error())
+		return nil, common.DbInternalError
+	}
+	return changes, nil
 }
 
 func (ps *InvestigationService) GetKeywordsByInvestigationId(investigationId uuid.UUID) ([]model.InvestigationKeyword, error) {
 	return ps.InvestigationRepo.GetKeywordsByInvestigationId(investigationId)
 }
+
+// SearchStrings writes the search string of the investigation for each
+// database. Keywords linked to the same element of the question make one
+// concept, searched as any of their words or synonyms, in the order of the
+// framework; every other keyword is a concept of its own.
+func (ps *InvestigationService) SearchStrings(investigation *model.Investigation) ([]search.Query, error) {
+	keywords, err := ps.InvestigationRepo.GetKeywordsByInvestigationId(investigation.Id)
+	if err != nil {
+		return nil, err
+	}
+	return search.BuildAll(concepts(investigation, keywords)), nil
+}
+
+// MeshSuggestions suggests the MeSH headings most frequent in the unique
+// records of the review that the keywords do not search for yet.
+func (ps *InvestigationService) MeshSuggestions(investigation *model.Investigation, keywords []model.InvestigationKeyword) ([]search.Heading, error) {
+	references, err := ps.ReferenceRepo.FindUniqueByReviewId(investigation.ReviewId)
+	if err != nil {
+		slog.Error("investigation mesh suggestions", "error", err.Error())
+		return nil, common.DbInternalError
+	}
+	records := make([][]string, 0, len(references))
+	for _, reference := range references {
+		records = append(records, reference.MeshTerms)
+	}
+	return search.SuggestHeadings(records, concepts(investigation, keywords), meshSuggestionLimit), nil
+}
+
+func concepts(investigation *model.Investigation, keywords []model.InvestigationKeyword) []search.Concept {
+	concepts := []search.Concept{}
+	for _, element := range investigation.Framework.Elements() {
+		terms := []string{}
+		for _, keyword := range keywords {
+			if keyword.Concept == element.Key {
+				terms = append(append(terms, keyword.Word), keyword.Synonyms...)
+			}
+		}
+		if len(terms) > 0 {
+			concept := search.NewConcept(terms[0], terms[1:])
+			concept.Name = element.Label
+			concepts = append(concepts, concept)
+		}
+	}
+	for _, keyword := range keywords {
+		if !investigation.Framework.Has(keyword.Concept) {
+			concepts = append(concepts, search.NewConcept(keyword.Word, keyword.Synonyms))
+		}
+	}
+	return concepts
+}
+
+// LogPilotSearch records a search run while investigating the question with
+// a snapshot of the keywords, so later searches can tell what changed. An
+// empty query is generated from the keywords for the database.
+func (ps *InvestigationService) LogPilotSearch(investigation *model.Investigation, userId uuid.UUID, data form.PilotSearchForm) (*model.PilotSearch, error) {
+	runOn, err := time.Parse("2006-01-02", data.RunOn)
+	if err != nil {
+		return nil, ErrorParseRunDate
+	}
+	if runOn.After(time.Now()) {
+		return nil, ErrorRunDateInFuture
+	}
+
+	keywords, err := ps.InvestigationRepo.GetKeywordsByInvestigationId(investigation.Id)
+	if err != nil {
+		slog.Error("pilot search log", "error", err.Error())
+		return nil, common.DbInternalError
+	}
+
+	query := strings.TrimSpace(data.Query)
+	if query == "" {
+		query = search.Build(search.Database(data.SourceDatabase), concepts(investigation, keywords))
+	}
+	if query == "" {
+		return nil, ErrorQueryRequired
+	}
+
+	pilotSearch := model.NewPilotSearch(investigation.Id, userId, data.SourceDatabase, query, runOn, data.Hits, strings.TrimSpace(data.Notes), model.NewKeywordSnapshot(keywords))
+	if err := ps.InvestigationRepo.CreatePilotSearch(pilotSearch); err != nil {
+		slog.Error("pilot search log", "error", err.Error())
+		return nil, common.DbInternalError
+	}
+
+	slog.Info("pilot search log", "result", "success", "search", pilotSearch)
+	return pilotSearch, nil
+}
+
+// PilotComparisons compares each pilot search of the investigation with the
+// previous one in the same database, most recent first.
+func (ps *InvestigationService) PilotComparisons(investigationId uuid.UUID) ([]model.PilotComparison, error) {
+	searches, err := ps.InvestigationRepo.FindPilotSearches(investigationId)
+	if err != nil {
+		slog.Error("pilot search list", "error", err.Error())
+		return nil, common.DbInternalError
+	}
+	return model.ComparePilotSearches(searches), nil
+}
+
+func (ps *InvestigationService) DeletePilotSearch(investigationId uuid.UUID, pilotSearchId uuid.UUID) error {
+	if err := ps.InvestigationRepo.DeletePilotSearch(investigationId, pilotSearchId); err != nil {
+		slog.Error("pilot search delete", "error", err.Error())
+		return common.DbInternalError
+	}
+	slog.Info("pilot search delete", "result", "success", "id", pilotSearchId.String())
+	return nil
+}
+
+// AddProtocol logs the protocol of a review on a similar question. A
+// registration is logged once per investigation.
+func (ps *InvestigationService) AddProtocol(investigation *model.Investigation, userId uuid.UUID, data form.ProtocolForm) (*model.RegisteredProtocol, error) {
+	protocols, err := ps.Protocols(investigation.Id)
+	if err != nil {
+		return nil, err
+	}
+	registrationId := strings.TrimSpace(data.RegistrationId)
+	for _, protocol := range protocols {
+		if registrationId != "" && protocol.Registry == data.Registry && strings.EqualFold(protocol.RegistrationId, registrationId) {
+			return nil, ErrorProtocolLogged
+		}
+	}
+
+	protocol := model.NewRegisteredProtocol(investigation.Id, userId, data.Registry, registrationId, strings.TrimSpace(data.Title), data.Status, strings.TrimSpace(data.Url), strings.TrimSpace(data.Notes))
+	if err := ps.InvestigationRepo.CreateProtocol(protocol); err != nil {
+		slog.Error("protocol create", "error", err.Error())
+		return nil, common.DbInternalError
+	}
+
+	slog.Info("protocol create", "result", "success", "protocol", protocol)
+	return protocol, nil
+}
+
+func (ps *InvestigationService) Protocols(investigationId uuid.UUID) ([]model.RegisteredProtocol, error) {
+	protocols, err := ps.InvestigationRepo.FindProtocols(investigationId)
+	if err != nil {
+		slog.Error("protocol list", "error", err.Error())
+		return nil, common.DbInternalError
+	}
+	return protocols, nil
+}
+
+func (ps *InvestigationService) DeleteProtocol(investigationId uuid.UUID, protocolId uuid.UUID) error {
+	if err := ps.InvestigationRepo.DeleteProtocol(investigationId, protocolId); err != nil {
+		slog.Error("protocol delete", "error", err.Error())
+		return common.DbInternalError
+	}
+	slog.Info("protocol delete", "result", "success", "id", protocolId.String())
+	return nil
+}
+
+// AddPublishedReview logs a review published on a similar question. DOIs
+// are stored without resolver prefixes and logged once per investigation.
+func (ps *InvestigationService) AddPublishedReview(investigation *model.Investigation, userId uuid.UUID, data form.PublishedReviewForm) (*model.PublishedReview, error) {
+	reviews, err := ps.PublishedReviews(investigation.Id)
+	if err != nil {
+		return nil, err
+	}
+	doi := citation.NormalizeDoi(data.Doi)
+	for _, review := range reviews {
+		if doi != "" && strings.EqualFold(review.Doi, doi) {
+			return nil, ErrorPublishedReviewLogged
+		}
+	}
+
+	review := model.NewPublishedReview(investigation.Id, userId, strings.TrimSpace(data.Citation), doi, data.Year, data.Overlap, strings.TrimSpace(data.Notes))
+	if err := ps.InvestigationRepo.CreatePublishedReview(review); err != nil {
+		slog.Error("published review create", "error", err.Error())
+		return nil, common.DbInternalError
+	}
+
+	slog.Info("published review create", "result", "success", "review", review)
+	return review, nil
+}
+
+func (ps *InvestigationService) PublishedReviews(investigationId uuid.UUID) ([]model.PublishedReview, error) {
+	reviews, err := ps.InvestigationRepo.FindPublishedReviews(investigationId)
+	if err != nil {
+		slog.Error("published review list", "error", err.Error())
+		return nil, common.DbInternalError
+	}
+	return reviews, nil
+}
+
+func (ps *InvestigationService) DeletePublishedReview(investigationId uuid.UUID, publishedReviewId uuid.UUID) error {
+	if err := ps.InvestigationRepo.DeletePublishedReview(investigationId, publishedReviewId); err != nil {
+		slog.Error("published review delete", "error", err.Error())
+		return common.DbInternalError
+	}
+	slog.Info("published review delete", "result", "success", "id", publishedReviewId.String())
+	return nil
+}
@@ -0,0 +1,361 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"github.com/google/uuid"
+	"golang.org/x/exp/slog"
+	"sci-review/common"
+	"sci-review/form"
+	"sci-review/meta"
+	"sci-review/model"
+	"sci-review/repo"
+	"sort"
+	"strings"
+	"time"
+)
+
+type MetaService struct {
+	MetaRepo          repo.MetaRepo
+	ScreeningService  *ScreeningService
+	CalculatorService *CalculatorService
+	ExtractionService *ExtractionService
+}
+
+func NewMetaService(metaRepo repo.MetaRepo, screeningService *ScreeningService, calculatorService *CalculatorService, extractionService *ExtractionService) *MetaService {
+	return &MetaService{MetaRepo: metaRepo, ScreeningService: screeningService, CalculatorService: calculatorService, ExtractionService: extractionService}
+}
+
+var (
+	ErrorOutcomeDataNotFound = errors.New("outcome data not found")
+	ErrorNotPoolable         = errors.New("outcome data is only extracted from studies included at full text")
+	ErrorModeratorNotFound   = errors.New("moderators are categorical or numeric fields of the extraction form")
+)
+
+// Studies returns the included studies with the outcome data extracted from
+// them.
+func (ms *MetaService) Studies(review *model.Review) ([]model.OutcomeStudy, error) {
+	included, err := ms.ScreeningService.Included(review)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := ms.MetaRepo.FindAllByReviewId(review.Id)
+	if err != nil {
+		slog.Error("meta studies", "error", err.Error())
+		return nil, common.DbInternalError
+	}
+
+	byReference := make(map[uuid.UUID][]model.OutcomeData)
+	for _, row := range data {
+		byReference[row.ReferenceId] = append(byReference[row.ReferenceId], row)
+	}
+
+	studies := make([]model.OutcomeStudy, len(included))
+	for i, reference := range included {
+		studies[i] = model.OutcomeStudy{Reference: reference, Data: byReference[reference.Id]}
+		if studies[i].Data == nil {
+			studies[i].Data = []model.OutcomeData{}
+		}
+	}
+	return studies, nil
+}
+
+// Outcomes returns the names of the outcomes data was extracted for, in
+// alphabetical order.
+func (ms *MetaService) Outcomes(review *model.Review) ([]string, error) {
+	data, err := ms.MetaRepo.FindAllByReviewId(review.Id)
+	if err != nil {
+		slog.Error("meta outcomes", "error", err.Error())
+		return nil, common.DbInternalError
+	}
+
+	seen := make(map[string]bool)
+	outcomes := []string{}
+	for _, row := range data {
+		if !seen[row.Outcome] {
+			seen[row.Outcome] = true
+			outcomes = append(outcomes, row.Outcome)
+		}
+	}
+	sort.Strings(outcomes)
+	return outcomes, nil
+}
+
+func (ms *MetaService) FindById(id uuid.UUID, reviewId uuid.UUID) (*model.OutcomeData, error) {
+	data, err := ms.MetaRepo.FindById(id)
+	if err != nil || data.ReviewId != reviewId {
+		return nil, ErrorOutcomeDataNotFound
+	}
+	return data, nil
+}
+
+// Save stores the data of an included study for an outcome, replacing what
+// was extracted for that outcome before. Only the numbers of the data type
+// are kept, converted by the calculator when they need to be.
+func (ms *MetaService) Save(review *model.Review, reviewer *model.Reviewer, data form.OutcomeDataForm) (*model.OutcomeData, error) {
+	referenceId, err := uuid.Parse(data.ReferenceId)
+	if err != nil {
+		return nil, ErrorNotPoolable
+	}
+	included, err := ms.ScreeningService.Included(review)
+	if err != nil {
+		return nil, err
+	}
+	found := false
+	for _, reference := range included {
+		found = found || reference.Id == referenceId
+	}
+	if !found {
+		return nil, ErrorNotPoolable
+	}
+
+	row := model.NewOutcomeData(review.Id, referenceId, strings.TrimSpace(data.Outcome), model.OutcomeDataType(data.DataType), reviewer.UserId)
+	if err := ms.CalculatorService.Calculate(row, data); err != nil {
+		return nil, err
+	}
+	row.UpdatedAt = time.Now()
+
+	if err := ms.MetaRepo.Save(row); err != nil {
+		slog.Error("meta save", "error", err.Error())
+		return nil, common.DbInternalError
+	}
+
+	slog.Info("meta save", "result", "success", "data", row)
+	return row, nil
+}
+
+func (ms *MetaService) Delete(id uuid.UUID, review *model.Review, owner *model.Reviewer) error {
+	if owner.ReviewerRole != model.ReviewerOwner {
+		return ErrorNotOwner
+	}
+	data, err := ms.FindById(id, review.Id)
+	if err != nil {
+		return err
+	}
+
+	if err := ms.MetaRepo.Delete(data.Id); err != nil {
+		slog.Error("meta delete", "error", err.Error())
+		return common.DbInternalError
+	}
+
+	slog.Info("meta delete", "result", "success", "data", data)
+	return nil
+}
+
+// effect computes the effect of a study with the measure from the data
+// extracted from it. Means estimated from medians are pooled as means, and
+// effects converted from test statistics as the effects they are on.
+func effect(label string, row model.OutcomeData, measure meta.Measure, method meta.Method) (meta.Study, error) {
+	var study meta.Study
+	var err error
+	switch row.DataType {
+	case model.OutcomeBinary:
+		study, err = meta.BinaryEffect(label, measure, meta.Binary{EventsT: row.EventsT, TotalT: row.TotalT, EventsC: row.EventsC, TotalC: row.TotalC})
+	case model.OutcomeContinuous, model.OutcomeMedian:
+		study, err = meta.ContinuousEffect(label, measure, meta.Continuous{MeanT: row.MeanT, SdT: row.SdT, NT: row.NT, MeanC: row.MeanC, SdC: row.SdC, NC: row.NC})
+	default:
+		if meta.Measure(row.Measure) != measure {
+			return meta.Study{}, meta.ErrorMeasure
+		}
+		if method == meta.MantelHaenszel {
+			return meta.Study{}, meta.ErrorMethod
+		}
+		study, err = meta.GenericEffect(label, row.Effect, row.SE)
+	}
+	study.Conversion = row.Conversion
+	return study, err
+}
+
+// Moderators returns the fields of the latest extraction form heterogeneity
+// can be explored with: choices and text as subgroups, numbers as the
+// covariate of a meta-regression.
+func (ms *MetaService) Moderators(review *model.Review) ([]model.ExtractionField, error) {
+	extractionForm, err := ms.ExtractionService.FindLatestForm(review.Id)
+	if err != nil {
+		if errors.Is(err, ErrorNoExtractionForm) {
+			return []model.ExtractionField{}, nil
+		}
+		return nil, err
+	}
+
+	fields := []model.ExtractionField{}
+	for _, field := range extractionForm.Schema.Fields {
+		switch field.Type {
+		case model.FieldSingleChoice, model.FieldText, model.FieldNumber:
+			fields = append(fields, field)
+		}
+	}
+	return fields, nil
+}
+
+// moderatorValues returns the value of the field for each included study:
+// the consensus value when the extractions were reconciled, otherwise the
+// value all its extractions agree on. Studies without one are left out.
+func (ms *MetaService) moderatorValues(review *model.Review, key string) (map[uuid.UUID]any, error) {
+	studies, err := ms.ExtractionService.Studies(review)
+	if err != nil {
+		return nil, err
+	}
+
+	values := make(map[uuid.UUID]any)
+	for _, study := range studies {
+		if study.Consensus != nil {
+			if value, found := study.Consensus.Data[key]; found && value != nil {
+				values[study.Reference.Id] = value
+			}
+			continue
+		}
+		var agreed any
+		for i, extraction := range study.Extractions {
+			value := extraction.Data[key]
+			if i > 0 && fmt.Sprint(value) != fmt.Sprint(agreed) {
+				agreed = nil
+				break
+			}
+			agreed = value
+		}
+		if agreed != nil {
+			values[study.Reference.Id] = agreed
+		}
+	}
+	return values, nil
+}
+
+// moderate splits the studies by the moderator into subgroups, or regresses
+// them on it when it is a number. Studies without a value are listed.
+func (ms *MetaService) moderate(review *model.Review, analysis *meta.Analysis, references []model.Reference, key string) error {
+	fields, err := ms.Moderators(review)
+	if err != nil {
+		return err
+	}
+	var field *model.ExtractionField
+	for i := range fields {
+		if fields[i].Key == key {
+			field = &fields[i]
+		}
+	}
+	if field == nil {
+		return ErrorModeratorNotFound
+	}
+	values, err := ms.moderatorValues(review, key)
+	if err != nil {
+		return err
+	}
+
+	analysis.Moderator = field.Label
+	analysis.Unmoderated = []string{}
+	studies, groups, covariate := []meta.Study{}, []string{}, []float64{}
+	for i, study := range analysis.Studies {
+		value := values[references[i].Id]
+		if field.Type == model.FieldNumber {
+			if number, ok := value.(float64); ok {
+				studies = append(studies, study)
+				covariate = append(covariate, number)
+				continue
+			}
+		} else if text := strings.TrimSpace(fmt.Sprint(value)); value != nil && text != "" {
+			studies = append(studies, study)
+			groups = append(groups, text)
+			continue
+		}
+		analysis.Unmoderated = append(analysis.Unmoderated, study.Label)
+	}
+
+	result := analysis.Result
+	if field.Type == model.FieldNumber {
+		analysis.Regression, err = meta.Regress(studies, covariate, result.Method, result.Level)
+	} else {
+		analysis.Subgroups, err = meta.Subgroups(studies, groups, result.Measure, result.Method, result.Level)
+	}
+	return err
+}
+
+// Analyze pools the included studies with data for the outcome and tests
+// for publication bias when there are enough of them. The same studies are
+// pooled again leaving each out, accumulated by year of publication and,
+// when a moderator is chosen, split or regressed by it. Studies whose data
+// does not give the measure are left out and listed with the reason. The
+// analysis is returned with the error when nothing could be pooled or the
+// moderator could not be analysed.
+func (ms *MetaService) Analyze(review *model.Review, data form.MetaAnalysisForm) (*meta.Analysis, error) {
+	studies, err := ms.Studies(review)
+	if err != nil {
+		return nil, err
+	}
+
+	type pooled struct {
+		study     meta.Study
+		reference model.Reference
+	}
+	measure, method := meta.Measure(data.Measure), meta.Method(data.Method)
+	analysis := &meta.Analysis{Outcome: data.Outcome, Studies: []meta.Study{}, Excluded: []meta.Excluded{}}
+	rows := []pooled{}
+	for _, study := range studies {
+		for _, row := range study.Data {
+			if row.Outcome != data.Outcome {
+				continue
+			}
+			label := studyLabel(study.Reference)
+			effect, err := effect(label, row, measure, method)
+			if err != nil {
+				analysis.Excluded = append(analysis.Excluded, meta.Excluded{Label: label, Reason: err.Error()})
+				continue
+			}
+			rows = append(rows, pooled{study: effect, reference: study.Reference})
+		}
+	}
+	sort.SliceStable(rows, func(i, j int) bool {
+		return rows[i].study.Label < rows[j].study.Label
+	})
+	references := make([]model.Reference, len(rows))
+	for i, row := range rows {
+		analysis.Studies = append(analysis.Studies, row.study)
+		references[i] = row.reference
+	}
+
+	level := data.Level
+	if level == 0 {
+		level = 0.95
+	}
+	result, err := meta.Pool(analysis.Studies, measure, method, level)
+	if err != nil {
+		return analysis, err
+	}
+	analysis.Result = result
+
+	if result.K >= meta.MinBiasStudies {
+		bias, err := meta.AssessBias(analysis.Studies, result)
+		if err != nil {
+			slog.Warn("meta bias", "error", err.Error())
+		}
+		analysis.Bias = bias
+	}
+
+	if result.K >= 2 {
+		analysis.LeaveOneOut, err = meta.LeaveOneOut(analysis.Studies, measure, method, level)
+		if err != nil {
+			slog.Warn("meta leave one out", "error", err.Error())
+		}
+	}
+	sort.SliceStable(rows, func(i, j int) bool {
+		return rows[i].reference.Year < rows[j].reference.Year
+	})
+	chronological := make([]meta.Study, len(rows))
+	for i, row := range rows {
+		chronological[i] = row.study
+	}
+	analysis.Cumulative, err = meta.Cumulative(chronological, measure, method, level)
+	if err != nil {
+		slog.Warn("meta cumulative", "error", err.Error())
+	}
+
+	if data.Moderator != "" {
+		if err := ms.moderate(review, analysis, references, data.Moderator); err != nil {
+			return analysis, err
+		}
+	}
+
+	slog.Info("meta analyze", "result", "success", "outcome", data.Outcome, "k", result.K)
+	return analysis, nil
+}
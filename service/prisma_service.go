@@ -0,0 +1,84 @@
+package service
+
+import (
+	"golang.org/x/exp/slog"
+	"sci-review/common"
+	"sci-review/model"
+	"sci-review/prisma"
+	"sci-review/repo"
+)
+
+type PrismaService struct {
+	ReferenceRepo    repo.ReferenceRepo
+	ScreeningService *ScreeningService
+}
+
+func NewPrismaService(referenceRepo repo.ReferenceRepo, screeningService *ScreeningService) *PrismaService {
+	return &PrismaService{ReferenceRepo: referenceRepo, ScreeningService: screeningService}
+}
+
+// Counts gathers the numbers of the PRISMA 2020 flow diagram from the
+// imported records, the duplicates removed and both screening stages.
+func (ps *PrismaService) Counts(review *model.Review) (*prisma.Counts, error) {
+	sources, err := ps.ReferenceRepo.CountBySource(review.Id)
+	if err != nil {
+		slog.Error("prisma counts", "error", err.Error())
+		return nil, common.DbInternalError
+	}
+
+	duplicates, err := ps.ReferenceRepo.CountDuplicates(review.Id)
+	if err != nil {
+		slog.Error("prisma counts", "error", err.Error())
+		return nil, common.DbInternalError
+	}
+
+	screening, err := ps.ScreeningService.StatusCounts(review, model.StageTitleAbstract)
+	if err != nil {
+		return nil, err
+	}
+
+	sought, err := ps.ScreeningService.sought(review)
+	if err != nil {
+		return nil, err
+	}
+
+	fullText, err := ps.ScreeningService.StatusCounts(review, model.StageFullText)
+	if err != nil {
+		return nil, err
+	}
+
+	reasons, err := ps.ScreeningService.ReasonCounts(review, model.StageFullText)
+	if err != nil {
+		return nil, err
+	}
+
+	counts := prisma.Counts{
+		Sources:        []prisma.Source{},
+		Duplicates:     duplicates,
+		ScreenExcluded: screening[model.StatusExcluded],
+		Sought:         len(sought),
+		Reasons:        []prisma.Reason{},
+		Included:       fullText[model.StatusIncluded],
+		Pending: screening[model.StatusPending] + screening[model.StatusConflict] +
+			fullText[model.StatusPending] + fullText[model.StatusConflict],
+	}
+	for _, source := range sources {
+		counts.Sources = append(counts.Sources, prisma.Source{Name: string(source.SourceDatabase), Records: source.Records})
+	}
+	for _, n := range screening {
+		counts.Screened += n
+	}
+	for _, reference := range sought {
+		if reference.NotRetrieved {
+			counts.NotRetrieved++
+		}
+	}
+	counts.Assessed = counts.Sought - counts.NotRetrieved
+	for _, reason := range reasons {
+		if reason.Records > 0 {
+			counts.Reasons = append(counts.Reasons, prisma.Reason{Label: reason.Reason.Label, Reports: reason.Records})
+		}
+	}
+
+	return &counts, nil
+}
@@ -0,0 +1,178 @@
+package service
+
+import (
+	"errors"
+	"github.com/google/uuid"
+	"golang.org/x/exp/slog"
+	"io"
+	"sci-review/citation"
+	"sci-review/common"
+	"sci-review/form"
+	"sci-review/model"
+	"sci-review/repo"
+)
+
+const referenceImportBatchSize = 500
+
+type ReferenceService struct {
+	ReferenceRepo repo.ReferenceRepo
+}
+
+func NewReferenceService(referenceRepo repo.ReferenceRepo) *ReferenceService {
+	return &ReferenceService{ReferenceRepo: referenceRepo}
+}
+
+var (
+	ErrorUnsupportedFormat = errors.New("unsupported file format")
+	ErrorReferenceNotFound = errors.New("reference not found")
+)
+
+func newCitationReader(format citation.Format, file io.Reader) (citation.Reader, error) {
+	switch format {
+	case citation.FormatRis:
+		return citation.NewRisReader(file), nil
+	case citation.FormatBibtex:
+		return citation.NewBibtexReader(file), nil
+	case citation.FormatCslJson:
+		return citation.NewCslJsonReader(file), nil
+	case citation.FormatMedline:
+		return citation.NewMedlineReader(file), nil
+	case citation.FormatPubmedXml:
+		return citation.NewPubmedXmlReader(file), nil
+	}
+	return nil, ErrorUnsupportedFormat
+}
+
+func (rs *ReferenceService) Import(data form.ReferenceImportForm, file io.Reader, reviewId uuid.UUID, userId uuid.UUID) (*model.ImportSummary, error) {
+	reader, err := newCitationReader(data.Format, file)
+	if err != nil {
+		return nil, err
+	}
+
+	summary := model.NewImportSummary()
+	batch := make([]model.Reference, 0, referenceImportBatchSize)
+
+	tx := rs.ReferenceRepo.GetDB().MustBegin()
+	defer tx.Rollback()
+
+	for {
+		record, err := reader.Next()
+		if err == io.EOF {
+			break
+		}
+		var parseError *citation.ParseError
+		if errors.As(err, &parseError) {
+			summary.Parsed++
+			summary.AddError(parseError.Line, parseError.Key, parseError.Message)
+			continue
+		}
+		if err != nil {
+			slog.Error("reference import", "error", err.Error())
+			return nil, err
+		}
+
+		summary.Parsed++
+		batch = append(batch, *newReferenceFromRecord(record, reviewId, userId, data.SourceDatabase))
+		if len(batch) == referenceImportBatchSize {
+			if err := rs.ReferenceRepo.CreateBatch(batch, tx); err != nil {
+				slog.Error("reference import", "error", err.Error())
+				return nil, err
+			}
+			summary.Imported += len(batch)
+			batch = batch[:0]
+		}
+	}
+
+	if err := rs.ReferenceRepo.CreateBatch(batch, tx); err != nil {
+		slog.Error("reference import", "error", err.Error())
+		return nil, err
+	}
+	summary.Imported += len(batch)
+
+	if err := tx.Commit(); err != nil {
+		slog.Error("reference import", "error", err.Error())
+		return nil, err
+	}
+
+	slog.Info("reference import", "reviewId", reviewId, "parsed", summary.Parsed, "imported", summary.Imported, "skipped", summary.Skipped)
+	return summary, nil
+}
+
+func newReferenceFromRecord(record *citation.Record, reviewId uuid.UUID, userId uuid.UUID, source model.SourceDatabase) *model.Reference {
+	reference := model.NewReference(reviewId, userId, source)
+	reference.Title = record.Title
+	reference.Abstract = record.Abstract
+	reference.Authors = record.Authors
+	reference.Year = record.Year
+	reference.Journal = record.Journal
+	reference.Doi = record.Doi
+	reference.Keywords = record.Keywords
+	reference.Pmid = record.Pmid
+	if record.MeshTerms != nil {
+		reference.MeshTerms = record.MeshTerms
+	}
+	if record.PublicationTypes != nil {
+		reference.PublicationTypes = record.PublicationTypes
+	}
+	reference.EntryType = record.Type
+	reference.CitationKey = record.Key
+	if record.Raw != nil {
+		reference.RawMetadata = record.Raw
+	}
+	return reference
+}
+
+func recordFromReference(reference model.Reference) citation.Record {
+	return citation.Record{
+		Key:      reference.CitationKey,
+		Type:     reference.EntryType,
+		Title:    reference.Title,
+		Abstract: reference.Abstract,
+		Authors:  reference.Authors,
+		Year:     reference.Year,
+		Journal:  reference.Journal,
+		Doi:      reference.Doi,
+		Keywords: reference.Keywords,
+		Pmid:     reference.Pmid,
+		Raw:      reference.RawMetadata,
+	}
+}
+
+// Export writes the references of a review matching filter in the given
+// format.
+func (rs *ReferenceService) Export(reviewId uuid.UUID, filter model.ReferenceFilter, format citation.Format, w io.Writer) error {
+	references, err := rs.ReferenceRepo.FindAllByFilter(reviewId, filter, 0, 0)
+	if err != nil {
+		slog.Error("reference export", "error", err.Error())
+		return common.DbInternalError
+	}
+
+	records := make([]citation.Record, 0, len(references))
+	for _, reference := range references {
+		records = append(records, recordFromReference(reference))
+	}
+
+	switch format {
+	case citation.FormatBibtex:
+		return citation.WriteBibtex(w, records)
+	case citation.FormatCslJson:
+		return citation.WriteCslJson(w, records)
+	}
+	return ErrorUnsupportedFormat
+}
+
+func (rs *ReferenceService) FindAllByFilter(reviewId uuid.UUID, filter model.ReferenceFilter, limit int, offset int) ([]model.Reference, error) {
+	return rs.ReferenceRepo.FindAllByFilter(reviewId, filter, limit, offset)
+}
+
+func (rs *ReferenceService) CountByFilter(reviewId uuid.UUID, filter model.ReferenceFilter) (int, error) {
+	return rs.ReferenceRepo.CountByFilter(reviewId, filter)
+}
+
+func (rs *ReferenceService) FindById(id uuid.UUID, reviewId uuid.UUID) (*model.Reference, error) {
+	reference, err := rs.ReferenceRepo.FindById(id)
+	if err != nil || reference.ReviewId != reviewId {
+		return nil, ErrorReferenceNotFound
+	}
+	return reference, nil
+}
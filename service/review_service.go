@@ -23,6 +23,8 @@ var (
 	ErrorParseEndDate   = errors.New("end date must be in format YYYY-MM-DD")
 	ErrorReviewDate     = errors.New("end date must be after start date")
 	ErrorReviewNotFound = errors.New("review not found")
+	ErrorNotReviewer    = errors.New("user is not an active reviewer of the review")
+	ErrorNotOwner       = errors.New("only the owner of the review can do this")
 )
 
 func (s *ReviewService) Create(data form.ReviewCreateForm, userId uuid.UUID) (*model.Review, error) {
@@ -80,3 +82,83 @@ func (s *ReviewService) FindById(id uuid.UUID, userId uuid.UUID) (*model.Review,
 	}
 	return review, nil
 }
+
+func (s *ReviewService) FindReviewers(reviewId uuid.UUID) ([]model.Reviewer, error) {
+	reviewers, err := s.ReviewRepo.FindReviewers(reviewId)
+	if err != nil {
+		slog.Error("review reviewers", "error", err.Error())
+		return nil, err
+	}
+	return reviewers, nil
+}
+
+func (s *ReviewService) FindReviewer(reviewId uuid.UUID, userId uuid.UUID) (*model.Reviewer, error) {
+	reviewers, err := s.FindReviewers(reviewId)
+	if err != nil {
+		return nil, err
+	}
+	for _, reviewer := range reviewers {
+		if reviewer.UserId == userId && reviewer.Active {
+			return &reviewer, nil
+		}
+	}
+	return nil, ErrorNotReviewer
+}
+
+// SetAdjudicator gives the adjudicator role to a member of the review. The
+// previous adjudicator goes back to being a member; choosing the owner
+// removes the role so that the owner adjudicates again.
+func (s *ReviewService) SetAdjudicator(reviewId uuid.UUID, owner *model.Reviewer, reviewerId uuid.UUID) error {
+	if owner.ReviewerRole != model.ReviewerOwner {
+		return ErrorNotOwner
+	}
+
+	reviewers, err := s.FindReviewers(reviewId)
+	if err != nil {
+		return err
+	}
+
+	var target *model.Reviewer
+	for i := range reviewers {
+		if reviewers[i].Id == reviewerId && reviewers[i].Active {
+			target = &reviewers[i]
+		}
+	}
+	if target == nil {
+		return ErrorNotReviewer
+	}
+
+	tx := s.ReviewRepo.GetDB().MustBegin()
+	defer tx.Rollback()
+
+	for _, reviewer := range reviewers {
+		if reviewer.ReviewerRole != model.ReviewerAdjudicator || reviewer.Id == target.Id {
+			continue
+		}
+		reviewer.ReviewerRole = model.ReviewerMember
+		reviewer.UpdatedAt = time.Now()
+		if err := s.ReviewRepo.UpdateReviewerRole(&reviewer, tx); err != nil {
+			slog.Error("review set adjudicator", "error", err.Error())
+			return err
+		}
+	}
+
+	if target.ReviewerRole == model.ReviewerMember {
+		updated := *target
+		updated.ReviewerRole = model.ReviewerAdjudicator
+		updated.UpdatedAt = time.Now()
+		if err := s.ReviewRepo.UpdateReviewerRole(&updated, tx); err != nil {
+			slog.Error("review set adjudicator", "error", err.Error())
+			return err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		slog.Error("review set adjudicator", "error", err.Error())
+		return err
+	}
+	s.ReviewRepo.InvalidateReviewers(reviewId)
+
+	slog.Info("review set adjudicator", "result", "success", "reviewerId", reviewerId)
+	return nil
+}
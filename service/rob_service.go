@@ -0,0 +1,157 @@
+package service
+
+import (
+	"errors"
+	"github.com/google/uuid"
+	"golang.org/x/exp/slog"
+	"sci-review/common"
+	"sci-review/form"
+	"sci-review/model"
+	"sci-review/repo"
+	"sci-review/rob"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type RobService struct {
+	RobRepo          repo.RobRepo
+	ScreeningService *ScreeningService
+}
+
+func NewRobService(robRepo repo.RobRepo, screeningService *ScreeningService) *RobService {
+	return &RobService{RobRepo: robRepo, ScreeningService: screeningService}
+}
+
+var (
+	ErrorNotAssessable = errors.New("risk of bias is only assessed for studies included at full text")
+)
+
+// Studies returns the included studies with their assessments made with the
+// tool.
+func (rs *RobService) Studies(review *model.Review, tool rob.Tool) ([]model.RobStudy, error) {
+	included, err := rs.ScreeningService.Included(review)
+	if err != nil {
+		return nil, err
+	}
+
+	assessments, err := rs.RobRepo.FindAllByReviewId(review.Id, tool.Id)
+	if err != nil {
+		slog.Error("rob studies", "error", err.Error())
+		return nil, common.DbInternalError
+	}
+
+	byReference := make(map[uuid.UUID][]model.RobAssessment)
+	for _, assessment := range assessments {
+		byReference[assessment.ReferenceId] = append(byReference[assessment.ReferenceId], assessment)
+	}
+
+	studies := make([]model.RobStudy, len(included))
+	for i, reference := range included {
+		studies[i] = model.RobStudy{Reference: reference, Assessments: byReference[reference.Id]}
+		if studies[i].Assessments == nil {
+			studies[i].Assessments = []model.RobAssessment{}
+		}
+	}
+	return studies, nil
+}
+
+func (rs *RobService) FindStudy(review *model.Review, referenceId uuid.UUID) (*model.Reference, error) {
+	included, err := rs.ScreeningService.Included(review)
+	if err != nil {
+		return nil, err
+	}
+	for _, reference := range included {
+		if reference.Id == referenceId {
+			return &reference, nil
+		}
+	}
+	return nil, ErrorNotAssessable
+}
+
+// Find returns the assessment of the reviewer with the tool, or nil when
+// there is none.
+func (rs *RobService) Find(referenceId uuid.UUID, reviewer *model.Reviewer, tool rob.Tool) (*model.RobAssessment, error) {
+	assessment, err := rs.RobRepo.Find(referenceId, reviewer.Id, tool.Id)
+	if err != nil {
+		if errors.Is(err, repo.NotFoundInRepo) {
+			return nil, nil
+		}
+		slog.Error("rob find", "error", err.Error())
+		return nil, common.DbInternalError
+	}
+	return assessment, nil
+}
+
+// Save stores the answers of the reviewer with the judgements the tool
+// derives from them. Every question must have been answered.
+func (rs *RobService) Save(review *model.Review, reviewer *model.Reviewer, referenceId uuid.UUID, tool rob.Tool, answers rob.Answers, data form.RobAssessmentForm) (*model.RobAssessment, error) {
+	if _, err := rs.FindStudy(review, referenceId); err != nil {
+		return nil, err
+	}
+
+	assessment := model.NewRobAssessment(review.Id, referenceId, reviewer.Id, tool.Id)
+	result := tool.Assess(answers)
+	for id, answer := range answers {
+		assessment.Answers[id] = string(answer)
+	}
+	for i, domain := range tool.Domains {
+		assessment.Judgements[domain.Id] = string(result.Domains[i])
+	}
+	assessment.Overall = string(result.Overall)
+	if data.Weight > 0 {
+		assessment.Weight = data.Weight
+	}
+	assessment.Note = data.Note
+	assessment.UpdatedAt = time.Now()
+
+	if err := rs.RobRepo.Save(assessment); err != nil {
+		slog.Error("rob save", "error", err.Error())
+		return nil, common.DbInternalError
+	}
+
+	slog.Info("rob save", "result", "success", "assessment", assessment)
+	return assessment, nil
+}
+
+// studyLabel names a study after its first author and year, as in the
+// summary charts of reviews.
+func studyLabel(reference model.Reference) string {
+	label := reference.Title
+	if len(reference.Authors) > 0 {
+		author, _, _ := strings.Cut(reference.Authors[0], ",")
+		label = strings.TrimSpace(author)
+	}
+	if reference.Year > 0 {
+		label += " " + strconv.Itoa(reference.Year)
+	}
+	return label
+}
+
+// Summary returns a row per assessed study for the charts. When several
+// reviewers assessed a study, the most recently updated assessment is shown.
+func (rs *RobService) Summary(review *model.Review, tool rob.Tool) ([]rob.Row, error) {
+	studies, err := rs.Studies(review, tool)
+	if err != nil {
+		return nil, err
+	}
+
+	rows := []rob.Row{}
+	for _, study := range studies {
+		if len(study.Assessments) == 0 {
+			continue
+		}
+		assessment := study.Assessments[0]
+		row := rob.Row{
+			Study:   studyLabel(study.Reference),
+			Weight:  assessment.Weight,
+			Domains: make([]rob.Judgement, len(tool.Domains)),
+			Overall: rob.Judgement(assessment.Overall),
+		}
+		for i, domain := range tool.Domains {
+			row.Domains[i] = rob.Judgement(assessment.Judgements[domain.Id])
+		}
+		rows = append(rows, row)
+	}
+	return rows, nil
+}
@@ -0,0 +1,801 @@
+package service
+
+import (
+	"errors"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"golang.org/x/exp/slog"
+	"sci-review/agreement"
+	"sci-review/common"
+	"sci-review/form"
+	"sci-review/model"
+	"sci-review/repo"
+)
+
+type ScreeningService struct {
+	ScreeningRepo       repo.ScreeningRepo
+	ReferenceRepo       repo.ReferenceRepo
+	ReviewRepo          repo.ReviewRepo
+	ExclusionReasonRepo repo.ExclusionReasonRepo
+}
+
+func NewScreeningService(screeningRepo repo.ScreeningRepo, referenceRepo repo.ReferenceRepo, reviewRepo repo.ReviewRepo, exclusionReasonRepo repo.ExclusionReasonRepo) *ScreeningService {
+	return &ScreeningService{
+		ScreeningRepo:       screeningRepo,
+		ReferenceRepo:       referenceRepo,
+		ReviewRepo:          reviewRepo,
+		ExclusionReasonRepo: exclusionReasonRepo,
+	}
+}
+
+var (
+	ErrorNothingToScreen    = errors.New("there are no records left to screen")
+	ErrorReferenceDuplicate = errors.New("the record was merged into another one and is not screened")
+	ErrorAdjudicated        = errors.New("the record was adjudicated and its decision is final")
+	ErrorNotAdjudicator     = errors.New("only the adjudicator of the review can resolve conflicts")
+	ErrorNoConflict         = errors.New("the record has no conflict to resolve")
+	ErrorNotEligible        = errors.New("only retrieved records included at title/abstract screening go to full text")
+	ErrorAssessed           = errors.New("the record was already assessed at full text")
+	ErrorReasonRequired     = errors.New("excluding a record at this stage requires an exclusion reason")
+	ErrorScreenersComplete  = errors.New("the record already has the decisions of every screener it requires")
+)
+
+// sought returns the unique records included at title/abstract, whose full
+// text has to be retrieved.
+func (ss *ScreeningService) sought(review *model.Review) ([]model.Reference, error) {
+	statuses, err := ss.Statuses(review, model.StageTitleAbstract)
+	if err != nil {
+		return nil, err
+	}
+
+	references, err := ss.ReferenceRepo.FindUniqueByReviewId(review.Id)
+	if err != nil {
+		slog.Error("screening sought", "error", err.Error())
+		return nil, common.DbInternalError
+	}
+
+	sought := []model.Reference{}
+	for _, reference := range references {
+		if statuses[reference.Id] == model.StatusIncluded {
+			sought = append(sought, reference)
+		}
+	}
+	return sought, nil
+}
+
+// eligible returns the records that can be screened in the stage, or nil
+// when every unique record can. Full text only takes the records included
+// at title/abstract whose full text was retrieved.
+func (ss *ScreeningService) eligible(review *model.Review, stage model.ScreeningStage) ([]uuid.UUID, error) {
+	if stage != model.StageFullText {
+		return nil, nil
+	}
+
+	sought, err := ss.sought(review)
+	if err != nil {
+		return nil, err
+	}
+
+	ids := []uuid.UUID{}
+	for _, reference := range sought {
+		if !reference.NotRetrieved {
+			ids = append(ids, reference.Id)
+		}
+	}
+	return ids, nil
+}
+
+// NotRetrieved returns the records included at title/abstract whose full
+// text could not be retrieved.
+func (ss *ScreeningService) NotRetrieved(review *model.Review) ([]model.Reference, error) {
+	sought, err := ss.sought(review)
+	if err != nil {
+		return nil, err
+	}
+
+	notRetrieved := []model.Reference{}
+	for _, reference := range sought {
+		if reference.NotRetrieved {
+			notRetrieved = append(notRetrieved, reference)
+		}
+	}
+	return notRetrieved, nil
+}
+
+// SetNotRetrieved records whether the full text of a record included at
+// title/abstract could be retrieved. A record already assessed at full text
+// cannot be marked as not retrieved.
+func (ss *ScreeningService) SetNotRetrieved(review *model.Review, referenceId uuid.UUID, notRetrieved bool) error {
+	sought, err := ss.sought(review)
+	if err != nil {
+		return err
+	}
+
+	var reference *model.Reference
+	for i := range sought {
+		if sought[i].Id == referenceId {
+			reference = &sought[i]
+		}
+	}
+	if reference == nil {
+		return ErrorNotEligible
+	}
+
+	if notRetrieved {
+		decisions, err := ss.ScreeningRepo.FindDecisionsByReference(referenceId, model.StageFullText)
+		if err != nil {
+			slog.Error("screening not retrieved", "error", err.Error())
+			return common.DbInternalError
+		}
+		if len(decisions) > 0 {
+			return ErrorAssessed
+		}
+	}
+
+	if err := ss.ReferenceRepo.UpdateNotRetrieved(referenceId, notRetrieved); err != nil {
+		slog.Error("screening not retrieved", "error", err.Error())
+		return common.DbInternalError
+	}
+
+	slog.Info("screening not retrieved", "result", "success", "referenceId", referenceId, "notRetrieved", notRetrieved)
+	return nil
+}
+
+// Next returns the next record the reviewer has to screen. Records of a
+// review that needs several screeners are only handed out until they have
+// enough decisions.
+func (ss *ScreeningService) Next(review *model.Review, reviewer *model.Reviewer, stage model.ScreeningStage) (*model.Reference, error) {
+	maxScreeners := 0
+	if required := review.ReviewType.RequiredScreeners(); required > 1 {
+		maxScreeners = required
+	}
+
+	eligible, err := ss.eligible(review, stage)
+	if err != nil {
+		return nil, err
+	}
+
+	reference, err := ss.ScreeningRepo.FindNextUnscreened(review.Id, reviewer.Id, stage, maxScreeners, eligible)
+	if errors.Is(err, repo.NotFoundInRepo) {
+		return nil, ErrorNothingToScreen
+	}
+	if err != nil {
+		slog.Error("screening next", "error", err.Error())
+		return nil, common.DbInternalError
+	}
+	return reference, nil
+}
+
+// FindReference returns a record of the review that can be screened.
+func (ss *ScreeningService) FindReference(referenceId uuid.UUID, reviewId uuid.UUID) (*model.Reference, error) {
+	reference, err := ss.ReferenceRepo.FindById(referenceId)
+	if err != nil || reference.ReviewId != reviewId {
+		return nil, ErrorReferenceNotFound
+	}
+	if reference.IsDuplicate() {
+		return nil, ErrorReferenceDuplicate
+	}
+	return reference, nil
+}
+
+// FindScreenable returns a record of the review that can be screened in the
+// stage.
+func (ss *ScreeningService) FindScreenable(review *model.Review, referenceId uuid.UUID, stage model.ScreeningStage) (*model.Reference, error) {
+	reference, err := ss.FindReference(referenceId, review.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	eligible, err := ss.eligible(review, stage)
+	if err != nil {
+		return nil, err
+	}
+	if eligible == nil {
+		return reference, nil
+	}
+	for _, id := range eligible {
+		if id == reference.Id {
+			return reference, nil
+		}
+	}
+	return nil, ErrorNotEligible
+}
+
+// exclusionReason checks the reason given with a decision. Only exclusions
+// keep a reason, and some stages require one.
+func (ss *ScreeningService) exclusionReason(reviewId uuid.UUID, stage model.ScreeningStage, decision model.Decision, reasonId string) (uuid.NullUUID, error) {
+	if decision != model.DecisionExclude {
+		return uuid.NullUUID{}, nil
+	}
+	if reasonId == "" {
+		if stage.RequiresReason() {
+			return uuid.NullUUID{}, ErrorReasonRequired
+		}
+		return uuid.NullUUID{}, nil
+	}
+
+	id, err := uuid.Parse(reasonId)
+	if err != nil {
+		return uuid.NullUUID{}, ErrorReasonNotFound
+	}
+	reason, err := ss.ExclusionReasonRepo.FindById(id)
+	if err != nil || reason.ReviewId != reviewId {
+		return uuid.NullUUID{}, ErrorReasonNotFound
+	}
+	return uuid.NullUUID{UUID: reason.Id, Valid: true}, nil
+}
+
+// FindDecision returns the reviewer's decision on a record, or nil when the
+// record was not screened by them yet.
+func (ss *ScreeningService) FindDecision(referenceId uuid.UUID, reviewer *model.Reviewer, stage model.ScreeningStage) (*model.ScreeningDecision, error) {
+	decision, err := ss.ScreeningRepo.FindDecision(referenceId, reviewer.Id, stage)
+	if errors.Is(err, repo.NotFoundInRepo) {
+		return nil, nil
+	}
+	if err != nil {
+		slog.Error("screening decision", "error", err.Error())
+		return nil, common.DbInternalError
+	}
+	return decision, nil
+}
+
+// FindAdjudication returns the adjudication of a record, or nil when there
+// is none.
+func (ss *ScreeningService) FindAdjudication(referenceId uuid.UUID, stage model.ScreeningStage) (*model.ScreeningAdjudication, error) {
+	adjudication, err := ss.ScreeningRepo.FindAdjudication(referenceId, stage)
+	if errors.Is(err, repo.NotFoundInRepo) {
+		return nil, nil
+	}
+	if err != nil {
+		slog.Error("screening adjudication", "error", err.Error())
+		return nil, common.DbInternalError
+	}
+	return adjudication, nil
+}
+
+func decisionsOf(decisions []model.ScreeningDecision) []model.Decision {
+	values := make([]model.Decision, len(decisions))
+	for i, decision := range decisions {
+		values[i] = decision.Decision
+	}
+	return values
+}
+
+func deriveStatus(review *model.Review, decisions []model.ScreeningDecision, adjudication *model.ScreeningAdjudication) model.ScreeningStatus {
+	var final *model.Decision
+	if adjudication != nil {
+		final = &adjudication.Decision
+	}
+	return model.DeriveScreeningStatus(decisionsOf(decisions), review.ReviewType.RequiredScreeners(), final)
+}
+
+// Decide records the reviewer's decision. A decision that turns the record
+// into a conflict is logged in its history as well.
+func (ss *ScreeningService) Decide(review *model.Review, reviewer *model.Reviewer, referenceId uuid.UUID, data form.ScreeningDecisionForm) (*model.ScreeningDecision, error) {
+	if _, err := ss.FindScreenable(review, referenceId, data.Stage); err != nil {
+		return nil, err
+	}
+
+	reasonId, err := ss.exclusionReason(review.Id, data.Stage, data.Decision, data.ExclusionReasonId)
+	if err != nil {
+		return nil, err
+	}
+
+	adjudication, err := ss.FindAdjudication(referenceId, data.Stage)
+	if err != nil {
+		return nil, err
+	}
+	if adjudication != nil {
+		return nil, ErrorAdjudicated
+	}
+
+	decisions, err := ss.ScreeningRepo.FindDecisionsByReference(referenceId, data.Stage)
+	if err != nil {
+		slog.Error("screening decide", "error", err.Error())
+		return nil, common.DbInternalError
+	}
+	if !model.AcceptsDecision(decisions, reviewer.Id, review.ReviewType.RequiredScreeners()) {
+		return nil, ErrorScreenersComplete
+	}
+	before := deriveStatus(review, decisions, nil)
+
+	decision := model.NewScreeningDecision(review.Id, referenceId, reviewer.Id, data.Stage, data.Decision, reasonId, data.Note)
+	action := model.ActionDecided
+	for i := range decisions {
+		if decisions[i].ReviewerId == reviewer.Id {
+			decisions[i].Decision = decision.Decision
+			action = model.ActionRevised
+		}
+	}
+	if action == model.ActionDecided {
+		decisions = append(decisions, *decision)
+	}
+	after := deriveStatus(review, decisions, nil)
+
+	tx := ss.ScreeningRepo.GetDB().MustBegin()
+	defer tx.Rollback()
+
+	if err := ss.ScreeningRepo.SaveDecision(decision, tx); err != nil {
+		slog.Error("screening decide", "error", err.Error())
+		return nil, common.DbInternalError
+	}
+
+	events := []*model.ScreeningEvent{
+		model.NewScreeningEvent(review.Id, referenceId, reviewer.Id, data.Stage, action, decision.Decision, reasonId, decision.Note),
+	}
+	if after == model.StatusConflict && before != model.StatusConflict {
+		events = append(events, model.NewScreeningEvent(review.Id, referenceId, reviewer.Id, data.Stage, model.ActionConflict, decision.Decision, uuid.NullUUID{}, ""))
+	}
+	if err := ss.addEvents(events, tx); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		slog.Error("screening decide", "error", err.Error())
+		return nil, common.DbInternalError
+	}
+
+	slog.Info("screening decide", "result", "success", "decision", decision, "status", after)
+	return decision, nil
+}
+
+func (ss *ScreeningService) addEvents(events []*model.ScreeningEvent, tx *sqlx.Tx) error {
+	for _, event := range events {
+		if err := ss.ScreeningRepo.AddEvent(event, tx); err != nil {
+			slog.Error("screening history", "error", err.Error())
+			return common.DbInternalError
+		}
+	}
+	return nil
+}
+
+// IsAdjudicator tells whether the reviewer resolves the conflicts of the
+// review.
+func (ss *ScreeningService) IsAdjudicator(reviewId uuid.UUID, reviewer *model.Reviewer) (bool, error) {
+	reviewers, err := ss.ReviewRepo.FindReviewers(reviewId)
+	if err != nil {
+		slog.Error("screening adjudicator", "error", err.Error())
+		return false, common.DbInternalError
+	}
+	adjudicator := model.Adjudicator(reviewers)
+	return adjudicator != nil && adjudicator.Id == reviewer.Id, nil
+}
+
+// Adjudicate records the adjudicator's final decision on a conflicting
+// record. The adjudicator may revise it later on.
+func (ss *ScreeningService) Adjudicate(review *model.Review, reviewer *model.Reviewer, referenceId uuid.UUID, data form.ScreeningAdjudicationForm) (*model.ScreeningAdjudication, error) {
+	isAdjudicator, err := ss.IsAdjudicator(review.Id, reviewer)
+	if err != nil {
+		return nil, err
+	}
+	if !isAdjudicator {
+		return nil, ErrorNotAdjudicator
+	}
+
+	record, err := ss.FindRecord(review, referenceId, data.Stage)
+	if err != nil {
+		return nil, err
+	}
+	if record.Adjudication == nil && record.Status != model.StatusConflict {
+		return nil, ErrorNoConflict
+	}
+
+	reasonId, err := ss.exclusionReason(review.Id, data.Stage, data.Decision, data.ExclusionReasonId)
+	if err != nil {
+		return nil, err
+	}
+
+	adjudication := model.NewScreeningAdjudication(review.Id, referenceId, reviewer.Id, data.Stage, data.Decision, reasonId, data.Note)
+
+	tx := ss.ScreeningRepo.GetDB().MustBegin()
+	defer tx.Rollback()
+
+	if err := ss.ScreeningRepo.SaveAdjudication(adjudication, tx); err != nil {
+		slog.Error("screening adjudicate", "error", err.Error())
+		return nil, common.DbInternalError
+	}
+
+	event := model.NewScreeningEvent(review.Id, referenceId, reviewer.Id, data.Stage, model.ActionAdjudicated, adjudication.Decision, reasonId, adjudication.Note)
+	if err := ss.addEvents([]*model.ScreeningEvent{event}, tx); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		slog.Error("screening adjudicate", "error", err.Error())
+		return nil, common.DbInternalError
+	}
+
+	slog.Info("screening adjudicate", "result", "success", "adjudication", adjudication)
+	return adjudication, nil
+}
+
+// FindRecord returns a record with every decision taken on it in the stage
+// and its derived status.
+func (ss *ScreeningService) FindRecord(review *model.Review, referenceId uuid.UUID, stage model.ScreeningStage) (*model.ScreeningRecord, error) {
+	reference, err := ss.FindScreenable(review, referenceId, stage)
+	if err != nil {
+		return nil, err
+	}
+
+	decisions, err := ss.ScreeningRepo.FindDecisionsByReference(referenceId, stage)
+	if err != nil {
+		slog.Error("screening record", "error", err.Error())
+		return nil, common.DbInternalError
+	}
+
+	adjudication, err := ss.FindAdjudication(referenceId, stage)
+	if err != nil {
+		return nil, err
+	}
+
+	return &model.ScreeningRecord{
+		Reference:    *reference,
+		Status:       deriveStatus(review, decisions, adjudication),
+		Decisions:    decisions,
+		Adjudication: adjudication,
+	}, nil
+}
+
+// stageDecisions holds what was decided on the records of a review in one
+// stage, by record.
+type stageDecisions struct {
+	decisions     map[uuid.UUID][]model.ScreeningDecision
+	adjudications map[uuid.UUID]*model.ScreeningAdjudication
+}
+
+func (ss *ScreeningService) loadStage(reviewId uuid.UUID, stage model.ScreeningStage) (*stageDecisions, error) {
+	decisions, err := ss.ScreeningRepo.FindDecisions(reviewId, stage)
+	if err != nil {
+		slog.Error("screening stage", "error", err.Error())
+		return nil, common.DbInternalError
+	}
+
+	adjudications, err := ss.ScreeningRepo.FindAdjudications(reviewId, stage)
+	if err != nil {
+		slog.Error("screening stage", "error", err.Error())
+		return nil, common.DbInternalError
+	}
+
+	loaded := &stageDecisions{
+		decisions:     make(map[uuid.UUID][]model.ScreeningDecision),
+		adjudications: make(map[uuid.UUID]*model.ScreeningAdjudication),
+	}
+	for _, decision := range decisions {
+		loaded.decisions[decision.ReferenceId] = append(loaded.decisions[decision.ReferenceId], decision)
+	}
+	for i := range adjudications {
+		loaded.adjudications[adjudications[i].ReferenceId] = &adjudications[i]
+	}
+	return loaded, nil
+}
+
+func (sd *stageDecisions) statuses(review *model.Review) map[uuid.UUID]model.ScreeningStatus {
+	statuses := make(map[uuid.UUID]model.ScreeningStatus)
+	for referenceId, decisions := range sd.decisions {
+		statuses[referenceId] = deriveStatus(review, decisions, sd.adjudications[referenceId])
+	}
+	for referenceId, adjudication := range sd.adjudications {
+		statuses[referenceId] = deriveStatus(review, sd.decisions[referenceId], adjudication)
+	}
+	return statuses
+}
+
+// reason returns the reason a record was excluded for: the adjudicator's,
+// or else the one most of its screeners gave, the earliest on a tie.
+func (sd *stageDecisions) reason(referenceId uuid.UUID) uuid.NullUUID {
+	if adjudication, found := sd.adjudications[referenceId]; found {
+		return adjudication.ExclusionReasonId
+	}
+
+	counts := make(map[uuid.UUID]int)
+	best := uuid.NullUUID{}
+	for _, decision := range sd.decisions[referenceId] {
+		if !decision.ExclusionReasonId.Valid {
+			continue
+		}
+		id := decision.ExclusionReasonId.UUID
+		counts[id]++
+		if !best.Valid || counts[id] > counts[best.UUID] {
+			best = decision.ExclusionReasonId
+		}
+	}
+	return best
+}
+
+// Statuses derives the status of every record of the review that has been
+// screened at least once in the stage. Records missing from the map are
+// pending.
+func (ss *ScreeningService) Statuses(review *model.Review, stage model.ScreeningStage) (map[uuid.UUID]model.ScreeningStatus, error) {
+	loaded, err := ss.loadStage(review.Id, stage)
+	if err != nil {
+		return nil, err
+	}
+	return loaded.statuses(review), nil
+}
+
+// screenable returns the unique records of the review that go through the
+// stage, the oldest first.
+func (ss *ScreeningService) screenable(review *model.Review, stage model.ScreeningStage) ([]model.Reference, error) {
+	references, err := ss.ReferenceRepo.FindUniqueByReviewId(review.Id)
+	if err != nil {
+		slog.Error("screening records", "error", err.Error())
+		return nil, common.DbInternalError
+	}
+
+	eligible, err := ss.eligible(review, stage)
+	if err != nil {
+		return nil, err
+	}
+	if eligible == nil {
+		return references, nil
+	}
+
+	included := make(map[uuid.UUID]bool, len(eligible))
+	for _, id := range eligible {
+		included[id] = true
+	}
+	screenable := []model.Reference{}
+	for _, reference := range references {
+		if included[reference.Id] {
+			screenable = append(screenable, reference)
+		}
+	}
+	return screenable, nil
+}
+
+// StatusCounts counts the records of the stage by status.
+func (ss *ScreeningService) StatusCounts(review *model.Review, stage model.ScreeningStage) (map[model.ScreeningStatus]int, error) {
+	references, err := ss.screenable(review, stage)
+	if err != nil {
+		return nil, err
+	}
+
+	statuses, err := ss.Statuses(review, stage)
+	if err != nil {
+		return nil, err
+	}
+
+	counts := make(map[model.ScreeningStatus]int)
+	for _, status := range model.ScreeningStatuses {
+		counts[status] = 0
+	}
+	for _, reference := range references {
+		status, found := statuses[reference.Id]
+		if !found {
+			status = model.StatusPending
+		}
+		counts[status]++
+	}
+	return counts, nil
+}
+
+// Conflicts returns the queue of records waiting for the adjudicator, the
+// oldest first.
+func (ss *ScreeningService) Conflicts(review *model.Review, stage model.ScreeningStage) ([]model.Reference, error) {
+	references, err := ss.screenable(review, stage)
+	if err != nil {
+		return nil, err
+	}
+
+	statuses, err := ss.Statuses(review, stage)
+	if err != nil {
+		return nil, err
+	}
+
+	conflicts := []model.Reference{}
+	for _, reference := range references {
+		if statuses[reference.Id] == model.StatusConflict {
+			conflicts = append(conflicts, reference)
+		}
+	}
+	return conflicts, nil
+}
+
+// Included returns the studies included at full text, which go on to data
+// extraction.
+func (ss *ScreeningService) Included(review *model.Review) ([]model.Reference, error) {
+	references, err := ss.screenable(review, model.StageFullText)
+	if err != nil {
+		return nil, err
+	}
+
+	statuses, err := ss.Statuses(review, model.StageFullText)
+	if err != nil {
+		return nil, err
+	}
+
+	included := []model.Reference{}
+	for _, reference := range references {
+		if statuses[reference.Id] == model.StatusIncluded {
+			included = append(included, reference)
+		}
+	}
+	return included, nil
+}
+
+// ReasonCounts counts, for every exclusion reason of the review, the
+// decisions that gave it in the stage and the excluded records it is the
+// reason of.
+func (ss *ScreeningService) ReasonCounts(review *model.Review, stage model.ScreeningStage) ([]model.ReasonCount, error) {
+	reasons, err := ss.ExclusionReasonRepo.FindAllByReviewId(review.Id)
+	if err != nil {
+		slog.Error("screening reason counts", "error", err.Error())
+		return nil, common.DbInternalError
+	}
+
+	decisionCounts, err := ss.ExclusionReasonRepo.CountByStage(review.Id, stage)
+	if err != nil {
+		slog.Error("screening reason counts", "error", err.Error())
+		return nil, common.DbInternalError
+	}
+
+	references, err := ss.screenable(review, stage)
+	if err != nil {
+		return nil, err
+	}
+
+	loaded, err := ss.loadStage(review.Id, stage)
+	if err != nil {
+		return nil, err
+	}
+	statuses := loaded.statuses(review)
+
+	recordCounts := make(map[uuid.UUID]int)
+	for _, reference := range references {
+		if statuses[reference.Id] != model.StatusExcluded {
+			continue
+		}
+		if reason := loaded.reason(reference.Id); reason.Valid {
+			recordCounts[reason.UUID]++
+		}
+	}
+
+	counts := make([]model.ReasonCount, len(reasons))
+	for i, reason := range reasons {
+		counts[i] = model.ReasonCount{
+			Reason:    reason,
+			Decisions: decisionCounts[reason.Id],
+			Records:   recordCounts[reason.Id],
+		}
+	}
+	return counts, nil
+}
+
+func (ss *ScreeningService) History(referenceId uuid.UUID) ([]model.ScreeningEvent, error) {
+	events, err := ss.ScreeningRepo.FindHistory(referenceId)
+	if err != nil {
+		slog.Error("screening history", "error", err.Error())
+		return nil, common.DbInternalError
+	}
+	return events, nil
+}
+
+func (ss *ScreeningService) Progress(review *model.Review, stage model.ScreeningStage) ([]model.ReviewerProgress, error) {
+	eligible, err := ss.eligible(review, stage)
+	if err != nil {
+		return nil, err
+	}
+
+	total, err := ss.ScreeningRepo.CountScreenable(review.Id, eligible)
+	if err != nil {
+		slog.Error("screening progress", "error", err.Error())
+		return nil, common.DbInternalError
+	}
+
+	progress, err := ss.ScreeningRepo.Progress(review.Id, stage)
+	if err != nil {
+		slog.Error("screening progress", "error", err.Error())
+		return nil, common.DbInternalError
+	}
+	for i := range progress {
+		progress[i].Total = total
+	}
+	return progress, nil
+}
+
+// Agreement measures the agreement between the screeners of the review in
+// every stage, on the unique records screened by more than one of them.
+func (ss *ScreeningService) Agreement(review *model.Review) ([]model.ScreeningAgreement, error) {
+	references, err := ss.ReferenceRepo.FindUniqueByReviewId(review.Id)
+	if err != nil {
+		slog.Error("screening agreement", "error", err.Error())
+		return nil, common.DbInternalError
+	}
+	unique := make(map[uuid.UUID]bool, len(references))
+	for _, reference := range references {
+		unique[reference.Id] = true
+	}
+
+	result := make([]model.ScreeningAgreement, 0, len(model.ScreeningStages))
+	for _, stage := range model.ScreeningStages {
+		reviewers, err := ss.Progress(review, stage)
+		if err != nil {
+			return nil, err
+		}
+
+		decisions, err := ss.ScreeningRepo.FindDecisions(review.Id, stage)
+		if err != nil {
+			slog.Error("screening agreement", "error", err.Error())
+			return nil, common.DbInternalError
+		}
+
+		ratings := make(map[uuid.UUID]map[uuid.UUID]string)
+		order := []uuid.UUID{}
+		for _, decision := range decisions {
+			if !unique[decision.ReferenceId] {
+				continue
+			}
+			if ratings[decision.ReferenceId] == nil {
+				ratings[decision.ReferenceId] = make(map[uuid.UUID]string)
+				order = append(order, decision.ReferenceId)
+			}
+			ratings[decision.ReferenceId][decision.ReviewerId] = string(decision.Decision)
+		}
+
+		result = append(result, stageAgreement(stage, reviewers, ratings, order))
+	}
+	return result, nil
+}
+
+func stageAgreement(stage model.ScreeningStage, reviewers []model.ReviewerProgress, ratings map[uuid.UUID]map[uuid.UUID]string, order []uuid.UUID) model.ScreeningAgreement {
+	stageAgreement := model.ScreeningAgreement{Stage: stage, Pairs: []model.RaterAgreement{}}
+
+	all := [][]string{}
+	for _, referenceId := range order {
+		item := []string{}
+		for _, reviewer := range reviewers {
+			if decision, found := ratings[referenceId][reviewer.ReviewerId]; found {
+				item = append(item, decision)
+			}
+		}
+		if len(item) >= 2 {
+			all = append(all, item)
+		}
+	}
+	stageAgreement.Items = len(all)
+	stageAgreement.Percent = agreement.Percent(all) * 100
+
+	for i := 0; i < len(reviewers); i++ {
+		for j := i + 1; j < len(reviewers); j++ {
+			first, second := []string{}, []string{}
+			for _, referenceId := range order {
+				a, foundA := ratings[referenceId][reviewers[i].ReviewerId]
+				b, foundB := ratings[referenceId][reviewers[j].ReviewerId]
+				if foundA && foundB {
+					first = append(first, a)
+					second = append(second, b)
+				}
+			}
+			if len(first) == 0 {
+				continue
+			}
+			pair := model.RaterAgreement{
+				FirstId:    reviewers[i].ReviewerId,
+				FirstName:  reviewers[i].Name,
+				SecondId:   reviewers[j].ReviewerId,
+				SecondName: reviewers[j].Name,
+				Items:      len(first),
+			}
+			pairItems := make([][]string, len(first))
+			for k := range first {
+				pairItems[k] = []string{first[k], second[k]}
+			}
+			pair.Percent = agreement.Percent(pairItems) * 100
+			if kappa, err := agreement.Cohen(first, second); err == nil {
+				pair.Kappa = kappa
+			}
+			stageAgreement.Pairs = append(stageAgreement.Pairs, pair)
+		}
+	}
+
+	if len(reviewers) >= 3 {
+		group := agreement.LargestGroup(all)
+		if len(group) > 0 {
+			stageAgreement.Raters = len(group[0])
+		}
+		if kappa, err := agreement.Fleiss(group); err == nil {
+			stageAgreement.Fleiss = kappa
+		}
+	}
+	return stageAgreement
+}
@@ -0,0 +1,110 @@
+package test
+
+import (
+	"math"
+	"sci-review/agreement"
+	"testing"
+)
+
+func repeat(first string, second string, times int, a []string, b []string) ([]string, []string) {
+	for i := 0; i < times; i++ {
+		a = append(a, first)
+		b = append(b, second)
+	}
+	return a, b
+}
+
+func TestCohen(t *testing.T) {
+	var a, b []string
+	a, b = repeat("yes", "yes", 20, a, b)
+	a, b = repeat("yes", "no", 5, a, b)
+	a, b = repeat("no", "yes", 10, a, b)
+	a, b = repeat("no", "no", 15, a, b)
+
+	estimate, err := agreement.Cohen(a, b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if math.Abs(estimate.Value-0.4) > 1e-9 {
+		t.Errorf("actual kappa %f, expect 0.4", estimate.Value)
+	}
+	if estimate.StdErr <= 0 || estimate.Lower >= estimate.Value || estimate.Upper <= estimate.Value {
+		t.Errorf("actual interval %+v", estimate)
+	}
+
+	if _, err := agreement.Cohen([]string{"yes", "yes"}, []string{"yes", "yes"}); err != agreement.ErrorUndefined {
+		t.Errorf("actual %v, expect undefined", err)
+	}
+}
+
+func TestFleiss(t *testing.T) {
+	table := [][]int{
+		{0, 0, 0, 0, 14},
+		{0, 2, 6, 4, 2},
+		{0, 0, 3, 5, 6},
+		{0, 3, 9, 2, 0},
+		{2, 2, 8, 1, 1},
+		{7, 7, 0, 0, 0},
+		{3, 2, 6, 3, 0},
+		{2, 5, 3, 2, 2},
+		{6, 5, 2, 1, 0},
+		{0, 2, 2, 3, 7},
+	}
+	items := make([][]string, len(table))
+	for i, row := range table {
+		for j, count := range row {
+			for k := 0; k < count; k++ {
+				items[i] = append(items[i], string(rune('a'+j)))
+			}
+		}
+	}
+
+	estimate, err := agreement.Fleiss(items)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if math.Abs(estimate.Value-0.2099) > 1e-3 {
+		t.Errorf("actual kappa %f, expect 0.210", estimate.Value)
+	}
+	if estimate.StdErr <= 0 {
+		t.Errorf("actual standard error %f", estimate.StdErr)
+	}
+}
+
+func TestPercent(t *testing.T) {
+	items := [][]string{{"a", "a"}, {"a", "b"}, {"b", "b", "b"}, {"a"}}
+	if actual := agreement.Percent(items); math.Abs(actual-2.0/3) > 1e-9 {
+		t.Errorf("actual %f, expect 0.667", actual)
+	}
+}
+
+func TestFleissTwoOfThreeReviewers(t *testing.T) {
+	// Three reviewers, each record screened by two of them.
+	items := [][]string{
+		{"include", "include"}, {"exclude", "exclude"}, {"include", "exclude"},
+		{"exclude", "exclude"}, {"include", "include"}, {"exclude", "include"},
+		{"exclude", "exclude"}, {"include", "include"}, {"exclude"},
+	}
+
+	group := agreement.LargestGroup(items)
+	if len(group) != 8 {
+		t.Fatalf("actual %d records, expect 8", len(group))
+	}
+	estimate, err := agreement.Fleiss(group)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if math.Abs(estimate.Value-0.5) > 1e-9 {
+		t.Errorf("actual kappa %f, expect 0.5", estimate.Value)
+	}
+}
+
+func TestLargestGroup(t *testing.T) {
+	items := [][]string{{"a", "a"}, {"a", "b", "b"}, {"b", "b", "b"}, {"a", "b"}, {"a"}}
+	if group := agreement.LargestGroup(items); len(group) != 2 || len(group[0]) != 3 {
+		t.Errorf("actual %v, expect the two records rated three times", group)
+	}
+	if group := agreement.LargestGroup([][]string{{"a"}}); len(group) != 0 {
+		t.Errorf("actual %v, expect no group", group)
+	}
+}
@@ -0,0 +1,130 @@
+package test
+
+import (
+	"math"
+	"os"
+	"regexp"
+	"sci-review/checklist"
+	"sci-review/model"
+	"strings"
+	"testing"
+)
+
+const definitionYaml = `
+name: Local appraisal tool
+options:
+  - value: "Yes"
+    score: 2
+  - value: "No"
+    score: 0
+  - value: N/A
+    score: 0
+    notApplicable: true
+questions:
+  - id: q1
+    text: Were the aims stated?
+  - id: q2
+    text: Was the sample adequate?
+  - id: q3
+    section: Analysis
+    text: How many analyses were pre-specified?
+    options:
+      - value: All
+        score: 3
+      - value: Some
+        score: 1
+      - value: None
+        score: 0
+scoring:
+  basis: percent
+  ratings:
+    - min: 0
+      label: Low
+    - min: 60
+      label: High
+`
+
+func TestParse(t *testing.T) {
+	definition, errs := checklist.Parse(definitionYaml)
+	if len(errs) > 0 {
+		t.Fatalf("actual %v", errs)
+	}
+	if len(definition.Questions) != 3 || !definition.Options[2].NotApplicable {
+		t.Errorf("actual %+v", definition)
+	}
+
+	definition, errs = checklist.Parse(`{"name": "JSON tool", "questions": [{"id": "1", "text": "Q", "options": [{"value": "Y", "score": 1}, {"value": "N", "score": 0}]}]}`)
+	if len(errs) > 0 || definition.Scoring.Basis != model.ScoringPoints {
+		t.Errorf("actual %+v %v", definition, errs)
+	}
+
+	_, errs = checklist.Parse(`{"name": "", "questions": [{"id": "1", "text": "Q", "options": [{"value": "Y", "score": -1}]}, {"id": "1", "text": ""}], "scoring": {"basis": "stars"}}`)
+	fields := []string{}
+	for _, err := range errs {
+		fields = append(fields, err.Field)
+	}
+	expect := "name questions[0].options[0].score questions[0].options questions[1].id questions[1].text questions[1].options scoring.basis"
+	if strings.Join(fields, " ") != expect {
+		t.Errorf("actual %v, expect %s", fields, expect)
+	}
+	if message := errs[2].Error; message != "questions[0].options must have at least 2 items" {
+		t.Errorf("actual message %q", message)
+	}
+
+	if _, errs = checklist.Parse("name: tool\nunknown: 1\n"); len(errs) != 1 || errs[0].Field != "definition" {
+		t.Errorf("actual %v", errs)
+	}
+}
+
+func TestScore(t *testing.T) {
+	definition, _ := checklist.Parse(definitionYaml)
+
+	result := checklist.Score(definition, map[string]string{"q1": "Yes", "q2": "N/A", "q3": "Some"})
+	if result.Score != 3 || result.Max != 5 || math.Abs(result.Percent-60) > 1e-9 || result.Rating != "High" {
+		t.Errorf("actual %+v", result)
+	}
+
+	result = checklist.Score(definition, map[string]string{"q1": "No", "q2": "Yes", "q3": "None"})
+	if result.Score != 2 || result.Max != 7 || result.Rating != "Low" {
+		t.Errorf("actual %+v", result)
+	}
+
+	unanswered := checklist.Unanswered(definition, map[string]string{"q1": "Yes", "q3": "Yes"})
+	if len(unanswered) != 2 || unanswered[0].Id != "q2" || unanswered[1].Id != "q3" {
+		t.Errorf("actual %+v", unanswered)
+	}
+
+	sections := checklist.Sections(definition)
+	if len(sections) != 2 || len(sections[0].Items) != 2 || sections[1].Name != "Analysis" || len(sections[1].Items[0].Options) != 3 {
+		t.Errorf("actual %+v", sections)
+	}
+}
+
+// TestSeedTemplates checks the checklists the migrations ship, which are
+// written as SQL string literals.
+func TestSeedTemplates(t *testing.T) {
+	sql, err := os.ReadFile("../../db/migrations/000018_create_checklist_tables.up.sql")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	literals := regexp.MustCompile(`(?s)'(\{(?:[^']|'')*\})'::jsonb`).FindAllStringSubmatch(string(sql), -1)
+	if len(literals) != 3 {
+		t.Fatalf("actual %d seed templates, expect 3", len(literals))
+	}
+	for _, literal := range literals {
+		definition, errs := checklist.Parse(strings.ReplaceAll(literal[1], "''", "'"))
+		if len(errs) > 0 {
+			t.Errorf("%s: %v", definition.Name, errs)
+		}
+	}
+
+	nos, _ := checklist.Parse(strings.ReplaceAll(literals[2][1], "''", "'"))
+	best := map[string]string{}
+	for _, question := range nos.Questions {
+		best[question.Id] = question.Options[0].Value
+	}
+	if result := checklist.Score(nos, best); result.Score != 9 || result.Rating != "Good" {
+		t.Errorf("actual %+v", result)
+	}
+}
@@ -0,0 +1,220 @@
+package test
+
+import (
+	"bytes"
+	"sci-review/citation"
+	"strings"
+	"testing"
+)
+
+const bibtexSample = `@string{jh = "Journal of Health"}
+
+@article{smith2019,
+  author = {Smith, John and M{\"u}ller, Anna},
+  title = {Effect of {Exercise} on depression},
+  journal = jh,
+  year = 2019,
+  doi = {10.1000/xyz123},
+  note = {Preprint available},
+}
+
+@mastersthesis{broken,
+  title = {Unclosed
+`
+
+func TestBibtexReader_Next(t *testing.T) {
+	records, parseErrors, err := readAll(citation.NewBibtexReader(strings.NewReader(bibtexSample)))
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	if len(records) != 1 {
+		t.Fatalf("actual %d records, expect 1", len(records))
+	}
+
+	record := records[0]
+	if record.Key != "smith2019" {
+		t.Errorf("actual key %q", record.Key)
+	}
+	if record.Title != "Effect of Exercise on depression" {
+		t.Errorf("actual title %q", record.Title)
+	}
+	if len(record.Authors) != 2 || record.Authors[1] != "Müller, Anna" {
+		t.Errorf("actual authors %v", record.Authors)
+	}
+	if record.Journal != "Journal of Health" {
+		t.Errorf("actual journal %q", record.Journal)
+	}
+	if record.Raw["note"] != "Preprint available" {
+		t.Errorf("actual raw %v", record.Raw)
+	}
+
+	if len(parseErrors) != 1 || parseErrors[0].Key != "broken" {
+		t.Errorf("actual errors %v", parseErrors)
+	}
+}
+
+func TestBibtex_RoundTrip(t *testing.T) {
+	records, _, err := readAll(citation.NewBibtexReader(strings.NewReader(bibtexSample)))
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	var exported bytes.Buffer
+	values := []citation.Record{*records[0]}
+	if err := citation.WriteBibtex(&exported, values); err != nil {
+		t.Fatal(err.Error())
+	}
+
+	reimported, parseErrors, err := readAll(citation.NewBibtexReader(&exported))
+	if err != nil || len(parseErrors) > 0 || len(reimported) != 1 {
+		t.Fatalf("actual %v %v %v", reimported, parseErrors, err)
+	}
+	if reimported[0].Raw["note"] != "Preprint available" || reimported[0].Key != "smith2019" {
+		t.Errorf("actual %+v", reimported[0])
+	}
+}
+
+func TestBibtex_RoundTripStrayBraces(t *testing.T) {
+	record := citation.Record{
+		Type:     "article-journal",
+		Title:    "Closing } before opening {",
+		Authors:  []string{"Smith, John"},
+		Keywords: []string{},
+		Raw:      map[string]any{"note": "only { opened", "series": `escaped \} brace`},
+	}
+
+	var exported bytes.Buffer
+	if err := citation.WriteBibtex(&exported, []citation.Record{record}); err != nil {
+		t.Fatal(err.Error())
+	}
+
+	reimported, parseErrors, err := readAll(citation.NewBibtexReader(&exported))
+	if err != nil || len(parseErrors) > 0 || len(reimported) != 1 {
+		t.Fatalf("actual %v %v %v\n%s", reimported, parseErrors, err, exported.String())
+	}
+	actual := reimported[0]
+	if actual.Title != record.Title {
+		t.Errorf("actual title %q, expect %q", actual.Title, record.Title)
+	}
+	if actual.Raw["note"] != `only \{ opened` || actual.Raw["series"] != `escaped \} brace` {
+		t.Errorf("actual raw %v", actual.Raw)
+	}
+	if len(actual.Authors) != 1 || actual.Authors[0] != "Smith, John" {
+		t.Errorf("actual authors %v", actual.Authors)
+	}
+}
+
+func TestWriteBibtex_LatexSpecials(t *testing.T) {
+	record := citation.Record{
+		Type:     "article-journal",
+		Title:    "Costs & benefits: 50% of #1 cases at $10 under p_value",
+		Authors:  []string{"Smith & Sons"},
+		Journal:  "Health_Econ",
+		Keywords: []string{"R&D"},
+		Raw:      map[string]any{"note": `50\% kept as imported`},
+	}
+
+	var exported bytes.Buffer
+	if err := citation.WriteBibtex(&exported, []citation.Record{record}); err != nil {
+		t.Fatal(err.Error())
+	}
+	text := exported.String()
+	if !strings.Contains(text, `title = {Costs \& benefits: 50\% of \#1 cases at \$10 under p\_value}`) {
+		t.Errorf("actual export %s", text)
+	}
+	if !strings.Contains(text, `note = {50\% kept as imported}`) {
+		t.Errorf("actual export %s", text)
+	}
+
+	reimported, parseErrors, err := readAll(citation.NewBibtexReader(&exported))
+	if err != nil || len(parseErrors) > 0 || len(reimported) != 1 {
+		t.Fatalf("actual %v %v %v", reimported, parseErrors, err)
+	}
+	actual := reimported[0]
+	if actual.Title != record.Title || actual.Journal != record.Journal || actual.Authors[0] != "Smith & Sons" || actual.Keywords[0] != "R&D" {
+		t.Errorf("actual %+v", actual)
+	}
+}
+
+func TestBibtex_RoundTripOriginalValues(t *testing.T) {
+	input := `@article{lee2021,
+  author = {M{\"u}ller, Anna and {World Health Organization}},
+  title = {{COVID}-19 outcomes on pages 10--20},
+  journal = {The {BMJ}},
+  year = {2021},
+  abstract = {Rates rose by 5\% in {ICU} patients, ~ \textasciicircum{}},
+  keywords = {{SARS-CoV-2}, mortality},
+  note = {Letter}
+}
+`
+	records, parseErrors, err := readAll(citation.NewBibtexReader(strings.NewReader(input)))
+	if err != nil || len(parseErrors) > 0 || len(records) != 1 {
+		t.Fatalf("actual %v %v %v", records, parseErrors, err)
+	}
+	if records[0].Title != "COVID-19 outcomes on pages 10–20" || records[0].Authors[0] != "Müller, Anna" {
+		t.Errorf("actual %+v", records[0])
+	}
+
+	var exported bytes.Buffer
+	if err := citation.WriteBibtex(&exported, []citation.Record{*records[0]}); err != nil {
+		t.Fatal(err.Error())
+	}
+	text := exported.String()
+	for _, field := range []string{
+		`author = {M{\"u}ller, Anna and {World Health Organization}}`,
+		`title = {{COVID}-19 outcomes on pages 10--20}`,
+		`journal = {The {BMJ}}`,
+		`abstract = {Rates rose by 5\% in {ICU} patients, ~ \textasciicircum{}}`,
+		`keywords = {{SARS-CoV-2}, mortality}`,
+		`note = {Letter}`,
+	} {
+		if !strings.Contains(text, field) {
+			t.Errorf("expect %s in\n%s", field, text)
+		}
+	}
+	if strings.Contains(text, "bibtex_") {
+		t.Errorf("original values written as fields\n%s", text)
+	}
+
+	edited := *records[0]
+	edited.Title = "Edited ~title^"
+	exported.Reset()
+	if err := citation.WriteBibtex(&exported, []citation.Record{edited}); err != nil {
+		t.Fatal(err.Error())
+	}
+	reimported, _, err := readAll(citation.NewBibtexReader(&exported))
+	if err != nil || len(reimported) != 1 || reimported[0].Title != edited.Title {
+		t.Errorf("actual %v %v\n%s", reimported, err, exported.String())
+	}
+}
+
+func TestCslJson_RoundTrip(t *testing.T) {
+	input := `[
+  {"id": "item1", "type": "article-journal", "title": "A trial",
+   "author": [{"family": "Smith", "given": "John"}],
+   "issued": {"date-parts": [[2020, 5, 1]]}, "volume": "12", "DOI": "10.1/abc"},
+  {"id": "item2", "type": "book"}
+]`
+	records, parseErrors, err := readAll(citation.NewCslJsonReader(strings.NewReader(input)))
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	if len(records) != 1 || records[0].Year != 2020 || records[0].Authors[0] != "Smith, John" {
+		t.Fatalf("actual records %v", records)
+	}
+	if len(parseErrors) != 1 || parseErrors[0].Key != "item2" {
+		t.Errorf("actual errors %v", parseErrors)
+	}
+
+	var exported bytes.Buffer
+	if err := citation.WriteCslJson(&exported, []citation.Record{*records[0]}); err != nil {
+		t.Fatal(err.Error())
+	}
+	reimported, _, err := readAll(citation.NewCslJsonReader(&exported))
+	if err != nil || len(reimported) != 1 {
+		t.Fatalf("actual %v %v", reimported, err)
+	}
+	if reimported[0].Raw["volume"] != "12" || reimported[0].Doi != "10.1/abc" {
+		t.Errorf("actual %+v", reimported[0])
+	}
+}
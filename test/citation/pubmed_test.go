@@ -0,0 +1,118 @@
+package test
+
+import (
+	"sci-review/citation"
+	"strings"
+	"testing"
+)
+
+const medlineSample = `PMID- 31000001
+OWN - NLM
+TI  - Exercise for depression in adults: a randomized
+      controlled trial.
+AB  - Background text.
+FAU - Smith, John
+AU  - Smith J
+FAU - Doe, Jane
+AU  - Doe J
+DP  - 2019 Mar
+JT  - Journal of Health
+LID - 10.1000/xyz123 [doi]
+PT  - Journal Article
+PT  - Randomized Controlled Trial
+MH  - Humans
+MH  - Depression/*therapy
+MH  - Carcinoma, Non-Small-Cell Lung
+
+PMID- 31000002
+TI  - Second article.
+DP  - 2020
+`
+
+func TestMedlineReader_Next(t *testing.T) {
+	records, parseErrors, err := readAll(citation.NewMedlineReader(strings.NewReader(medlineSample)))
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	if len(parseErrors) != 0 || len(records) != 2 {
+		t.Fatalf("actual %d records, %v errors", len(records), parseErrors)
+	}
+
+	record := records[0]
+	if record.Pmid != "31000001" {
+		t.Errorf("actual pmid %q", record.Pmid)
+	}
+	if record.Title != "Exercise for depression in adults: a randomized controlled trial." {
+		t.Errorf("actual title %q", record.Title)
+	}
+	if len(record.Authors) != 2 || record.Authors[0] != "Smith, John" {
+		t.Errorf("actual authors %v", record.Authors)
+	}
+	if record.Doi != "10.1000/xyz123" || record.Year != 2019 {
+		t.Errorf("actual doi %q year %d", record.Doi, record.Year)
+	}
+	if len(record.MeshTerms) != 3 || record.MeshTerms[2] != "Carcinoma, Non-Small-Cell Lung" {
+		t.Errorf("actual mesh %v", record.MeshTerms)
+	}
+	if len(record.PublicationTypes) != 2 {
+		t.Errorf("actual publication types %v", record.PublicationTypes)
+	}
+	if records[1].Pmid != "31000002" || records[1].Year != 2020 {
+		t.Errorf("actual second record %+v", records[1])
+	}
+}
+
+const pubmedXmlSample = `<?xml version="1.0" ?>
+<!DOCTYPE PubmedArticleSet PUBLIC "-//NLM//DTD PubMedArticle, 1st January 2019//EN" "https://dtd.nlm.nih.gov/ncbi/pubmed/out/pubmed_190101.dtd">
+<PubmedArticleSet>
+<PubmedArticle>
+  <MedlineCitation Status="MEDLINE" Owner="NLM">
+    <PMID Version="1">31000001</PMID>
+    <Article PubModel="Print">
+      <Journal>
+        <JournalIssue CitedMedium="Internet"><PubDate><Year>2019</Year><Month>Mar</Month></PubDate></JournalIssue>
+        <Title>Journal of Health</Title>
+      </Journal>
+      <ArticleTitle>Exercise for <i>depression</i> in adults.</ArticleTitle>
+      <ELocationID EIdType="doi" ValidYN="Y">10.1000/xyz123</ELocationID>
+      <Abstract>
+        <AbstractText Label="BACKGROUND">Some background.</AbstractText>
+        <AbstractText Label="RESULTS">Some results.</AbstractText>
+      </Abstract>
+      <AuthorList><Author><LastName>Smith</LastName><ForeName>John</ForeName></Author></AuthorList>
+      <PublicationTypeList><PublicationType UI="D016428">Journal Article</PublicationType></PublicationTypeList>
+    </Article>
+    <MeshHeadingList>
+      <MeshHeading><DescriptorName UI="D006801" MajorTopicYN="N">Humans</DescriptorName></MeshHeading>
+      <MeshHeading>
+        <DescriptorName UI="D003863" MajorTopicYN="N">Depression</DescriptorName>
+        <QualifierName UI="Q000628" MajorTopicYN="Y">therapy</QualifierName>
+      </MeshHeading>
+    </MeshHeadingList>
+  </MedlineCitation>
+</PubmedArticle>
+</PubmedArticleSet>`
+
+func TestPubmedXmlReader_Next(t *testing.T) {
+	records, parseErrors, err := readAll(citation.NewPubmedXmlReader(strings.NewReader(pubmedXmlSample)))
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	if len(parseErrors) != 0 || len(records) != 1 {
+		t.Fatalf("actual %d records, %v errors", len(records), parseErrors)
+	}
+
+	record := records[0]
+	if record.Title != "Exercise for depression in adults." {
+		t.Errorf("actual title %q", record.Title)
+	}
+	if record.Abstract != "BACKGROUND: Some background. RESULTS: Some results." {
+		t.Errorf("actual abstract %q", record.Abstract)
+	}
+	if record.Pmid != "31000001" || record.Doi != "10.1000/xyz123" || record.Year != 2019 {
+		t.Errorf("actual %+v", record)
+	}
+	if len(record.MeshTerms) != 2 || record.MeshTerms[1] != "Depression/*therapy" {
+		t.Errorf("actual mesh %v", record.MeshTerms)
+	}
+}
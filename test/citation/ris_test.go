@@ -0,0 +1,106 @@
+package test
+
+import (
+	"errors"
+	"io"
+	"sci-review/citation"
+	"strings"
+	"testing"
+)
+
+const risSample = `TY  - JOUR
+TI  - Effect of exercise on depression
+AU  - Smith, John
+AU  - Doe, Jane
+PY  - 2019///
+JO  - Journal of Health
+DO  - https://doi.org/10.1000/xyz123
+AB  - A randomized trial
+  with a wrapped abstract.
+KW  - exercise
+KW  - depression
+ER  - 
+
+stray line
+TY  - JOUR
+AU  - Nobody
+ER  - 
+TY  - JOUR
+TI  - Second record
+PY  - 2020
+ER  - 
+`
+
+func readAll(reader citation.Reader) ([]*citation.Record, []*citation.ParseError, error) {
+	var records []*citation.Record
+	var parseErrors []*citation.ParseError
+	for {
+		record, err := reader.Next()
+		if err == io.EOF {
+			return records, parseErrors, nil
+		}
+		var parseError *citation.ParseError
+		if errors.As(err, &parseError) {
+			parseErrors = append(parseErrors, parseError)
+			continue
+		}
+		if err != nil {
+			return nil, nil, err
+		}
+		records = append(records, record)
+	}
+}
+
+func TestRisReader_Next(t *testing.T) {
+	records, parseErrors, err := readAll(citation.NewRisReader(strings.NewReader(risSample)))
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	if len(records) != 2 {
+		t.Fatalf("actual %d records, expect 2", len(records))
+	}
+	first := records[0]
+	if first.Title != "Effect of exercise on depression" {
+		t.Errorf("actual title %q", first.Title)
+	}
+	if len(first.Authors) != 2 || first.Authors[0] != "Smith, John" {
+		t.Errorf("actual authors %v", first.Authors)
+	}
+	if first.Year != 2019 {
+		t.Errorf("actual year %d, expect 2019", first.Year)
+	}
+	if first.Doi != "10.1000/xyz123" {
+		t.Errorf("actual doi %q", first.Doi)
+	}
+	if first.Abstract != "A randomized trial with a wrapped abstract." {
+		t.Errorf("actual abstract %q", first.Abstract)
+	}
+	if len(first.Keywords) != 2 {
+		t.Errorf("actual keywords %v", first.Keywords)
+	}
+
+	if len(parseErrors) != 2 {
+		t.Fatalf("actual %d errors, expect 2", len(parseErrors))
+	}
+	if parseErrors[0].Line != 14 {
+		t.Errorf("actual stray line %d, expect 14", parseErrors[0].Line)
+	}
+	if parseErrors[1].Line != 15 {
+		t.Errorf("actual untitled record line %d, expect 15", parseErrors[1].Line)
+	}
+}
+
+func TestRisReader_MissingEndTag(t *testing.T) {
+	input := "TY  - JOUR\nTI  - First\nTY  - JOUR\nTI  - Second\nER  - \n"
+	records, parseErrors, err := readAll(citation.NewRisReader(strings.NewReader(input)))
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	if len(records) != 1 || records[0].Title != "Second" {
+		t.Errorf("actual records %v", records)
+	}
+	if len(parseErrors) != 1 || parseErrors[0].Line != 1 {
+		t.Errorf("actual errors %v", parseErrors)
+	}
+}
@@ -0,0 +1,64 @@
+package test
+
+import (
+	"github.com/google/uuid"
+	"sci-review/dedup"
+	"testing"
+)
+
+func TestFindClusters(t *testing.T) {
+	candidates := []dedup.Candidate{
+		{Id: uuid.New(), Title: "Exercise for depression: a randomized trial", Year: 2019, FirstAuthor: "Smith, John", Doi: "10.1000/ABC"},
+		{Id: uuid.New(), Title: "Exercise for Depression - A Randomised Trial.", Year: 2020, FirstAuthor: "Smith J"},
+		{Id: uuid.New(), Title: "Completely different title", Year: 2019, FirstAuthor: "Doe, Jane", Doi: "10.1000/abc"},
+		{Id: uuid.New(), Title: "Exercise for depression: a randomized trial", Year: 2019, FirstAuthor: "Smith, John", Doi: "10.1000/other"},
+		{Id: uuid.New(), Title: "Unrelated study", Year: 2015, FirstAuthor: "Brown, Anna", Pmid: "123"},
+		{Id: uuid.New(), Title: "Unrelated study (reprint)", Year: 2001, FirstAuthor: "Other, Author", Pmid: "123"},
+	}
+
+	clusters := dedup.FindClusters(candidates, 0.9)
+	if len(clusters) != 2 {
+		t.Fatalf("actual %d clusters, expect 2: %+v", len(clusters), clusters)
+	}
+
+	for _, cluster := range clusters {
+		switch len(cluster.Ids) {
+		case 3:
+			// DOI match (0, 2) joined by the fuzzy match (0, 1); 3 has a different DOI
+			if cluster.Match != dedup.MatchDoi || cluster.Score >= 1 {
+				t.Errorf("actual %+v", cluster)
+			}
+			for _, id := range cluster.Ids {
+				if id == candidates[3].Id {
+					t.Error("record with a different DOI must not be grouped")
+				}
+			}
+		case 2:
+			if cluster.Match != dedup.MatchPmid {
+				t.Errorf("actual %+v", cluster)
+			}
+		default:
+			t.Errorf("unexpected cluster %+v", cluster)
+		}
+	}
+}
+
+func TestNormalizeAuthor(t *testing.T) {
+	cases := map[string]string{
+		"Smith, John": "smith",
+		"Smith JA":    "smith",
+		"Smith J.A.":  "smith",
+		"John Smith":  "smith",
+		"Wei Li":      "li",
+		"John Wu":     "wu",
+		"Anna Ng":     "ng",
+		"Müller Ä":    "muller",
+		"Li":          "li",
+		"":            "",
+	}
+	for input, expect := range cases {
+		if actual := dedup.NormalizeAuthor(input); actual != expect {
+			t.Errorf("actual %q, expect %q", actual, expect)
+		}
+	}
+}
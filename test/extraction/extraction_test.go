@@ -0,0 +1,212 @@
+package test
+
+import (
+	"net/url"
+	"sci-review/extraction"
+	"sci-review/model"
+	"testing"
+)
+
+const schemaJson = `{"fields": [
+	{"key": "sample_size", "label": "Sample size", "type": "Number", "required": true, "min": 1},
+	{"key": "design", "label": "Design", "type": "SingleChoice", "options": ["RCT", "Cohort"]},
+	{"key": "countries", "label": "Countries", "type": "MultiChoice", "options": ["UK", "US"]},
+	{"key": "start", "label": "Start", "type": "Date"},
+	{"key": "arms", "label": "Arms", "type": "Table", "columns": [
+		{"key": "name", "label": "Name", "type": "Text", "required": true},
+		{"key": "n", "label": "N", "type": "Number"}
+	]}
+]}`
+
+func TestParseSchema(t *testing.T) {
+	schema, errs := extraction.ParseSchema(schemaJson)
+	if len(errs) > 0 {
+		t.Fatalf("actual %v", errs)
+	}
+	if len(schema.Fields) != 5 || len(schema.Fields[4].Columns) != 2 {
+		t.Errorf("actual %+v", schema)
+	}
+
+	_, errs = extraction.ParseSchema(`{"fields": [{"key": "x"`)
+	if len(errs) != 1 || errs[0].Field != "schema" {
+		t.Errorf("actual %v", errs)
+	}
+}
+
+func TestCheckSchema(t *testing.T) {
+	lower, upper := 10.0, 1.0
+	schema := model.ExtractionSchema{Fields: []model.ExtractionField{
+		{Key: "Bad key", Label: "A", Type: model.FieldText},
+		{Key: "a", Label: "", Type: model.FieldText},
+		{Key: "a", Label: "B", Type: "Slider"},
+		{Key: "c", Label: "C", Type: model.FieldSingleChoice},
+		{Key: "d", Label: "D", Type: model.FieldNumber, Min: &lower, Max: &upper},
+		{Key: "e", Label: "E", Type: model.FieldTable, Columns: []model.ExtractionField{
+			{Key: "nested", Label: "Nested", Type: model.FieldTable},
+		}},
+	}}
+
+	expect := map[string]string{
+		"fields[0].key":             "fields[0].key must start with a letter and contain only lowercase letters, digits and underscores",
+		"fields[1].label":           "fields[1].label is required",
+		"fields[2].key":             "fields[2].key must be unique",
+		"fields[2].type":            "fields[2].type must be one of the following: Text Number SingleChoice MultiChoice Date Table",
+		"fields[3].options":         "fields[3].options is required",
+		"fields[4].min":             "fields[4].min must be less than or equal to 1",
+		"fields[5].columns[0].type": "fields[5].columns[0].type must be one of the following: Text Number SingleChoice MultiChoice Date",
+	}
+	errs := extraction.CheckSchema(schema)
+	if len(errs) != len(expect) {
+		t.Errorf("actual %v", errs)
+	}
+	for _, err := range errs {
+		if expect[err.Field] != err.Error {
+			t.Errorf("actual %q for %s, expect %q", err.Error, err.Field, expect[err.Field])
+		}
+	}
+}
+
+func TestParse(t *testing.T) {
+	schema, _ := extraction.ParseSchema(schemaJson)
+
+	values, errs := extraction.Parse(schema, url.Values{
+		"f.sample_size":  {"120"},
+		"f.design":       {"RCT"},
+		"f.countries":    {"UK", "US"},
+		"f.start":        {""},
+		"f.arms.0.name":  {"Exercise"},
+		"f.arms.0.n":     {"60"},
+		"f.arms.1.name":  {""},
+		"f.arms.1.n":     {""},
+		"f.arms.10.name": {"Control"},
+	})
+	if len(errs) > 0 {
+		t.Fatalf("actual %v", errs)
+	}
+	if values["sample_size"] != 120.0 || values["design"] != "RCT" {
+		t.Errorf("actual %v", values)
+	}
+	if _, found := values["start"]; found {
+		t.Error("empty answers must be left out")
+	}
+	arms := values["arms"].([]any)
+	if len(arms) != 2 || arms[1].(map[string]any)["name"] != "Control" {
+		t.Errorf("actual %v", arms)
+	}
+
+	_, errs = extraction.Parse(schema, url.Values{
+		"f.sample_size": {"0"},
+		"f.design":      {"Case series"},
+		"f.countries":   {"FR"},
+		"f.start":       {"2020-13-01"},
+		"f.arms.0.n":    {"x"},
+	})
+	expect := map[string]string{
+		"f.sample_size": "Sample size must be greater than or equal to 1",
+		"f.design":      "Design must be one of the following: RCT, Cohort",
+		"f.countries":   "Countries must be one of the following: UK, US",
+		"f.start":       "Start must be a date in format YYYY-MM-DD",
+		"f.arms.0.name": "Arms row 1 Name is required",
+		"f.arms.0.n":    "Arms row 1 N must be a numeric value",
+	}
+	if len(errs) != len(expect) {
+		t.Errorf("actual %v", errs)
+	}
+	for _, err := range errs {
+		if expect[err.Field] != err.Error {
+			t.Errorf("actual %q for %s, expect %q", err.Error, err.Field, expect[err.Field])
+		}
+	}
+
+	for _, text := range []string{"NaN", "Inf", "-Inf", "1e400"} {
+		_, errs = extraction.Parse(schema, url.Values{"f.sample_size": {text}, "f.arms.0.name": {"Exercise"}, "f.arms.0.n": {text}})
+		if len(errs) != 2 || errs[0].Error != "Sample size must be a numeric value" || errs[1].Error != "Arms row 1 N must be a numeric value" {
+			t.Errorf("%s: actual %v", text, errs)
+		}
+	}
+
+	_, errs = extraction.Parse(schema, url.Values{})
+	if len(errs) != 1 || errs[0].Error != "Sample size is required" {
+		t.Errorf("actual %v", errs)
+	}
+}
+
+func TestInputs(t *testing.T) {
+	schema, _ := extraction.ParseSchema(schemaJson)
+	inputs := extraction.Inputs(schema, model.ExtractionValues{
+		"sample_size": 120.0,
+		"countries":   []any{"US"},
+		"arms":        []any{map[string]any{"name": "Exercise", "n": 60.0}},
+		"removed":     "ignored",
+	})
+
+	if len(inputs) != 5 || inputs[0].Value != "120" || !inputs[2].Checked["US"] || inputs[2].Checked["UK"] {
+		t.Errorf("actual %+v", inputs)
+	}
+	arms := inputs[4]
+	if len(arms.Rows) != 1+extraction.BlankRows || arms.Rows[0][1].Value != "60" || arms.Rows[0][1].Name != "f.arms.0.n" {
+		t.Errorf("actual %+v", arms.Rows)
+	}
+}
+
+func TestCompare(t *testing.T) {
+	schema, errs := extraction.ParseSchema(`{"fields": [
+		{"key": "mean", "label": "Mean", "type": "Number", "tolerance": 0.05},
+		{"key": "n", "label": "N", "type": "Number"},
+		{"key": "setting", "label": "Setting", "type": "Text"},
+		{"key": "countries", "label": "Countries", "type": "MultiChoice", "options": ["UK", "US"]},
+		{"key": "arms", "label": "Arms", "type": "Table", "columns": [
+			{"key": "name", "label": "Name", "type": "Text"}
+		]}
+	]}`)
+	if len(errs) > 0 {
+		t.Fatalf("actual %v", errs)
+	}
+
+	left := model.ExtractionValues{
+		"mean":      12.31,
+		"n":         40.0,
+		"setting":   "Primary  care",
+		"countries": []any{"US", "UK"},
+		"arms":      []any{map[string]any{"name": "Exercise"}},
+	}
+	right := model.ExtractionValues{
+		"mean":      12.35,
+		"n":         41.0,
+		"setting":   "primary care",
+		"countries": []string{"UK", "US"},
+		"arms":      []any{map[string]any{"name": "Exercise"}, map[string]any{"name": "Control"}},
+	}
+
+	comparisons := extraction.Compare(schema, left, right)
+	expect := map[string]bool{
+		"mean":         true,
+		"n":            false,
+		"setting":      true,
+		"countries":    true,
+		"arms[0].name": true,
+		"arms[1].name": false,
+	}
+	if len(comparisons) != len(expect) {
+		t.Fatalf("actual %+v", comparisons)
+	}
+	for _, comparison := range comparisons {
+		if agree, found := expect[comparison.Path]; !found || agree != comparison.Agree {
+			t.Errorf("actual %+v", comparison)
+		}
+	}
+	if actual := extraction.Discrepancies(comparisons); actual != 2 {
+		t.Errorf("actual %d, expect 2", actual)
+	}
+
+	agreed := extraction.Agreed(schema, left, right)
+	if _, found := agreed["n"]; found {
+		t.Error("answers in disagreement must be left out")
+	}
+	if _, found := agreed["arms"]; found {
+		t.Error("tables with a discrepancy must be left out")
+	}
+	if agreed["mean"] != 12.31 || agreed["setting"] != "Primary  care" {
+		t.Errorf("actual %v", agreed)
+	}
+}
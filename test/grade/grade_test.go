@@ -0,0 +1,90 @@
+package test
+
+import (
+	"bytes"
+	"encoding/csv"
+	"github.com/google/uuid"
+	"sci-review/grade"
+	"sci-review/model"
+	"strings"
+	"testing"
+)
+
+func TestRate(t *testing.T) {
+	assessment := model.NewGradeAssessment(uuid.New(), "Mortality", uuid.New())
+	if certainty := grade.Rate(*assessment); certainty != model.CertaintyHigh {
+		t.Errorf("actual %s, expect High", certainty)
+	}
+
+	assessment.RiskOfBias = 1
+	assessment.Imprecision = 2
+	if certainty := grade.Rate(*assessment); certainty != model.CertaintyVeryLow {
+		t.Errorf("actual %s, expect Very low", certainty)
+	}
+
+	assessment.Inconsistency = 2
+	if certainty := grade.Rate(*assessment); certainty != model.CertaintyVeryLow {
+		t.Errorf("actual %s, expect the rating to stop at Very low", certainty)
+	}
+
+	observational := model.NewGradeAssessment(uuid.New(), "Mortality", uuid.New())
+	observational.Design = model.DesignObservational
+	observational.LargeEffect = 2
+	observational.DoseResponse = 1
+	if certainty := grade.Rate(*observational); certainty != model.CertaintyHigh {
+		t.Errorf("actual %s, expect the rating to stop at High", certainty)
+	}
+	if errs := grade.Check(*observational); len(errs) != 0 {
+		t.Errorf("actual %v", errs)
+	}
+
+	observational.Design = model.DesignRandomized
+	observational.Imprecision = 3
+	if errs := grade.Check(*observational); len(errs) != 3 {
+		t.Errorf("actual %v, expect imprecision beyond its levels and two upgrades of randomized trials", errs)
+	}
+}
+
+func TestSummaryOfFindings(t *testing.T) {
+	mortality := model.NewGradeAssessment(uuid.New(), "Mortality", uuid.New())
+	mortality.Studies = 3
+	mortality.Participants = 1200
+	mortality.RelativeEffect = "RR 0.70 (0.60 to 0.82)"
+	mortality.RiskOfBias = 1
+	mortality.Footnotes[model.FactorImprecision] = "Few events, wide interval"
+	mortality.Certainty = grade.Rate(*mortality)
+
+	pain := model.NewGradeAssessment(uuid.New(), "Pain <VAS>", uuid.New())
+	pain.Design = model.DesignObservational
+	pain.Studies = 1
+	pain.RiskOfBias = 1
+	pain.Certainty = grade.Rate(*pain)
+
+	table := grade.NewTable("Exercise for depression", []model.GradeAssessment{*mortality, *pain})
+	if len(table.Footnotes) != 2 || table.Rows[1].Footnotes[0] != 1 {
+		t.Fatalf("actual %+v", table)
+	}
+
+	var buffer bytes.Buffer
+	if err := grade.WriteHtml(&buffer, table); err != nil {
+		t.Fatal(err)
+	}
+	document := buffer.String()
+	for _, expect := range []string{"⊕⊕⊕◯ Moderate<sup>1,2</sup>", "⊕◯◯◯ Very low<sup>1</sup>", "Pain &lt;VAS&gt;", "1200 (3 RCTs)", "<li>Few events, wide interval</li>"} {
+		if !strings.Contains(document, expect) {
+			t.Errorf("document misses %q", expect)
+		}
+	}
+
+	buffer.Reset()
+	if err := grade.WriteCsv(&buffer, table); err != nil {
+		t.Fatal(err)
+	}
+	records, err := csv.NewReader(&buffer).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != 3 || records[1][4] != "RR 0.70 (0.60 to 0.82)" || records[2][8] != "Very low" {
+		t.Errorf("actual %v", records)
+	}
+}
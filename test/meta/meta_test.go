@@ -0,0 +1,325 @@
+package test
+
+import (
+	"bytes"
+	"errors"
+	"math"
+	"sci-review/meta"
+	"strings"
+	"testing"
+)
+
+// bcg holds the trials of the BCG vaccine against tuberculosis (Colditz et
+// al., 1994), as events and totals of the vaccinated and the control group.
+var bcg = []meta.Binary{
+	{EventsT: 4, TotalT: 123, EventsC: 11, TotalC: 139},
+	{EventsT: 6, TotalT: 306, EventsC: 29, TotalC: 303},
+	{EventsT: 3, TotalT: 231, EventsC: 11, TotalC: 220},
+	{EventsT: 62, TotalT: 13598, EventsC: 248, TotalC: 12867},
+	{EventsT: 33, TotalT: 5069, EventsC: 47, TotalC: 5808},
+	{EventsT: 180, TotalT: 1541, EventsC: 372, TotalC: 1451},
+	{EventsT: 8, TotalT: 2545, EventsC: 10, TotalC: 629},
+	{EventsT: 505, TotalT: 88391, EventsC: 499, TotalC: 88391},
+	{EventsT: 29, TotalT: 7499, EventsC: 45, TotalC: 7277},
+	{EventsT: 17, TotalT: 1716, EventsC: 65, TotalC: 1665},
+	{EventsT: 186, TotalT: 50634, EventsC: 141, TotalC: 27338},
+	{EventsT: 5, TotalT: 2498, EventsC: 3, TotalC: 2341},
+	{EventsT: 27, TotalT: 16913, EventsC: 29, TotalC: 17854},
+}
+
+func bcgStudies(t *testing.T, measure meta.Measure) []meta.Study {
+	studies := []meta.Study{}
+	for i, data := range bcg {
+		study, err := meta.BinaryEffect(string(rune('A'+i)), measure, data)
+		if err != nil {
+			t.Fatal(err)
+		}
+		studies = append(studies, study)
+	}
+	return studies
+}
+
+func near(t *testing.T, name string, actual float64, expect float64, tolerance float64) {
+	t.Helper()
+	if math.Abs(actual-expect) > tolerance {
+		t.Errorf("%s: actual %.5f, expect %.5f", name, actual, expect)
+	}
+}
+
+// TestPool checks the pooled log risk ratios of the BCG trials against
+// those of metafor.
+func TestPool(t *testing.T) {
+	studies := bcgStudies(t, meta.RiskRatio)
+
+	fixed, err := meta.Pool(studies, meta.RiskRatio, meta.InverseVariance, 0.95)
+	if err != nil {
+		t.Fatal(err)
+	}
+	near(t, "IV estimate", fixed.Estimate, -0.4303, 0.0001)
+	near(t, "IV se", fixed.SE, 0.0405, 0.0001)
+	near(t, "Q", fixed.Q, 152.2330, 0.001)
+	near(t, "I2", fixed.I2, 92.12, 0.01)
+	if fixed.Df != 12 || fixed.Tau2 != 0 || fixed.Prediction {
+		t.Errorf("actual df %d, tau2 %f, prediction %t", fixed.Df, fixed.Tau2, fixed.Prediction)
+	}
+
+	dl, err := meta.Pool(studies, meta.RiskRatio, meta.DerSimonianLaird, 0.95)
+	if err != nil {
+		t.Fatal(err)
+	}
+	near(t, "DL tau2", dl.Tau2, 0.3088, 0.0001)
+	near(t, "DL estimate", dl.Estimate, -0.7141, 0.0001)
+	near(t, "DL se", dl.SE, 0.1787, 0.0001)
+	if !dl.Prediction || dl.PredictionLower > dl.Lower || dl.PredictionUpper < dl.Upper {
+		t.Errorf("actual prediction interval [%f, %f], expect it around [%f, %f]", dl.PredictionLower, dl.PredictionUpper, dl.Lower, dl.Upper)
+	}
+
+	reml, err := meta.Pool(studies, meta.RiskRatio, meta.RestrictedML, 0.95)
+	if err != nil {
+		t.Fatal(err)
+	}
+	near(t, "REML tau2", reml.Tau2, 0.3132, 0.0001)
+	near(t, "REML estimate", reml.Estimate, -0.7145, 0.0001)
+
+	mh, err := meta.Pool(studies, meta.RiskRatio, meta.MantelHaenszel, 0.95)
+	if err != nil {
+		t.Fatal(err)
+	}
+	near(t, "MH estimate", mh.Estimate, -0.4537, 0.0001)
+
+	total := 0.0
+	for _, weight := range reml.Weights {
+		total += weight
+	}
+	near(t, "weights", total, 100, 1e-9)
+}
+
+func TestEffects(t *testing.T) {
+	study, err := meta.BinaryEffect("Zero", meta.OddsRatio, meta.Binary{EventsT: 0, TotalT: 10, EventsC: 2, TotalC: 10})
+	if err != nil {
+		t.Fatal(err)
+	}
+	near(t, "corrected log OR", study.Yi, math.Log(0.5*8.5/(10.5*2.5)), 1e-9)
+
+	if _, err := meta.BinaryEffect("None", meta.RiskRatio, meta.Binary{TotalT: 10, TotalC: 10}); !errors.Is(err, meta.ErrorNoEvents) {
+		t.Errorf("actual %v, expect %v", err, meta.ErrorNoEvents)
+	}
+	if _, err := meta.BinaryEffect("None", meta.RiskDifference, meta.Binary{TotalT: 10, TotalC: 10}); err != nil {
+		t.Errorf("actual %v, expect a risk difference of zero", err)
+	}
+
+	data := meta.Continuous{MeanT: 10, SdT: 2, NT: 20, MeanC: 8, SdC: 2, NC: 20}
+	md, _ := meta.ContinuousEffect("MD", meta.MeanDifference, data)
+	near(t, "MD", md.Yi, 2, 1e-9)
+	near(t, "MD variance", md.Vi, 0.4, 1e-9)
+
+	smd, _ := meta.ContinuousEffect("SMD", meta.StandardizedMeanDifference, data)
+	near(t, "Hedges' g", smd.Yi, 0.98013, 0.00001)
+	near(t, "Hedges' g variance", smd.Vi, 0.11201, 0.00001)
+
+	if _, err := meta.ContinuousEffect("MD", meta.OddsRatio, data); !errors.Is(err, meta.ErrorMeasure) {
+		t.Errorf("actual %v, expect %v", err, meta.ErrorMeasure)
+	}
+}
+
+func TestPoolErrors(t *testing.T) {
+	if _, err := meta.Pool(nil, meta.OddsRatio, meta.InverseVariance, 0.95); !errors.Is(err, meta.ErrorNoStudies) {
+		t.Errorf("actual %v, expect %v", err, meta.ErrorNoStudies)
+	}
+
+	generic, _ := meta.GenericEffect("Generic", -0.2, 0.1)
+	if _, err := meta.Pool([]meta.Study{generic}, meta.OddsRatio, meta.MantelHaenszel, 0.95); !errors.Is(err, meta.ErrorMethod) {
+		t.Errorf("actual %v, expect %v", err, meta.ErrorMethod)
+	}
+
+	if _, err := meta.Pool([]meta.Study{generic}, meta.OddsRatio, meta.InverseVariance, 1); !errors.Is(err, meta.ErrorLevel) {
+		t.Errorf("actual %v, expect %v", err, meta.ErrorLevel)
+	}
+}
+
+func TestForest(t *testing.T) {
+	studies := bcgStudies(t, meta.RiskRatio)
+	studies[0].Label = "Aronson <1948>"
+	result, err := meta.Pool(studies, meta.RiskRatio, meta.RestrictedML, 0.95)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var out bytes.Buffer
+	if err := meta.WriteForest(&out, studies, result); err != nil {
+		t.Fatal(err)
+	}
+	svg := out.String()
+	for _, expect := range []string{"<svg", "Aronson &lt;1948&gt;", "Total (Random effects, REML)", "Prediction interval", "0.49 [0.34, 0.70]", "I² = 92%", "<polygon"} {
+		if !strings.Contains(svg, expect) {
+			t.Errorf("expect %q in the forest plot", expect)
+		}
+	}
+}
+
+func TestEstimateMean(t *testing.T) {
+	normal := meta.Median{Median: 0, Q1: -0.67449, Q3: 0.67449, Min: -4, Max: 4, N: 1000000}
+	mean, sd, err := meta.EstimateMean(meta.WanIqr, normal)
+	if err != nil {
+		t.Fatal(err)
+	}
+	near(t, "Wan mean", mean, 0, 1e-9)
+	near(t, "Wan SD", sd, 1, 0.001)
+
+	small := meta.Median{Median: 5, Min: 1, Max: 11, N: 10}
+	mean, sd, _ = meta.EstimateMean(meta.Hozo, small)
+	near(t, "Hozo mean", mean, 5.55, 1e-9)
+	near(t, "Hozo SD", sd, math.Sqrt(101.0/12), 1e-9)
+
+	small.N = 50
+	mean, sd, _ = meta.EstimateMean(meta.Hozo, small)
+	near(t, "Hozo mean above 25", mean, 5, 1e-9)
+	near(t, "Hozo SD above 15", sd, 2.5, 1e-9)
+
+	if _, _, err := meta.EstimateMean(meta.WanRangeIqr, meta.Median{Median: 5, Q1: 6, Q3: 8, Min: 1, Max: 11, N: 10}); !errors.Is(err, meta.ErrorQuantiles) {
+		t.Errorf("actual %v, expect %v", err, meta.ErrorQuantiles)
+	}
+}
+
+func TestTestStatisticEffects(t *testing.T) {
+	smd, _ := meta.ContinuousEffect("SMD", meta.StandardizedMeanDifference, meta.Continuous{MeanT: 10, SdT: 2, NT: 20, MeanC: 8, SdC: 2, NC: 20})
+	fromT, err := meta.TEffect("t", 1/math.Sqrt(0.1), 20, 20)
+	if err != nil {
+		t.Fatal(err)
+	}
+	near(t, "g from t", fromT.Yi, smd.Yi, 1e-9)
+	near(t, "variance from t", fromT.Vi, smd.Vi, 1e-9)
+
+	fromP, err := meta.PEffect("p", 0.05, 20, 20, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	near(t, "g from p", fromP.Yi, -0.62745, 0.0001)
+
+	if _, err := meta.PEffect("p", 0, 20, 20, false); !errors.Is(err, meta.ErrorPValue) {
+		t.Errorf("actual %v, expect %v", err, meta.ErrorPValue)
+	}
+}
+
+// TestPublicationBias checks the tests for funnel plot asymmetry of the BCG
+// trials against those of metafor.
+func TestPublicationBias(t *testing.T) {
+	studies := bcgStudies(t, meta.RiskRatio)
+	result, err := meta.Pool(studies, meta.RiskRatio, meta.DerSimonianLaird, 0.95)
+	if err != nil {
+		t.Fatal(err)
+	}
+	bias, err := meta.AssessBias(studies, result)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	near(t, "egger t", bias.Egger.T, -1.4013, 1e-4)
+	near(t, "egger p", bias.Egger.P, 0.1887, 1e-4)
+	if bias.Egger.Df != 11 {
+		t.Errorf("egger df: actual %d, expect 11", bias.Egger.Df)
+	}
+	near(t, "begg tau", bias.Begg.Tau, 0.0256, 1e-4)
+	if bias.TrimFill.K0 != 1 || bias.TrimFill.Side != "right" {
+		t.Errorf("trim-and-fill: actual %d on the %s, expect 1 on the right", bias.TrimFill.K0, bias.TrimFill.Side)
+	}
+	if bias.TrimFill.Result.K != len(studies)+1 {
+		t.Errorf("trim-and-fill k: actual %d, expect %d", bias.TrimFill.Result.K, len(studies)+1)
+	}
+
+	var out bytes.Buffer
+	if err := meta.WriteFunnel(&out, studies, result, bias.TrimFill); err != nil {
+		t.Fatal(err)
+	}
+	for _, expect := range []string{"<svg", "Standard error", "Filled in by trim-and-fill (1)"} {
+		if !strings.Contains(out.String(), expect) {
+			t.Errorf("expect %q in the funnel plot", expect)
+		}
+	}
+
+	if _, err := meta.AssessBias(studies[:9], result); !errors.Is(err, meta.ErrorFewStudies) {
+		t.Errorf("expect ErrorFewStudies, actual %v", err)
+	}
+}
+
+// absoluteLatitude is the absolute latitude of the place of each BCG trial
+// and allocation how its participants were allocated.
+var (
+	absoluteLatitude = []float64{44, 55, 42, 52, 13, 44, 19, 13, 27, 42, 18, 33, 33}
+	allocation       = []string{"random", "random", "random", "random", "alternate", "alternate", "random", "random", "random", "systematic", "systematic", "systematic", "systematic"}
+)
+
+// TestRegress checks the meta-regression of the log risk ratios of the BCG
+// trials on absolute latitude against that of metafor.
+func TestRegress(t *testing.T) {
+	studies := bcgStudies(t, meta.RiskRatio)
+	regression, err := meta.Regress(studies, absoluteLatitude, meta.RestrictedML, 0.95)
+	if err != nil {
+		t.Fatal(err)
+	}
+	near(t, "intercept", regression.Intercept.Estimate, 0.2515, 1e-4)
+	near(t, "slope", regression.Slope.Estimate, -0.0291, 1e-4)
+	near(t, "slope se", regression.Slope.SE, 0.0072, 1e-4)
+	near(t, "tau2", regression.Tau2, 0.0764, 1e-4)
+	near(t, "r2", regression.R2, 75.63, 1e-2)
+	near(t, "qe", regression.QE, 30.7331, 1e-4)
+	if regression.DfE != 11 {
+		t.Errorf("df: actual %d, expect 11", regression.DfE)
+	}
+
+	if _, err := meta.Regress(studies[:3], []float64{1, 1, 1}, meta.RestrictedML, 0.95); !errors.Is(err, meta.ErrorFewCovariates) {
+		t.Errorf("expect ErrorFewCovariates, actual %v", err)
+	}
+}
+
+// TestSubgroups checks that with a fixed-effect model the test for
+// subgroup differences is the total Q less the Q within subgroups.
+func TestSubgroups(t *testing.T) {
+	studies := bcgStudies(t, meta.RiskRatio)
+	subgroups, err := meta.Subgroups(studies, allocation, meta.RiskRatio, meta.InverseVariance, 0.95)
+	if err != nil {
+		t.Fatal(err)
+	}
+	total, err := meta.Pool(studies, meta.RiskRatio, meta.InverseVariance, 0.95)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	within := 0.0
+	names := []string{}
+	for _, group := range subgroups.Groups {
+		within += group.Result.Q
+		names = append(names, group.Name)
+	}
+	near(t, "q between", subgroups.Q, total.Q-within, 1e-8)
+	if subgroups.Df != 2 || strings.Join(names, ",") != "alternate,random,systematic" {
+		t.Errorf("subgroups: actual %v with df %d", names, subgroups.Df)
+	}
+
+	if _, err := meta.Subgroups(studies[:4], allocation[:4], meta.RiskRatio, meta.InverseVariance, 0.95); !errors.Is(err, meta.ErrorFewGroups) {
+		t.Errorf("expect ErrorFewGroups, actual %v", err)
+	}
+}
+
+func TestSensitivity(t *testing.T) {
+	studies := bcgStudies(t, meta.RiskRatio)
+	leaveOneOut, err := meta.LeaveOneOut(studies, meta.RiskRatio, meta.DerSimonianLaird, 0.95)
+	if err != nil {
+		t.Fatal(err)
+	}
+	withoutFirst, err := meta.Pool(studies[1:], meta.RiskRatio, meta.DerSimonianLaird, 0.95)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(leaveOneOut) != len(studies) || leaveOneOut[0].Label != "A" || leaveOneOut[0].Result.K != len(studies)-1 {
+		t.Fatalf("leave-one-out: unexpected %d results", len(leaveOneOut))
+	}
+	near(t, "leave one out", leaveOneOut[0].Result.Estimate, withoutFirst.Estimate, 1e-12)
+
+	cumulative, err := meta.Cumulative(studies, meta.RiskRatio, meta.DerSimonianLaird, 0.95)
+	if err != nil {
+		t.Fatal(err)
+	}
+	near(t, "cumulative first", cumulative[0].Result.Estimate, studies[0].Yi, 1e-12)
+	near(t, "cumulative last", cumulative[len(cumulative)-1].Result.Estimate, -0.7141, 1e-4)
+}
@@ -0,0 +1,82 @@
+package test
+
+import (
+	"reflect"
+	"sci-review/model"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func TestCleanSynonyms(t *testing.T) {
+	actual := model.CleanSynonyms("Diabetes", []string{" dm ", "", "diabetes", "DM", "t2dm", "  "})
+	expect := []string{"dm", "t2dm"}
+	if !reflect.DeepEqual(actual, expect) {
+		t.Errorf("actual %v, expect %v", actual, expect)
+	}
+}
+
+func TestMergeKeywords(t *testing.T) {
+	userId, investigationId := uuid.New(), uuid.New()
+	into := model.NewInvestigationKeyword(userId, investigationId, "diabetes", []string{"dm"}, "")
+	other := model.NewInvestigationKeyword(userId, investigationId, "diabetes mellitus", []string{"DM", "t2dm"}, "population")
+
+	into.Merge(*other)
+	expect := []string{"dm", "diabetes mellitus", "t2dm"}
+	if !reflect.DeepEqual([]string(into.Synonyms), expect) {
+		t.Errorf("synonyms: actual %v, expect %v", into.Synonyms, expect)
+	}
+	if into.Concept != "population" {
+		t.Errorf("concept: actual %q, expect the concept of the merged keyword", into.Concept)
+	}
+}
+
+func TestKeywordSummary(t *testing.T) {
+	previous := *model.NewInvestigationKeyword(uuid.New(), uuid.New(), "diabetis", []string{"dm", "diabetic"}, "")
+	updated := previous
+	updated.Word = "diabetes"
+	updated.Synonyms = []string{"dm", "t2dm"}
+	updated.Concept = "population"
+
+	expect := "renamed from diabetis; − diabetic (diabetes); + t2dm (diabetes); concept none → Population"
+	if actual := updated.Summary(previous, model.FrameworkPICO); actual != expect {
+		t.Errorf("actual %q, expect %q", actual, expect)
+	}
+	if actual := previous.Summary(previous, model.FrameworkPICO); actual != "" {
+		t.Errorf("unchanged keyword: actual %q, expect none", actual)
+	}
+}
+
+func TestMoveKeyword(t *testing.T) {
+	keywords := []model.InvestigationKeyword{}
+	for _, word := range []string{"a", "b", "c"} {
+		keywords = append(keywords, *model.NewInvestigationKeyword(uuid.New(), uuid.New(), word, nil, ""))
+	}
+	words := func(keywords []model.InvestigationKeyword) []string {
+		words := []string{}
+		for _, keyword := range keywords {
+			words = append(words, keyword.Word)
+		}
+		return words
+	}
+
+	moved, ok := model.MoveKeyword(keywords, keywords[2].Id, -1)
+	if !ok || !reflect.DeepEqual(words(moved), []string{"a", "c", "b"}) {
+		t.Errorf("move up: actual %v %v", words(moved), ok)
+	}
+	for i, keyword := range moved {
+		if keyword.Position != i+1 {
+			t.Errorf("position of %s: actual %d, expect %d", keyword.Word, keyword.Position, i+1)
+		}
+	}
+	if keywords[1].Word != "b" {
+		t.Errorf("the keywords given should not be reordered")
+	}
+
+	if _, ok := model.MoveKeyword(keywords, keywords[0].Id, -1); ok {
+		t.Errorf("the first keyword cannot move up")
+	}
+	if _, ok := model.MoveKeyword(keywords, uuid.New(), 1); ok {
+		t.Errorf("an unknown keyword cannot move")
+	}
+}
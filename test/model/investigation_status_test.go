@@ -0,0 +1,41 @@
+package test
+
+import (
+	"sci-review/model"
+	"testing"
+)
+
+func TestInvestigationTransitions(t *testing.T) {
+	inProgress := model.InvestigationStatus(model.PiStatusInProgress)
+	proceed := model.InvestigationStatus(model.PiStatusProceed)
+	doNotProceed := model.InvestigationStatus(model.PiStatusDoNotProceed)
+	cancelled := model.InvestigationStatus(model.PiStatusCancelled)
+
+	tests := []struct {
+		from   model.InvestigationStatus
+		to     model.InvestigationStatus
+		expect bool
+	}{
+		{inProgress, proceed, true},
+		{inProgress, doNotProceed, true},
+		{inProgress, cancelled, true},
+		{inProgress, inProgress, false},
+		{proceed, inProgress, true},
+		{proceed, doNotProceed, false},
+		{doNotProceed, proceed, false},
+		{cancelled, inProgress, true},
+		{cancelled, proceed, false},
+	}
+	for _, test := range tests {
+		if actual := test.from.CanMoveTo(test.to); actual != test.expect {
+			t.Errorf("%s to %s: actual %v, expect %v", test.from, test.to, actual, test.expect)
+		}
+	}
+
+	for _, status := range []model.InvestigationStatus{inProgress, proceed, doNotProceed, cancelled} {
+		expect := status == proceed || status == doNotProceed
+		if actual := status.RequiresConclusion(); actual != expect {
+			t.Errorf("%s requires a conclusion: actual %v, expect %v", status, actual, expect)
+		}
+	}
+}
@@ -0,0 +1,67 @@
+package test
+
+import (
+	"reflect"
+	"sci-review/model"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestKeywordSnapshotDiff(t *testing.T) {
+	previous := model.KeywordSnapshot{
+		{Word: "diabetes", Synonyms: []string{"DM", "diabetic*"}},
+		{Word: "insulin"},
+	}
+	current := model.KeywordSnapshot{
+		{Word: "Diabetes", Synonyms: []string{"dm", "T2DM"}},
+		{Word: "exercise", Synonyms: []string{"physical activity"}},
+	}
+
+	expect := []model.KeywordChange{
+		{Kind: model.KeywordRemoved, Keyword: "insulin"},
+		{Kind: model.SynonymRemoved, Keyword: "Diabetes", Synonym: "diabetic*"},
+		{Kind: model.SynonymAdded, Keyword: "Diabetes", Synonym: "T2DM"},
+		{Kind: model.KeywordAdded, Keyword: "exercise"},
+	}
+	if actual := current.Diff(previous); !reflect.DeepEqual(actual, expect) {
+		t.Errorf("actual %v, expect %v", actual, expect)
+	}
+	if actual := current.Diff(current); len(actual) != 0 {
+		t.Errorf("diff with itself: actual %v, expect none", actual)
+	}
+}
+
+func TestComparePilotSearches(t *testing.T) {
+	day := time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+	search := func(database model.SourceDatabase, query string, runOn time.Time, hits int, keywords model.KeywordSnapshot) model.PilotSearch {
+		return *model.NewPilotSearch(uuid.New(), uuid.New(), database, query, runOn, hits, "", keywords)
+	}
+	first := search(model.SourcePubMed, "diabetes[tiab]", day, 200, model.KeywordSnapshot{{Word: "diabetes"}})
+	scopus := search(model.SourceScopus, "TITLE-ABS-KEY(diabetes)", day.AddDate(0, 0, 1), 350, model.KeywordSnapshot{{Word: "diabetes"}})
+	second := search(model.SourcePubMed, "(diabetes[tiab] OR dm[tiab])", day.AddDate(0, 0, 2), 250, model.KeywordSnapshot{{Word: "diabetes", Synonyms: []string{"dm"}}})
+
+	comparisons := model.ComparePilotSearches([]model.PilotSearch{second, scopus, first})
+	if len(comparisons) != 3 {
+		t.Fatalf("comparisons: actual %d, expect 3", len(comparisons))
+	}
+
+	latest := comparisons[0]
+	if latest.Search.Id != second.Id || latest.Previous == nil || latest.Previous.Id != first.Id {
+		t.Fatalf("latest search should be compared with the first one in PubMed")
+	}
+	if latest.Delta != 50 || !latest.QueryChanged || latest.DeltaText() != "+50 (+25.0%)" {
+		t.Errorf("delta: actual %d %v %q", latest.Delta, latest.QueryChanged, latest.DeltaText())
+	}
+	expect := []model.KeywordChange{{Kind: model.SynonymAdded, Keyword: "diabetes", Synonym: "dm"}}
+	if !reflect.DeepEqual(latest.Changes, expect) {
+		t.Errorf("changes: actual %v, expect %v", latest.Changes, expect)
+	}
+
+	for _, comparison := range comparisons[1:] {
+		if comparison.Previous != nil || comparison.DeltaText() != "" {
+			t.Errorf("%s search on %s has no previous one", comparison.Search.SourceDatabase, comparison.Search.RunOn.Format("2006-01-02"))
+		}
+	}
+}
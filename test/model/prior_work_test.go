@@ -0,0 +1,44 @@
+package test
+
+import (
+	"sci-review/model"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func TestPriorWork(t *testing.T) {
+	investigationId, userId := uuid.New(), uuid.New()
+	protocol := func(status model.ProtocolStatus) model.RegisteredProtocol {
+		return *model.NewRegisteredProtocol(investigationId, userId, model.RegistryPROSPERO, "", "Protocol", status, "", "")
+	}
+	review := func(year int, overlap model.ReviewOverlap) model.PublishedReview {
+		return *model.NewPublishedReview(investigationId, userId, "Review", "", year, overlap, "")
+	}
+
+	empty := model.NewPriorWork(nil, nil)
+	if empty.Duplicates() {
+		t.Errorf("no prior work should not duplicate the review")
+	}
+
+	protocols := []model.RegisteredProtocol{protocol(model.ProtocolPublished), protocol(model.ProtocolAbandoned)}
+	reviews := []model.PublishedReview{review(2015, model.OverlapPartial), review(2019, model.OverlapNone)}
+	unrelated := model.NewPriorWork(protocols, reviews)
+	expect := model.PriorWork{Protocols: 2, Reviews: 2, PartialReviews: 1}
+	if unrelated != expect {
+		t.Errorf("actual %+v, expect %+v", unrelated, expect)
+	}
+	if unrelated.Duplicates() {
+		t.Errorf("partial overlap and inactive protocols should not duplicate the review")
+	}
+
+	ongoing := model.NewPriorWork(append(protocols, protocol(model.ProtocolOngoing)), reviews)
+	if ongoing.ActiveProtocols != 1 || !ongoing.Duplicates() {
+		t.Errorf("an ongoing protocol should duplicate the review: %+v", ongoing)
+	}
+
+	full := model.NewPriorWork(nil, append(reviews, review(2018, model.OverlapFull), review(2021, model.OverlapFull), review(0, model.OverlapFull)))
+	if full.FullReviews != 3 || full.LatestFullYear != 2021 || !full.Duplicates() {
+		t.Errorf("reviews on the same question: %+v", full)
+	}
+}
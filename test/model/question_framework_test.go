@@ -0,0 +1,68 @@
+package test
+
+import (
+	"github.com/google/uuid"
+	"sci-review/model"
+	"testing"
+)
+
+func TestDefaultFramework(t *testing.T) {
+	tests := []struct {
+		reviewType model.ReviewType
+		expect     model.QuestionFramework
+	}{
+		{model.SystematicReview, model.FrameworkPICO},
+		{model.ScopingReview, model.FrameworkPCC},
+		{model.RapidReview, model.FrameworkPICO},
+	}
+	for _, test := range tests {
+		if actual := test.reviewType.DefaultFramework(); actual != test.expect {
+			t.Errorf("%s: actual %s, expect %s", test.reviewType, actual, test.expect)
+		}
+	}
+}
+
+func TestFrameworkElements(t *testing.T) {
+	framework := model.QuestionFramework(model.FrameworkSPIDER)
+	if !framework.Has("phenomenon") || framework.Has("population") {
+		t.Errorf("SPIDER elements: %+v", framework.Elements())
+	}
+	if actual := model.QuestionFramework(model.FrameworkPCC).Label("context"); actual != "Context" {
+		t.Errorf("actual %q, expect Context", actual)
+	}
+	if len(model.QuestionFramework("").Elements()) != 0 {
+		t.Error("expect no elements without a framework")
+	}
+
+	keys := make(map[string]bool)
+	for _, element := range model.AllElements() {
+		if keys[element.Key] {
+			t.Errorf("element %s listed twice", element.Key)
+		}
+		keys[element.Key] = true
+	}
+	if len(keys) != 12 {
+		t.Errorf("actual %d elements, expect 12", len(keys))
+	}
+}
+
+func TestCriteria(t *testing.T) {
+	investigation := model.NewInvestigation(uuid.New(), uuid.New(), "Does aspirin prevent stroke?", model.FrameworkPICO, model.QuestionElements{"population": " adults "}, model.PiStatusInProgress)
+	keywords := []model.InvestigationKeyword{
+		*model.NewInvestigationKeyword(uuid.New(), investigation.Id, "adult", nil, "population"),
+		*model.NewInvestigationKeyword(uuid.New(), investigation.Id, "aspirin", nil, "intervention"),
+		*model.NewInvestigationKeyword(uuid.New(), investigation.Id, "elderly", nil, "population"),
+		*model.NewInvestigationKeyword(uuid.New(), investigation.Id, "stroke", nil, ""),
+	}
+
+	criteria := investigation.Criteria(keywords)
+	if len(criteria) != 4 {
+		t.Fatalf("actual %d criteria, expect 4", len(criteria))
+	}
+	if criteria[0].Label != "Population" || criteria[0].Text != "adults" || len(criteria[0].Keywords) != 2 {
+		t.Errorf("population: %+v", criteria[0])
+	}
+	if criteria[3].Key != "outcome" || criteria[3].Text != "" || len(criteria[3].Keywords) != 0 {
+		t.Errorf("outcome: %+v", criteria[3])
+	}
+}
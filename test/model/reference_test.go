@@ -0,0 +1,39 @@
+package test
+
+import (
+	"github.com/google/uuid"
+	"reflect"
+	"sci-review/model"
+	"testing"
+)
+
+func TestReferenceAuthorsRoundTrip(t *testing.T) {
+	reference := model.NewReference(uuid.New(), uuid.New(), model.SourcePubMed)
+	reference.Authors = model.Strings{"Smith, J.", "Müller, Anna", `O"Brien, K.`}
+	reference.Keywords = model.Strings{"depression, major", "exercise"}
+
+	for _, field := range []model.Strings{reference.Authors, reference.Keywords} {
+		value, err := field.Value()
+		if err != nil {
+			t.Fatalf("value: %s", err)
+		}
+		var actual model.Strings
+		if err := actual.Scan(value); err != nil {
+			t.Fatalf("scan: %s", err)
+		}
+		if !reflect.DeepEqual(actual, field) {
+			t.Errorf("actual %q, expect %q", actual, field)
+		}
+	}
+}
+
+func TestReferenceAuthorsScan(t *testing.T) {
+	var authors model.Strings
+	if err := authors.Scan([]byte(`{"Smith, J.","Jones, A. B."}`)); err != nil {
+		t.Fatal(err)
+	}
+	reference := model.Reference{Authors: authors}
+	if len(reference.Authors) != 2 || reference.FirstAuthor() != "Smith, J." {
+		t.Errorf("actual authors %q", reference.Authors)
+	}
+}
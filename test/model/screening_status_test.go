@@ -0,0 +1,72 @@
+package test
+
+import (
+	"github.com/google/uuid"
+	"sci-review/model"
+	"testing"
+)
+
+func TestDeriveScreeningStatus(t *testing.T) {
+	include := model.Decision(model.DecisionInclude)
+	exclude := model.Decision(model.DecisionExclude)
+	maybe := model.Decision(model.DecisionMaybe)
+
+	tests := []struct {
+		name         string
+		decisions    []model.Decision
+		required     int
+		adjudication *model.Decision
+		expect       model.ScreeningStatus
+	}{
+		{"no decisions", nil, 2, nil, model.StatusPending},
+		{"one of two", []model.Decision{include}, 2, nil, model.StatusPending},
+		{"agree include", []model.Decision{include, include}, 2, nil, model.StatusIncluded},
+		{"agree exclude", []model.Decision{exclude, exclude}, 2, nil, model.StatusExcluded},
+		{"disagree", []model.Decision{include, exclude}, 2, nil, model.StatusConflict},
+		{"both maybe", []model.Decision{maybe, maybe}, 2, nil, model.StatusConflict},
+		{"single screener", []model.Decision{exclude}, 1, nil, model.StatusExcluded},
+		{"adjudicated", []model.Decision{include, exclude}, 2, &exclude, model.StatusExcluded},
+	}
+
+	for _, test := range tests {
+		actual := model.DeriveScreeningStatus(test.decisions, test.required, test.adjudication)
+		if actual != test.expect {
+			t.Errorf("%s: actual %s, expect %s", test.name, actual, test.expect)
+		}
+	}
+}
+
+func TestAdjudicator(t *testing.T) {
+	owner := model.Reviewer{Id: [16]byte{1}, ReviewerRole: model.ReviewerOwner, Active: true}
+	member := model.Reviewer{Id: [16]byte{2}, ReviewerRole: model.ReviewerMember, Active: true}
+
+	if actual := model.Adjudicator([]model.Reviewer{owner, member}); actual == nil || actual.Id != owner.Id {
+		t.Errorf("actual %+v, expect the owner", actual)
+	}
+
+	member.ReviewerRole = model.ReviewerAdjudicator
+	if actual := model.Adjudicator([]model.Reviewer{owner, member}); actual == nil || actual.Id != member.Id {
+		t.Errorf("actual %+v, expect the member", actual)
+	}
+}
+
+func TestAcceptsDecision(t *testing.T) {
+	first, second, third := uuid.New(), uuid.New(), uuid.New()
+	decisions := []model.ScreeningDecision{
+		{ReviewerId: first, Decision: model.DecisionInclude},
+		{ReviewerId: second, Decision: model.DecisionInclude},
+	}
+
+	if model.AcceptsDecision(decisions, third, 2) {
+		t.Errorf("a third screener decided on a record screened by two of two")
+	}
+	if !model.AcceptsDecision(decisions, first, 2) {
+		t.Errorf("a screener could not revise their decision")
+	}
+	if !model.AcceptsDecision(decisions[:1], third, 2) {
+		t.Errorf("a second screener could not decide")
+	}
+	if model.AcceptsDecision(decisions[:1], third, 1) {
+		t.Errorf("a second screener decided on a single screening record")
+	}
+}
@@ -0,0 +1,65 @@
+package test
+
+import (
+	"reflect"
+	"sci-review/model"
+	"testing"
+)
+
+func TestStringsRoundTrip(t *testing.T) {
+	synonyms := model.Strings{
+		"non-small cell lung cancer, stage IV",
+		`"heart attack"[ti]`,
+		`back\slash`,
+		"{braces}",
+		"NULL",
+		" padded ",
+		"infarct*",
+		"",
+	}
+	value, err := synonyms.Value()
+	if err != nil {
+		t.Fatalf("value: %s", err)
+	}
+
+	for _, src := range []interface{}{value, []byte(value.(string))} {
+		var actual model.Strings
+		if err := actual.Scan(src); err != nil {
+			t.Fatalf("scan %T: %s", src, err)
+		}
+		if !reflect.DeepEqual(actual, synonyms) {
+			t.Errorf("scan %T: actual %q, expect %q", src, actual, synonyms)
+		}
+	}
+}
+
+func TestStringsScan(t *testing.T) {
+	cases := []struct {
+		src    interface{}
+		expect model.Strings
+	}{
+		{"{}", model.Strings{}},
+		{nil, model.Strings{}},
+		{"{asthma,wheez*}", model.Strings{"asthma", "wheez*"}},
+		{[]byte(`{"lung cancer, stage IV","say \"hi\""}`), model.Strings{"lung cancer, stage IV", `say "hi"`}},
+		{`{a,NULL,"NULL"}`, model.Strings{"a", "NULL"}},
+	}
+	for _, c := range cases {
+		var actual model.Strings
+		if err := actual.Scan(c.src); err != nil {
+			t.Errorf("%v: %s", c.src, err)
+			continue
+		}
+		if !reflect.DeepEqual(actual, c.expect) {
+			t.Errorf("%v: actual %q, expect %q", c.src, actual, c.expect)
+		}
+	}
+
+	var actual model.Strings
+	if err := actual.Scan(42); err == nil {
+		t.Errorf("expect an error scanning a number")
+	}
+	if value, _ := model.Strings(nil).Value(); value != "{}" {
+		t.Errorf("nil value: actual %v, expect {}", value)
+	}
+}
@@ -0,0 +1,65 @@
+package test
+
+import (
+	"bytes"
+	"image/png"
+	"sci-review/prisma"
+	"strings"
+	"testing"
+)
+
+var counts = prisma.Counts{
+	Sources:        []prisma.Source{{Name: "PubMed", Records: 120}, {Name: "Scopus", Records: 80}},
+	Duplicates:     40,
+	Screened:       160,
+	ScreenExcluded: 130,
+	Sought:         30,
+	NotRetrieved:   2,
+	Assessed:       28,
+	Reasons:        []prisma.Reason{{Label: "Wrong population", Reports: 10}, {Label: "Wrong <design>", Reports: 6}},
+	Included:       12,
+}
+
+func TestCounts(t *testing.T) {
+	if actual := counts.Identified(); actual != 200 {
+		t.Errorf("actual %d, expect 200", actual)
+	}
+	if actual := counts.ReportsExcluded(); actual != 16 {
+		t.Errorf("actual %d, expect 16", actual)
+	}
+}
+
+func TestWriteSvg(t *testing.T) {
+	var out bytes.Buffer
+	if err := prisma.WriteSvg(&out, counts); err != nil {
+		t.Fatal(err)
+	}
+
+	svg := out.String()
+	for _, expect := range []string{
+		"<svg", "Identification", "Screening", "Included",
+		"Databases (n = 200)", "PubMed (n = 120)", "Duplicate records removed (n = 40)",
+		"Records screened (n = 160)", "Reports not retrieved (n = 2)",
+		"Reports excluded (n = 16)", "Wrong &lt;design&gt; (n = 6)",
+		"Studies included in review (n = 12)",
+	} {
+		if !strings.Contains(svg, expect) {
+			t.Errorf("svg does not contain %q", expect)
+		}
+	}
+}
+
+func TestWritePng(t *testing.T) {
+	var out bytes.Buffer
+	if err := prisma.WritePng(&out, counts); err != nil {
+		t.Fatal(err)
+	}
+
+	img, err := png.Decode(&out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if bounds := img.Bounds(); bounds.Dx() < 300 || bounds.Dy() < 300 {
+		t.Errorf("actual size %v", bounds)
+	}
+}
@@ -0,0 +1,101 @@
+package test
+
+import (
+	"bytes"
+	"math"
+	"sci-review/rob"
+	"strings"
+	"testing"
+)
+
+func lowRisk() rob.Answers {
+	return rob.Answers{
+		"1.1": rob.AnswerYes, "1.2": rob.AnswerYes, "1.3": rob.AnswerNo,
+		"2.1": rob.AnswerNo, "2.2": rob.AnswerNo, "2.3": rob.AnswerNotApplicable, "2.4": rob.AnswerNotApplicable,
+		"2.5": rob.AnswerNotApplicable, "2.6": rob.AnswerYes, "2.7": rob.AnswerNotApplicable,
+		"3.1": rob.AnswerYes, "3.2": rob.AnswerNotApplicable, "3.3": rob.AnswerNotApplicable, "3.4": rob.AnswerNotApplicable,
+		"4.1": rob.AnswerNo, "4.2": rob.AnswerNo, "4.3": rob.AnswerNo, "4.4": rob.AnswerNotApplicable, "4.5": rob.AnswerNotApplicable,
+		"5.1": rob.AnswerYes, "5.2": rob.AnswerNo, "5.3": rob.AnswerProbablyNo,
+	}
+}
+
+func TestRoB2Assess(t *testing.T) {
+	answers := lowRisk()
+	result := rob.RoB2.Assess(answers)
+	for i, judgement := range result.Domains {
+		if judgement != rob.Low {
+			t.Errorf("domain %d actual %s, expect Low", i+1, judgement)
+		}
+	}
+	if result.Overall != rob.Low {
+		t.Errorf("overall actual %s, expect Low", result.Overall)
+	}
+	if unanswered := rob.RoB2.Unanswered(answers); len(unanswered) != 0 {
+		t.Errorf("actual %d unanswered questions, expect 0", len(unanswered))
+	}
+
+	answers["1.2"] = rob.AnswerNo
+	answers["5.1"] = rob.AnswerNoInformation
+	result = rob.RoB2.Assess(answers)
+	if result.Domains[0] != rob.High || result.Domains[4] != rob.SomeConcerns || result.Overall != rob.High {
+		t.Errorf("actual %+v", result)
+	}
+
+	delete(answers, "4.4")
+	answers["4.5"] = "maybe"
+	if unanswered := rob.RoB2.Unanswered(answers); len(unanswered) != 2 {
+		t.Errorf("actual %d unanswered questions, expect 2", len(unanswered))
+	}
+}
+
+func TestRobinsIOverall(t *testing.T) {
+	answers := rob.Answers{}
+	for _, question := range rob.RobinsI.Questions() {
+		answers[question.Id] = rob.AnswerNoInformation
+	}
+	answers["1.1"] = rob.AnswerNo
+	result := rob.RobinsI.Assess(answers)
+	if result.Domains[0] != rob.Low || result.Overall != rob.NoInformation {
+		t.Errorf("actual %+v", result)
+	}
+
+	answers["7.1"] = rob.AnswerYes
+	result = rob.RobinsI.Assess(answers)
+	if result.Domains[6] != rob.Serious || result.Overall != rob.Serious {
+		t.Errorf("actual %+v", result)
+	}
+}
+
+func TestSummaryCharts(t *testing.T) {
+	rows := []rob.Row{
+		{Study: "Smith 2019", Weight: 3, Domains: []rob.Judgement{rob.Low, rob.Low, rob.Low, rob.Low, rob.Low}, Overall: rob.Low},
+		{Study: "Doe 2020", Weight: 1, Domains: []rob.Judgement{rob.High, rob.Low, rob.SomeConcerns, rob.Low, rob.Low}, Overall: rob.High},
+	}
+
+	proportions := rob.Proportions(rob.RoB2, rows, true)
+	if math.Abs(proportions[0][0]-0.75) > 1e-9 || math.Abs(proportions[5][2]-0.25) > 1e-9 {
+		t.Errorf("actual %v", proportions)
+	}
+	proportions = rob.Proportions(rob.RoB2, rows, false)
+	if math.Abs(proportions[0][0]-0.5) > 1e-9 {
+		t.Errorf("actual %v", proportions)
+	}
+
+	var buffer bytes.Buffer
+	if err := rob.WriteTrafficLight(&buffer, rob.RoB2, rows); err != nil {
+		t.Fatal(err)
+	}
+	for _, expect := range []string{"<svg", "D1", "D5", "Smith 2019", "Overall"} {
+		if !strings.Contains(buffer.String(), expect) {
+			t.Errorf("traffic light misses %q", expect)
+		}
+	}
+
+	buffer.Reset()
+	if err := rob.WriteSummary(&buffer, rob.RoB2, rows, true); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(buffer.String(), rob.RoB2.Domains[0].Name) {
+		t.Error("summary misses the domain names")
+	}
+}
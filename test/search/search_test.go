@@ -0,0 +1,119 @@
+package test
+
+import (
+	"sci-review/search"
+	"testing"
+)
+
+func TestParseTerm(t *testing.T) {
+	cases := []struct {
+		text   string
+		expect search.Term
+	}{
+		{"asthma", search.Term{Text: "asthma"}},
+		{" infarct* ", search.Term{Text: "infarct", Truncated: true}},
+		{"child$", search.Term{Text: "child", Truncated: true}},
+		{"heart  attack", search.Term{Text: "heart attack", Phrase: true}},
+		{`"smoking"`, search.Term{Text: "smoking", Phrase: true}},
+		{`"myocardial infarct*"`, search.Term{Text: "myocardial infarct", Phrase: true, Truncated: true}},
+		{`quit "smoking`, search.Term{Text: "quit smoking", Phrase: true}},
+		{`"non-small cell lung cancer, stage IV"`, search.Term{Text: "non-small cell lung cancer, stage IV", Phrase: true}},
+		{`"lung neoplasm"*`, search.Term{Text: "lung neoplasm", Phrase: true, Truncated: true}},
+		{"infarct* [ti]", search.Term{Text: "infarct", Truncated: true, Field: search.FieldTitle}},
+		{`"heart attack"[AB]`, search.Term{Text: "heart attack", Phrase: true, Field: search.FieldAbstract}},
+		{"myocardial infarction[mesh]", search.Term{Text: "myocardial infarction", Phrase: true, Field: search.FieldSubject}},
+		{"aspirin[tiab]", search.Term{Text: "aspirin"}},
+		{"vitamin [b12]", search.Term{Text: "vitamin [b12]", Phrase: true}},
+	}
+	for _, c := range cases {
+		if actual := search.ParseTerm(c.text); actual != c.expect {
+			t.Errorf("%q: actual %+v, expect %+v", c.text, actual, c.expect)
+		}
+	}
+}
+
+func TestTermString(t *testing.T) {
+	for _, text := range []string{"asthma", "infarct*", `"heart attack"`, `"myocardial infarct"*[ti]`, "aspirin[sh]"} {
+		if actual := search.ParseTerm(text).String(); actual != text {
+			t.Errorf("actual %q, expect %q", actual, text)
+		}
+	}
+}
+
+func TestBuildFields(t *testing.T) {
+	concepts := []search.Concept{
+		search.NewConcept("myocardial infarction[mesh]", []string{"heart attack", "infarct*[ti]", "heart attack[ti]"}),
+		search.NewConcept("aspirin[kw]", nil),
+	}
+	if len(concepts[0].Terms) != 4 {
+		t.Fatalf("expect the same term in different fields kept, actual %+v", concepts[0].Terms)
+	}
+
+	expect := map[search.Database]string{
+		search.PubMed:       `(("heart attack"[tiab]) OR "myocardial infarction"[mh] OR infarct*[ti] OR "heart attack"[ti]) AND aspirin[ot]`,
+		search.Scopus:       `(TITLE-ABS-KEY("heart attack") OR INDEXTERMS("myocardial infarction") OR TITLE(infarct*) OR TITLE("heart attack")) AND AUTHKEY(aspirin)`,
+		search.WebOfScience: `(TS=("heart attack") OR KP=("myocardial infarction") OR TI=(infarct*) OR TI=("heart attack")) AND AK=(aspirin)`,
+		search.Embase:       `(("heart attack").ti,ab,kw. or exp myocardial infarction/ or infarct*.ti. or "heart attack".ti.) and aspirin.kw.`,
+		search.CINAHL:       `((TI ("heart attack") OR AB ("heart attack")) OR (MH "myocardial infarction") OR TI infarct* OR TI "heart attack") AND SU aspirin`,
+	}
+	for _, query := range search.BuildAll(concepts) {
+		if query.Text != expect[query.Database] {
+			t.Errorf("%s:\nactual %s\nexpect %s", query.Name, query.Text, expect[query.Database])
+		}
+	}
+}
+
+func TestBuild(t *testing.T) {
+	concepts := []search.Concept{
+		search.NewConcept("myocardial infarction", []string{"heart attack", "infarct*", "Heart attack", ""}),
+		search.NewConcept("aspirin", []string{"acetylsalicylic acid"}),
+		search.NewConcept("", nil),
+	}
+	if len(concepts[0].Terms) != 3 {
+		t.Fatalf("expect repeated and empty synonyms left out, actual %+v", concepts[0].Terms)
+	}
+
+	expect := map[search.Database]string{
+		search.PubMed:       `("myocardial infarction"[tiab] OR "heart attack"[tiab] OR infarct*[tiab]) AND (aspirin[tiab] OR "acetylsalicylic acid"[tiab])`,
+		search.Scopus:       `TITLE-ABS-KEY("myocardial infarction" OR "heart attack" OR infarct*) AND TITLE-ABS-KEY(aspirin OR "acetylsalicylic acid")`,
+		search.WebOfScience: `TS=("myocardial infarction" OR "heart attack" OR infarct*) AND TS=(aspirin OR "acetylsalicylic acid")`,
+		search.Embase:       `("myocardial infarction" or "heart attack" or infarct*).ti,ab,kw. and (aspirin or "acetylsalicylic acid").ti,ab,kw.`,
+		search.CINAHL:       `(TI ("myocardial infarction" OR "heart attack" OR infarct*) OR AB ("myocardial infarction" OR "heart attack" OR infarct*)) AND (TI (aspirin OR "acetylsalicylic acid") OR AB (aspirin OR "acetylsalicylic acid"))`,
+	}
+	queries := search.BuildAll(concepts)
+	if len(queries) != len(search.Databases) {
+		t.Fatalf("actual %d queries, expect %d", len(queries), len(search.Databases))
+	}
+	for _, query := range queries {
+		if query.Text != expect[query.Database] {
+			t.Errorf("%s:\nactual %s\nexpect %s", query.Name, query.Text, expect[query.Database])
+		}
+	}
+
+	if actual := search.Build(search.PubMed, nil); actual != "" {
+		t.Errorf("expect an empty string without concepts, actual %q", actual)
+	}
+}
+
+func TestSuggestHeadings(t *testing.T) {
+	records := [][]string{
+		{"*Exercise", "Depression/*therapy", "Humans"},
+		{"Exercise/physiology", "Exercise Therapy/methods", "Humans"},
+		{"Depressive Disorder, Major/therapy", "Humans", "Aged"},
+	}
+	concepts := []search.Concept{search.NewConcept("depression", []string{"Humans[sh]"})}
+
+	actual := search.SuggestHeadings(records, concepts, 3)
+	expect := []search.Heading{{Text: "Exercise", Records: 2}, {Text: "Aged", Records: 1}, {Text: "Depressive Disorder, Major", Records: 1}}
+	if len(actual) != len(expect) {
+		t.Fatalf("actual %+v, expect %+v", actual, expect)
+	}
+	for i := range expect {
+		if actual[i] != expect[i] {
+			t.Errorf("actual %+v, expect %+v", actual[i], expect[i])
+		}
+	}
+	if term := actual[2].Term(); term != `"Depressive Disorder, Major"[sh]` {
+		t.Errorf("actual term %q", term)
+	}
+}